@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
 
 	"github.com/hibiken/asynq"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rhajizada/gazette/internal/config"
 	"github.com/rhajizada/gazette/internal/database"
+	"github.com/rhajizada/gazette/internal/health"
+	"github.com/rhajizada/gazette/internal/logging"
+	"github.com/rhajizada/gazette/internal/metrics"
 	"github.com/rhajizada/gazette/internal/workers"
 )
 
@@ -25,28 +31,39 @@ func main() {
 
 	cfg, err := config.LoadScheduler()
 	if err != nil {
-		log.Panicf("error loading config: %v", err)
+		logging.Fatal("error loading config", err)
 	}
+	logger := logging.Setup(&cfg.Logging, "gazette-scheduler")
 	conn := database.CreateRedisClient(&cfg.Redis)
 	scheduler := asynq.NewScheduler(conn, &asynq.SchedulerOpts{
 		HeartbeatInterval: cfg.HeartbeatInterval,
 		Location:          cfg.Location,
+		Logger:            logging.AsynqLogger{Logger: logger},
+		LogLevel:          logging.AsynqLevel(cfg.Logging.Level),
 	})
 
 	err = scheduler.Ping()
 	if err != nil {
-		log.Panicf("failed to initialze scheduler: %v", err)
+		logging.Fatal("failed to initialze scheduler", err)
 	}
 
+	inspector := asynq.NewInspector(conn)
+	prometheus.MustRegister(metrics.NewQueueCollector(inspector))
+	checker := health.New(health.DefaultTimeout)
+	checker.Add("redis", func(context.Context) error { return scheduler.Ping() })
+	probes := http.NewServeMux()
+	checker.Register(probes)
+	metrics.Serve(cfg.MetricsPort, probes)
+
 	dataSyncTask, _ := workers.NewSyncDataTask()
 
-	id, err := scheduler.Register("@every 30m", dataSyncTask, asynq.Queue("critical"))
+	id, err := scheduler.Register(fmt.Sprintf("@every %s", workers.SyncInterval), dataSyncTask, asynq.Queue("critical"))
 	if err != nil {
-		log.Panicf("failed to schedule data sync task: %v", err)
+		logging.Fatal("failed to schedule data sync task", err)
 	}
-	log.Printf("scheduled data sync task %s", id)
+	slog.Info("scheduled data sync task", slog.String("entry_id", id))
 
 	if err := scheduler.Run(); err != nil {
-		log.Panicf("could not run scheduler: %v", err)
+		logging.Fatal("could not run scheduler", err)
 	}
 }
@@ -1,23 +1,30 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/hibiken/asynq"
 	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/pressly/goose/v3"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rhajizada/gazette/internal/config"
 	"github.com/rhajizada/gazette/internal/database"
 	"github.com/rhajizada/gazette/internal/handler"
+	"github.com/rhajizada/gazette/internal/health"
+	"github.com/rhajizada/gazette/internal/logging"
+	"github.com/rhajizada/gazette/internal/metrics"
 	"github.com/rhajizada/gazette/internal/middleware"
 	"github.com/rhajizada/gazette/internal/oauth"
-	"github.com/rhajizada/gazette/internal/repository"
 	"github.com/rhajizada/gazette/internal/router"
 	"github.com/rhajizada/gazette/internal/service"
+	"github.com/rhajizada/gazette/internal/tracing"
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
@@ -44,67 +51,121 @@ func main() {
 
 	cfg, err := config.LoadServer()
 	if err != nil {
-		log.Panicf("error loading config: %v", err)
+		logging.Fatal("error loading config", err)
 	}
+	logging.Setup(&cfg.Logging, "gazette-server")
+
+	shutdownTracing, err := tracing.Init(context.Background(), &cfg.Tracing, "gazette-server", Version)
+	if err != nil {
+		logging.Fatal("failed to initialize tracing", err)
+	}
+	defer shutdownTracing(context.Background())
 
 	pool, err := database.CreatePool(&cfg.Database)
 	if err != nil {
-		log.Panicf("failed to connect to database: %v", err)
+		logging.Fatal("failed to connect to database", err)
 	}
 	defer pool.Close()
 
 	migrationsDir := "data/sql/migrations"
 	if _, err := os.Stat(migrationsDir); os.IsNotExist(err) {
-		log.Panicf("migrations directory does not exist: %s", migrationsDir)
+		logging.Fatal("migrations directory does not exist", fmt.Errorf("stat %s: %w", migrationsDir, err))
 	}
 
 	if err := goose.SetDialect("postgres"); err != nil {
-		log.Panicf("failed to set goose dialect: %v", err)
+		logging.Fatal("failed to set goose dialect", err)
 	}
 
 	db := stdlib.OpenDBFromPool(pool)
 
 	if err := goose.Up(db, migrationsDir); err != nil {
-		log.Panicf("failed to apply migrations: %v", err)
+		logging.Fatal("failed to apply migrations", err)
 	}
 
-	rq := repository.New(pool)
 	conn := database.CreateRedisClient(&cfg.Redis)
 	client := *asynq.NewClient(conn)
 	err = client.Ping()
 	if err != nil {
-		log.Panicf("failed to connect to Redis: %v", err)
+		logging.Fatal("failed to connect to Redis", err)
 	}
 
-	verifier, err := oauth.GetVerifier(&cfg.OAuth)
+	providers, err := oauth.GetProviders(cfg.Providers)
 	if err != nil {
-		log.Panicf("failed to initialize auth provider: %v", err)
-	}
-
-	oauthCfg, err := oauth.GetConfig(&cfg.OAuth)
-	if err != nil {
-		log.Panicf("failed to initialize auth provider: %v", err)
+		logging.Fatal("failed to initialize auth provider", err)
 	}
 
 	// Create handler
-	service := service.New(rq, &client)
-	handler := handler.New(service, []byte(cfg.SecretKey), verifier, oauthCfg)
+	inspector := asynq.NewInspector(conn)
+	service := service.New(pool, &client, inspector)
+	service.AllowPrivateWebhooks = cfg.Webhooks.AllowPrivate
+	handler := handler.New(service, []byte(cfg.SecretKey), providers, &cfg.Session, &cfg.Roles, cfg.PublicURL)
 
 	mux := http.NewServeMux()
 	apiRoutes := router.RegisterAPI(handler)
 	oauthRoutes := router.RegisterOAuthRoutes(handler)
-
-	loggingMiddleware := middleware.Logging()
-	authMiddleware := middleware.APIAuthMiddleware([]byte(cfg.SecretKey))
-
-	mux.Handle("/api/", http.StripPrefix("/api", authMiddleware(apiRoutes)))
+	readerAuthRoutes := router.RegisterReaderAuthRoutes(handler)
+	readerRoutes := router.RegisterReaderAPI(handler)
+	feverRoutes := router.RegisterFeverAPI(handler)
+	publicRoutes := router.RegisterPublicRoutes(handler)
+
+	stack := middleware.CreateStack(
+		middleware.Tracing(),
+		middleware.RequestID(),
+		middleware.Logging(),
+	)
+	authMiddleware := middleware.APIAuthMiddleware([]byte(cfg.SecretKey), service)
+	readerAuthMiddleware := middleware.ReaderAuthMiddleware([]byte(cfg.SecretKey), service)
+
+	prometheus.MustRegister(metrics.NewPoolCollector(pool))
+	checker := health.New(health.DefaultTimeout)
+	checker.Add("postgres", pool.Ping)
+	checker.Add("redis", func(context.Context) error { return client.Ping() })
+	checker.Add("migrations", func(ctx context.Context) error {
+		return database.CheckMigrations(ctx, db, migrationsDir)
+	})
+	checker.Register(mux)
+
+	mux.Handle("/api/", http.StripPrefix("/api", authMiddleware(middleware.Route("/api", apiRoutes))))
 	mux.Handle("/api/docs/", httpSwagger.WrapHandler)
-	mux.Handle("/oauth/", http.StripPrefix("/oauth", oauthRoutes))
+	mux.Handle("/oauth/", http.StripPrefix("/oauth", middleware.Route("/oauth", oauthRoutes)))
+	mux.Handle("/reader/accounts/", http.StripPrefix("/reader", middleware.Route("/reader", readerAuthRoutes)))
+	mux.Handle("/reader/api/0/", http.StripPrefix("/reader/api/0", readerAuthMiddleware(middleware.Route("/reader/api/0", readerRoutes))))
+	mux.Handle("/fever/", http.StripPrefix("/fever", middleware.Route("/fever", feverRoutes)))
+	mux.Handle("/public/", http.StripPrefix("/public", middleware.Route("/public", publicRoutes)))
+	mux.Handle("GET /metrics", metrics.Handler())
 	mux.Handle("/", http.HandlerFunc(handler.WebHandler))
 
-	log.Printf("server is running on port %v\n", cfg.Port)
-	addr := fmt.Sprintf(":%v", cfg.Port)
-	if err := http.ListenAndServe(addr, loggingMiddleware(mux)); err != nil {
-		log.Panicf("could not start server: %s\n", err.Error())
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%v", cfg.Port),
+		Handler: stack(mux),
+	}
+	errs := make(chan error, 1)
+	go func() {
+		slog.Info("server is running", slog.Int("port", cfg.Port))
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		logging.Fatal("could not start server", err)
+	case <-ctx.Done():
+	}
+	stop()
+
+	slog.Info("shutting down server", slog.Duration("timeout", cfg.ShutdownTimeout))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		slog.Error("failed to drain connections", slog.Any("error", err))
+	}
+	if err := client.Close(); err != nil {
+		slog.Error("failed to close Redis client", slog.Any("error", err))
+	}
+	if err := inspector.Close(); err != nil {
+		slog.Error("failed to close Redis inspector", slog.Any("error", err))
 	}
+	slog.Info("server stopped")
 }
@@ -1,19 +1,28 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/hibiken/asynq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rhajizada/gazette/internal/metrics"
+	"github.com/rhajizada/gazette/internal/webhooks"
 	"github.com/rhajizada/gazette/internal/workers"
 
 	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/pressly/goose/v3"
 	"github.com/rhajizada/gazette/internal/config"
 	"github.com/rhajizada/gazette/internal/database"
+	"github.com/rhajizada/gazette/internal/health"
+	"github.com/rhajizada/gazette/internal/logging"
 	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/tracing"
 )
 
 var Version = "dev"
@@ -29,28 +38,35 @@ func main() {
 
 	cfg, err := config.LoadWorker()
 	if err != nil {
-		log.Panicf("error loading config: %v", err)
+		logging.Fatal("error loading config", err)
 	}
+	logger := logging.Setup(&cfg.Logging, "gazette-worker")
+
+	shutdownTracing, err := tracing.Init(context.Background(), &cfg.Tracing, "gazette-worker", Version)
+	if err != nil {
+		logging.Fatal("failed to initialize tracing", err)
+	}
+	defer shutdownTracing(context.Background())
 
 	pool, err := database.CreatePool(&cfg.Database)
 	if err != nil {
-		log.Panicf("failed to connect to database: %v", err)
+		logging.Fatal("failed to connect to database", err)
 	}
 	defer pool.Close()
 
 	migrationsDir := "data/sql/migrations"
 	if _, err := os.Stat(migrationsDir); os.IsNotExist(err) {
-		log.Panicf("migrations directory does not exist: %s", migrationsDir)
+		logging.Fatal("migrations directory does not exist", fmt.Errorf("stat %s: %w", migrationsDir, err))
 	}
 
 	if err := goose.SetDialect("postgres"); err != nil {
-		log.Panicf("failed to set goose dialect: %v", err)
+		logging.Fatal("failed to set goose dialect", err)
 	}
 
 	db := stdlib.OpenDBFromPool(pool)
 
 	if err := goose.Up(db, migrationsDir); err != nil {
-		log.Panicf("failed to apply migrations: %v", err)
+		logging.Fatal("failed to apply migrations", err)
 	}
 
 	rq := repository.New(pool)
@@ -58,32 +74,68 @@ func main() {
 	client := *asynq.NewClient(conn)
 	err = client.Ping()
 	if err != nil {
-		log.Panicf("failed to connect to Redis: %v", err)
+		logging.Fatal("failed to connect to Redis", err)
 	}
 	if err != nil {
-		log.Panicf("failed to connect to Redis: %v", err)
+		logging.Fatal("failed to connect to Redis", err)
 	}
 
 	serverConfig := workers.GetConfig(&cfg.Queues)
+	serverConfig.Logger = logging.AsynqLogger{Logger: logger}
+	serverConfig.LogLevel = logging.AsynqLevel(cfg.Logging.Level)
+	serverConfig.ShutdownTimeout = cfg.ShutdownTimeout
 
 	ollamaClient, err := workers.GetOllamaClient(&cfg.Ollama)
 	if err != nil {
-		log.Panicf("failed to initialize Ollama client: %v", err)
+		logging.Fatal("failed to initialize Ollama client", err)
 	}
-	err = workers.InitModels(ollamaClient, &cfg.Ollama)
+	err = workers.InitModels(context.Background(), ollamaClient, &cfg.Ollama)
 	if err != nil {
-		log.Panicf("failed to initialize models: %v", err)
+		logging.Fatal("failed to initialize models", err)
 	}
 
+	prometheus.MustRegister(metrics.NewPoolCollector(pool))
+	checker := health.New(health.DefaultTimeout)
+	checker.Add("postgres", pool.Ping)
+	checker.Add("redis", func(context.Context) error { return client.Ping() })
+	checker.Add("ollama", func(ctx context.Context) error {
+		return workers.InitModels(ctx, ollamaClient, &cfg.Ollama)
+	})
+	checker.Add("migrations", func(ctx context.Context) error {
+		return database.CheckMigrations(ctx, db, migrationsDir)
+	})
+	probes := http.NewServeMux()
+	checker.Register(probes)
+	probeServer := metrics.Serve(cfg.MetricsPort, probes)
+
 	server := asynq.NewServer(conn, *serverConfig)
 
-	handler := workers.NewHandler(rq, &client, &cfg.Ollama)
+	inspector := asynq.NewInspector(conn)
+	handler := workers.NewHandler(rq, &client, inspector, &cfg.Ollama, webhooks.NewClient(cfg.Webhooks.AllowPrivate))
 	mux := asynq.NewServeMux()
+	mux.Use(workers.Tracing, workers.Logging)
 	mux.HandleFunc(workers.TypeSyncData, handler.HandleDataSync)
 	mux.HandleFunc(workers.TypeSyncFeed, handler.HandleFeedSync)
 	mux.HandleFunc(workers.TypeEmbedItem, handler.HandleEmbedItem)
+	mux.HandleFunc(workers.TypeEmbedCollection, handler.HandleEmbedCollection)
+	mux.HandleFunc(workers.TypeDeliverWebhook, handler.HandleWebhookDelivery)
 
+	// Run blocks until SIGINT or SIGTERM, then waits up to the shutdown
+	// timeout for in-flight tasks before requeueing them.
 	if err := server.Run(mux); err != nil {
-		log.Panicf("could not run worker: %v", err)
+		logging.Fatal("could not run worker", err)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := probeServer.Shutdown(shutdownCtx); err != nil {
+		slog.Error("failed to stop metrics server", slog.Any("error", err))
+	}
+	if err := client.Close(); err != nil {
+		slog.Error("failed to close Redis client", slog.Any("error", err))
+	}
+	if err := inspector.Close(); err != nil {
+		slog.Error("failed to close Redis inspector", slog.Any("error", err))
 	}
+	slog.Info("worker stopped")
 }
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- numeric item identifiers for third-party reader protocols
+ALTER TABLE items ADD COLUMN seq BIGINT GENERATED ALWAYS AS IDENTITY UNIQUE;
+
+CREATE TABLE user_reads (
+  user_id  UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  item_id  UUID         NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+  read_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, item_id)
+);
+
+CREATE TABLE app_passwords (
+  id             UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id        UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name           TEXT         NOT NULL,
+  password_hash  TEXT         UNIQUE NOT NULL,
+  created_at     TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  last_used_at   TIMESTAMPTZ,
+  UNIQUE (user_id, name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS app_passwords;
+DROP TABLE IF EXISTS user_reads;
+
+ALTER TABLE items DROP COLUMN IF EXISTS seq;
+-- +goose StatementEnd
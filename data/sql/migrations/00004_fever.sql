@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- numeric feed identifiers for the Fever API
+ALTER TABLE feeds ADD COLUMN seq BIGINT GENERATED ALWAYS AS IDENTITY UNIQUE;
+
+CREATE TABLE fever_credentials (
+  user_id     UUID         PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  api_key     TEXT         UNIQUE NOT NULL,
+  created_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS fever_credentials;
+
+ALTER TABLE feeds DROP COLUMN IF EXISTS seq;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE access_tokens (
+  id            UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id       UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name          TEXT         NOT NULL,
+  token_hash    TEXT         UNIQUE NOT NULL,
+  scopes        TEXT[]       NOT NULL,
+  expires_at    TIMESTAMPTZ,
+  created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  last_used_at  TIMESTAMPTZ,
+  UNIQUE (user_id, name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS access_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE sessions (
+  id            UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id       UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  device        TEXT         NOT NULL,
+  ip            TEXT         NOT NULL,
+  user_agent    TEXT         NOT NULL,
+  id_token      TEXT,
+  created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  last_seen_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  expires_at    TIMESTAMPTZ  NOT NULL,
+  revoked_at    TIMESTAMPTZ
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+
+CREATE TABLE refresh_tokens (
+  id          UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+  session_id  UUID         NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+  token_hash  TEXT         UNIQUE NOT NULL,
+  created_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  used_at     TIMESTAMPTZ
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS sessions;
+-- +goose StatementEnd
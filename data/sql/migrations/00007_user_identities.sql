@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE user_identities (
+  id             UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id        UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  provider       TEXT         NOT NULL,
+  issuer         TEXT         NOT NULL,
+  sub            TEXT         NOT NULL,
+  email          TEXT,
+  created_at     TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  last_login_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  UNIQUE (issuer, sub)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities(user_id);
+
+-- subjects are only unique per issuer
+ALTER TABLE users DROP CONSTRAINT users_sub_key;
+CREATE INDEX idx_users_sub ON users(sub);
+
+-- names and emails come from different providers now, unrelated people may
+-- share them and both get an account
+ALTER TABLE users DROP CONSTRAINT users_name_key;
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+CREATE INDEX idx_users_email ON users(lower(email));
+
+ALTER TABLE sessions ADD COLUMN provider TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE sessions DROP COLUMN IF EXISTS provider;
+DROP INDEX IF EXISTS idx_users_email;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+ALTER TABLE users ADD CONSTRAINT users_name_key UNIQUE (name);
+DROP INDEX IF EXISTS idx_users_sub;
+ALTER TABLE users ADD CONSTRAINT users_sub_key UNIQUE (sub);
+DROP TABLE IF EXISTS user_identities;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN disabled_at TIMESTAMPTZ;
+
+CREATE TABLE feed_health (
+  feed_id          UUID         PRIMARY KEY REFERENCES feeds(id) ON DELETE CASCADE,
+  last_success_at  TIMESTAMPTZ,
+  last_failure_at  TIMESTAMPTZ,
+  last_error       TEXT,
+  failure_count    INTEGER      NOT NULL DEFAULT 0
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS feed_health;
+ALTER TABLE users DROP COLUMN IF EXISTS disabled_at;
+-- +goose StatementEnd
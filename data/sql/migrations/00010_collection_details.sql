@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE collections
+  ADD COLUMN description  TEXT,
+  ADD COLUMN icon         TEXT,
+  ADD COLUMN color        TEXT,
+  ADD COLUMN pinned       BOOLEAN  NOT NULL DEFAULT FALSE,
+  ADD COLUMN position     INTEGER;
+
+-- position is set when the user reorders; unordered rows sort first, newest first
+ALTER TABLE collection_items ADD COLUMN position INTEGER;
+
+CREATE INDEX idx_collections_user_order ON collections (user_id, pinned DESC, position NULLS FIRST, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_collections_user_order;
+ALTER TABLE collection_items DROP COLUMN IF EXISTS position;
+ALTER TABLE collections
+  DROP COLUMN IF EXISTS position,
+  DROP COLUMN IF EXISTS pinned,
+  DROP COLUMN IF EXISTS color,
+  DROP COLUMN IF EXISTS icon,
+  DROP COLUMN IF EXISTS description;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- slug is generated the first time a collection is published and kept when it
+-- is made private again, so the public URL only changes when it is rotated
+ALTER TABLE collections
+  ADD COLUMN public  BOOLEAN  NOT NULL DEFAULT FALSE,
+  ADD COLUMN slug    TEXT     UNIQUE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE collections
+  DROP COLUMN IF EXISTS slug,
+  DROP COLUMN IF EXISTS public;
+-- +goose StatementEnd
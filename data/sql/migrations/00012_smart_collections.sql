@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- rules turns a collection into a smart collection whose items are matched
+-- when listed instead of read from collection_items
+ALTER TABLE collections ADD COLUMN rules JSONB;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE collections DROP COLUMN IF EXISTS rules;
+-- +goose StatementEnd
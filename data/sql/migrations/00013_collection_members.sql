@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- members are invited by the owner of a collection and can access it once
+-- they accept; editors can add and remove items, viewers can only read
+CREATE TABLE collection_members (
+  collection_id  UUID         NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+  user_id        UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role           TEXT         NOT NULL CHECK (role IN ('viewer', 'editor')),
+  invited_by     UUID         REFERENCES users(id) ON DELETE SET NULL,
+  invited_at     TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  accepted_at    TIMESTAMPTZ,
+  PRIMARY KEY (collection_id, user_id)
+);
+
+CREATE INDEX idx_collection_members_user ON collection_members (user_id);
+
+ALTER TABLE collection_items ADD COLUMN added_by UUID REFERENCES users(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE collection_items DROP COLUMN IF EXISTS added_by;
+DROP INDEX IF EXISTS idx_collection_members_user;
+DROP TABLE IF EXISTS collection_members;
+-- +goose StatementEnd
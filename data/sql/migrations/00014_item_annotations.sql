@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- an annotation is a note on an item, a highlight of its text or both;
+-- highlights are anchored to the quoted text and its character offsets in the
+-- text of the sanitized content
+CREATE TABLE item_annotations (
+  id            UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id       UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  item_id       UUID         NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+  note          TEXT,
+  quote         TEXT,
+  start_offset  INTEGER,
+  end_offset    INTEGER,
+  created_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  CHECK (note IS NOT NULL OR quote IS NOT NULL),
+  CHECK ((start_offset IS NULL) = (end_offset IS NULL)),
+  CHECK (start_offset IS NULL OR (start_offset >= 0 AND start_offset < end_offset))
+);
+
+CREATE INDEX idx_item_annotations_user_item ON item_annotations (user_id, item_id);
+CREATE INDEX idx_item_annotations_user_created ON item_annotations (user_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_item_annotations_user_created;
+DROP INDEX IF EXISTS idx_item_annotations_user_item;
+DROP TABLE IF EXISTS item_annotations;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- tags are defined by each user and applied to items and to the feeds they
+-- subscribe to; feed tags double as folders
+CREATE TABLE tags (
+  id          UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id     UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name        TEXT         NOT NULL,
+  created_at  TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX idx_tags_user_name ON tags (user_id, lower(name));
+
+CREATE TABLE item_tags (
+  tag_id     UUID         NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  item_id    UUID         NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+  tagged_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  PRIMARY KEY (tag_id, item_id)
+);
+
+CREATE INDEX idx_item_tags_item ON item_tags (item_id);
+
+CREATE TABLE feed_tags (
+  tag_id     UUID         NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  feed_id    UUID         NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+  tagged_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  PRIMARY KEY (tag_id, feed_id)
+);
+
+CREATE INDEX idx_feed_tags_feed ON feed_tags (feed_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_feed_tags_feed;
+DROP TABLE IF EXISTS feed_tags;
+DROP INDEX IF EXISTS idx_item_tags_item;
+DROP TABLE IF EXISTS item_tags;
+DROP INDEX IF EXISTS idx_tags_user_name;
+DROP TABLE IF EXISTS tags;
+-- +goose StatementEnd
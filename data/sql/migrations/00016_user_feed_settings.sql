@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- per-subscription settings, each user organizes a shared feed their own way
+ALTER TABLE user_feeds
+  ADD COLUMN folder        TEXT,
+  ADD COLUMN title         TEXT,
+  ADD COLUMN icon          TEXT,
+  -- none leaves new items of the feed out of feed.item webhooks
+  ADD COLUMN notify        TEXT     NOT NULL DEFAULT 'all'
+    CHECK (notify IN ('none', 'all')),
+  ADD COLUMN sort_order    TEXT     NOT NULL DEFAULT 'newest'
+    CHECK (sort_order IN ('newest', 'oldest')),
+  ADD COLUMN muted         BOOLEAN  NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_feeds
+  DROP COLUMN IF EXISTS muted,
+  DROP COLUMN IF EXISTS sort_order,
+  DROP COLUMN IF EXISTS notify,
+  DROP COLUMN IF EXISTS icon,
+  DROP COLUMN IF EXISTS title,
+  DROP COLUMN IF EXISTS folder;
+-- +goose StatementEnd
@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+-- filter rules of a user match items of their subscriptions by keyword or
+-- regex when feeds are synced, and hide them, mark them read or add them to
+-- a collection
+CREATE TABLE filter_rules (
+  id             UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id        UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name           TEXT         NOT NULL,
+  feed_id        UUID         REFERENCES feeds(id) ON DELETE CASCADE,
+  field          TEXT         NOT NULL DEFAULT 'any'
+    CHECK (field IN ('any', 'title', 'content', 'author', 'category', 'link')),
+  match_type     TEXT         NOT NULL CHECK (match_type IN ('keyword', 'regex')),
+  pattern        TEXT         NOT NULL,
+  action         TEXT         NOT NULL CHECK (action IN ('hide', 'mark_read', 'add_to_collection')),
+  collection_id  UUID         REFERENCES collections(id) ON DELETE CASCADE,
+  enabled        BOOLEAN      NOT NULL DEFAULT true,
+  hit_count      BIGINT       NOT NULL DEFAULT 0,
+  last_hit_at    TIMESTAMPTZ,
+  created_at     TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at     TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  CHECK ((action = 'add_to_collection') = (collection_id IS NOT NULL))
+);
+
+CREATE INDEX idx_filter_rules_user ON filter_rules (user_id);
+CREATE INDEX idx_filter_rules_feed ON filter_rules (feed_id);
+
+-- items hidden from a user by their hide rules, left out of listings
+CREATE TABLE hidden_items (
+  user_id    UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  item_id    UUID         NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+  rule_id    UUID         NOT NULL REFERENCES filter_rules(id) ON DELETE CASCADE,
+  hidden_at  TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, item_id, rule_id)
+);
+
+CREATE INDEX idx_hidden_items_rule ON hidden_items (rule_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_hidden_items_rule;
+DROP TABLE IF EXISTS hidden_items;
+DROP INDEX IF EXISTS idx_filter_rules_feed;
+DROP INDEX IF EXISTS idx_filter_rules_user;
+DROP TABLE IF EXISTS filter_rules;
+-- +goose StatementEnd
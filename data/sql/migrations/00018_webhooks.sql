@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+-- outbound webhooks of a user, posted signed events when new items arrive in
+-- their feeds or collections or match their filter rules. Empty feed,
+-- collection and rule lists select all of them.
+CREATE TABLE webhooks (
+  id                UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+  user_id           UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name              TEXT         NOT NULL,
+  url               TEXT         NOT NULL,
+  secret            TEXT         NOT NULL,
+  events            TEXT[]       NOT NULL
+    CHECK (cardinality(events) > 0 AND events <@ ARRAY['feed.item', 'collection.item', 'rule.match']),
+  feed_ids          UUID[]       NOT NULL DEFAULT '{}',
+  collection_ids    UUID[]       NOT NULL DEFAULT '{}',
+  rule_ids          UUID[]       NOT NULL DEFAULT '{}',
+  enabled           BOOLEAN      NOT NULL DEFAULT true,
+  -- consecutive deliveries that failed after all their retries
+  failure_count     INTEGER      NOT NULL DEFAULT 0,
+  disabled_reason   TEXT,
+  last_delivery_at  TIMESTAMPTZ,
+  created_at        TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  updated_at        TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_webhooks_user ON webhooks (user_id);
+
+-- delivery log of webhooks, payloads are kept so retries send the same body
+CREATE TABLE webhook_deliveries (
+  id               UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+  webhook_id       UUID         NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  event            TEXT         NOT NULL,
+  payload          JSONB        NOT NULL,
+  status           TEXT         NOT NULL DEFAULT 'pending'
+    CHECK (status IN ('pending', 'succeeded', 'failed')),
+  attempts         INTEGER      NOT NULL DEFAULT 0,
+  response_status  INTEGER,
+  error            TEXT,
+  duration_ms      INTEGER,
+  created_at       TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  attempted_at     TIMESTAMPTZ
+);
+
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_created ON webhook_deliveries (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_webhook_deliveries_created;
+DROP INDEX IF EXISTS idx_webhook_deliveries_webhook;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP INDEX IF EXISTS idx_webhooks_user;
+DROP TABLE IF EXISTS webhooks;
+-- +goose StatementEnd
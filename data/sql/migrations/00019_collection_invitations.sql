@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- invitations to collections are addressed to emails, whether or not a user
+-- has it yet, so inviting does not reveal which emails have accounts. A user
+-- whose provider verified the email becomes a member once they accept.
+CREATE TABLE collection_invitations (
+  collection_id  UUID         NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+  -- lowercase
+  email          TEXT         NOT NULL,
+  role           TEXT         NOT NULL CHECK (role IN ('viewer', 'editor')),
+  invited_by     UUID         REFERENCES users(id) ON DELETE SET NULL,
+  invited_at     TIMESTAMPTZ  NOT NULL DEFAULT now(),
+  PRIMARY KEY (collection_id, email)
+);
+
+CREATE INDEX idx_collection_invitations_email ON collection_invitations (email);
+
+INSERT INTO collection_invitations (collection_id, email, role, invited_by, invited_at)
+SELECT cm.collection_id, lower(u.email), cm.role, cm.invited_by, cm.invited_at
+FROM collection_members cm
+JOIN users u ON u.id = cm.user_id
+WHERE cm.accepted_at IS NULL
+ON CONFLICT DO NOTHING;
+
+DELETE FROM collection_members WHERE accepted_at IS NULL;
+
+-- set at each sign-in from the email_verified claim of the provider
+ALTER TABLE user_identities ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE user_identities DROP COLUMN IF EXISTS email_verified;
+
+INSERT INTO collection_members (collection_id, user_id, role, invited_by, invited_at)
+SELECT ci.collection_id, u.id, ci.role, ci.invited_by, ci.invited_at
+FROM collection_invitations ci
+JOIN users u ON lower(u.email) = ci.email
+ON CONFLICT DO NOTHING;
+
+DROP INDEX IF EXISTS idx_collection_invitations_email;
+DROP TABLE IF EXISTS collection_invitations;
+-- +goose StatementEnd
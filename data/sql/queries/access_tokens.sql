@@ -0,0 +1,25 @@
+-- name: CreateAccessToken :one
+INSERT INTO access_tokens (user_id, name, token_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, name, token_hash, scopes, expires_at, created_at, last_used_at;
+
+-- name: GetAccessTokenByHash :one
+SELECT id, user_id, name, token_hash, scopes, expires_at, created_at, last_used_at
+FROM access_tokens
+WHERE token_hash = $1;
+
+-- name: ListAccessTokensByUserID :many
+SELECT id, user_id, name, token_hash, scopes, expires_at, created_at, last_used_at
+FROM access_tokens
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: TouchAccessToken :exec
+UPDATE access_tokens
+SET last_used_at = now()
+WHERE id = $1;
+
+-- name: DeleteAccessToken :execrows
+DELETE FROM access_tokens
+WHERE id = $1
+  AND user_id = $2;
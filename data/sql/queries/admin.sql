@@ -0,0 +1,98 @@
+-- name: ListUsersWithStats :many
+SELECT
+  u.id, u.sub, u.name, u.email, u.role, u.created_at, u.last_updated_at, u.disabled_at,
+  (SELECT COUNT(*) FROM user_feeds uf WHERE uf.user_id = u.id) AS feeds,
+  (SELECT COUNT(*) FROM user_likes ul WHERE ul.user_id = u.id) AS likes,
+  (SELECT COUNT(*) FROM collections c WHERE c.user_id = u.id) AS collections,
+  (SELECT COUNT(*) FROM sessions s
+    WHERE s.user_id = u.id
+      AND s.revoked_at IS NULL
+      AND s.expires_at > now()) AS sessions
+FROM users u
+WHERE @query::text = ''
+  OR u.name ILIKE '%' || @query::text || '%'
+  OR u.email ILIKE '%' || @query::text || '%'
+ORDER BY u.created_at DESC
+LIMIT  sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountUsersByQuery :one
+SELECT COUNT(*) AS count
+FROM users u
+WHERE @query::text = ''
+  OR u.name ILIKE '%' || @query::text || '%'
+  OR u.email ILIKE '%' || @query::text || '%';
+
+-- name: GetUserWithStats :one
+SELECT
+  u.id, u.sub, u.name, u.email, u.role, u.created_at, u.last_updated_at, u.disabled_at,
+  (SELECT COUNT(*) FROM user_feeds uf WHERE uf.user_id = u.id) AS feeds,
+  (SELECT COUNT(*) FROM user_likes ul WHERE ul.user_id = u.id) AS likes,
+  (SELECT COUNT(*) FROM collections c WHERE c.user_id = u.id) AS collections,
+  (SELECT COUNT(*) FROM sessions s
+    WHERE s.user_id = u.id
+      AND s.revoked_at IS NULL
+      AND s.expires_at > now()) AS sessions
+FROM users u
+WHERE u.id = $1;
+
+-- name: ListFeedsWithHealth :many
+SELECT
+  f.id, f.title, f.feed_link, f.created_at, f.last_updated_at,
+  (SELECT COUNT(*) FROM user_feeds uf WHERE uf.feed_id = f.id) AS subscribers,
+  (SELECT COUNT(*) FROM items i WHERE i.feed_id = f.id) AS items,
+  h.last_success_at, h.last_failure_at, h.last_error,
+  COALESCE(h.failure_count, 0)::integer AS failure_count
+FROM feeds f
+LEFT JOIN feed_health h ON h.feed_id = f.id
+WHERE NOT @failing_only::boolean
+  OR h.failure_count > 0
+ORDER BY f.created_at DESC
+LIMIT  sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: CountFeedsWithHealth :one
+SELECT COUNT(*) AS count
+FROM feeds f
+LEFT JOIN feed_health h ON h.feed_id = f.id
+WHERE NOT @failing_only::boolean
+  OR h.failure_count > 0;
+
+-- name: MoveFeedItems :execrows
+UPDATE items
+SET feed_id = @target_id
+WHERE feed_id = @source_id;
+
+-- name: MoveFeedSubscriptions :exec
+INSERT INTO user_feeds (user_id, feed_id, subscribed_at, folder, title, icon, notify, sort_order, muted)
+SELECT uf.user_id, @target_id::uuid, uf.subscribed_at,
+       uf.folder, uf.title, uf.icon, uf.notify, uf.sort_order, uf.muted
+FROM user_feeds uf
+WHERE uf.feed_id = @source_id
+ON CONFLICT (user_id, feed_id) DO NOTHING;
+
+-- name: MoveFeedTags :exec
+INSERT INTO feed_tags (tag_id, feed_id, tagged_at)
+SELECT ft.tag_id, @target_id::uuid, ft.tagged_at
+FROM feed_tags ft
+WHERE ft.feed_id = @source_id
+ON CONFLICT (tag_id, feed_id) DO NOTHING;
+
+-- name: MoveFeedFilterRules :exec
+UPDATE filter_rules
+SET feed_id = @target_id
+WHERE feed_id = @source_id;
+
+-- name: PurgeItems :execrows
+DELETE FROM items i
+WHERE COALESCE(i.published_parsed, i.created_at) < @before
+  AND (sqlc.narg('feed_id')::uuid IS NULL OR i.feed_id = sqlc.narg('feed_id'))
+  AND (
+    NOT @keep_saved::boolean
+    OR (
+      NOT EXISTS (SELECT 1 FROM user_likes ul WHERE ul.item_id = i.id)
+      AND NOT EXISTS (SELECT 1 FROM collection_items ci WHERE ci.item_id = i.id)
+      AND NOT EXISTS (SELECT 1 FROM item_annotations ia WHERE ia.item_id = i.id)
+      AND NOT EXISTS (SELECT 1 FROM item_tags it WHERE it.item_id = i.id)
+    )
+  );
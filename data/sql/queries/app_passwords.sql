@@ -0,0 +1,37 @@
+-- name: CreateAppPassword :one
+INSERT INTO app_passwords (user_id, name, password_hash)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, name, password_hash, created_at, last_used_at;
+
+-- name: GetAppPasswordByHash :one
+SELECT id, user_id, name, password_hash, created_at, last_used_at
+FROM app_passwords
+WHERE password_hash = $1;
+
+-- name: ListAppPasswordsByUserID :many
+SELECT id, user_id, name, password_hash, created_at, last_used_at
+FROM app_passwords
+WHERE user_id = $1
+ORDER BY created_at DESC;
+
+-- name: TouchAppPassword :exec
+UPDATE app_passwords
+SET last_used_at = now()
+WHERE id = $1;
+
+-- name: DeleteAppPassword :execrows
+DELETE FROM app_passwords
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: AppPasswordIsActive :one
+-- Reports whether an app password of the user still exists and the user is
+-- not disabled, reader tokens issued with it stop working otherwise.
+SELECT EXISTS (
+  SELECT 1
+  FROM app_passwords ap
+  JOIN users u ON u.id = ap.user_id
+  WHERE ap.id      = $1
+    AND ap.user_id = $2
+    AND u.disabled_at IS NULL
+) AS active;
@@ -37,3 +37,17 @@ RETURNING
 -- name: DeleteCollectionEmbeddingByID :exec
 DELETE FROM collection_embeddings
 WHERE collection_id = $1;
+
+-- name: UpsertCollectionEmbedding :exec
+INSERT INTO collection_embeddings (
+  collection_id,
+  embedding
+)
+VALUES (
+  $1,
+  $2
+)
+ON CONFLICT (collection_id) DO UPDATE
+SET
+  embedding  = EXCLUDED.embedding,
+  updated_at = now();
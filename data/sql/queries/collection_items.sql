@@ -1,29 +1,168 @@
 -- name: AddItemToCollection :one
-INSERT INTO collection_items (collection_id, item_id)
-VALUES ($1, $2)
-RETURNING collection_id, item_id, added_at;
+-- user_id is the owner of the collection, added_by the user adding the item.
+INSERT INTO collection_items (collection_id, item_id, added_by)
+SELECT c.id, sqlc.arg('item_id')::uuid, sqlc.arg('added_by')::uuid
+FROM collections c
+WHERE c.id      = sqlc.arg('collection_id')
+  AND c.user_id = sqlc.arg('user_id')
+RETURNING collection_id, item_id, added_at, position, added_by;
 
 -- name: GetCollectionItem :one
-SELECT collection_id, item_id, added_at
+SELECT collection_id, item_id, added_at, position, added_by
 FROM collection_items
 WHERE collection_id = $1
   AND item_id       = $2;
 
 -- name: ListItemsInCollection :many
-SELECT ci.collection_id, ci.item_id, ci.added_at, i.*
+SELECT ci.collection_id, ci.item_id, ci.added_at, i.*, ci.added_by, u.name AS added_by_name
 FROM collection_items ci
+JOIN collections c ON c.id = ci.collection_id
 JOIN items i ON i.id = ci.item_id
+LEFT JOIN users u ON u.id = ci.added_by
 WHERE ci.collection_id = $1
-ORDER BY ci.added_at DESC
-LIMIT  $2
-OFFSET $3;
+  AND c.user_id        = $2
+ORDER BY ci.position ASC NULLS FIRST, ci.added_at DESC
+LIMIT  $3
+OFFSET $4;
 
 -- name: CountItemsInCollection :one
 SELECT COUNT(*) AS count
-FROM collection_items
-WHERE collection_id = $1;
+FROM collection_items ci
+JOIN collections c ON c.id = ci.collection_id
+WHERE ci.collection_id = $1
+  AND c.user_id        = $2;
 
--- name: RemoveItemFromCollection :exec
-DELETE FROM collection_items
-WHERE collection_id = $1
-  AND item_id       = $2;
+-- name: RemoveItemFromCollection :execrows
+DELETE FROM collection_items ci
+USING collections c
+WHERE c.id              = ci.collection_id
+  AND ci.collection_id  = $1
+  AND ci.item_id        = $2
+  AND c.user_id         = $3;
+
+-- name: SetCollectionItemPositions :execrows
+-- Positions follow the order of item_ids. Nothing is updated unless every
+-- item is in the collection.
+UPDATE collection_items ci
+SET position = o.position::integer
+FROM unnest(sqlc.arg('item_ids')::uuid[]) WITH ORDINALITY AS o(item_id, position)
+WHERE ci.collection_id = sqlc.arg('collection_id')
+  AND ci.item_id       = o.item_id
+  AND (
+    SELECT COUNT(*) FROM collection_items
+    WHERE collection_id = sqlc.arg('collection_id')
+      AND item_id = ANY(sqlc.arg('item_ids')::uuid[])
+  ) = cardinality(sqlc.arg('item_ids')::uuid[]);
+
+-- name: CountItemsMatchingRules :one
+-- Rules of smart collections are matched against items of the feeds the
+-- user is subscribed to. NULL filters match every item.
+SELECT COUNT(*) AS count
+FROM items i
+LEFT JOIN user_likes ul
+  ON ul.item_id = i.id
+  AND ul.user_id = sqlc.arg('user_id')
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = sqlc.arg('user_id')
+WHERE
+  EXISTS (
+    SELECT 1 FROM user_feeds uf
+    WHERE uf.user_id = sqlc.arg('user_id')
+      AND uf.feed_id = i.feed_id
+  )
+  AND (sqlc.narg('feed_ids')::uuid[] IS NULL OR i.feed_id = ANY(sqlc.narg('feed_ids')::uuid[]))
+  AND (sqlc.narg('categories')::text[] IS NULL OR i.categories && sqlc.narg('categories')::text[])
+  AND (
+    sqlc.narg('keywords')::text[] IS NULL
+    OR EXISTS (
+      SELECT 1 FROM unnest(sqlc.narg('keywords')::text[]) AS k(keyword)
+      WHERE i.title       ILIKE '%' || k.keyword || '%'
+         OR i.description ILIKE '%' || k.keyword || '%'
+         OR i.content     ILIKE '%' || k.keyword || '%'
+    )
+  )
+  AND (
+    sqlc.narg('author')::text IS NULL
+    OR EXISTS (
+      SELECT 1
+      FROM jsonb_array_elements(CASE WHEN jsonb_typeof(i.authors) = 'array' THEN i.authors ELSE '[]'::jsonb END) AS a(author)
+      WHERE a.author->>'name' ILIKE '%' || sqlc.narg('author')::text || '%'
+    )
+  )
+  AND (sqlc.narg('published_after')::timestamptz IS NULL OR COALESCE(i.published_parsed, i.created_at) >= sqlc.narg('published_after'))
+  AND (sqlc.narg('published_before')::timestamptz IS NULL OR COALESCE(i.published_parsed, i.created_at) < sqlc.narg('published_before'))
+  AND (sqlc.narg('liked')::boolean IS NULL OR (ul.user_id IS NOT NULL) = sqlc.narg('liked'))
+  AND (sqlc.narg('unread')::boolean IS NULL OR (ur.user_id IS NULL) = sqlc.narg('unread'))
+  AND (
+    sqlc.narg('min_similarity')::float8 IS NULL
+    OR EXISTS (
+      SELECT 1
+      FROM item_embeddings ie
+      JOIN collection_embeddings ce
+        ON ce.collection_id = sqlc.arg('collection_id')
+      WHERE ie.item_id = i.id
+        AND 1 - (ie.embedding <=> ce.embedding) >= sqlc.narg('min_similarity')
+    )
+  );
+
+-- name: ListItemsMatchingRules :many
+-- Items are returned in the shape of ListItemsInCollection, with the publish
+-- date as the date they were added.
+SELECT
+  sqlc.arg('collection_id')::uuid                          AS collection_id,
+  i.id                                                     AS item_id,
+  COALESCE(i.published_parsed, i.created_at)::timestamptz  AS added_at,
+  i.id, i.feed_id, i.title, i.description, i.content, i.link, i.links, i.updated_parsed, i.published_parsed, i.authors, i.guid, i.image, i.categories, i.enclosures, i.created_at, i.updated_at, i.seq,
+  NULL::uuid                                               AS added_by,
+  NULL::text                                               AS added_by_name
+FROM items i
+LEFT JOIN user_likes ul
+  ON ul.item_id = i.id
+  AND ul.user_id = sqlc.arg('user_id')
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = sqlc.arg('user_id')
+WHERE
+  EXISTS (
+    SELECT 1 FROM user_feeds uf
+    WHERE uf.user_id = sqlc.arg('user_id')
+      AND uf.feed_id = i.feed_id
+  )
+  AND (sqlc.narg('feed_ids')::uuid[] IS NULL OR i.feed_id = ANY(sqlc.narg('feed_ids')::uuid[]))
+  AND (sqlc.narg('categories')::text[] IS NULL OR i.categories && sqlc.narg('categories')::text[])
+  AND (
+    sqlc.narg('keywords')::text[] IS NULL
+    OR EXISTS (
+      SELECT 1 FROM unnest(sqlc.narg('keywords')::text[]) AS k(keyword)
+      WHERE i.title       ILIKE '%' || k.keyword || '%'
+         OR i.description ILIKE '%' || k.keyword || '%'
+         OR i.content     ILIKE '%' || k.keyword || '%'
+    )
+  )
+  AND (
+    sqlc.narg('author')::text IS NULL
+    OR EXISTS (
+      SELECT 1
+      FROM jsonb_array_elements(CASE WHEN jsonb_typeof(i.authors) = 'array' THEN i.authors ELSE '[]'::jsonb END) AS a(author)
+      WHERE a.author->>'name' ILIKE '%' || sqlc.narg('author')::text || '%'
+    )
+  )
+  AND (sqlc.narg('published_after')::timestamptz IS NULL OR COALESCE(i.published_parsed, i.created_at) >= sqlc.narg('published_after'))
+  AND (sqlc.narg('published_before')::timestamptz IS NULL OR COALESCE(i.published_parsed, i.created_at) < sqlc.narg('published_before'))
+  AND (sqlc.narg('liked')::boolean IS NULL OR (ul.user_id IS NOT NULL) = sqlc.narg('liked'))
+  AND (sqlc.narg('unread')::boolean IS NULL OR (ur.user_id IS NULL) = sqlc.narg('unread'))
+  AND (
+    sqlc.narg('min_similarity')::float8 IS NULL
+    OR EXISTS (
+      SELECT 1
+      FROM item_embeddings ie
+      JOIN collection_embeddings ce
+        ON ce.collection_id = sqlc.arg('collection_id')
+      WHERE ie.item_id = i.id
+        AND 1 - (ie.embedding <=> ce.embedding) >= sqlc.narg('min_similarity')
+    )
+  )
+ORDER BY COALESCE(i.published_parsed, i.created_at) DESC, i.seq DESC
+LIMIT  sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
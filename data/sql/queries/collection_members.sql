@@ -0,0 +1,77 @@
+-- name: CreateCollectionInvitation :one
+-- Inviting an email again updates the role of its invitation.
+INSERT INTO collection_invitations (collection_id, email, role, invited_by)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (collection_id, email) DO UPDATE
+SET role = EXCLUDED.role
+RETURNING collection_id, email, role, invited_by, invited_at;
+
+-- name: ListCollectionInvitationsByCollection :many
+SELECT collection_id, email, role, invited_by, invited_at
+FROM collection_invitations
+WHERE collection_id = $1
+ORDER BY invited_at ASC;
+
+-- name: DeleteCollectionInvitation :execrows
+DELETE FROM collection_invitations
+WHERE collection_id = $1
+  AND email         = $2;
+
+-- name: ListCollectionMembers :many
+SELECT sqlc.embed(cm), u.name, u.email
+FROM collection_members cm
+JOIN users u ON u.id = cm.user_id
+WHERE cm.collection_id = $1
+ORDER BY cm.invited_at ASC;
+
+-- name: UpdateCollectionMemberRole :one
+UPDATE collection_members
+SET role = $3
+WHERE collection_id = $1
+  AND user_id       = $2
+RETURNING collection_id, user_id, role, invited_by, invited_at, accepted_at;
+
+-- name: DeleteCollectionMember :execrows
+DELETE FROM collection_members
+WHERE collection_id = $1
+  AND user_id       = $2;
+
+-- name: AcceptCollectionInvitation :execrows
+-- Makes the user a member of the collection with the role of the invitation
+-- to one of their verified emails, the role of a member is updated.
+WITH invitation AS (
+  DELETE FROM collection_invitations ci
+  USING user_identities ui
+  WHERE ci.collection_id = $1
+    AND ui.user_id       = $2
+    AND ui.email_verified
+    AND ci.email         = lower(ui.email)
+  RETURNING ci.collection_id, ci.role, ci.invited_by, ci.invited_at
+)
+INSERT INTO collection_members (collection_id, user_id, role, invited_by, invited_at, accepted_at)
+SELECT collection_id, $2, role, invited_by, invited_at, now()
+FROM invitation
+ON CONFLICT (collection_id, user_id) DO UPDATE
+SET role = EXCLUDED.role;
+
+-- name: DeclineCollectionInvitation :execrows
+DELETE FROM collection_invitations ci
+USING user_identities ui
+WHERE ci.collection_id = $1
+  AND ui.user_id       = $2
+  AND ui.email_verified
+  AND ci.email         = lower(ui.email);
+
+-- name: ListCollectionInvitations :many
+-- Lists the invitations to the verified emails of the user.
+SELECT sqlc.embed(ci), c.name AS collection_name, inviter.name AS invited_by_name
+FROM collection_invitations ci
+JOIN collections c ON c.id = ci.collection_id
+LEFT JOIN users inviter ON inviter.id = ci.invited_by
+WHERE EXISTS (
+  SELECT 1 FROM user_identities ui
+  WHERE ui.user_id = $1
+    AND ui.email_verified
+    AND ci.email = lower(ui.email)
+)
+ORDER BY ci.invited_at DESC;
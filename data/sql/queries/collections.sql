@@ -1,63 +1,159 @@
 -- name: CreateCollection :one
 INSERT INTO collections (user_id, name)
 VALUES ($1, $2)
-RETURNING id, user_id, name, created_at, last_updated;
+RETURNING id, user_id, name, created_at, last_updated, description, icon, color, pinned, position, public, slug, rules;
 
 -- name: CountCollectionsByUserID :one
+-- Counts the collections of the user and those shared with them.
 SELECT COUNT(*) AS count
-FROM collections
-WHERE user_id = $1;
+FROM collections c
+LEFT JOIN collection_members cm
+  ON cm.collection_id = c.id
+  AND cm.user_id = @user_id
+  AND cm.accepted_at IS NOT NULL
+WHERE c.user_id = @user_id
+   OR cm.user_id IS NOT NULL;
 
 -- name: GetCollectionByID :one
-SELECT id, user_id, name, created_at, last_updated
+SELECT id, user_id, name, created_at, last_updated, description, icon, color, pinned, position, public, slug, rules
 FROM collections
-WHERE id = $1;
+WHERE id      = $1
+  AND user_id = $2;
 
--- name: ListCollectionsByUser :many
-SELECT id, user_id, name, created_at, last_updated
+-- name: GetCollectionForUser :one
+-- Returns a collection the user owns or is a member of, with the role of the
+-- user in it.
+SELECT sqlc.embed(c),
+  (CASE WHEN c.user_id = @user_id THEN 'owner' ELSE cm.role END)::text AS role
+FROM collections c
+LEFT JOIN collection_members cm
+  ON cm.collection_id = c.id
+  AND cm.user_id = @user_id
+  AND cm.accepted_at IS NOT NULL
+WHERE c.id = @id
+  AND (c.user_id = @user_id OR cm.user_id IS NOT NULL);
+
+-- name: GetCollectionByName :one
+SELECT id, user_id, name, created_at, last_updated, description, icon, color, pinned, position, public, slug, rules
 FROM collections
 WHERE user_id = $1
-ORDER BY created_at DESC
-LIMIT  $2
-OFFSET $3;
+  AND name    = $2;
+
+-- name: ListCollectionsByUser :many
+-- Lists the collections of the user followed by those shared with them.
+SELECT sqlc.embed(c),
+  (CASE WHEN c.user_id = @user_id THEN 'owner' ELSE cm.role END)::text AS role
+FROM collections c
+LEFT JOIN collection_members cm
+  ON cm.collection_id = c.id
+  AND cm.user_id = @user_id
+  AND cm.accepted_at IS NOT NULL
+WHERE c.user_id = @user_id
+   OR cm.user_id IS NOT NULL
+ORDER BY (c.user_id = @user_id) DESC, c.pinned DESC, c.position ASC NULLS FIRST, c.created_at DESC
+LIMIT  sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
 
 -- name: UpdateCollectionByID :one
+-- NULL leaves a field unchanged, an empty description, icon or color clears it.
+-- slug is only set when the collection has none yet.
 UPDATE collections
-SET name         = $2,
+SET name         = COALESCE(sqlc.narg('name'), name),
+    description  = CASE WHEN sqlc.narg('description')::text IS NULL THEN description
+                        ELSE NULLIF(sqlc.narg('description')::text, '') END,
+    icon         = CASE WHEN sqlc.narg('icon')::text IS NULL THEN icon
+                        ELSE NULLIF(sqlc.narg('icon')::text, '') END,
+    color        = CASE WHEN sqlc.narg('color')::text IS NULL THEN color
+                        ELSE NULLIF(sqlc.narg('color')::text, '') END,
+    pinned       = COALESCE(sqlc.narg('pinned'), pinned),
+    public       = COALESCE(sqlc.narg('public'), public),
+    slug         = COALESCE(slug, sqlc.narg('slug')),
     last_updated = now()
-WHERE id = $1
-RETURNING id, user_id, name, created_at, last_updated;
+WHERE id      = sqlc.arg('id')
+  AND user_id = sqlc.arg('user_id')
+RETURNING id, user_id, name, created_at, last_updated, description, icon, color, pinned, position, public, slug, rules;
 
--- name: DeleteCollectionByID :exec
-DELETE FROM collections
+-- name: SetCollectionPositions :execrows
+-- Positions follow the order of ids. Nothing is updated unless every id is a
+-- collection of the user.
+UPDATE collections c
+SET position = o.position::integer
+FROM unnest(sqlc.arg('ids')::uuid[]) WITH ORDINALITY AS o(id, position)
+WHERE c.id      = o.id
+  AND c.user_id = sqlc.arg('user_id')
+  AND (
+    SELECT COUNT(*) FROM collections
+    WHERE user_id = sqlc.arg('user_id')
+      AND id = ANY(sqlc.arg('ids')::uuid[])
+  ) = cardinality(sqlc.arg('ids')::uuid[]);
+
+-- name: RotateCollectionSlug :one
+UPDATE collections
+SET slug = $3
+WHERE id      = $1
+  AND user_id = $2
+RETURNING id, user_id, name, created_at, last_updated, description, icon, color, pinned, position, public, slug, rules;
+
+-- name: GetPublicCollectionBySlug :one
+SELECT id, user_id, name, created_at, last_updated, description, icon, color, pinned, position, public, slug, rules
+FROM collections
+WHERE slug   = $1
+  AND public = TRUE;
+
+-- name: SetCollectionRules :one
+-- NULL rules turn a smart collection back into a manual one.
+UPDATE collections
+SET rules        = sqlc.narg('rules'),
+    last_updated = now()
+WHERE id      = sqlc.arg('id')
+  AND user_id = sqlc.arg('user_id')
+RETURNING id, user_id, name, created_at, last_updated, description, icon, color, pinned, position, public, slug, rules;
+
+-- name: GetCollectionRulesByID :one
+SELECT rules
+FROM collections
 WHERE id = $1;
 
+-- name: GetCollectionNameByID :one
+SELECT name
+FROM collections
+WHERE id = $1;
+
+-- name: DeleteCollectionByID :execrows
+DELETE FROM collections
+WHERE id      = $1
+  AND user_id = $2;
+
 -- name: CountCollectionsByItemID :one
 SELECT
   COUNT(*) AS count
 FROM collection_items ci
 JOIN collections c
   ON ci.collection_id = c.id
+LEFT JOIN collection_members cm
+  ON cm.collection_id = c.id
+  AND cm.user_id = @user_id
+  AND cm.accepted_at IS NOT NULL
 WHERE
-  ci.item_id = $1
-  AND c.user_id = $2;
+  ci.item_id = @item_id
+  AND (c.user_id = @user_id OR cm.user_id IS NOT NULL);
 
 
 -- name: ListCollectionsByItemID :many
 SELECT
-  c.id,
-  c.user_id,
-  c.name,
-  c.created_at,
-  c.last_updated
+  sqlc.embed(c),
+  (CASE WHEN c.user_id = @user_id THEN 'owner' ELSE cm.role END)::text AS role
 FROM collections c
 JOIN collection_items ci
   ON ci.collection_id = c.id
+LEFT JOIN collection_members cm
+  ON cm.collection_id = c.id
+  AND cm.user_id = @user_id
+  AND cm.accepted_at IS NOT NULL
 WHERE
-  ci.item_id = $1
-  AND c.user_id = $2
+  ci.item_id = @item_id
+  AND (c.user_id = @user_id OR cm.user_id IS NOT NULL)
 ORDER BY
   ci.added_at DESC
-LIMIT  $3
-OFFSET $4;
-
+LIMIT  sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
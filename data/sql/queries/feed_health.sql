@@ -0,0 +1,20 @@
+-- name: GetFeedHealth :one
+SELECT feed_id, last_success_at, last_failure_at, last_error, failure_count
+FROM feed_health
+WHERE feed_id = $1;
+
+-- name: RecordFeedSyncSuccess :exec
+INSERT INTO feed_health (feed_id, last_success_at, failure_count)
+VALUES ($1, now(), 0)
+ON CONFLICT (feed_id) DO UPDATE
+SET last_success_at = now(),
+    last_error      = NULL,
+    failure_count   = 0;
+
+-- name: RecordFeedSyncFailure :exec
+INSERT INTO feed_health (feed_id, last_failure_at, last_error, failure_count)
+VALUES ($1, now(), $2, 1)
+ON CONFLICT (feed_id) DO UPDATE
+SET last_failure_at = now(),
+    last_error      = EXCLUDED.last_error,
+    failure_count   = feed_health.failure_count + 1;
@@ -10,7 +10,7 @@ VALUES
 RETURNING
   id, title, description, link, feed_link, links, updated_parsed, published_parsed,
   authors, language, image, copyright, generator,
-  categories, feed_type, feed_version, created_at, last_updated_at;
+  categories, feed_type, feed_version, created_at, last_updated_at, seq;
 
 -- name: CountFeeds :one
 SELECT COUNT(*) AS count
@@ -20,7 +20,7 @@ FROM feeds;
 SELECT
   id, title, description, link, feed_link, links, updated_parsed, published_parsed,
   authors, language, image, copyright, generator,
-  categories, feed_type, feed_version, created_at, last_updated_at
+  categories, feed_type, feed_version, created_at, last_updated_at, seq
 FROM feeds
 WHERE id = $1;
 
@@ -28,7 +28,7 @@ WHERE id = $1;
 SELECT
   id, title, description, link, feed_link, links, updated_parsed, published_parsed,
   authors, language, image, copyright, generator,
-  categories, feed_type, feed_version, created_at, last_updated_at
+  categories, feed_type, feed_version, created_at, last_updated_at, seq
 FROM feeds
 WHERE feed_link = $1;
 
@@ -36,7 +36,7 @@ WHERE feed_link = $1;
 SELECT
   id, title, description, link, feed_link, links, updated_parsed, published_parsed,
   authors, language, image, copyright, generator,
-  categories, feed_type, feed_version, created_at, last_updated_at
+  categories, feed_type, feed_version, created_at, last_updated_at, seq
 FROM feeds
 ORDER BY created_at DESC
 LIMIT  $1
@@ -65,7 +65,7 @@ WHERE id = $1
 RETURNING
   id, title, description, link, feed_link, links, updated_parsed, published_parsed,
   authors, language, image, copyright, generator,
-  categories, feed_type, feed_version, created_at, last_updated_at;
+  categories, feed_type, feed_version, created_at, last_updated_at, seq;
 
 -- name: DeleteFeedByID :exec
 DELETE FROM feeds WHERE id = $1;
@@ -119,3 +119,16 @@ JOIN user_feeds uf ON uf.feed_id = f.id
 WHERE uf.user_id = $1
   AND f.id      = $2;
 
+
+-- name: ListSubscribedFeedRefs :many
+SELECT
+  f.id,
+  f.seq,
+  COALESCE(uf.title, f.title) AS title,
+  f.link,
+  f.feed_link,
+  f.last_updated_at
+FROM feeds f
+JOIN user_feeds uf ON uf.feed_id = f.id
+WHERE uf.user_id = $1
+ORDER BY f.seq;
@@ -0,0 +1,21 @@
+-- name: UpsertFeverCredential :one
+INSERT INTO fever_credentials (user_id, api_key)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET api_key    = EXCLUDED.api_key,
+    created_at = now()
+RETURNING user_id, api_key, created_at;
+
+-- name: GetFeverCredentialByAPIKey :one
+SELECT user_id, api_key, created_at
+FROM fever_credentials
+WHERE api_key = $1;
+
+-- name: GetFeverCredentialByUserID :one
+SELECT user_id, api_key, created_at
+FROM fever_credentials
+WHERE user_id = $1;
+
+-- name: DeleteFeverCredential :execrows
+DELETE FROM fever_credentials
+WHERE user_id = $1;
@@ -0,0 +1,113 @@
+-- name: CreateFilterRule :one
+INSERT INTO filter_rules (user_id, name, feed_id, field, match_type, pattern, action, collection_id, enabled)
+VALUES (
+  sqlc.arg('user_id'), sqlc.arg('name'), sqlc.narg('feed_id'), sqlc.arg('field'), sqlc.arg('match_type'),
+  sqlc.arg('pattern'), sqlc.arg('action'), sqlc.narg('collection_id'), sqlc.arg('enabled')
+)
+RETURNING id, user_id, name, feed_id, field, match_type, pattern, action, collection_id, enabled,
+          hit_count, last_hit_at, created_at, updated_at;
+
+-- name: GetFilterRule :one
+SELECT id, user_id, name, feed_id, field, match_type, pattern, action, collection_id, enabled,
+       hit_count, last_hit_at, created_at, updated_at
+FROM filter_rules
+WHERE id      = $1
+  AND user_id = $2;
+
+-- name: ListFilterRules :many
+SELECT id, user_id, name, feed_id, field, match_type, pattern, action, collection_id, enabled,
+       hit_count, last_hit_at, created_at, updated_at
+FROM filter_rules
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: UpdateFilterRule :one
+UPDATE filter_rules
+SET name          = sqlc.arg('name'),
+    feed_id       = sqlc.narg('feed_id'),
+    field         = sqlc.arg('field'),
+    match_type    = sqlc.arg('match_type'),
+    pattern       = sqlc.arg('pattern'),
+    action        = sqlc.arg('action'),
+    collection_id = sqlc.narg('collection_id'),
+    enabled       = sqlc.arg('enabled'),
+    updated_at    = now()
+WHERE id      = sqlc.arg('id')
+  AND user_id = sqlc.arg('user_id')
+RETURNING id, user_id, name, feed_id, field, match_type, pattern, action, collection_id, enabled,
+          hit_count, last_hit_at, created_at, updated_at;
+
+-- name: DeleteFilterRule :execrows
+DELETE FROM filter_rules
+WHERE id      = $1
+  AND user_id = $2;
+
+-- name: ListFilterRulesForFeed :many
+-- Lists the enabled rules of the subscribers of a feed that apply to it.
+SELECT r.id, r.user_id, r.name, r.feed_id, r.field, r.match_type, r.pattern, r.action, r.collection_id, r.enabled,
+       r.hit_count, r.last_hit_at, r.created_at, r.updated_at
+FROM filter_rules r
+JOIN user_feeds uf
+  ON uf.user_id = r.user_id
+  AND uf.feed_id = @feed_id
+WHERE r.enabled
+  AND (r.feed_id IS NULL OR r.feed_id = @feed_id)
+ORDER BY r.created_at;
+
+-- name: RecordFilterRuleHit :exec
+UPDATE filter_rules
+SET hit_count   = hit_count + 1,
+    last_hit_at = now()
+WHERE id = $1;
+
+-- name: HideItems :execrows
+INSERT INTO hidden_items (user_id, item_id, rule_id)
+SELECT @user_id::uuid, i.id, @rule_id::uuid
+FROM items i
+WHERE i.id = ANY(@item_ids::uuid[])
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteFilterRuleHides :exec
+DELETE FROM hidden_items
+WHERE rule_id = $1;
+
+-- name: AddItemToCollectionByRule :execrows
+-- Adds an item to a manual collection the user owns or can edit.
+INSERT INTO collection_items (collection_id, item_id, added_by)
+SELECT c.id, @item_id::uuid, @user_id::uuid
+FROM collections c
+WHERE c.id = @collection_id
+  AND c.rules IS NULL
+  AND (
+    c.user_id = @user_id
+    OR EXISTS (
+      SELECT 1 FROM collection_members m
+      WHERE m.collection_id = c.id
+        AND m.user_id       = @user_id
+        AND m.role          = 'editor'
+        AND m.accepted_at IS NOT NULL
+    )
+  )
+ON CONFLICT DO NOTHING;
+
+-- name: ListRecentItemsForFilters :many
+-- Lists the most recent items of the subscribed feeds of the user, or of one
+-- of them, to preview and apply rules on.
+SELECT
+  i.id,
+  i.feed_id,
+  i.title,
+  i.description,
+  i.content,
+  i.link,
+  i.authors,
+  i.categories,
+  i.published_parsed,
+  i.created_at
+FROM items i
+JOIN user_feeds uf
+  ON uf.feed_id = i.feed_id
+  AND uf.user_id = @user_id
+WHERE sqlc.narg('feed_id')::uuid IS NULL OR i.feed_id = sqlc.narg('feed_id')
+ORDER BY i.created_at DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,61 @@
+-- name: CreateItemAnnotation :one
+INSERT INTO item_annotations (user_id, item_id, note, quote, start_offset, end_offset)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, item_id, note, quote, start_offset, end_offset, created_at, updated_at;
+
+-- name: GetItemAnnotation :one
+SELECT id, user_id, item_id, note, quote, start_offset, end_offset, created_at, updated_at
+FROM item_annotations
+WHERE id      = $1
+  AND item_id = $2
+  AND user_id = $3;
+
+-- name: UpdateItemAnnotationNote :one
+UPDATE item_annotations
+SET note       = sqlc.narg('note'),
+    updated_at = now()
+WHERE id      = sqlc.arg('id')
+  AND item_id = sqlc.arg('item_id')
+  AND user_id = sqlc.arg('user_id')
+RETURNING id, user_id, item_id, note, quote, start_offset, end_offset, created_at, updated_at;
+
+-- name: DeleteItemAnnotation :execrows
+DELETE FROM item_annotations
+WHERE id      = $1
+  AND item_id = $2
+  AND user_id = $3;
+
+-- name: ListItemAnnotationsByItem :many
+-- Lists the annotations of the user on an item in reading order, notes
+-- without a highlight last.
+SELECT id, user_id, item_id, note, quote, start_offset, end_offset, created_at, updated_at
+FROM item_annotations
+WHERE user_id = $1
+  AND item_id = $2
+ORDER BY start_offset ASC NULLS LAST, created_at ASC;
+
+-- name: CountItemAnnotationsByUser :one
+SELECT COUNT(*) AS count
+FROM item_annotations
+WHERE user_id = $1;
+
+-- name: ListItemAnnotationsByUser :many
+SELECT sqlc.embed(a), i.title AS item_title, i.link AS item_link, i.feed_id
+FROM item_annotations a
+JOIN items i ON i.id = a.item_id
+WHERE a.user_id = $1
+ORDER BY a.created_at DESC
+LIMIT  $2
+OFFSET $3;
+
+-- name: ListItemAnnotationsForExport :many
+-- Lists the annotations of the user grouped by item, newest items first, and
+-- in reading order within an item.
+SELECT sqlc.embed(a), i.title AS item_title, i.link AS item_link, f.title AS feed_title
+FROM item_annotations a
+JOIN items i ON i.id = a.item_id
+JOIN feeds f ON f.id = i.feed_id
+WHERE a.user_id = sqlc.arg('user_id')
+  AND (sqlc.narg('item_id')::uuid IS NULL OR a.item_id = sqlc.narg('item_id'))
+ORDER BY COALESCE(i.published_parsed, i.created_at) DESC, i.id,
+  a.start_offset ASC NULLS LAST, a.created_at ASC;
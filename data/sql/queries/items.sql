@@ -7,14 +7,14 @@ VALUES
    $9, $10, $11, $12, $13)
 RETURNING
   id, feed_id, title, description, content, link, links, updated_parsed, published_parsed,
-  authors, guid, image, categories, enclosures, created_at, updated_at;
+  authors, guid, image, categories, enclosures, created_at, updated_at, seq;
 
 
 -- name: GetLastItem :one
 SELECT
   id, feed_id, title, description, content, link, links,
   updated_parsed, published_parsed, authors, guid, image,
-  categories, enclosures, created_at, updated_at
+  categories, enclosures, created_at, updated_at, seq
 FROM items
 WHERE feed_id = $1
 ORDER BY published_parsed DESC
@@ -23,7 +23,7 @@ LIMIT 1;
 -- name: GetItemByID :one
 SELECT
   id, feed_id, title, description, content, link, links, updated_parsed, published_parsed,
-  authors, guid, image, categories, enclosures, created_at, updated_at
+  authors, guid, image, categories, enclosures, created_at, updated_at, seq
 FROM items
 WHERE id = $1;
 
@@ -32,6 +32,17 @@ SELECT COUNT(*) AS count
 FROM items
 WHERE feed_id = $1;
 
+-- name: CountItemsByFeedIDForUser :one
+-- Counts the items of a feed not hidden from the user.
+SELECT COUNT(*) AS count
+FROM items i
+WHERE i.feed_id = $1
+  AND NOT EXISTS (
+    SELECT 1 FROM hidden_items h
+    WHERE h.user_id = $2
+      AND h.item_id = i.id
+  );
+
 -- name: CountLikedItems :one
 SELECT
   COUNT(*) AS count
@@ -41,7 +52,7 @@ WHERE user_id = $1;
 -- name: ListItemsByFeedID :many
 SELECT
   id, feed_id, title, description, content, link, links, updated_parsed, published_parsed,
-  authors, guid, image, categories, enclosures, created_at, updated_at
+  authors, guid, image, categories, enclosures, created_at, updated_at, seq
 FROM items
 WHERE feed_id = $1
 ORDER BY published_parsed DESC
@@ -100,8 +111,19 @@ FROM items i
 LEFT JOIN user_likes ul
   ON ul.item_id = i.id
   AND ul.user_id = $2
+LEFT JOIN user_feeds uf
+  ON uf.feed_id = i.feed_id
+  AND uf.user_id = $2
 WHERE i.feed_id = $1
-ORDER BY i.published_parsed DESC
+  AND NOT EXISTS (
+    SELECT 1 FROM hidden_items h
+    WHERE h.user_id = $2
+      AND h.item_id = i.id
+  )
+ORDER BY
+  -- subscribers may prefer to read a feed oldest first
+  CASE WHEN uf.sort_order = 'oldest' THEN i.published_parsed END ASC,
+  i.published_parsed DESC
 LIMIT  $3
 OFFSET $4;
 
@@ -124,7 +146,261 @@ SET
 WHERE id = $1
 RETURNING
   id, feed_id, title, description, content, link, links, updated_parsed, published_parsed,
-  authors, guid, image, categories, enclosures, created_at, updated_at;
+  authors, guid, image, categories, enclosures, created_at, updated_at, seq;
 
 -- name: DeleteItemByID :exec
 DELETE FROM items WHERE id = $1;
+
+-- name: ListStreamItems :many
+SELECT
+  i.id,
+  i.seq,
+  i.feed_id,
+  i.title,
+  i.description,
+  i.content,
+  i.link,
+  i.published_parsed,
+  i.authors,
+  i.categories,
+  i.created_at,
+  i.updated_at,
+  f.title                            AS feed_title,
+  f.link                             AS feed_site_link,
+  (ul.user_id IS NOT NULL)::boolean  AS liked,
+  (ur.user_id IS NOT NULL)::boolean  AS read
+FROM items i
+JOIN feeds f
+  ON f.id = i.feed_id
+LEFT JOIN user_likes ul
+  ON ul.item_id = i.id
+  AND ul.user_id = @user_id
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = @user_id
+WHERE
+  -- feed, collection and liked streams may contain items from feeds the
+  -- user is not subscribed to; everything else is limited to subscriptions
+  -- that are not muted
+  (
+    sqlc.narg('feed_id')::uuid IS NOT NULL
+    OR sqlc.narg('collection_id')::uuid IS NOT NULL
+    OR @liked_only::boolean
+    OR EXISTS (
+      SELECT 1 FROM user_feeds uf
+      WHERE uf.user_id = @user_id
+        AND uf.feed_id = i.feed_id
+        AND NOT uf.muted
+    )
+  )
+  AND (sqlc.narg('feed_id')::uuid IS NULL OR i.feed_id = sqlc.narg('feed_id'))
+  AND (
+    sqlc.narg('collection_id')::uuid IS NULL
+    OR EXISTS (
+      SELECT 1 FROM collection_items ci
+      WHERE ci.collection_id = sqlc.narg('collection_id')
+        AND ci.item_id = i.id
+    )
+  )
+  AND (NOT @liked_only::boolean OR ul.user_id IS NOT NULL)
+  -- items hidden by rules stay in collections and liked items
+  AND (
+    sqlc.narg('collection_id')::uuid IS NOT NULL
+    OR @liked_only::boolean
+    OR NOT EXISTS (
+      SELECT 1 FROM hidden_items h
+      WHERE h.user_id = @user_id
+        AND h.item_id = i.id
+    )
+  )
+  AND (sqlc.narg('read')::boolean IS NULL OR (ur.user_id IS NOT NULL) = sqlc.narg('read'))
+  AND (sqlc.narg('newer_than')::timestamptz IS NULL OR i.created_at >= sqlc.narg('newer_than'))
+  AND (sqlc.narg('older_than')::timestamptz IS NULL OR i.created_at < sqlc.narg('older_than'))
+ORDER BY
+  CASE WHEN @oldest_first::boolean THEN i.seq END ASC,
+  i.seq DESC
+LIMIT  sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: ListStreamItemRefs :many
+SELECT
+  i.seq,
+  i.created_at
+FROM items i
+LEFT JOIN user_likes ul
+  ON ul.item_id = i.id
+  AND ul.user_id = @user_id
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = @user_id
+WHERE
+  (
+    sqlc.narg('feed_id')::uuid IS NOT NULL
+    OR sqlc.narg('collection_id')::uuid IS NOT NULL
+    OR @liked_only::boolean
+    OR EXISTS (
+      SELECT 1 FROM user_feeds uf
+      WHERE uf.user_id = @user_id
+        AND uf.feed_id = i.feed_id
+        AND NOT uf.muted
+    )
+  )
+  AND (sqlc.narg('feed_id')::uuid IS NULL OR i.feed_id = sqlc.narg('feed_id'))
+  AND (
+    sqlc.narg('collection_id')::uuid IS NULL
+    OR EXISTS (
+      SELECT 1 FROM collection_items ci
+      WHERE ci.collection_id = sqlc.narg('collection_id')
+        AND ci.item_id = i.id
+    )
+  )
+  AND (NOT @liked_only::boolean OR ul.user_id IS NOT NULL)
+  -- items hidden by rules stay in collections and liked items
+  AND (
+    sqlc.narg('collection_id')::uuid IS NOT NULL
+    OR @liked_only::boolean
+    OR NOT EXISTS (
+      SELECT 1 FROM hidden_items h
+      WHERE h.user_id = @user_id
+        AND h.item_id = i.id
+    )
+  )
+  AND (sqlc.narg('read')::boolean IS NULL OR (ur.user_id IS NOT NULL) = sqlc.narg('read'))
+  AND (sqlc.narg('newer_than')::timestamptz IS NULL OR i.created_at >= sqlc.narg('newer_than'))
+  AND (sqlc.narg('older_than')::timestamptz IS NULL OR i.created_at < sqlc.narg('older_than'))
+ORDER BY
+  CASE WHEN @oldest_first::boolean THEN i.seq END ASC,
+  i.seq DESC
+LIMIT  sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: ListStreamItemsBySeqs :many
+SELECT
+  i.id,
+  i.seq,
+  i.feed_id,
+  i.title,
+  i.description,
+  i.content,
+  i.link,
+  i.published_parsed,
+  i.authors,
+  i.categories,
+  i.created_at,
+  i.updated_at,
+  f.title                            AS feed_title,
+  f.link                             AS feed_site_link,
+  (ul.user_id IS NOT NULL)::boolean  AS liked,
+  (ur.user_id IS NOT NULL)::boolean  AS read
+FROM items i
+JOIN feeds f
+  ON f.id = i.feed_id
+LEFT JOIN user_likes ul
+  ON ul.item_id = i.id
+  AND ul.user_id = @user_id
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = @user_id
+WHERE i.seq = ANY(@seqs::bigint[])
+ORDER BY i.seq DESC;
+
+-- name: ListItemIDsBySeqs :many
+SELECT id, seq
+FROM items
+WHERE seq = ANY(@seqs::bigint[]);
+
+-- name: CountUnreadItemsByFeed :many
+SELECT
+  i.feed_id,
+  COUNT(*)                       AS count,
+  MAX(i.created_at)::timestamptz AS newest_item_at
+FROM items i
+JOIN user_feeds uf
+  ON uf.feed_id = i.feed_id
+  AND uf.user_id = @user_id
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = @user_id
+WHERE ur.user_id IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM hidden_items h
+    WHERE h.user_id = @user_id
+      AND h.item_id = i.id
+  )
+GROUP BY i.feed_id;
+
+-- name: ListItemsBySeqRange :many
+SELECT
+  i.id,
+  i.seq,
+  i.feed_id,
+  i.title,
+  i.description,
+  i.content,
+  i.link,
+  i.published_parsed,
+  i.authors,
+  i.categories,
+  i.created_at,
+  i.updated_at,
+  f.title                            AS feed_title,
+  f.link                             AS feed_site_link,
+  (ul.user_id IS NOT NULL)::boolean  AS liked,
+  (ur.user_id IS NOT NULL)::boolean  AS read
+FROM items i
+JOIN feeds f
+  ON f.id = i.feed_id
+JOIN user_feeds uf
+  ON uf.feed_id = i.feed_id
+  AND uf.user_id = @user_id
+LEFT JOIN user_likes ul
+  ON ul.item_id = i.id
+  AND ul.user_id = @user_id
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = @user_id
+WHERE
+  (sqlc.narg('since_seq')::bigint IS NULL OR i.seq > sqlc.narg('since_seq'))
+  AND (sqlc.narg('max_seq')::bigint IS NULL OR i.seq < sqlc.narg('max_seq'))
+  AND NOT EXISTS (
+    SELECT 1 FROM hidden_items h
+    WHERE h.user_id = @user_id
+      AND h.item_id = i.id
+  )
+ORDER BY
+  -- items after since_seq are returned oldest first, items before max_seq newest first
+  CASE WHEN sqlc.narg('max_seq')::bigint IS NULL THEN i.seq END ASC,
+  i.seq DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountSubscribedItems :one
+SELECT COUNT(*) AS count
+FROM items i
+JOIN user_feeds uf
+  ON uf.feed_id = i.feed_id
+  AND uf.user_id = $1;
+
+-- name: ListUnreadItemSeqs :many
+SELECT i.seq
+FROM items i
+JOIN user_feeds uf
+  ON uf.feed_id = i.feed_id
+  AND uf.user_id = @user_id
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = @user_id
+WHERE ur.user_id IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM hidden_items h
+    WHERE h.user_id = @user_id
+      AND h.item_id = i.id
+  )
+ORDER BY i.seq;
+
+-- name: ListLikedItemSeqs :many
+SELECT i.seq
+FROM items i
+JOIN user_likes ul
+  ON ul.item_id = i.id
+WHERE ul.user_id = $1
+ORDER BY i.seq;
@@ -0,0 +1,15 @@
+-- name: CreateRefreshToken :one
+INSERT INTO refresh_tokens (session_id, token_hash)
+VALUES ($1, $2)
+RETURNING id, session_id, token_hash, created_at, used_at;
+
+-- name: GetRefreshTokenByHash :one
+SELECT id, session_id, token_hash, created_at, used_at
+FROM refresh_tokens
+WHERE token_hash = $1;
+
+-- name: MarkRefreshTokenUsed :execrows
+UPDATE refresh_tokens
+SET used_at = now()
+WHERE id = $1
+  AND used_at IS NULL;
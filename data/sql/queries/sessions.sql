@@ -0,0 +1,63 @@
+-- name: CreateSession :one
+INSERT INTO sessions (user_id, device, ip, user_agent, id_token, provider, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, user_id, device, ip, user_agent, id_token, created_at, last_seen_at, expires_at, revoked_at, provider;
+
+-- name: GetSessionByID :one
+SELECT id, user_id, device, ip, user_agent, id_token, created_at, last_seen_at, expires_at, revoked_at, provider
+FROM sessions
+WHERE id = $1;
+
+-- name: ListActiveSessionsByUserID :many
+SELECT id, user_id, device, ip, user_agent, id_token, created_at, last_seen_at, expires_at, revoked_at, provider
+FROM sessions
+WHERE user_id = $1
+  AND revoked_at IS NULL
+  AND expires_at > now()
+ORDER BY last_seen_at DESC;
+
+-- name: TouchSession :one
+-- Reports whether a session is active, recording its use at most once a
+-- minute so authenticated requests do not each write to the table.
+WITH touched AS (
+  UPDATE sessions
+  SET last_seen_at = now()
+  WHERE id = $1
+    AND revoked_at IS NULL
+    AND expires_at > now()
+    AND last_seen_at < now() - interval '1 minute'
+)
+SELECT EXISTS (
+  SELECT 1 FROM sessions
+  WHERE id = $1
+    AND revoked_at IS NULL
+    AND expires_at > now()
+) AS active;
+
+-- name: ExtendSession :exec
+UPDATE sessions
+SET ip           = $2,
+    user_agent   = $3,
+    last_seen_at = now(),
+    expires_at   = $4
+WHERE id = $1;
+
+-- name: RevokeSession :execrows
+UPDATE sessions
+SET revoked_at = now()
+WHERE id = $1
+  AND user_id = $2
+  AND revoked_at IS NULL;
+
+-- name: RevokeOtherSessions :execrows
+UPDATE sessions
+SET revoked_at = now()
+WHERE user_id = $1
+  AND id <> $2
+  AND revoked_at IS NULL;
+
+-- name: RevokeUserSessions :execrows
+UPDATE sessions
+SET revoked_at = now()
+WHERE user_id = $1
+  AND revoked_at IS NULL;
@@ -0,0 +1,254 @@
+-- name: UpsertTags :many
+-- Creates the tags of the user that do not exist yet and returns every tag
+-- named. Names are unique ignoring case and must not repeat.
+INSERT INTO tags (user_id, name)
+SELECT @user_id::uuid, n.name
+FROM unnest(@names::text[]) AS n(name)
+ON CONFLICT (user_id, (lower(name))) DO UPDATE
+SET name = tags.name
+RETURNING id, user_id, name, created_at;
+
+-- name: GetTagByID :one
+SELECT id, user_id, name, created_at
+FROM tags
+WHERE id      = $1
+  AND user_id = $2;
+
+-- name: GetTagByName :one
+SELECT id, user_id, name, created_at
+FROM tags
+WHERE user_id     = @user_id
+  AND lower(name) = lower(@name);
+
+-- name: ListTagsByNames :many
+-- names must be lower case.
+SELECT id, user_id, name, created_at
+FROM tags
+WHERE user_id = @user_id
+  AND lower(name) = ANY(@names::text[]);
+
+-- name: ListTags :many
+-- Lists the tags of the user starting with prefix, most used first. Feeds
+-- only count while the user is subscribed to them.
+SELECT id, user_id, name, created_at, item_count, feed_count
+FROM (
+  SELECT
+    t.id, t.user_id, t.name, t.created_at,
+    (SELECT COUNT(*) FROM item_tags it WHERE it.tag_id = t.id) AS item_count,
+    (
+      SELECT COUNT(*)
+      FROM feed_tags ft
+      JOIN user_feeds uf ON uf.feed_id = ft.feed_id AND uf.user_id = t.user_id
+      WHERE ft.tag_id = t.id
+    ) AS feed_count
+  FROM tags t
+  WHERE t.user_id = @user_id
+    AND (sqlc.narg('prefix')::text IS NULL OR starts_with(lower(t.name), lower(sqlc.narg('prefix'))))
+) t
+ORDER BY item_count + feed_count DESC, lower(name)
+LIMIT sqlc.arg('limit');
+
+-- name: RenameTag :one
+UPDATE tags
+SET name = $3
+WHERE id      = $1
+  AND user_id = $2
+RETURNING id, user_id, name, created_at;
+
+-- name: MoveTagItems :exec
+INSERT INTO item_tags (tag_id, item_id, tagged_at)
+SELECT @target_id::uuid, it.item_id, it.tagged_at
+FROM item_tags it
+WHERE it.tag_id = @source_id
+ON CONFLICT (tag_id, item_id) DO NOTHING;
+
+-- name: MoveTagFeeds :exec
+INSERT INTO feed_tags (tag_id, feed_id, tagged_at)
+SELECT @target_id::uuid, ft.feed_id, ft.tagged_at
+FROM feed_tags ft
+WHERE ft.tag_id = @source_id
+ON CONFLICT (tag_id, feed_id) DO NOTHING;
+
+-- name: DeleteTag :execrows
+DELETE FROM tags
+WHERE id      = $1
+  AND user_id = $2;
+
+-- name: TagItems :execrows
+-- Items that do not exist are skipped.
+INSERT INTO item_tags (tag_id, item_id)
+SELECT tg.id, i.id
+FROM unnest(@tag_ids::uuid[]) AS tg(id)
+CROSS JOIN items i
+WHERE i.id = ANY(@item_ids::uuid[])
+ON CONFLICT (tag_id, item_id) DO NOTHING;
+
+-- name: UntagItems :execrows
+DELETE FROM item_tags
+WHERE tag_id  = ANY(@tag_ids::uuid[])
+  AND item_id = ANY(@item_ids::uuid[]);
+
+-- name: UntagItemExcept :execrows
+-- Removes the tags of the user from an item, except keep_ids.
+DELETE FROM item_tags it
+USING tags t
+WHERE t.id      = it.tag_id
+  AND t.user_id = @user_id
+  AND it.item_id = @item_id
+  AND NOT (it.tag_id = ANY(@keep_ids::uuid[]));
+
+-- name: TagFeeds :execrows
+-- Only feeds the user is subscribed to are tagged.
+INSERT INTO feed_tags (tag_id, feed_id)
+SELECT tg.id, uf.feed_id
+FROM unnest(@tag_ids::uuid[]) AS tg(id)
+CROSS JOIN user_feeds uf
+WHERE uf.user_id = @user_id
+  AND uf.feed_id = ANY(@feed_ids::uuid[])
+ON CONFLICT (tag_id, feed_id) DO NOTHING;
+
+-- name: UntagFeeds :execrows
+DELETE FROM feed_tags
+WHERE tag_id  = ANY(@tag_ids::uuid[])
+  AND feed_id = ANY(@feed_ids::uuid[]);
+
+-- name: UntagFeedExcept :execrows
+-- Removes the tags of the user from a feed, except keep_ids.
+DELETE FROM feed_tags ft
+USING tags t
+WHERE t.id       = ft.tag_id
+  AND t.user_id  = @user_id
+  AND ft.feed_id = @feed_id
+  AND NOT (ft.tag_id = ANY(@keep_ids::uuid[]));
+
+-- name: ListItemTagNames :many
+SELECT it.item_id, t.name
+FROM item_tags it
+JOIN tags t ON t.id = it.tag_id
+WHERE t.user_id = @user_id
+  AND it.item_id = ANY(@item_ids::uuid[])
+ORDER BY lower(t.name);
+
+-- name: ListFeedTagNames :many
+SELECT ft.feed_id, t.name
+FROM feed_tags ft
+JOIN tags t ON t.id = ft.tag_id
+WHERE t.user_id = @user_id
+  AND ft.feed_id = ANY(@feed_ids::uuid[])
+ORDER BY lower(t.name);
+
+-- name: ListFeedFolders :many
+-- Lists the subscribed feeds of the user by folder. A feed with an explicit
+-- folder is listed once in it, other feeds once per tag, and feeds with
+-- neither once with a NULL folder.
+SELECT
+  t.id                          AS tag_id,
+  COALESCE(uf.folder, t.name)   AS folder,
+  f.id,
+  COALESCE(uf.title, f.title)   AS title,
+  f.link,
+  f.feed_link
+FROM user_feeds uf
+JOIN feeds f ON f.id = uf.feed_id
+LEFT JOIN (feed_tags ft JOIN tags t ON t.id = ft.tag_id AND t.user_id = @user_id)
+  ON ft.feed_id = f.id
+  AND uf.folder IS NULL
+WHERE uf.user_id = @user_id
+ORDER BY lower(COALESCE(uf.folder, t.name)) NULLS LAST,
+         lower(COALESCE(uf.title, f.title, f.feed_link));
+
+-- name: CountFeedsByTag :one
+SELECT COUNT(*) AS count
+FROM user_feeds uf
+JOIN feed_tags ft ON ft.feed_id = uf.feed_id
+WHERE uf.user_id = $1
+  AND ft.tag_id  = $2;
+
+-- name: ListFeedsByTag :many
+SELECT
+  f.id, f.title, f.description, f.link, f.feed_link, f.links,
+  f.updated_parsed, f.published_parsed,
+  f.authors, f.language, f.image, f.copyright, f.generator,
+  f.categories, f.feed_type, f.feed_version,
+  f.created_at, f.last_updated_at,
+  uf.subscribed_at
+FROM feeds f
+JOIN user_feeds uf
+  ON uf.feed_id = f.id
+  AND uf.user_id = $1
+JOIN feed_tags ft
+  ON ft.feed_id = f.id
+  AND ft.tag_id = $2
+ORDER BY f.created_at DESC
+LIMIT  $3
+OFFSET $4;
+
+-- name: CountItemsByTag :one
+-- Counts the items with the tag and the items of subscribed feeds with the
+-- tag that are not muted, leaving out those hidden by rules.
+SELECT COUNT(*) AS count
+FROM items i
+WHERE EXISTS (
+    SELECT 1 FROM item_tags it
+    WHERE it.item_id = i.id
+      AND it.tag_id  = @tag_id
+  )
+  OR EXISTS (
+    SELECT 1 FROM feed_tags ft
+    JOIN user_feeds uf ON uf.feed_id = ft.feed_id AND uf.user_id = @user_id
+    WHERE ft.feed_id = i.feed_id
+      AND ft.tag_id  = @tag_id
+      AND NOT uf.muted
+      AND NOT EXISTS (
+        SELECT 1 FROM hidden_items h
+        WHERE h.user_id = @user_id
+          AND h.item_id = i.id
+      )
+  );
+
+-- name: ListItemsByTag :many
+-- Lists the items with the tag and the items of subscribed feeds with the
+-- tag that are not muted, leaving out those hidden by rules, newest first.
+SELECT
+  i.id,
+  i.feed_id,
+  i.title,
+  i.description,
+  i.content,
+  i.link,
+  i.links,
+  i.updated_parsed,
+  i.published_parsed,
+  i.authors,
+  i.guid,
+  i.image,
+  i.categories,
+  i.enclosures,
+  i.created_at,
+  i.updated_at,
+  (ul.user_id IS NOT NULL)        AS liked,
+  ul.liked_at
+FROM items i
+LEFT JOIN user_likes ul
+  ON ul.item_id = i.id
+  AND ul.user_id = @user_id
+WHERE EXISTS (
+    SELECT 1 FROM item_tags it
+    WHERE it.item_id = i.id
+      AND it.tag_id  = @tag_id
+  )
+  OR EXISTS (
+    SELECT 1 FROM feed_tags ft
+    JOIN user_feeds uf ON uf.feed_id = ft.feed_id AND uf.user_id = @user_id
+    WHERE ft.feed_id = i.feed_id
+      AND ft.tag_id  = @tag_id
+      AND NOT uf.muted
+      AND NOT EXISTS (
+        SELECT 1 FROM hidden_items h
+        WHERE h.user_id = @user_id
+          AND h.item_id = i.id
+      )
+  )
+ORDER BY COALESCE(i.published_parsed, i.created_at) DESC
+LIMIT  sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
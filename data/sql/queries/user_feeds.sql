@@ -1,22 +1,44 @@
 -- name: CreateUserFeedSubscription :one
 INSERT INTO user_feeds (user_id, feed_id)
 VALUES ($1, $2)
-RETURNING user_id, feed_id, subscribed_at;
+RETURNING user_id, feed_id, subscribed_at, folder, title, icon, notify, sort_order, muted;
 
 -- name: GetUserFeedSubscription :one
-SELECT user_id, feed_id, subscribed_at
+SELECT user_id, feed_id, subscribed_at, folder, title, icon, notify, sort_order, muted
 FROM user_feeds
 WHERE user_id = $1
   AND feed_id = $2;
 
 -- name: ListUserFeedSubscriptions :many
-SELECT user_id, feed_id, subscribed_at
+SELECT user_id, feed_id, subscribed_at, folder, title, icon, notify, sort_order, muted
 FROM user_feeds
 WHERE user_id = $1
 ORDER BY subscribed_at DESC
 LIMIT  $2
 OFFSET $3;
 
+-- name: ListUserFeedSubscriptionsByFeedIDs :many
+SELECT user_id, feed_id, subscribed_at, folder, title, icon, notify, sort_order, muted
+FROM user_feeds
+WHERE user_id = @user_id
+  AND feed_id = ANY(@feed_ids::uuid[]);
+
+-- name: UpdateUserFeedSubscription :one
+-- NULL leaves a field unchanged, an empty folder, title or icon clears it.
+UPDATE user_feeds
+SET folder       = CASE WHEN sqlc.narg('folder')::text IS NULL THEN folder
+                        ELSE NULLIF(sqlc.narg('folder')::text, '') END,
+    title        = CASE WHEN sqlc.narg('title')::text IS NULL THEN title
+                        ELSE NULLIF(sqlc.narg('title')::text, '') END,
+    icon         = CASE WHEN sqlc.narg('icon')::text IS NULL THEN icon
+                        ELSE NULLIF(sqlc.narg('icon')::text, '') END,
+    notify       = COALESCE(sqlc.narg('notify'), notify),
+    sort_order   = COALESCE(sqlc.narg('sort_order'), sort_order),
+    muted        = COALESCE(sqlc.narg('muted'), muted)
+WHERE user_id = sqlc.arg('user_id')
+  AND feed_id = sqlc.arg('feed_id')
+RETURNING user_id, feed_id, subscribed_at, folder, title, icon, notify, sort_order, muted;
+
 -- name: DeleteUserFeedSubscription :exec
 DELETE FROM user_feeds
 WHERE user_id = $1
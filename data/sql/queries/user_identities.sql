@@ -0,0 +1,34 @@
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (user_id, provider, issuer, sub, email, email_verified)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, provider, issuer, sub, email, created_at, last_login_at, email_verified;
+
+-- name: GetUserIdentityByIssuerSub :one
+SELECT id, user_id, provider, issuer, sub, email, created_at, last_login_at, email_verified
+FROM user_identities
+WHERE issuer = $1
+  AND sub = $2;
+
+-- name: TouchUserIdentity :exec
+UPDATE user_identities
+SET provider       = $2,
+    email          = $3,
+    email_verified = $4,
+    last_login_at  = now()
+WHERE id = $1;
+
+-- name: ListUserIdentitiesByUserID :many
+SELECT id, user_id, provider, issuer, sub, email, created_at, last_login_at, email_verified
+FROM user_identities
+WHERE user_id = $1
+ORDER BY created_at ASC;
+
+-- name: CountUserIdentitiesByUserID :one
+SELECT COUNT(*) AS count
+FROM user_identities
+WHERE user_id = $1;
+
+-- name: DeleteUserIdentity :execrows
+DELETE FROM user_identities
+WHERE id = $1
+  AND user_id = $2;
@@ -0,0 +1,44 @@
+-- name: CreateUserRead :exec
+INSERT INTO user_reads (user_id, item_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteUserRead :exec
+DELETE FROM user_reads
+WHERE user_id = $1
+  AND item_id = $2;
+
+-- name: MarkStreamItemsRead :execrows
+INSERT INTO user_reads (user_id, item_id)
+SELECT @user_id::uuid, i.id
+FROM items i
+WHERE
+  (
+    sqlc.narg('feed_id')::uuid IS NOT NULL
+    OR sqlc.narg('collection_id')::uuid IS NOT NULL
+    OR @liked_only::boolean
+    OR EXISTS (
+      SELECT 1 FROM user_feeds uf
+      WHERE uf.user_id = @user_id::uuid
+        AND uf.feed_id = i.feed_id
+    )
+  )
+  AND (sqlc.narg('feed_id')::uuid IS NULL OR i.feed_id = sqlc.narg('feed_id'))
+  AND (
+    sqlc.narg('collection_id')::uuid IS NULL
+    OR EXISTS (
+      SELECT 1 FROM collection_items ci
+      WHERE ci.collection_id = sqlc.narg('collection_id')
+        AND ci.item_id = i.id
+    )
+  )
+  AND (
+    NOT @liked_only::boolean
+    OR EXISTS (
+      SELECT 1 FROM user_likes ul
+      WHERE ul.user_id = @user_id::uuid
+        AND ul.item_id = i.id
+    )
+  )
+  AND (sqlc.narg('before')::timestamptz IS NULL OR i.created_at <= sqlc.narg('before'))
+ON CONFLICT DO NOTHING;
@@ -1,30 +1,42 @@
 -- name: CreateUser :one
-INSERT INTO users (sub, name, email)
-VALUES ($1, $2, $3)
+INSERT INTO users (sub, name, email, role)
+VALUES ($1, $2, $3, $4)
 RETURNING
-  id, sub, name, email, created_at, last_updated_at;
+  id, sub, name, email, created_at, last_updated_at, role, disabled_at;
 
 -- name: GetUserByID :one
 SELECT
-  id, sub, name, email, created_at, last_updated_at
+  id, sub, name, email, created_at, last_updated_at, role, disabled_at
 FROM users
 WHERE id = $1;
 
 -- name: GetUserBySub :one
 SELECT
-  id, sub, name, email, created_at, last_updated_at
+  id, sub, name, email, created_at, last_updated_at, role, disabled_at
 FROM users
 WHERE sub = $1;
 
+-- name: GetUnlinkedUserBySub :one
+SELECT
+  u.id, u.sub, u.name, u.email, u.created_at, u.last_updated_at, u.role, u.disabled_at
+FROM users u
+WHERE u.sub = $1
+  AND NOT EXISTS (
+    SELECT 1
+    FROM user_identities ui
+    WHERE ui.user_id = u.id
+  )
+LIMIT 1;
+
 -- name: GetUserByEmail :one
 SELECT
-  id, sub, name, email, created_at, last_updated_at
+  id, sub, name, email, created_at, last_updated_at, role, disabled_at
 FROM users
 WHERE email = $1;
 
 -- name: ListUsers :many
 SELECT
-  id, sub, name, email, created_at, last_updated_at
+  id, sub, name, email, created_at, last_updated_at, role, disabled_at
 FROM users
 ORDER BY created_at DESC
 LIMIT  $1
@@ -43,7 +55,21 @@ SET
   last_updated_at = now()
 WHERE id = $1
 RETURNING
-  id, sub, name, email, created_at, last_updated_at;
+  id, sub, name, email, created_at, last_updated_at, role, disabled_at;
+
+-- name: UpdateUserRole :exec
+UPDATE users
+SET
+  role            = $2,
+  last_updated_at = now()
+WHERE id = $1;
+
+-- name: SetUserDisabled :execrows
+UPDATE users
+SET
+  disabled_at     = CASE WHEN @disabled::boolean THEN COALESCE(disabled_at, now()) END,
+  last_updated_at = now()
+WHERE id = @id;
 
 -- name: DeleteUserByID :exec
 DELETE FROM users
@@ -0,0 +1,171 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (user_id, name, url, secret, events, feed_ids, collection_ids, rule_ids, enabled)
+VALUES (
+  sqlc.arg('user_id'), sqlc.arg('name'), sqlc.arg('url'), sqlc.arg('secret'), sqlc.arg('events'),
+  sqlc.arg('feed_ids'), sqlc.arg('collection_ids'), sqlc.arg('rule_ids'), sqlc.arg('enabled')
+)
+RETURNING id, user_id, name, url, secret, events, feed_ids, collection_ids, rule_ids, enabled,
+          failure_count, disabled_reason, last_delivery_at, created_at, updated_at;
+
+-- name: GetWebhook :one
+SELECT id, user_id, name, url, secret, events, feed_ids, collection_ids, rule_ids, enabled,
+       failure_count, disabled_reason, last_delivery_at, created_at, updated_at
+FROM webhooks
+WHERE id      = $1
+  AND user_id = $2;
+
+-- name: GetWebhookByID :one
+SELECT id, user_id, name, url, secret, events, feed_ids, collection_ids, rule_ids, enabled,
+       failure_count, disabled_reason, last_delivery_at, created_at, updated_at
+FROM webhooks
+WHERE id = $1;
+
+-- name: ListWebhooks :many
+SELECT id, user_id, name, url, secret, events, feed_ids, collection_ids, rule_ids, enabled,
+       failure_count, disabled_reason, last_delivery_at, created_at, updated_at
+FROM webhooks
+WHERE user_id = $1
+ORDER BY created_at;
+
+-- name: CountWebhooks :one
+SELECT COUNT(*) AS count
+FROM webhooks
+WHERE user_id = $1;
+
+-- name: UpdateWebhook :one
+-- Replaces the definition of a webhook, keeping its secret when none is
+-- given. Enabling a disabled webhook resets its failures.
+UPDATE webhooks
+SET name            = sqlc.arg('name'),
+    url             = sqlc.arg('url'),
+    secret          = COALESCE(sqlc.narg('secret'), secret),
+    events          = sqlc.arg('events'),
+    feed_ids        = sqlc.arg('feed_ids'),
+    collection_ids  = sqlc.arg('collection_ids'),
+    rule_ids        = sqlc.arg('rule_ids'),
+    failure_count   = CASE WHEN sqlc.arg('enabled') AND NOT enabled THEN 0 ELSE failure_count END,
+    disabled_reason = CASE WHEN sqlc.arg('enabled') THEN NULL ELSE disabled_reason END,
+    enabled         = sqlc.arg('enabled'),
+    updated_at      = now()
+WHERE id      = sqlc.arg('id')
+  AND user_id = sqlc.arg('user_id')
+RETURNING id, user_id, name, url, secret, events, feed_ids, collection_ids, rule_ids, enabled,
+          failure_count, disabled_reason, last_delivery_at, created_at, updated_at;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks
+WHERE id      = $1
+  AND user_id = $2;
+
+-- name: ListWebhooksForFeedItem :many
+-- Lists the enabled webhooks of the subscribers of a feed to notify of a new
+-- item of it, leaving out muted subscriptions, subscriptions with notify set
+-- to none and items hidden by rules.
+SELECT w.id, w.user_id, w.name, w.url, w.secret, w.events, w.feed_ids, w.collection_ids, w.rule_ids, w.enabled,
+       w.failure_count, w.disabled_reason, w.last_delivery_at, w.created_at, w.updated_at
+FROM webhooks w
+JOIN user_feeds uf
+  ON uf.user_id = w.user_id
+  AND uf.feed_id = @feed_id
+  AND NOT uf.muted
+  AND uf.notify = 'all'
+WHERE w.enabled
+  AND 'feed.item' = ANY(w.events)
+  AND (cardinality(w.feed_ids) = 0 OR @feed_id = ANY(w.feed_ids))
+  AND NOT EXISTS (
+    SELECT 1 FROM hidden_items h
+    WHERE h.user_id = w.user_id
+      AND h.item_id = @item_id
+  );
+
+-- name: ListWebhooksForCollectionItem :many
+-- Lists the enabled webhooks of the owner and members of a collection to
+-- notify of an item added to it.
+SELECT w.id, w.user_id, w.name, w.url, w.secret, w.events, w.feed_ids, w.collection_ids, w.rule_ids, w.enabled,
+       w.failure_count, w.disabled_reason, w.last_delivery_at, w.created_at, w.updated_at
+FROM webhooks w
+JOIN collections c
+  ON c.id = @collection_id
+WHERE w.enabled
+  AND 'collection.item' = ANY(w.events)
+  AND (cardinality(w.collection_ids) = 0 OR @collection_id = ANY(w.collection_ids))
+  AND (
+    c.user_id = w.user_id
+    OR EXISTS (
+      SELECT 1 FROM collection_members m
+      WHERE m.collection_id = c.id
+        AND m.user_id       = w.user_id
+        AND m.accepted_at IS NOT NULL
+    )
+  );
+
+-- name: ListWebhooksForRuleMatch :many
+-- Lists the enabled webhooks of the user to notify of an item matched by one
+-- of their filter rules.
+SELECT id, user_id, name, url, secret, events, feed_ids, collection_ids, rule_ids, enabled,
+       failure_count, disabled_reason, last_delivery_at, created_at, updated_at
+FROM webhooks
+WHERE user_id = @user_id
+  AND enabled
+  AND 'rule.match' = ANY(events)
+  AND (cardinality(rule_ids) = 0 OR @rule_id = ANY(rule_ids));
+
+-- name: RecordWebhookSuccess :exec
+UPDATE webhooks
+SET failure_count    = 0,
+    last_delivery_at = now()
+WHERE id = $1;
+
+-- name: RecordWebhookFailure :one
+-- Counts a failed delivery, disabling the webhook once max_failures
+-- deliveries in a row failed.
+UPDATE webhooks
+SET failure_count    = failure_count + 1,
+    enabled          = enabled AND failure_count + 1 < sqlc.arg('max_failures'),
+    disabled_reason  = CASE
+                         WHEN enabled AND failure_count + 1 >= sqlc.arg('max_failures')
+                         THEN sqlc.arg('reason')::text
+                         ELSE disabled_reason
+                       END,
+    last_delivery_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING enabled, failure_count;
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (webhook_id, event, payload)
+VALUES ($1, $2, $3)
+RETURNING id, webhook_id, event, payload, status, attempts, response_status, error, duration_ms,
+          created_at, attempted_at;
+
+-- name: GetWebhookDelivery :one
+SELECT id, webhook_id, event, payload, status, attempts, response_status, error, duration_ms,
+       created_at, attempted_at
+FROM webhook_deliveries
+WHERE id = $1;
+
+-- name: CountWebhookDeliveries :one
+SELECT COUNT(*) AS count
+FROM webhook_deliveries
+WHERE webhook_id = $1;
+
+-- name: ListWebhookDeliveries :many
+SELECT id, webhook_id, event, payload, status, attempts, response_status, error, duration_ms,
+       created_at, attempted_at
+FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: RecordWebhookAttempt :exec
+UPDATE webhook_deliveries
+SET status          = sqlc.arg('status'),
+    attempts        = attempts + 1,
+    response_status = sqlc.narg('response_status'),
+    error           = sqlc.narg('error'),
+    duration_ms     = sqlc.arg('duration_ms'),
+    attempted_at    = now()
+WHERE id = sqlc.arg('id');
+
+-- name: DeleteWebhookDeliveriesBefore :execrows
+DELETE FROM webhook_deliveries
+WHERE created_at < $1;
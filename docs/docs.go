@@ -19,29 +19,35 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/collections": {
+        "/api/admin/feeds": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves paginated collections for the current user.",
+                "description": "Lists all feeds with subscriber and item counts and the outcome of recent syncs.",
                 "tags": [
-                    "Collections"
+                    "Admin"
                 ],
-                "summary": "List collections",
+                "summary": "List feeds",
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only feeds whose last sync failed",
+                        "name": "failingOnly",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Max number of collections",
+                        "description": "Max number of feeds to return",
                         "name": "limit",
                         "in": "query",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Number of collections to skip",
+                        "description": "Number of feeds to skip",
                         "name": "offset",
                         "in": "query",
                         "required": true
@@ -51,7 +57,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListCollectionsResponse"
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListAdminFeedsResponse"
                         }
                     },
                     "400": {
@@ -60,6 +66,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -67,26 +79,38 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/admin/feeds/{feedID}/merge": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a named collection for the current user.FeedURL@Tags         Collections",
+                "description": "Moves the items and subscribers of a duplicate feed to the target feed and deletes the duplicate.",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Collections"
+                    "Admin"
                 ],
-                "summary": "Create collection",
+                "summary": "Merge feeds",
                 "parameters": [
                     {
-                        "description": "Collection name",
+                        "type": "string",
+                        "description": "Duplicate feed UUID",
+                        "name": "feedID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Feed to merge into",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.CreateCollectionRequest"
+                            "$ref": "#/definitions/internal_handler.MergeFeedsRequest"
                         }
                     }
                 ],
@@ -94,7 +118,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Collection"
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.MergeFeedsResponse"
                         }
                     },
                     "400": {
@@ -103,8 +127,14 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
@@ -118,32 +148,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/collections/{collectionID}": {
-            "get": {
+        "/api/admin/feeds/{feedID}/sync": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a collection by ID.",
+                "description": "Queues an immediate sync of a feed, unless a sync of the feed is already queued or running.",
                 "tags": [
-                    "Collections"
+                    "Admin"
                 ],
-                "summary": "Get collection",
+                "summary": "Resync feed",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Collection UUID",
-                        "name": "collectionID",
+                        "description": "Feed UUID",
+                        "name": "feedID",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Collection"
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.SyncFeedResponse"
                         }
                     },
                     "400": {
@@ -152,6 +182,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -165,30 +201,47 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/admin/items": {
             "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes a collection by ID.",
+                "description": "Deletes items published before a date, across all feeds or for one feed. Liked, annotated and tagged items and items in collections are kept unless keepSaved is false, otherwise their annotations and tags are deleted with them.",
                 "tags": [
-                    "Collections"
+                    "Admin"
                 ],
-                "summary": "Delete collection",
+                "summary": "Purge items",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Collection UUID",
-                        "name": "collectionID",
-                        "in": "path",
+                        "description": "RFC 3339 timestamp",
+                        "name": "before",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Feed UUID",
+                        "name": "feedID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Keep liked, annotated and tagged items and items in collections, defaults to true",
+                        "name": "keepSaved",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.PurgeItemsResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -196,6 +249,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -211,31 +270,37 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/collections/{collectionID}/item/{itemID}": {
-            "post": {
+        "/api/admin/users": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adds an item to the specified collection.",
+                "description": "Lists users whose name or email match the query, with per-user stats.",
                 "tags": [
-                    "Collections"
+                    "Admin"
                 ],
-                "summary": "Add item to collection",
+                "summary": "List users",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Collection UUID",
-                        "name": "collectionID",
-                        "in": "path",
+                        "description": "Name or email search",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of users to return",
+                        "name": "limit",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Item UUID",
-                        "name": "itemID",
-                        "in": "path",
+                        "type": "integer",
+                        "description": "Number of users to skip",
+                        "name": "offset",
+                        "in": "query",
                         "required": true
                     }
                 ],
@@ -243,7 +308,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.AddItemToCollectionResponse"
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListAdminUsersResponse"
                         }
                     },
                     "400": {
@@ -252,6 +317,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -259,37 +330,35 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/admin/users/{userID}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Removes the specified item from the collection.",
+                "description": "Retrieves a user with per-user stats.",
                 "tags": [
-                    "Collections"
+                    "Admin"
                 ],
-                "summary": "Remove item from collection",
+                "summary": "Get user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Collection UUID",
-                        "name": "collectionID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Item UUID",
-                        "name": "itemID",
+                        "description": "User UUID",
+                        "name": "userID",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.AdminUser"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -297,6 +366,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -304,49 +385,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/collections/{collectionID}/items": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves items in the collection, including like status.",
+                "description": "Deletes a user with their subscriptions, likes, collections and credentials.",
                 "tags": [
-                    "Collections"
+                    "Admin"
                 ],
-                "summary": "List items in collection",
+                "summary": "Delete user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Collection UUID",
-                        "name": "collectionID",
+                        "description": "User UUID",
+                        "name": "userID",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Max number of items",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to skip",
-                        "name": "offset",
-                        "in": "query",
-                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListCollectionItemsResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -354,6 +416,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -363,37 +437,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/feeds": {
-            "get": {
+        "/api/admin/users/{userID}/disable": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns all feeds or only those the user is subscribed to.",
+                "description": "Disables a user and logs out all their sessions, disabled users can not sign in or use tokens.",
                 "tags": [
-                    "Feeds"
+                    "Admin"
                 ],
-                "summary": "List feeds",
+                "summary": "Disable user",
                 "parameters": [
                     {
-                        "type": "boolean",
-                        "description": "Only subscribed feeds",
-                        "name": "subscribedOnly",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Max number of feeds to return",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of feeds to skip",
-                        "name": "offset",
-                        "in": "query",
+                        "type": "string",
+                        "description": "User UUID",
+                        "name": "userID",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -401,7 +462,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListFeedsResponse"
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.AdminUser"
                         }
                     },
                     "400": {
@@ -410,6 +471,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -418,33 +491,31 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a new feed from URL or subscribes the user to it.",
+                "description": "Lets a disabled user sign in again.",
                 "tags": [
-                    "Feeds"
+                    "Admin"
                 ],
-                "summary": "Create or subscribe feed",
+                "summary": "Enable user",
                 "parameters": [
                     {
-                        "description": "Create feed payload",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handler.CreateFeedRequest"
-                        }
+                        "type": "string",
+                        "description": "User UUID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Feed"
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.AdminUser"
                         }
                     },
                     "400": {
@@ -453,8 +524,14 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
@@ -468,34 +545,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/feeds/export": {
+        "/api/annotations": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns a CSV list of all feeds, or only those the user is subscribed to.",
+                "description": "Retrieves the notes and highlights of the user on all items, newest first, paginated.",
                 "produces": [
-                    "text/csv"
+                    "application/json"
                 ],
                 "tags": [
-                    "Feeds"
+                    "Annotations"
                 ],
-                "summary": "Export feeds",
+                "summary": "List annotations",
                 "parameters": [
                     {
-                        "type": "boolean",
-                        "description": "Only subscribed feeds",
-                        "name": "subscribedOnly",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "Max number of annotations",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of annotations to skip",
+                        "name": "offset",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "CSV file with one column: Feed URL",
+                        "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListAnnotationsResponse"
                         }
                     },
                     "400": {
@@ -513,44 +598,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/feeds/{feedID}": {
+        "/api/annotations/export": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a feed by ID, including the user’s subscription status.",
-                "tags": [
-                    "Feeds"
+                "description": "Renders the highlights and notes of the user on all items as a Markdown document, grouped by item.",
+                "produces": [
+                    "text/markdown"
                 ],
-                "summary": "Get feed",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Feed UUID",
-                        "name": "feedID",
-                        "in": "path",
-                        "required": true
-                    }
+                "tags": [
+                    "Annotations"
                 ],
+                "summary": "Export annotations",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Feed"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                        "description": "Exported annotations",
                         "schema": {
-                            "type": "string"
+                            "type": "file"
                         }
                     },
                     "500": {
@@ -560,30 +627,42 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/collections": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Removes a feed and all its subscriptions/items.",
+                "description": "Retrieves paginated collections for the current user.",
                 "tags": [
-                    "Feeds"
+                    "Collections"
                 ],
-                "summary": "Delete feed",
+                "summary": "List collections",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Feed UUID",
-                        "name": "feedID",
-                        "in": "path",
+                        "type": "integer",
+                        "description": "Max number of collections",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of collections to skip",
+                        "name": "offset",
+                        "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListCollectionsResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -598,48 +677,34 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/feeds/{feedID}/items": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves feed items.",
+                "description": "Creates a named collection for the current user.FeedURL@Tags         Collections",
                 "tags": [
-                    "Items"
+                    "Collections"
                 ],
-                "summary": "List feed items",
+                "summary": "Create collection",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Feed UUID",
-                        "name": "feedID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Max number of items",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to skip",
-                        "name": "offset",
-                        "in": "query",
-                        "required": true
+                        "description": "Collection name",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CreateCollectionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListItemsResponse"
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Collection"
                         }
                     },
                     "400": {
@@ -648,6 +713,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -657,38 +728,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/feeds/{feedID}/subscribe": {
-            "put": {
+        "/api/collections/invitations": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Subscribes the user to an existing feed.",
-                "tags": [
-                    "Feeds"
+                "description": "Lists the invitations to the verified emails of the user to collections of other users that are not accepted yet.",
+                "produces": [
+                    "application/json"
                 ],
-                "summary": "Subscribe to feed",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Feed UUID",
-                        "name": "feedID",
-                        "in": "path",
-                        "required": true
-                    }
+                "tags": [
+                    "Collections"
                 ],
+                "summary": "List collection invitations",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.SubscibeToFeedResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListCollectionInvitationsResponse"
                         }
                     },
                     "500": {
@@ -698,25 +757,32 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/collections/order": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Removes the user’s subscription to a feed.",
+                "description": "Sets the manual order of the user's collections to the order of the given IDs. Pinned collections are still listed first.",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Feeds"
+                    "Collections"
                 ],
-                "summary": "Unsubscribe from feed",
+                "summary": "Reorder collections",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Feed UUID",
-                        "name": "feedID",
-                        "in": "path",
-                        "required": true
+                        "description": "Collection IDs in order",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ReorderCollectionsRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -729,6 +795,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -738,31 +810,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/items": {
+        "/api/collections/{collectionID}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves items liked by the user, paginated.",
+                "description": "Retrieves a collection by ID.",
                 "tags": [
-                    "Items"
+                    "Collections"
                 ],
-                "summary": "List liked items",
+                "summary": "Get collection",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Max number of items",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to skip",
-                        "name": "offset",
-                        "in": "query",
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -770,7 +835,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListItemsResponse"
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Collection"
                         }
                     },
                     "400": {
@@ -779,6 +844,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -786,35 +857,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/items/{itemID}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves an item by ID, including like status for the user.",
+                "description": "Deletes a collection by ID.",
                 "tags": [
-                    "Items"
+                    "Collections"
                 ],
-                "summary": "Get item",
+                "summary": "Delete collection",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Item UUID",
-                        "name": "itemID",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Item"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -835,48 +901,47 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/items/{itemID}/collections": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves list of collections that given item is in.",
+                "description": "Renames a collection or changes its description, icon, color, pinned state or visibility. Omitted fields are left unchanged. Publishing a collection gives it a slug under /public/collections.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Items"
+                    "Collections"
                 ],
-                "summary": "Get collections item is in.",
+                "summary": "Update collection",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Item UUID",
-                        "name": "itemID",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Max number of items",
-                        "name": "limit",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to skip",
-                        "name": "offset",
-                        "in": "query",
-                        "required": true
+                        "description": "Fields to update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.UpdateCollectionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListCollectionsResponse"
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Collection"
                         }
                     },
                     "400": {
@@ -885,6 +950,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -894,19 +971,151 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/items/{itemID}/like": {
+        "/api/collections/{collectionID}/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renders every item of the collection with its title, authors, source feed, publish date and sanitized content as a Markdown document, a self-contained HTML document or an EPUB book. Images are linked unless inlineImages is set. The document is streamed as it is rendered.",
+                "produces": [
+                    "text/markdown",
+                    "text/html",
+                    "application/epub+zip"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Export collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "md",
+                            "html",
+                            "epub"
+                        ],
+                        "type": "string",
+                        "default": "md",
+                        "description": "Document format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Embed images in the document",
+                        "name": "inlineImages",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exported collection",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/collections/{collectionID}/invitations/{email}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Withdraws the pending invitation of an email to a collection of the user.",
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Withdraw collection invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invited email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/collections/{collectionID}/item/{itemID}": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Creates a like record for the current user on an item.",
+                "description": "Adds an item to the specified collection.",
                 "tags": [
-                    "Items"
+                    "Collections"
                 ],
-                "summary": "Like item",
+                "summary": "Add item to collection",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Item UUID",
@@ -919,7 +1128,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.LikeItemResponse"
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.AddItemToCollectionResponse"
                         }
                     },
                     "400": {
@@ -928,6 +1137,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -942,12 +1157,19 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Deletes the like record for the current user on an item.",
+                "description": "Removes the specified item from the collection.",
                 "tags": [
-                    "Items"
+                    "Collections"
                 ],
-                "summary": "Unlike item",
+                "summary": "Remove item from collection",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Item UUID",
@@ -966,6 +1188,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -975,23 +1203,46 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/user": {
+        "/api/collections/{collectionID}/items": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves currently logged in user.",
+                "description": "Retrieves items in the collection, including like status. Items of smart collections are matched against their rules.",
                 "tags": [
-                    "Users"
+                    "Collections"
+                ],
+                "summary": "List items in collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of items",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "summary": "Get user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.User"
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListCollectionItemsResponse"
                         }
                     },
                     "400": {
@@ -1014,314 +1265,5635 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_rhajizada_gazette_internal_service.AddItemToCollectionResponse": {
-            "type": "object",
-            "properties": {
-                "added_at": {
-                    "type": "string"
+        },
+        "/api/collections/{collectionID}/items/order": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the manual order of items in the collection to the order of the given IDs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Reorder collection items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Item IDs in order",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ReorderCollectionItemsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
                 }
             }
         },
-        "github_com_rhajizada_gazette_internal_service.Collection": {
-            "type": "object",
-            "properties": {
+        "/api/collections/{collectionID}/members": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the members of a collection the user owns or is a member of, and the emails with pending invitations to it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "List collection members",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListCollectionMembersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Invites an email to a collection of the user as viewer or editor. The collection is shared with the user whose sign-in provider verified the email once they accept. Emails are invited whether or not a user has them, the response is the same either way. Inviting an email again changes the role of its invitation, and of its member once accepted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Invite collection member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Invitation",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.InviteCollectionMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.CollectionMemberInvitation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/collections/{collectionID}/members/{userID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a member from a collection of the user.",
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Remove collection member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Member UUID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the role of a member of a collection of the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Update collection member",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Member UUID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.UpdateCollectionMemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.CollectionMember"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/collections/{collectionID}/membership": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accepts an invitation of the user to a collection, which is then listed with their own collections.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Accept collection invitation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the user from a collection shared with them, or declines a pending invitation to it.",
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Leave collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/collections/{collectionID}/rules": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the rules of a smart collection. Its items are then matched against the rules when listed instead of being added manually.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Set collection rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Collection rules",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.CollectionRules"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the rules of a smart collection. Items added manually before are listed again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Delete collection rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/collections/{collectionID}/slug": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generates a new slug for the collection. The previous public URL and feeds stop working.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collections"
+                ],
+                "summary": "Rotate collection slug",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection UUID",
+                        "name": "collectionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Collection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/feeds": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns all feeds or only those the user is subscribed to. With tag, returns only the subscribed feeds with that tag of the user.",
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "List feeds",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only subscribed feeds",
+                        "name": "subscribedOnly",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only subscribed feeds with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of feeds to return",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of feeds to skip",
+                        "name": "offset",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListFeedsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a new feed from URL or subscribes the user to it.",
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Create or subscribe feed",
+                "parameters": [
+                    {
+                        "description": "Create feed payload",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CreateFeedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Feed"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/feeds/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a CSV list of all feeds, or only those the user is subscribed to.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Export feeds",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only subscribed feeds",
+                        "name": "subscribedOnly",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of feeds",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/feeds/folders": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the subscribed feeds of the user grouped by the folder set on their subscription, or else by their tags, which act as folders. A feed with several tags is listed in each folder, feeds without folder or tags are unfiled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "List feed folders",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListFeedFoldersResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/feeds/{feedID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a feed by ID, including the user’s subscription status.",
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Get feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed UUID",
+                        "name": "feedID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Feed"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a feed and all its subscriptions/items. Requires the admin role.",
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Delete feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed UUID",
+                        "name": "feedID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/feeds/{feedID}/items": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves feed items, newest first or oldest first as set on the subscription of the user.",
+                "tags": [
+                    "Items"
+                ],
+                "summary": "List feed items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed UUID",
+                        "name": "feedID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of items",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListItemsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/feeds/{feedID}/refresh": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queues an immediate sync of a feed. Refreshing a feed whose sync is already queued or running returns that task instead of queueing another one.",
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Refresh feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed UUID",
+                        "name": "feedID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.SyncFeedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/feeds/{feedID}/subscribe": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribes the user to an existing feed.",
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Subscribe to feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed UUID",
+                        "name": "feedID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.SubscibeToFeedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the user’s subscription to a feed.",
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Unsubscribe from feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed UUID",
+                        "name": "feedID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/feeds/{feedID}/subscription": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the settings of the user for a subscribed feed. Omitted fields are left unchanged, an empty folder, title or icon clears it. A folder takes precedence over tags when listing feed folders.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Update subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed UUID",
+                        "name": "feedID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription settings",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.UpdateSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/feeds/{feedID}/sync-status": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reports the outcome of the last sync of a feed, the sync task currently queued or running and when feeds are synced next.",
+                "tags": [
+                    "Feeds"
+                ],
+                "summary": "Get feed sync status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed UUID",
+                        "name": "feedID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.SyncStatusResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/feeds/{feedID}/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the tags of the user on a subscribed feed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "List feed tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed UUID",
+                        "name": "feedID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.TagsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the tags of the user on a subscribed feed, creating tags that do not exist yet. Feed tags act as folders.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Set feed tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed UUID",
+                        "name": "feedID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tags",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.SetTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.TagsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/filters": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the filter rules of the user with their hit counters, oldest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Filters"
+                ],
+                "summary": "List filter rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListFilterRulesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a keyword or regex rule run on the items of subscribed feeds as they are synced, hiding them, marking them read or adding them to a manual collection. A hide rule also hides the recent items it matches.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Filters"
+                ],
+                "summary": "Create filter rule",
+                "parameters": [
+                    {
+                        "description": "Filter rule",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.FilterRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.FilterRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/filters/preview": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Matches a filter rule against the most recent items of subscribed feeds without saving it or running its action.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Filters"
+                ],
+                "summary": "Preview filter rule",
+                "parameters": [
+                    {
+                        "description": "Filter rule",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.FilterRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.PreviewFilterRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/filters/{filterID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a filter rule of the user with its hit counter.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Filters"
+                ],
+                "summary": "Get filter rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter rule UUID",
+                        "name": "filterID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.FilterRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the definition of a filter rule of the user. Items the rule hid are shown again and, for an enabled hide rule, the recent items it now matches hidden.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Filters"
+                ],
+                "summary": "Update filter rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter rule UUID",
+                        "name": "filterID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Filter rule",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.FilterRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.FilterRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a filter rule of the user and shows the items it hid again.",
+                "tags": [
+                    "Filters"
+                ],
+                "summary": "Delete filter rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter rule UUID",
+                        "name": "filterID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/items": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves items liked by the user, paginated.",
+                "tags": [
+                    "Items"
+                ],
+                "summary": "List liked items",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Max number of items",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListItemsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/items/{itemID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves an item by ID, including like status for the user.",
+                "tags": [
+                    "Items"
+                ],
+                "summary": "Get item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item UUID",
+                        "name": "itemID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Item"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/items/{itemID}/annotations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the notes and highlights of the user on an item in reading order, notes without a highlight last.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Annotations"
+                ],
+                "summary": "List item annotations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item UUID",
+                        "name": "itemID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListItemAnnotationsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a note or a highlight of the user to an item. A highlight is anchored to the text of the sanitized item content by quote, by start_offset and end_offset in characters, or by both. The missing anchor is filled in, and the quote must match the text at the offsets when both are given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Annotations"
+                ],
+                "summary": "Create item annotation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item UUID",
+                        "name": "itemID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Annotation",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CreateAnnotationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Annotation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/items/{itemID}/annotations/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renders the highlights and notes of the user on an item as a Markdown document.",
+                "produces": [
+                    "text/markdown"
+                ],
+                "tags": [
+                    "Annotations"
+                ],
+                "summary": "Export item annotations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item UUID",
+                        "name": "itemID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exported annotations",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/items/{itemID}/annotations/{annotationID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a note or highlight of the user on an item.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Annotations"
+                ],
+                "summary": "Get item annotation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item UUID",
+                        "name": "itemID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Annotation UUID",
+                        "name": "annotationID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Annotation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a note or highlight of the user on an item.",
+                "tags": [
+                    "Annotations"
+                ],
+                "summary": "Delete item annotation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item UUID",
+                        "name": "itemID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Annotation UUID",
+                        "name": "annotationID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the note of an annotation of the user. An empty note removes the note of a highlight. Highlights cannot be moved, delete and recreate them instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Annotations"
+                ],
+                "summary": "Update item annotation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item UUID",
+                        "name": "itemID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Annotation UUID",
+                        "name": "annotationID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.UpdateAnnotationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Annotation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/items/{itemID}/collections": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves list of collections that given item is in.",
+                "tags": [
+                    "Items"
+                ],
+                "summary": "Get collections item is in.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item UUID",
+                        "name": "itemID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of items",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListCollectionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/items/{itemID}/like": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a like record for the current user on an item.",
+                "tags": [
+                    "Items"
+                ],
+                "summary": "Like item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item UUID",
+                        "name": "itemID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.LikeItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes the like record for the current user on an item.",
+                "tags": [
+                    "Items"
+                ],
+                "summary": "Unlike item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item UUID",
+                        "name": "itemID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/items/{itemID}/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the tags of the user on an item.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "List item tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item UUID",
+                        "name": "itemID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.TagsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the tags of the user on an item, creating tags that do not exist yet.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Set item tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Item UUID",
+                        "name": "itemID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tags",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.SetTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.TagsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the tags of the user, most used first. With q, lists only tags starting with q, to complete tag names.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "List tags",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag name prefix",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of tags",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListTagsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tags/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds and removes tags of the user on many items and subscribed feeds at once, creating tags that do not exist yet. Items that do not exist and feeds the user is not subscribed to are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Bulk tag",
+                "parameters": [
+                    {
+                        "description": "Tags and targets",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.BulkTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.BulkTagResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tags/{tagID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a tag of the user and removes it from all items and feeds.",
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Delete tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag UUID",
+                        "name": "tagID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renames a tag of the user. Renaming a tag to the name of another tag merges it into the other tag, which is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Rename tag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag UUID",
+                        "name": "tagID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New name",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.RenameTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tags/{tagID}/items": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the items with a tag of the user and the items of subscribed feeds with the tag, newest first, paginated.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "List tagged items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag UUID",
+                        "name": "tagID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of items",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListItemsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves currently logged in user.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/app-passwords": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves app passwords used by third-party reader clients.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List app passwords",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListAppPasswordsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generates an app password for third-party reader clients. The password is only returned once.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create app password",
+                "parameters": [
+                    {
+                        "description": "App password name",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CreateAppPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.CreateAppPasswordResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/app-passwords/{appPasswordID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes an app password of the current user.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete app password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "App password UUID",
+                        "name": "appPasswordID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/fever": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves whether the Fever API is enabled for the current user.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get Fever API access",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.FeverCredential"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enables the Fever API. Clients log in with the user's email and this password.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Set Fever API password",
+                "parameters": [
+                    {
+                        "description": "Fever password",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.SetFeverPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.FeverCredential"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Disables the Fever API for the current user.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete Fever API password",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/identities": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists provider identities the current user can sign in with.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List identities",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListUserIdentitiesResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts a login at the provider, the identity is linked to the current user once the login completes. The returned URL must be opened in the browser that made this request.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Link identity",
+                "parameters": [
+                    {
+                        "description": "Provider to link",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.LinkIdentityRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.LinkIdentityResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/identities/{identityID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a provider identity from the current user, the last identity can not be removed.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Unlink identity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identity UUID",
+                        "name": "identityID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves active login sessions of the current user, the session making the request is marked as current.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List sessions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListSessionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes every login session of the current user except the one making the request.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Log out other sessions",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/sessions/{sessionID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes a login session of the current user.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Log out session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session UUID",
+                        "name": "sessionID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/tokens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves personal access tokens of the current user.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List personal access tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListAccessTokensResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generates a personal access token with the given scopes (read, write, admin) and optional expiry. The token is only returned once.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create personal access token",
+                "parameters": [
+                    {
+                        "description": "Token name, scopes and expiry",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CreateAccessTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.CreateAccessTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/tokens/{tokenID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes a personal access token of the current user.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete personal access token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token UUID",
+                        "name": "tokenID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the webhooks of the user with their failure counters, oldest first. Secrets are not returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListWebhooksResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates a webhook posting signed events when new items arrive in feeds or collections of the user or match their filter rules. URLs must resolve to public addresses unless the instance allows private ones. Deliveries carry an X-Gazette-Signature header, sha256= followed by the hex HMAC-SHA256 of the X-Gazette-Timestamp header, a dot and the body, keyed by the secret. The secret is only returned here and when replaced.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Create webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks/{webhookID}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a webhook of the user without its secret.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook UUID",
+                        "name": "webhookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the definition of a webhook of the user. Enabling a webhook disabled after failed deliveries resets its failures. The secret is returned when replaced.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Update webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook UUID",
+                        "name": "webhookID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Deletes a webhook of the user with its delivery log. Queued deliveries are dropped.",
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook UUID",
+                        "name": "webhookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks/{webhookID}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the deliveries of a webhook of the user with their status, attempts and last response, newest first, paginated. Deliveries are kept for 30 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook UUID",
+                        "name": "webhookID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of deliveries",
+                        "name": "limit",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of deliveries to skip",
+                        "name": "offset",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.ListWebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhooks/{webhookID}/test": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queues a ping event to a webhook of the user, delivered, retried and logged like other events. Its outcome is listed in the delivery log.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Test webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook UUID",
+                        "name": "webhookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.WebhookDelivery"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/logout": {
+            "post": {
+                "description": "Revokes the current session and returns the URL that ends the session at the identity provider.",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Log out",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.LogoutResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/providers": {
+            "get": {
+                "description": "Lists the identity providers users can sign in with, the first one is the default.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "List login providers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ListProvidersResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/oauth/refresh": {
+            "post": {
+                "description": "Exchanges a refresh token, from the session cookie or request body, for a new access token. The refresh token is rotated on every use and reusing an old one revokes the session.",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Refresh access token",
+                "parameters": [
+                    {
+                        "description": "Refresh token for non-browser clients",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/collections/{slug}": {
+            "get": {
+                "description": "Retrieves a published collection and its items by slug without authentication. limit and offset are optional and default to the first 100 items.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Get public collection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max number of items",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.PublicCollectionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/collections/{slug}/atom.xml": {
+            "get": {
+                "description": "Returns the latest items of a published collection as an Atom feed.",
+                "produces": [
+                    "application/atom+xml"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Public collection Atom feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Atom feed",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/collections/{slug}/feed.json": {
+            "get": {
+                "description": "Returns the latest items of a published collection as a JSON Feed.",
+                "produces": [
+                    "application/feed+json"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Public collection JSON Feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "JSON Feed",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/collections/{slug}/rss.xml": {
+            "get": {
+                "description": "Returns the latest items of a published collection as an RSS 2.0 feed.",
+                "produces": [
+                    "application/rss+xml"
+                ],
+                "tags": [
+                    "Public"
+                ],
+                "summary": "Public collection RSS feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Collection slug",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "RSS feed",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_rhajizada_gazette_internal_service.AccessToken": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.AddItemToCollectionResponse": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.AdminFeed": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "feed_link": {
+                    "type": "string"
+                },
+                "health": {
+                    "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.FeedHealth"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "integer"
+                },
+                "last_updated_at": {
+                    "type": "string"
+                },
+                "subscribers": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.AdminUser": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_updated_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "stats": {
+                    "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.UserStats"
+                },
+                "sub": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.AnnotatedItem": {
+            "type": "object",
+            "properties": {
+                "feed_id": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.Annotation": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "end_offset": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "item": {
+                    "description": "Item is set when listing annotations across items.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.AnnotatedItem"
+                        }
+                    ]
+                },
+                "item_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "quote": {
+                    "type": "string"
+                },
+                "start_offset": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.AppPassword": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.BulkTagResponse": {
+            "type": "object",
+            "properties": {
+                "tagged": {
+                    "type": "integer"
+                },
+                "untagged": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.Collection": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_updated": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "string"
+                },
+                "pinned": {
+                    "type": "boolean"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "public": {
+                    "type": "boolean"
+                },
+                "role": {
+                    "description": "Role is the role of the user in the collection, owner, editor or\nviewer.",
+                    "type": "string"
+                },
+                "rules": {
+                    "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.CollectionRules"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "smart": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.CollectionInvitation": {
+            "type": "object",
+            "properties": {
+                "collection_id": {
+                    "type": "string"
+                },
+                "collection_name": {
+                    "type": "string"
+                },
+                "invited_at": {
+                    "type": "string"
+                },
+                "invited_by": {
+                    "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.UserSummary"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.CollectionMember": {
+            "type": "object",
+            "properties": {
+                "accepted_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "invited_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.CollectionMemberInvitation": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "invited_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.CollectionRules": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "description": "Author is matched against the names of the authors.",
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "feed_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "keywords": {
+                    "description": "Keywords are matched against the title, description and content.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "liked": {
+                    "type": "boolean"
+                },
+                "min_similarity": {
+                    "description": "MinSimilarity is the cosine similarity to SimilarTo items need, between\n0 and 1. Defaults to 0.5.",
+                    "type": "number"
+                },
+                "published_after": {
+                    "type": "string"
+                },
+                "published_before": {
+                    "type": "string"
+                },
+                "similar_to": {
+                    "description": "SimilarTo is a seed text items are semantically compared to. Items\nmatch once the text has been embedded.",
+                    "type": "string"
+                },
+                "unread": {
+                    "type": "boolean"
+                },
+                "within_days": {
+                    "description": "WithinDays limits items to those published in the last days.",
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.CreateAccessTokenResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.CreateAppPasswordResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.Feed": {
+            "type": "object",
+            "properties": {
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Person"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "copyright": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "feed_link": {
+                    "type": "string"
+                },
+                "feed_type": {
+                    "type": "string"
+                },
+                "feed_version": {
+                    "type": "string"
+                },
+                "generator": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {},
+                "language": {
+                    "type": "string"
+                },
+                "last_updated_at": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "published_parsed": {
+                    "type": "string"
+                },
+                "subscribed": {
+                    "type": "boolean"
+                },
+                "subscribed_at": {
+                    "type": "string"
+                },
+                "subscription": {
+                    "description": "Subscription holds the settings of the user for the feed, set when\nsubscribed.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Subscription"
+                        }
+                    ]
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_parsed": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.FeedFolder": {
+            "type": "object",
+            "properties": {
+                "feeds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.FolderFeed"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "tag_id": {
+                    "description": "TagID is set when the folder is a tag.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.FeedHealth": {
+            "type": "object",
+            "properties": {
+                "failure_count": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_failure_at": {
+                    "type": "string"
+                },
+                "last_success_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.FeverCredential": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.FilterRule": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is hide, mark_read or add_to_collection.",
+                    "type": "string"
+                },
+                "collection_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "feed_id": {
+                    "description": "FeedID limits the rule to a feed, it applies to all subscriptions\notherwise.",
+                    "type": "string"
+                },
+                "field": {
+                    "description": "Field is any, title, content, author, category or link.",
+                    "type": "string"
+                },
+                "hit_count": {
+                    "description": "HitCount counts the items the rule matched as feeds were synced.",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_hit_at": {
+                    "type": "string"
+                },
+                "match_type": {
+                    "description": "MatchType is keyword or regex.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.FilterRuleMatch": {
+            "type": "object",
+            "properties": {
+                "feed_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "published_parsed": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.FolderFeed": {
+            "type": "object",
+            "properties": {
+                "feed_link": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.Item": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "description": "AddedAt and AddedBy are set when listing the items of a collection.",
+                    "type": "string"
+                },
+                "added_by": {
+                    "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.UserSummary"
+                },
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Person"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enclosures": {},
+                "feed_id": {
+                    "type": "string"
+                },
+                "guid": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image": {},
+                "liked": {
+                    "type": "boolean"
+                },
+                "liked_at": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "links": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "published_parsed": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Tags are the tags of the user on the item, set for single items.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_parsed": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.LikeItemResponse": {
+            "type": "object",
+            "properties": {
+                "liked_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListAccessTokensResponse": {
+            "type": "object",
+            "properties": {
+                "access_tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.AccessToken"
+                    }
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListAdminFeedsResponse": {
+            "type": "object",
+            "properties": {
+                "feeds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.AdminFeed"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListAdminUsersResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total_count": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.AdminUser"
+                    }
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListAnnotationsResponse": {
+            "type": "object",
+            "properties": {
+                "annotations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Annotation"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListAppPasswordsResponse": {
+            "type": "object",
+            "properties": {
+                "app_passwords": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.AppPassword"
+                    }
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListCollectionInvitationsResponse": {
+            "type": "object",
+            "properties": {
+                "invitations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.CollectionInvitation"
+                    }
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListCollectionItemsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Item"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListCollectionMembersResponse": {
+            "type": "object",
+            "properties": {
+                "invitations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.CollectionMemberInvitation"
+                    }
+                },
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.CollectionMember"
+                    }
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListCollectionsResponse": {
+            "type": "object",
+            "properties": {
+                "collections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Collection"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListFeedFoldersResponse": {
+            "type": "object",
+            "properties": {
+                "folders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.FeedFolder"
+                    }
+                },
+                "unfiled": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.FolderFeed"
+                    }
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListFeedsResponse": {
+            "type": "object",
+            "properties": {
+                "feeds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Feed"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListFilterRulesResponse": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.FilterRule"
+                    }
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListItemAnnotationsResponse": {
+            "type": "object",
+            "properties": {
+                "annotations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Annotation"
+                    }
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListItemsResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Item"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListSessionsResponse": {
+            "type": "object",
+            "properties": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Session"
+                    }
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListTagsResponse": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Tag"
+                    }
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListUserIdentitiesResponse": {
+            "type": "object",
+            "properties": {
+                "identities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.UserIdentity"
+                    }
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListWebhookDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.WebhookDelivery"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.ListWebhooksResponse": {
+            "type": "object",
+            "properties": {
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Webhook"
+                    }
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.MergeFeedsResponse": {
+            "type": "object",
+            "properties": {
+                "moved_items": {
+                    "type": "integer"
+                },
+                "target_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.Person": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "example: jane@example.com",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "example: Jane Doe",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.PreviewFilterRuleResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.FilterRuleMatch"
+                    }
+                },
+                "matched": {
+                    "type": "integer"
+                },
+                "scanned": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.PublicItem": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string"
+                },
+                "authors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Person"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "content": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "published_parsed": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_parsed": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.PurgeItemsResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.Session": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "device": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.SubscibeToFeedResponse": {
+            "type": "object",
+            "properties": {
+                "subscribed_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.Subscription": {
+            "type": "object",
+            "properties": {
+                "folder": {
+                    "description": "Folder files the feed in a folder, taking precedence over its tags.",
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "muted": {
+                    "description": "Muted leaves items of the feed out of the reading list and tag\ntimelines.",
+                    "type": "boolean"
+                },
+                "notify": {
+                    "description": "Notify is none or all, none leaves new items of the feed out of\nfeed.item webhooks.",
+                    "type": "string"
+                },
+                "sort_order": {
+                    "description": "SortOrder is newest or oldest, the order items of the feed are listed.",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "Title and Icon override those of the feed for the user.",
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.SyncFeedResponse": {
+            "type": "object",
+            "properties": {
+                "deduplicated": {
+                    "type": "boolean"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.SyncStatusResponse": {
+            "type": "object",
+            "properties": {
+                "failure_count": {
+                    "type": "integer"
+                },
+                "feed_id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_failure_at": {
+                    "type": "string"
+                },
+                "last_success_at": {
+                    "type": "string"
+                },
+                "next_sync_at": {
+                    "type": "string"
+                },
+                "task": {
+                    "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.SyncTask"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.SyncTask": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "max_retry": {
+                    "type": "integer"
+                },
+                "next_process_at": {
+                    "type": "string"
+                },
+                "retried": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.Tag": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "string"
                 },
+                "feed_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "item_count": {
+                    "description": "ItemCount and FeedCount are set when listing tags.",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.TagsResponse": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.User": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "disabledAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
-                "last_updated": {
+                "lastUpdatedAt": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "sub": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_rhajizada_gazette_internal_service.Feed": {
+        "github_com_rhajizada_gazette_internal_service.UserIdentity": {
             "type": "object",
             "properties": {
-                "authors": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "description": "EmailVerified is set when the provider verified Email, collection\ninvitations to it can be accepted.",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issuer": {
+                    "type": "string"
+                },
+                "last_login_at": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.UserStats": {
+            "type": "object",
+            "properties": {
+                "collections": {
+                    "type": "integer"
+                },
+                "feeds": {
+                    "type": "integer"
+                },
+                "likes": {
+                    "type": "integer"
+                },
+                "sessions": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.UserSummary": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.Webhook": {
+            "type": "object",
+            "properties": {
+                "collection_ids": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Person"
+                        "type": "string"
                     }
                 },
-                "categories": {
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "copyright": {
+                "failure_count": {
+                    "type": "integer"
+                },
+                "feed_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_delivery_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rule_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_rhajizada_gazette_internal_service.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempted_at": {
+                    "type": "string"
+                },
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "response_status": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.BulkTagRequest": {
+            "type": "object",
+            "properties": {
+                "add": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "feed_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "item_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "remove": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_handler.CreateAccessTokenRequest": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_handler.CreateAnnotationRequest": {
+            "type": "object",
+            "properties": {
+                "end_offset": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "quote": {
+                    "type": "string"
+                },
+                "start_offset": {
+                    "description": "StartOffset and EndOffset count characters of the text of the sanitized\nitem content.",
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.CreateAppPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.CreateCollectionRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.CreateFeedRequest": {
+            "type": "object",
+            "properties": {
+                "feed_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.FilterRuleRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is hide, mark_read or add_to_collection.",
                     "type": "string"
                 },
-                "created_at": {
+                "collection_id": {
+                    "description": "CollectionID is the manual collection add_to_collection adds items to.",
                     "type": "string"
                 },
-                "description": {
-                    "type": "string"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "feed_link": {
+                "feed_id": {
+                    "description": "FeedID limits the rule to one subscribed feed, all feeds when omitted.",
                     "type": "string"
                 },
-                "feed_type": {
+                "field": {
+                    "description": "Field is any, title, content, author, category or link, any when\nomitted.",
                     "type": "string"
                 },
-                "feed_version": {
+                "match_type": {
+                    "description": "MatchType is keyword or regex.",
                     "type": "string"
                 },
-                "generator": {
+                "name": {
                     "type": "string"
                 },
-                "id": {
+                "pattern": {
                     "type": "string"
-                },
-                "image": {},
-                "language": {
+                }
+            }
+        },
+        "internal_handler.InviteCollectionMemberRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
                     "type": "string"
                 },
-                "last_updated_at": {
+                "role": {
+                    "description": "Role is viewer or editor.",
                     "type": "string"
-                },
-                "link": {
+                }
+            }
+        },
+        "internal_handler.LinkIdentityRequest": {
+            "type": "object",
+            "properties": {
+                "provider": {
                     "type": "string"
-                },
-                "links": {
+                }
+            }
+        },
+        "internal_handler.LinkIdentityResponse": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.ListProvidersResponse": {
+            "type": "object",
+            "properties": {
+                "providers": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/internal_handler.Provider"
                     }
-                },
-                "published_parsed": {
+                }
+            }
+        },
+        "internal_handler.LogoutResponse": {
+            "type": "object",
+            "properties": {
+                "redirect_url": {
                     "type": "string"
-                },
-                "subscribed": {
-                    "type": "boolean"
-                },
-                "subscribed_at": {
+                }
+            }
+        },
+        "internal_handler.MergeFeedsRequest": {
+            "type": "object",
+            "properties": {
+                "target_feed_id": {
                     "type": "string"
-                },
-                "title": {
+                }
+            }
+        },
+        "internal_handler.Provider": {
+            "type": "object",
+            "properties": {
+                "display_name": {
                     "type": "string"
                 },
-                "updated_parsed": {
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "github_com_rhajizada_gazette_internal_service.Item": {
+        "internal_handler.PublicCollectionFeeds": {
             "type": "object",
             "properties": {
-                "authors": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Person"
-                    }
-                },
-                "categories": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "content": {
-                    "type": "string"
-                },
-                "created_at": {
+                "atom": {
                     "type": "string"
                 },
-                "description": {
+                "json_feed": {
                     "type": "string"
                 },
-                "enclosures": {},
-                "feed_id": {
+                "rss": {
                     "type": "string"
-                },
-                "guid": {
+                }
+            }
+        },
+        "internal_handler.PublicCollectionResponse": {
+            "type": "object",
+            "properties": {
+                "color": {
                     "type": "string"
                 },
-                "id": {
+                "description": {
                     "type": "string"
                 },
-                "image": {},
-                "liked": {
-                    "type": "boolean"
-                },
-                "liked_at": {
-                    "type": "string"
+                "feeds": {
+                    "$ref": "#/definitions/internal_handler.PublicCollectionFeeds"
                 },
-                "link": {
+                "icon": {
                     "type": "string"
                 },
-                "links": {
+                "items": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.PublicItem"
                     }
                 },
-                "published_parsed": {
+                "last_updated": {
                     "type": "string"
                 },
-                "title": {
+                "limit": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
                 },
-                "updated_at": {
+                "offset": {
+                    "type": "integer"
+                },
+                "slug": {
                     "type": "string"
                 },
-                "updated_parsed": {
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.RefreshTokenRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
                     "type": "string"
                 }
             }
         },
-        "github_com_rhajizada_gazette_internal_service.LikeItemResponse": {
+        "internal_handler.RenameTagRequest": {
             "type": "object",
             "properties": {
-                "liked_at": {
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "github_com_rhajizada_gazette_internal_service.ListCollectionItemsResponse": {
+        "internal_handler.ReorderCollectionItemsRequest": {
             "type": "object",
             "properties": {
-                "items": {
+                "item_ids": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Item"
+                        "type": "string"
                     }
-                },
-                "limit": {
-                    "type": "integer"
-                },
-                "offset": {
-                    "type": "integer"
-                },
-                "total_count": {
-                    "type": "integer"
                 }
             }
         },
-        "github_com_rhajizada_gazette_internal_service.ListCollectionsResponse": {
+        "internal_handler.ReorderCollectionsRequest": {
             "type": "object",
             "properties": {
-                "collections": {
+                "collection_ids": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Collection"
+                        "type": "string"
                     }
-                },
-                "limit": {
-                    "type": "integer"
-                },
-                "offset": {
-                    "type": "integer"
-                },
-                "total_count": {
-                    "type": "integer"
                 }
             }
         },
-        "github_com_rhajizada_gazette_internal_service.ListFeedsResponse": {
+        "internal_handler.SetFeverPasswordRequest": {
             "type": "object",
             "properties": {
-                "feeds": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Feed"
-                    }
-                },
-                "limit": {
-                    "type": "integer"
-                },
-                "offset": {
-                    "type": "integer"
-                },
-                "total_count": {
-                    "type": "integer"
+                "password": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_rhajizada_gazette_internal_service.ListItemsResponse": {
+        "internal_handler.SetTagsRequest": {
             "type": "object",
             "properties": {
-                "items": {
+                "tags": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/github_com_rhajizada_gazette_internal_service.Item"
+                        "type": "string"
                     }
-                },
-                "limit": {
-                    "type": "integer"
-                },
-                "offset": {
-                    "type": "integer"
-                },
-                "total_count": {
-                    "type": "integer"
                 }
             }
         },
-        "github_com_rhajizada_gazette_internal_service.Person": {
+        "internal_handler.TokenResponse": {
             "type": "object",
             "properties": {
-                "email": {
-                    "description": "example: jane@example.com",
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
                     "type": "string"
                 },
-                "name": {
-                    "description": "example: Jane Doe",
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "github_com_rhajizada_gazette_internal_service.SubscibeToFeedResponse": {
+        "internal_handler.UpdateAnnotationRequest": {
             "type": "object",
             "properties": {
-                "subscribed_at": {
+                "note": {
                     "type": "string"
                 }
             }
         },
-        "github_com_rhajizada_gazette_internal_service.User": {
+        "internal_handler.UpdateCollectionMemberRequest": {
             "type": "object",
             "properties": {
-                "createdAt": {
+                "role": {
+                    "description": "Role is viewer or editor.",
                     "type": "string"
-                },
-                "email": {
+                }
+            }
+        },
+        "internal_handler.UpdateCollectionRequest": {
+            "type": "object",
+            "properties": {
+                "color": {
                     "type": "string"
                 },
-                "id": {
+                "description": {
                     "type": "string"
                 },
-                "lastUpdatedAt": {
+                "icon": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
-                "sub": {
-                    "type": "string"
+                "pinned": {
+                    "type": "boolean"
+                },
+                "public": {
+                    "type": "boolean"
                 }
             }
         },
-        "internal_handler.CreateCollectionRequest": {
+        "internal_handler.UpdateSubscriptionRequest": {
             "type": "object",
             "properties": {
-                "name": {
+                "folder": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "muted": {
+                    "type": "boolean"
+                },
+                "notify": {
+                    "description": "Notify is none or all, none leaves new items of the feed out of\nfeed.item webhooks.",
+                    "type": "string"
+                },
+                "sort_order": {
+                    "description": "SortOrder is newest or oldest.",
+                    "type": "string"
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "internal_handler.CreateFeedRequest": {
+        "internal_handler.WebhookRequest": {
             "type": "object",
             "properties": {
-                "feed_url": {
+                "collection_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "description": "Events are feed.item, collection.item or rule.match.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "feed_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rule_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "description": "Secret signs deliveries. It is generated when omitted on creation and\nkept when omitted on update, an empty secret is generated anew.",
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
                 }
             }
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/caarlos0/env/v11"
@@ -26,19 +29,98 @@ type PostgresConfig struct {
 
 // ServerConfig holds server-related settings.
 type ServerConfig struct {
-	Port      int    `env:"GAZETTE_PORT" envDefault:"8080"`
-	SecretKey string `env:"GAZETTE_SECRET_KEY,notEmpty"`
-	Database  PostgresConfig
-	Redis     RedisConfig
-	OAuth     OAuthConfig
+	Port int `env:"GAZETTE_PORT" envDefault:"8080"`
+	// ShutdownTimeout bounds how long in-flight requests may take to
+	// finish once the server is asked to stop.
+	ShutdownTimeout time.Duration `env:"GAZETTE_SHUTDOWN_TIMEOUT" envDefault:"30s"`
+	SecretKey       string        `env:"GAZETTE_SECRET_KEY,notEmpty"`
+	Database        PostgresConfig
+	Redis           RedisConfig
+	OAuth           OAuthConfig
+	Providers       []OAuthProviderConfig `envPrefix:"GAZETTE_OAUTH_PROVIDERS_"`
+	Session         SessionConfig
+	Roles           RolesConfig
+	Tracing         TracingConfig
+	Logging         LoggingConfig
+	Webhooks        WebhooksConfig
+	// PublicURL is the external base URL of the server, e.g.
+	// https://gazette.example.com, used for links in public collections.
+	// Without it links use the host of the request, forwarded headers are
+	// not trusted since any client can set them.
+	PublicURL string `env:"GAZETTE_PUBLIC_URL"`
 }
 
-// OAuthConfig holds OAuth provider settings.
+// WebhooksConfig holds webhook settings. Webhooks may only target public
+// addresses unless AllowPrivate is set, e.g. for receivers on the same
+// network in trusted deployments. It must be set alike on servers, which
+// validate webhook URLs, and workers, which deliver to them.
+type WebhooksConfig struct {
+	AllowPrivate bool `env:"GAZETTE_WEBHOOKS_ALLOW_PRIVATE" envDefault:"false"`
+}
+
+// LoggingConfig holds log settings. Level is one of debug, info, warn or
+// error and Format is either json or text.
+type LoggingConfig struct {
+	Level  slog.Level `env:"GAZETTE_LOG_LEVEL" envDefault:"info"`
+	Format string     `env:"GAZETTE_LOG_FORMAT" envDefault:"json"`
+}
+
+// TracingConfig enables exporting traces over OTLP/HTTP. Tracing is disabled
+// unless an endpoint is set, the exporter reads the remaining standard
+// OTEL_EXPORTER_OTLP_* variables (headers, timeout, TLS) itself.
+type TracingConfig struct {
+	Endpoint       string `env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	TracesEndpoint string `env:"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"`
+	Disabled       bool   `env:"OTEL_SDK_DISABLED"`
+}
+
+// Enabled reports whether traces should be exported.
+func (c *TracingConfig) Enabled() bool {
+	return !c.Disabled && (c.Endpoint != "" || c.TracesEndpoint != "")
+}
+
+// OAuthConfig holds settings of the single OAuth provider supported by
+// earlier releases, it is used when no named providers are configured.
 type OAuthConfig struct {
-	ClientID     string `env:"GAZETTE_OAUTH_CLIENT_ID,notEmpty"`
-	ClientSecret string `env:"GAZETTE_OAUTH_CLIENT_SECRET,notEmpty"`
-	IssuerURL    string `env:"GAZETTE_OAUTH_ISSUER_URL,notEmpty"`
-	RedirectURL  string `env:"GAZETTE_OAUTH_REDIRECT_URL,notEmpty"`
+	ClientID              string `env:"GAZETTE_OAUTH_CLIENT_ID"`
+	ClientSecret          string `env:"GAZETTE_OAUTH_CLIENT_SECRET"`
+	IssuerURL             string `env:"GAZETTE_OAUTH_ISSUER_URL"`
+	RedirectURL           string `env:"GAZETTE_OAUTH_REDIRECT_URL"`
+	PostLogoutRedirectURL string `env:"GAZETTE_OAUTH_POST_LOGOUT_REDIRECT_URL"`
+}
+
+// OAuthProviderConfig holds settings of a named OAuth provider, loaded from
+// GAZETTE_OAUTH_PROVIDERS_<index>_<setting>.
+type OAuthProviderConfig struct {
+	Name                  string `env:"NAME,notEmpty"`
+	DisplayName           string `env:"DISPLAY_NAME"`
+	ClientID              string `env:"CLIENT_ID,notEmpty"`
+	ClientSecret          string `env:"CLIENT_SECRET,notEmpty"`
+	IssuerURL             string `env:"ISSUER_URL,notEmpty"`
+	RedirectURL           string `env:"REDIRECT_URL,notEmpty"`
+	PostLogoutRedirectURL string `env:"POST_LOGOUT_REDIRECT_URL"`
+}
+
+// DefaultProviderName is the name of the provider configured with the
+// legacy GAZETTE_OAUTH_* settings. Accounts created by earlier releases are
+// only claimed through it, a named provider of the same issuer can take over
+// by using this name.
+const DefaultProviderName = "default"
+
+// SessionConfig holds login session settings.
+type SessionConfig struct {
+	AccessTokenTTL  time.Duration `env:"GAZETTE_ACCESS_TOKEN_TTL" envDefault:"1h"`
+	RefreshTokenTTL time.Duration `env:"GAZETTE_REFRESH_TOKEN_TTL" envDefault:"720h"`
+	SecureCookies   bool          `env:"GAZETTE_SECURE_COOKIES" envDefault:"false"`
+}
+
+// RolesConfig maps provider groups to application roles. Users in an admin
+// group become admins, when member groups are set only users in a member or
+// admin group may sign in.
+type RolesConfig struct {
+	GroupsClaim  string   `env:"GAZETTE_GROUPS_CLAIM" envDefault:"groups"`
+	AdminGroups  []string `env:"GAZETTE_ADMIN_GROUPS" envSeparator:","`
+	MemberGroups []string `env:"GAZETTE_MEMBER_GROUPS" envSeparator:","`
 }
 
 // OllamaConfig holds ollama settings.
@@ -52,6 +134,9 @@ type QueuesConfig struct {
 	Critical int `env:"GAZETTE_CRITICAL_QUEUES_COUNT" envDefault:"4"`
 	Default  int `env:"GAZETTE_DEFAULT_QUEUES_COUNT" envDefault:"2"`
 	Low      int `env:"GAZETTE_LOW_QUEUES_COUNT" envDefault:"2"`
+	// Webhooks is the priority of the queue webhook deliveries go through,
+	// kept apart so slow receivers do not hold up syncs.
+	Webhooks int `env:"GAZETTE_WEBHOOKS_QUEUES_COUNT" envDefault:"2"`
 }
 
 // WorkerConfig holds worker-related settings.
@@ -60,13 +145,23 @@ type WorkerConfig struct {
 	Redis    RedisConfig
 	Ollama   OllamaConfig
 	Queues   QueuesConfig
+	Tracing  TracingConfig
+	Logging  LoggingConfig
+	Webhooks WebhooksConfig
+
+	MetricsPort int `env:"GAZETTE_METRICS_PORT" envDefault:"9090"`
+	// ShutdownTimeout bounds how long in-flight tasks may take to finish
+	// once the worker is asked to stop, unfinished tasks are requeued.
+	ShutdownTimeout time.Duration `env:"GAZETTE_SHUTDOWN_TIMEOUT" envDefault:"30s"`
 }
 
 // SchedulerConfig holds scheduler-related settings.
 type SchedulerConfig struct {
 	Redis             RedisConfig
+	Logging           LoggingConfig
 	HeartbeatInterval time.Duration  `env:"GAZETTE_HEARTBEAT_INTERVAL" envDefault:"30s"`
 	Location          *time.Location `env:"GAZETTE_LOCATION" envDefault:"UTC"`
+	MetricsPort       int            `env:"GAZETTE_METRICS_PORT" envDefault:"9091"`
 }
 
 // LoadServer populates ServerConfig from environment variables prefixed with GAZETTE_.
@@ -75,6 +170,29 @@ func LoadServer() (*ServerConfig, error) {
 	if err := env.Parse(&cfg); err != nil {
 		return nil, err
 	}
+
+	if len(cfg.Providers) == 0 {
+		legacy := cfg.OAuth
+		if legacy.ClientID == "" || legacy.ClientSecret == "" || legacy.IssuerURL == "" || legacy.RedirectURL == "" {
+			return nil, errors.New("no OAuth provider configured")
+		}
+		cfg.Providers = []OAuthProviderConfig{{
+			Name:                  DefaultProviderName,
+			ClientID:              legacy.ClientID,
+			ClientSecret:          legacy.ClientSecret,
+			IssuerURL:             legacy.IssuerURL,
+			RedirectURL:           legacy.RedirectURL,
+			PostLogoutRedirectURL: legacy.PostLogoutRedirectURL,
+		}}
+	}
+
+	names := make(map[string]bool, len(cfg.Providers))
+	for _, p := range cfg.Providers {
+		if names[p.Name] {
+			return nil, fmt.Errorf("duplicate OAuth provider %s", p.Name)
+		}
+		names[p.Name] = true
+	}
 	return &cfg, nil
 }
 
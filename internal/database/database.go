@@ -2,10 +2,13 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 
+	"github.com/exaring/otelpgx"
 	"github.com/hibiken/asynq"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pressly/goose/v3"
 	"github.com/rhajizada/gazette/internal/config"
 )
 
@@ -13,7 +16,13 @@ import (
 func CreatePool(cfg *config.PostgresConfig) (*pgxpool.Pool, error) {
 	connStr := fmt.Sprintf("postgres://%s:%s@%s:%d/%s?sslmode=%s",
 		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName, cfg.SSLMode)
-	pool, err := pgxpool.New(context.Background(), connStr)
+	poolCfg, err := pgxpool.ParseConfig(connStr)
+	if err != nil {
+		return nil, err
+	}
+	poolCfg.ConnConfig.Tracer = otelpgx.NewTracer()
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
 	if err != nil {
 		return nil, err
 	}
@@ -24,6 +33,27 @@ func CreatePool(cfg *config.PostgresConfig) (*pgxpool.Pool, error) {
 	return pool, nil
 }
 
+// CheckMigrations verifies the schema of db is at the latest migration found
+// in dir.
+func CheckMigrations(ctx context.Context, db *sql.DB, dir string) error {
+	migrations, err := goose.CollectMigrations(dir, 0, goose.MaxVersion)
+	if err != nil {
+		return err
+	}
+	latest, err := migrations.Last()
+	if err != nil {
+		return err
+	}
+	current, err := goose.GetDBVersionContext(ctx, db)
+	if err != nil {
+		return err
+	}
+	if current < latest.Version {
+		return fmt.Errorf("database is at migration %d, expected %d", current, latest.Version)
+	}
+	return nil
+}
+
 // CreateRedisClient create Redis Client connection for asynq
 func CreateRedisClient(cfg *config.RedisConfig) *asynq.RedisClientOpt {
 	conn := asynq.RedisClientOpt{
@@ -0,0 +1,170 @@
+package export
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/microcosm-cc/bluemonday"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	// maxImageSize bounds the size of an inlined image.
+	maxImageSize = 5 << 20
+
+	// imageTimeout bounds the time spent fetching an inlined image.
+	imageTimeout = 15 * time.Second
+)
+
+// policy strips scripts, styles, frames and event handlers from content.
+var policy = bluemonday.UGCPolicy()
+
+// parseContent sanitizes the content of an item and parses it. Relative links
+// and image sources are resolved against the link of the item.
+func parseContent(item Item) ([]*html.Node, error) {
+	clean := policy.Sanitize(item.Content)
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(clean), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse content: %w", err)
+	}
+
+	base, err := url.Parse(item.Link)
+	if err != nil || !base.IsAbs() {
+		base = nil
+	}
+	for _, n := range nodes {
+		walk(n, func(n *html.Node) {
+			switch n.DataAtom {
+			case atom.A:
+				resolveAttr(n, "href", base)
+			case atom.Img:
+				resolveAttr(n, "src", base)
+			}
+		})
+	}
+	return nodes, nil
+}
+
+// walk calls fn for n and all of its descendants.
+func walk(n *html.Node, fn func(*html.Node)) {
+	fn(n)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, fn)
+	}
+}
+
+func resolveAttr(n *html.Node, key string, base *url.URL) {
+	if base == nil {
+		return
+	}
+	for i, a := range n.Attr {
+		if a.Key != key {
+			continue
+		}
+		if ref, err := url.Parse(a.Val); err == nil {
+			n.Attr[i].Val = base.ResolveReference(ref).String()
+		}
+	}
+}
+
+// rewriteImages replaces the source of every image in nodes with the result
+// of replace.
+func rewriteImages(nodes []*html.Node, replace func(src string) string) {
+	for _, n := range nodes {
+		walk(n, func(n *html.Node) {
+			if n.DataAtom != atom.Img {
+				return
+			}
+			for i, a := range n.Attr {
+				if a.Key == "src" {
+					n.Attr[i].Val = replace(a.Val)
+				}
+			}
+		})
+	}
+}
+
+// image is a fetched image.
+type image struct {
+	data      []byte
+	mediaType string
+}
+
+// dataURI returns img as a data URI.
+func (img *image) dataURI() string {
+	return "data:" + img.mediaType + ";base64," + base64.StdEncoding.EncodeToString(img.data)
+}
+
+// fetchImage downloads the image at src. Responses that are not images or
+// larger than maxImageSize are rejected.
+func fetchImage(ctx context.Context, client *http.Client, src string) (*image, error) {
+	u, err := url.Parse(src)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("unsupported image source %q", src)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, imageTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageSize {
+		return nil, fmt.Errorf("image is larger than %d bytes", maxImageSize)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mediaType, "image/") {
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(data))
+	}
+	if !strings.HasPrefix(mediaType, "image/") {
+		return nil, fmt.Errorf("%s is not an image", mediaType)
+	}
+	return &image{data: data, mediaType: mediaType}, nil
+}
+
+// inlineDataURIs replaces the images in nodes with data URIs. Images that
+// cannot be fetched keep their source.
+func inlineDataURIs(ctx context.Context, client *http.Client, nodes []*html.Node) {
+	rewriteImages(nodes, func(src string) string {
+		img, err := fetchImage(ctx, client, src)
+		if err != nil {
+			slog.DebugContext(ctx, "failed to inline image", slog.String("src", src), slog.Any("error", err))
+			return src
+		}
+		return img.dataURI()
+	})
+}
+
+// renderHTML renders nodes as HTML.
+func renderHTML(w io.Writer, nodes []*html.Node) error {
+	for _, n := range nodes {
+		if err := html.Render(w, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
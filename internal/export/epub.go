@@ -0,0 +1,314 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"hash/crc32"
+	"html"
+	"io"
+	"log/slog"
+	"strings"
+
+	nethtml "golang.org/x/net/html"
+)
+
+const epubMimetype = "application/epub+zip"
+
+const epubContainer = `<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+`
+
+const epubStyle = `body{font-family:serif;line-height:1.5}img{max-width:100%}.byline{font-style:italic}`
+
+// epubImageExtensions maps the media types of inlined images to file
+// extensions.
+var epubImageExtensions = map[string]string{
+	"image/jpeg":    "jpg",
+	"image/png":     "png",
+	"image/gif":     "gif",
+	"image/webp":    "webp",
+	"image/svg+xml": "svg",
+}
+
+// voidElements are written as self-closing tags in XHTML.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// epubResource is an entry of the package manifest.
+type epubResource struct {
+	id         string
+	href       string
+	mediaType  string
+	properties string
+	title      string
+}
+
+// epubWriter writes an EPUB 3 book with a chapter per item. Chapters and
+// images are written as they come, the package document and table of contents
+// that list them are written on Close.
+type epubWriter struct {
+	zw         *zip.Writer
+	collection Collection
+	opts       Options
+	chapters   []epubResource
+	images     []epubResource
+	// inlined maps image sources to the path of the image in the book.
+	inlined map[string]string
+}
+
+func newEPUBWriter(w io.Writer, c Collection, opts Options) (*epubWriter, error) {
+	zw := zip.NewWriter(w)
+
+	// the mimetype must come first and be stored uncompressed, without a
+	// data descriptor
+	mt, err := zw.CreateRaw(&zip.FileHeader{
+		Name:               "mimetype",
+		Method:             zip.Store,
+		CRC32:              crc32.ChecksumIEEE([]byte(epubMimetype)),
+		CompressedSize64:   uint64(len(epubMimetype)),
+		UncompressedSize64: uint64(len(epubMimetype)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(mt, epubMimetype); err != nil {
+		return nil, err
+	}
+
+	e := &epubWriter{zw: zw, collection: c, opts: opts, inlined: make(map[string]string)}
+	if err := e.writeFile("META-INF/container.xml", epubContainer); err != nil {
+		return nil, err
+	}
+	if err := e.writeFile("OEBPS/style.css", epubStyle); err != nil {
+		return nil, err
+	}
+
+	var page strings.Builder
+	fmt.Fprintf(&page, "<h1>%s</h1>\n", html.EscapeString(c.Title))
+	if c.Description != "" {
+		fmt.Fprintf(&page, "<p>%s</p>\n", html.EscapeString(c.Description))
+	}
+	if err := e.writeChapter("title", c.Title, page.String(), ""); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *epubWriter) writeFile(name, content string) error {
+	f, err := e.zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, content)
+	return err
+}
+
+// writeChapter writes an XHTML document with body to the book and adds it to
+// the spine.
+func (e *epubWriter) writeChapter(id, title, body, properties string) error {
+	href := "text/" + id + ".xhtml"
+	err := e.writeFile("OEBPS/"+href, fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head>
+<title>%s</title>
+<link rel="stylesheet" type="text/css" href="../style.css"/>
+</head>
+<body>
+%s</body>
+</html>
+`, html.EscapeString(title), body))
+	if err != nil {
+		return err
+	}
+	e.chapters = append(e.chapters, epubResource{
+		id:         id,
+		href:       href,
+		mediaType:  "application/xhtml+xml",
+		properties: properties,
+		title:      title,
+	})
+	return nil
+}
+
+// inlineImage adds the image at src to the book and returns its path relative
+// to the chapters. Images that cannot be fetched keep their source.
+func (e *epubWriter) inlineImage(ctx context.Context, src string) string {
+	if href, ok := e.inlined[src]; ok {
+		return "../" + href
+	}
+	img, err := fetchImage(ctx, e.opts.Client, src)
+	if err == nil && epubImageExtensions[img.mediaType] == "" {
+		err = fmt.Errorf("%s images are not supported in EPUB", img.mediaType)
+	}
+	if err != nil {
+		slog.DebugContext(ctx, "failed to inline image", slog.String("src", src), slog.Any("error", err))
+		return src
+	}
+
+	id := fmt.Sprintf("image-%04d", len(e.images)+1)
+	href := "images/" + id + "." + epubImageExtensions[img.mediaType]
+	f, err := e.zw.CreateHeader(&zip.FileHeader{Name: "OEBPS/" + href, Method: zip.Store})
+	if err == nil {
+		_, err = f.Write(img.data)
+	}
+	if err != nil {
+		slog.DebugContext(ctx, "failed to write image", slog.String("src", src), slog.Any("error", err))
+		return src
+	}
+	e.images = append(e.images, epubResource{id: id, href: href, mediaType: img.mediaType})
+	e.inlined[src] = href
+	return "../" + href
+}
+
+func (e *epubWriter) WriteItem(ctx context.Context, item Item) error {
+	nodes, err := parseContent(item)
+	if err != nil {
+		return err
+	}
+	if e.opts.InlineImages {
+		rewriteImages(nodes, func(src string) string {
+			return e.inlineImage(ctx, src)
+		})
+	}
+	remote := false
+	rewriteImages(nodes, func(src string) string {
+		if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+			remote = true
+		}
+		return src
+	})
+
+	var body strings.Builder
+	heading := html.EscapeString(title(item))
+	if item.Link != "" {
+		heading = fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(item.Link), heading)
+	}
+	fmt.Fprintf(&body, "<article>\n<h1>%s</h1>\n", heading)
+	if by := byline(item); by != "" {
+		fmt.Fprintf(&body, "<p class=\"byline\">%s</p>\n", html.EscapeString(by))
+	}
+	for _, n := range nodes {
+		renderXHTML(&body, n)
+	}
+	body.WriteString("\n</article>\n")
+
+	var properties string
+	if remote {
+		properties = "remote-resources"
+	}
+	return e.writeChapter(fmt.Sprintf("item-%04d", len(e.chapters)), title(item), body.String(), properties)
+}
+
+func (e *epubWriter) Close() error {
+	var nav, ncx, manifest, spine strings.Builder
+	for i, ch := range e.chapters {
+		fmt.Fprintf(&nav, "<li><a href=\"%s\">%s</a></li>\n", ch.href, html.EscapeString(ch.title))
+		fmt.Fprintf(&ncx, "<navPoint id=\"%s\" playOrder=\"%d\"><navLabel><text>%s</text></navLabel><content src=\"%s\"/></navPoint>\n",
+			ch.id, i+1, html.EscapeString(ch.title), ch.href)
+		fmt.Fprintf(&spine, "<itemref idref=\"%s\"/>\n", ch.id)
+	}
+	for _, r := range append(e.chapters, e.images...) {
+		if r.properties != "" {
+			fmt.Fprintf(&manifest, "<item id=\"%s\" href=\"%s\" media-type=\"%s\" properties=\"%s\"/>\n", r.id, r.href, r.mediaType, r.properties)
+		} else {
+			fmt.Fprintf(&manifest, "<item id=\"%s\" href=\"%s\" media-type=\"%s\"/>\n", r.id, r.href, r.mediaType)
+		}
+	}
+
+	title := html.EscapeString(e.collection.Title)
+	identifier := "urn:uuid:" + html.EscapeString(e.collection.ID)
+
+	err := e.writeFile("OEBPS/nav.xhtml", fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head><title>%s</title></head>
+<body>
+<nav epub:type="toc">
+<h1>%s</h1>
+<ol>
+%s</ol>
+</nav>
+</body>
+</html>
+`, title, title, nav.String()))
+	if err != nil {
+		return err
+	}
+
+	err = e.writeFile("OEBPS/toc.ncx", fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1">
+<head><meta name="dtb:uid" content="%s"/></head>
+<docTitle><text>%s</text></docTitle>
+<navMap>
+%s</navMap>
+</ncx>
+`, identifier, title, ncx.String()))
+	if err != nil {
+		return err
+	}
+
+	var description string
+	if e.collection.Description != "" {
+		description = fmt.Sprintf("<dc:description>%s</dc:description>\n", html.EscapeString(e.collection.Description))
+	}
+	err = e.writeFile("OEBPS/content.opf", fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">
+<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+<dc:identifier id="uid">%s</dc:identifier>
+<dc:title>%s</dc:title>
+<dc:language>en</dc:language>
+%s<meta property="dcterms:modified">%s</meta>
+</metadata>
+<manifest>
+<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+<item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+<item id="style" href="style.css" media-type="text/css"/>
+%s</manifest>
+<spine toc="ncx">
+%s</spine>
+</package>
+`, identifier, title, description, e.collection.Updated.UTC().Format("2006-01-02T15:04:05Z"), manifest.String(), spine.String()))
+	if err != nil {
+		return err
+	}
+	return e.zw.Close()
+}
+
+// renderXHTML renders n as XHTML, which unlike HTML requires void elements to
+// be closed.
+func renderXHTML(b *strings.Builder, n *nethtml.Node) {
+	switch n.Type {
+	case nethtml.TextNode:
+		b.WriteString(html.EscapeString(n.Data))
+	case nethtml.ElementNode:
+		b.WriteString("<" + n.Data)
+		hasAlt := false
+		for _, a := range n.Attr {
+			if a.Namespace != "" {
+				continue
+			}
+			hasAlt = hasAlt || a.Key == "alt"
+			fmt.Fprintf(b, ` %s="%s"`, a.Key, html.EscapeString(a.Val))
+		}
+		if n.Data == "img" && !hasAlt {
+			b.WriteString(` alt=""`)
+		}
+		if voidElements[n.Data] {
+			b.WriteString("/>")
+			return
+		}
+		b.WriteString(">")
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			renderXHTML(b, c)
+		}
+		b.WriteString("</" + n.Data + ">")
+	}
+}
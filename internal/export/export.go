@@ -0,0 +1,152 @@
+// Package export renders collections as documents for offline reading and
+// the highlights of users as Markdown.
+//
+// Items are written one at a time as they are read, so large collections are
+// streamed rather than buffered. Only the images of the item being written
+// and, for EPUB, the table of contents are kept in memory.
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/rhajizada/gazette/internal/netguard"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Format is a document format collections can be exported to.
+type Format string
+
+const (
+	Markdown Format = "md"
+	HTML     Format = "html"
+	EPUB     Format = "epub"
+)
+
+// ParseFormat parses the name of a format.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case Markdown, HTML, EPUB:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q, expected md, html or epub", s)
+	}
+}
+
+// ContentType returns the media type of documents in the format.
+func (f Format) ContentType() string {
+	switch f {
+	case Markdown:
+		return "text/markdown; charset=utf-8"
+	case HTML:
+		return "text/html; charset=utf-8"
+	case EPUB:
+		return "application/epub+zip"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Filename returns the file name of a document named name in the format.
+func (f Format) Filename(name string) string {
+	base := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			return r
+		case unicode.IsSpace(r):
+			return '-'
+		default:
+			return -1
+		}
+	}, name)
+	if base == "" {
+		base = "collection"
+	}
+	return base + "." + string(f)
+}
+
+// Collection describes the exported collection.
+type Collection struct {
+	ID          string
+	Title       string
+	Description string
+	Updated     time.Time
+}
+
+// Item is an item of the exported collection.
+type Item struct {
+	Title     string
+	Link      string
+	Feed      string
+	Authors   []string
+	Published *time.Time
+	// Content is the HTML content of the item, it is sanitized before it is
+	// written.
+	Content string
+}
+
+// ImageClient fetches images to inline. Image sources come from feeds, so
+// it only connects to public addresses.
+var ImageClient = &http.Client{
+	Transport: otelhttp.NewTransport(netguard.Transport()),
+}
+
+// Options control how items are exported.
+type Options struct {
+	// InlineImages embeds images in the document instead of linking them.
+	InlineImages bool
+	// Client fetches images, ImageClient is used when nil.
+	Client *http.Client
+}
+
+// Writer writes the items of a collection to a document.
+type Writer interface {
+	// WriteItem appends an item to the document.
+	WriteItem(ctx context.Context, item Item) error
+	// Close completes the document. It does not close the underlying writer.
+	Close() error
+}
+
+// New starts a document in format f for collection c on w.
+func New(w io.Writer, f Format, c Collection, opts Options) (Writer, error) {
+	if opts.Client == nil {
+		opts.Client = ImageClient
+	}
+	switch f {
+	case Markdown:
+		return newMarkdownWriter(w, c, opts)
+	case HTML:
+		return newHTMLWriter(w, c, opts)
+	case EPUB:
+		return newEPUBWriter(w, c, opts)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", f)
+	}
+}
+
+// byline describes the author, source feed and publish date of an item.
+func byline(item Item) string {
+	var parts []string
+	if len(item.Authors) > 0 {
+		parts = append(parts, strings.Join(item.Authors, ", "))
+	}
+	if item.Feed != "" {
+		parts = append(parts, item.Feed)
+	}
+	if item.Published != nil {
+		parts = append(parts, item.Published.Format("2 January 2006"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func title(item Item) string {
+	if t := strings.TrimSpace(item.Title); t != "" {
+		return t
+	}
+	return "Untitled"
+}
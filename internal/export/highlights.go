@@ -0,0 +1,82 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Text returns the text of HTML content once sanitized, the text highlights
+// are anchored to.
+func Text(content string) string {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(policy.Sanitize(content)), body)
+	if err != nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, n := range nodes {
+		walk(n, func(n *html.Node) {
+			if n.Type == html.TextNode {
+				b.WriteString(n.Data)
+			}
+		})
+	}
+	return b.String()
+}
+
+// Annotation is a highlight or note of an item.
+type Annotation struct {
+	// Quote is the highlighted text, empty for notes on the whole item.
+	Quote string
+	Note  string
+}
+
+// AnnotatedItem is an item with the annotations of a user in reading order.
+type AnnotatedItem struct {
+	Item
+	Annotations []Annotation
+}
+
+// HighlightsWriter writes annotated items as a Markdown document, highlights
+// as block quotes followed by their notes.
+type HighlightsWriter struct {
+	w io.Writer
+}
+
+// NewHighlightsWriter starts a document titled title on w.
+func NewHighlightsWriter(w io.Writer, title string) (*HighlightsWriter, error) {
+	if _, err := fmt.Fprintf(w, "# %s\n\n", markdownEscaper.Replace(title)); err != nil {
+		return nil, err
+	}
+	return &HighlightsWriter{w: w}, nil
+}
+
+// WriteItem appends an item and its annotations to the document.
+func (h *HighlightsWriter) WriteItem(item AnnotatedItem) error {
+	var b strings.Builder
+	heading := markdownEscaper.Replace(title(item.Item))
+	if item.Link != "" {
+		heading = fmt.Sprintf("[%s](<%s>)", heading, item.Link)
+	}
+	fmt.Fprintf(&b, "## %s\n\n", heading)
+	if by := byline(item.Item); by != "" {
+		fmt.Fprintf(&b, "*%s*\n\n", markdownEscaper.Replace(by))
+	}
+	for _, a := range item.Annotations {
+		if quote := strings.TrimSpace(a.Quote); quote != "" {
+			for _, line := range strings.Split(quote, "\n") {
+				fmt.Fprintf(&b, "> %s\n", markdownEscaper.Replace(strings.TrimSpace(line)))
+			}
+			b.WriteString("\n")
+		}
+		if note := strings.TrimSpace(a.Note); note != "" {
+			fmt.Fprintf(&b, "%s\n\n", note)
+		}
+	}
+	_, err := io.WriteString(h.w, b.String())
+	return err
+}
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+)
+
+const htmlStyle = `body{max-width:42em;margin:2em auto;padding:0 1em;font-family:Georgia,serif;line-height:1.6}` +
+	`article{border-top:1px solid #ccc;margin-top:2em}img{max-width:100%;height:auto}` +
+	`.byline{color:#666;font-style:italic}`
+
+// htmlWriter writes a single self-contained HTML document, inlined images are
+// embedded as data URIs.
+type htmlWriter struct {
+	w    io.Writer
+	opts Options
+}
+
+func newHTMLWriter(w io.Writer, c Collection, opts Options) (*htmlWriter, error) {
+	_, err := fmt.Fprintf(w,
+		"<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n<style>%s</style>\n</head>\n<body>\n<header>\n<h1>%s</h1>\n",
+		html.EscapeString(c.Title), htmlStyle, html.EscapeString(c.Title),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if c.Description != "" {
+		if _, err := fmt.Fprintf(w, "<p>%s</p>\n", html.EscapeString(c.Description)); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := io.WriteString(w, "</header>\n"); err != nil {
+		return nil, err
+	}
+	return &htmlWriter{w: w, opts: opts}, nil
+}
+
+func (h *htmlWriter) WriteItem(ctx context.Context, item Item) error {
+	nodes, err := parseContent(item)
+	if err != nil {
+		return err
+	}
+	if h.opts.InlineImages {
+		inlineDataURIs(ctx, h.opts.Client, nodes)
+	}
+
+	heading := html.EscapeString(title(item))
+	if item.Link != "" {
+		heading = fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(item.Link), heading)
+	}
+	if _, err := fmt.Fprintf(h.w, "<article>\n<h2>%s</h2>\n", heading); err != nil {
+		return err
+	}
+	if by := byline(item); by != "" {
+		if _, err := fmt.Fprintf(h.w, "<p class=\"byline\">%s</p>\n", html.EscapeString(by)); err != nil {
+			return err
+		}
+	}
+	if err := renderHTML(h.w, nodes); err != nil {
+		return err
+	}
+	_, err = io.WriteString(h.w, "\n</article>\n")
+	return err
+}
+
+func (h *htmlWriter) Close() error {
+	_, err := io.WriteString(h.w, "</body>\n</html>\n")
+	return err
+}
@@ -0,0 +1,75 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+)
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "#", `\#`, "<", `\<`,
+)
+
+// markdownWriter writes a single Markdown document, inlined images are
+// embedded as data URIs.
+type markdownWriter struct {
+	w    io.Writer
+	opts Options
+}
+
+func newMarkdownWriter(w io.Writer, c Collection, opts Options) (*markdownWriter, error) {
+	if _, err := fmt.Fprintf(w, "# %s\n\n", markdownEscaper.Replace(c.Title)); err != nil {
+		return nil, err
+	}
+	if c.Description != "" {
+		if _, err := fmt.Fprintf(w, "%s\n\n", markdownEscaper.Replace(c.Description)); err != nil {
+			return nil, err
+		}
+	}
+	return &markdownWriter{w: w, opts: opts}, nil
+}
+
+func (m *markdownWriter) WriteItem(ctx context.Context, item Item) error {
+	nodes, err := parseContent(item)
+	if err != nil {
+		return err
+	}
+	if m.opts.InlineImages {
+		inlineDataURIs(ctx, m.opts.Client, nodes)
+	}
+	var buf bytes.Buffer
+	if err := renderHTML(&buf, nodes); err != nil {
+		return err
+	}
+	content, err := htmltomarkdown.ConvertString(buf.String())
+	if err != nil {
+		return fmt.Errorf("failed to convert content to markdown: %w", err)
+	}
+
+	heading := markdownEscaper.Replace(title(item))
+	if item.Link != "" {
+		heading = fmt.Sprintf("[%s](<%s>)", heading, item.Link)
+	}
+	if _, err := fmt.Fprintf(m.w, "---\n\n## %s\n\n", heading); err != nil {
+		return err
+	}
+	if by := byline(item); by != "" {
+		if _, err := fmt.Fprintf(m.w, "*%s*\n\n", markdownEscaper.Replace(by)); err != nil {
+			return err
+		}
+	}
+	if content != "" {
+		if _, err := fmt.Fprintf(m.w, "%s\n\n", content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *markdownWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,179 @@
+// Package filters matches items against the filter rules of users, by
+// keyword or by regular expression on one field of the item or on all of
+// them.
+//
+// Rules are matched in Go both when feeds are synced and when they are
+// previewed, so a preview shows exactly what a rule does at ingest.
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/rhajizada/gazette/internal/export"
+)
+
+// Fields of an item a rule matches.
+const (
+	FieldAny      = "any"
+	FieldTitle    = "title"
+	FieldContent  = "content"
+	FieldAuthor   = "author"
+	FieldCategory = "category"
+	FieldLink     = "link"
+)
+
+// Ways a rule matches its pattern.
+const (
+	// MatchKeyword matches text containing the pattern ignoring case, or for
+	// categories a category equal to it ignoring case.
+	MatchKeyword = "keyword"
+	// MatchRegex matches text containing a match of the pattern, in the RE2
+	// syntax of Go.
+	MatchRegex = "regex"
+)
+
+// Actions of a rule on the items it matches.
+const (
+	ActionHide            = "hide"
+	ActionMarkRead        = "mark_read"
+	ActionAddToCollection = "add_to_collection"
+)
+
+// MaxPatternLength bounds the length of a pattern in bytes.
+const MaxPatternLength = 512
+
+// ValidField reports whether field is a field rules match.
+func ValidField(field string) bool {
+	switch field {
+	case FieldAny, FieldTitle, FieldContent, FieldAuthor, FieldCategory, FieldLink:
+		return true
+	}
+	return false
+}
+
+// ValidAction reports whether action is an action of rules.
+func ValidAction(action string) bool {
+	switch action {
+	case ActionHide, ActionMarkRead, ActionAddToCollection:
+		return true
+	}
+	return false
+}
+
+// Item holds the fields of an item rules match. Description and Content may
+// be HTML.
+type Item struct {
+	Title       string
+	Description string
+	Content     string
+	Link        string
+	Authors     []string
+	Categories  []string
+}
+
+// AuthorNames returns the names of authors, or their email when unnamed.
+func AuthorNames(authors []*gofeed.Person) []string {
+	names := make([]string, 0, len(authors))
+	for _, a := range authors {
+		if a == nil {
+			continue
+		}
+		if a.Name != "" {
+			names = append(names, a.Name)
+		} else if a.Email != "" {
+			names = append(names, a.Email)
+		}
+	}
+	return names
+}
+
+// Matcher matches items against a compiled rule.
+type Matcher struct {
+	field   string
+	keyword string
+	re      *regexp.Regexp
+}
+
+// Compile compiles a rule matching pattern on field.
+func Compile(field, matchType, pattern string) (*Matcher, error) {
+	if !ValidField(field) {
+		return nil, fmt.Errorf("invalid field %s", field)
+	}
+	if strings.TrimSpace(pattern) == "" {
+		return nil, errors.New("pattern must not be empty")
+	}
+	if len(pattern) > MaxPatternLength {
+		return nil, fmt.Errorf("pattern must be at most %d bytes", MaxPatternLength)
+	}
+	m := &Matcher{field: field}
+	switch matchType {
+	case MatchKeyword:
+		m.keyword = strings.ToLower(strings.TrimSpace(pattern))
+	case MatchRegex:
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %v", err)
+		}
+		m.re = re
+	default:
+		return nil, fmt.Errorf("invalid match type %s", matchType)
+	}
+	return m, nil
+}
+
+// Match reports whether the item matches the rule.
+func (m *Matcher) Match(item Item) bool {
+	switch m.field {
+	case FieldTitle:
+		return m.matchText(item.Title)
+	case FieldContent:
+		return m.matchText(export.Text(item.Description)) || m.matchText(export.Text(item.Content))
+	case FieldAuthor:
+		return m.matchAny(item.Authors)
+	case FieldCategory:
+		return m.matchCategories(item.Categories)
+	case FieldLink:
+		return m.matchText(item.Link)
+	}
+	return m.matchText(item.Title) ||
+		m.matchText(export.Text(item.Description)) ||
+		m.matchText(export.Text(item.Content)) ||
+		m.matchAny(item.Authors) ||
+		m.matchCategories(item.Categories) ||
+		m.matchText(item.Link)
+}
+
+func (m *Matcher) matchText(text string) bool {
+	if text == "" {
+		return false
+	}
+	if m.re != nil {
+		return m.re.MatchString(text)
+	}
+	return strings.Contains(strings.ToLower(text), m.keyword)
+}
+
+func (m *Matcher) matchAny(texts []string) bool {
+	for _, t := range texts {
+		if m.matchText(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Matcher) matchCategories(categories []string) bool {
+	if m.re != nil {
+		return m.matchAny(categories)
+	}
+	for _, c := range categories {
+		if strings.EqualFold(strings.TrimSpace(c), m.keyword) {
+			return true
+		}
+	}
+	return false
+}
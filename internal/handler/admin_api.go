@@ -0,0 +1,381 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/middleware"
+	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/service"
+)
+
+// AdminListUsers lists and searches users.
+// @Summary      List users
+// @Description  Lists users whose name or email match the query, with per-user stats.
+// @Tags         Admin
+// @Param        query   query     string  false  "Name or email search"
+// @Param        limit   query     int32   true   "Max number of users to return"
+// @Param        offset  query     int32   true   "Number of users to skip"
+// @Success      200     {object}  service.ListAdminUsersResponse
+// @Failure      400     {object}  string
+// @Failure      403     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/admin/users [get]
+func (h *Handler) AdminListUsers(w http.ResponseWriter, r *http.Request) {
+	params, err := getPageParams(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.ListAdminUsers(r.Context(), service.ListAdminUsersRequest{
+		Query:  r.URL.Query().Get("query"),
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to list users", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// AdminGetUser returns a user with stats.
+// @Summary      Get user
+// @Description  Retrieves a user with per-user stats.
+// @Tags         Admin
+// @Param        userID  path      string  true  "User UUID"
+// @Success      200     {object}  service.AdminUser
+// @Failure      400     {object}  string
+// @Failure      403     {object}  string
+// @Failure      404     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/admin/users/{userID} [get]
+func (h *Handler) AdminGetUser(w http.ResponseWriter, r *http.Request) {
+	path := r.PathValue("userID")
+	userID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.Service.GetAdminUser(r.Context(), userID)
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to fetch user %s", userID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// setUserDisabled disables or enables the user in the path.
+func (h *Handler) setUserDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	path := r.PathValue("userID")
+	userID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+	if disabled && userID == middleware.GetUserClaims(r).UserID {
+		http.Error(w, "cannot disable your own account", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.Service.SetUserDisabled(r.Context(), repository.SetUserDisabledParams{
+		Disabled: disabled,
+		ID:       userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to update user %s", userID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+// AdminDisableUser disables a user.
+// @Summary      Disable user
+// @Description  Disables a user and logs out all their sessions, disabled users can not sign in or use tokens.
+// @Tags         Admin
+// @Param        userID  path      string  true  "User UUID"
+// @Success      200     {object}  service.AdminUser
+// @Failure      400     {object}  string
+// @Failure      403     {object}  string
+// @Failure      404     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/admin/users/{userID}/disable [put]
+func (h *Handler) AdminDisableUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, true)
+}
+
+// AdminEnableUser enables a disabled user.
+// @Summary      Enable user
+// @Description  Lets a disabled user sign in again.
+// @Tags         Admin
+// @Param        userID  path      string  true  "User UUID"
+// @Success      200     {object}  service.AdminUser
+// @Failure      400     {object}  string
+// @Failure      403     {object}  string
+// @Failure      404     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/admin/users/{userID}/disable [delete]
+func (h *Handler) AdminEnableUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, false)
+}
+
+// AdminDeleteUser deletes a user.
+// @Summary      Delete user
+// @Description  Deletes a user with their subscriptions, likes, collections and credentials.
+// @Tags         Admin
+// @Param        userID  path  string  true  "User UUID"
+// @Success      204     "No Content"
+// @Failure      400     {object}  string
+// @Failure      403     {object}  string
+// @Failure      404     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/admin/users/{userID} [delete]
+func (h *Handler) AdminDeleteUser(w http.ResponseWriter, r *http.Request) {
+	path := r.PathValue("userID")
+	userID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+	if userID == middleware.GetUserClaims(r).UserID {
+		http.Error(w, "cannot delete your own account", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Service.DeleteUser(r.Context(), userID); err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to delete user %s", userID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AdminListFeeds lists feeds with subscriber counts and sync health.
+// @Summary      List feeds
+// @Description  Lists all feeds with subscriber and item counts and the outcome of recent syncs.
+// @Tags         Admin
+// @Param        failingOnly  query     bool   false  "Only feeds whose last sync failed"
+// @Param        limit        query     int32  true   "Max number of feeds to return"
+// @Param        offset       query     int32  true   "Number of feeds to skip"
+// @Success      200          {object}  service.ListAdminFeedsResponse
+// @Failure      400          {object}  string
+// @Failure      403          {object}  string
+// @Failure      500          {object}  string
+// @Security     BearerAuth
+// @Router       /api/admin/feeds [get]
+func (h *Handler) AdminListFeeds(w http.ResponseWriter, r *http.Request) {
+	params, err := getPageParams(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	failingOnly := false
+	if v := r.URL.Query().Get("failingOnly"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			failingOnly = b
+		}
+	}
+
+	resp, err := h.Service.ListAdminFeeds(r.Context(), service.ListAdminFeedsRequest{
+		FailingOnly: failingOnly,
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to list feeds", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// AdminSyncFeed queues an immediate sync of a feed.
+// @Summary      Resync feed
+// @Description  Queues an immediate sync of a feed, unless a sync of the feed is already queued or running.
+// @Tags         Admin
+// @Param        feedID  path      string  true  "Feed UUID"
+// @Success      202     {object}  service.SyncFeedResponse
+// @Failure      400     {object}  string
+// @Failure      403     {object}  string
+// @Failure      404     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/admin/feeds/{feedID}/sync [post]
+func (h *Handler) AdminSyncFeed(w http.ResponseWriter, r *http.Request) {
+	path := r.PathValue("feedID")
+	feedID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.RefreshFeed(r.Context(), feedID)
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to sync feed %s", feedID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// AdminMergeFeeds merges a duplicate feed into another feed.
+// @Summary      Merge feeds
+// @Description  Moves the items and subscribers of a duplicate feed to the target feed and deletes the duplicate.
+// @Tags         Admin
+// @Accept       json
+// @Param        feedID  path      string             true  "Duplicate feed UUID"
+// @Param        body    body      MergeFeedsRequest  true  "Feed to merge into"
+// @Success      200     {object}  service.MergeFeedsResponse
+// @Failure      400     {object}  string
+// @Failure      403     {object}  string
+// @Failure      404     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/admin/feeds/{feedID}/merge [post]
+func (h *Handler) AdminMergeFeeds(w http.ResponseWriter, r *http.Request) {
+	path := r.PathValue("feedID")
+	feedID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	var req MergeFeedsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.MergeFeeds(r.Context(), service.MergeFeedsRequest{
+		SourceID: feedID,
+		TargetID: req.TargetFeedID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to merge feed %s", feedID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// AdminPurgeItems deletes items published before a date.
+// @Summary      Purge items
+// @Description  Deletes items published before a date, across all feeds or for one feed. Liked, annotated and tagged items and items in collections are kept unless keepSaved is false, otherwise their annotations and tags are deleted with them.
+// @Tags         Admin
+// @Param        before     query     string  true   "RFC 3339 timestamp"
+// @Param        feedID     query     string  false  "Feed UUID"
+// @Param        keepSaved  query     bool    false  "Keep liked, annotated and tagged items and items in collections, defaults to true"
+// @Success      200        {object}  service.PurgeItemsResponse
+// @Failure      400        {object}  string
+// @Failure      403        {object}  string
+// @Failure      404        {object}  string
+// @Failure      500        {object}  string
+// @Security     BearerAuth
+// @Router       /api/admin/items [delete]
+func (h *Handler) AdminPurgeItems(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	before, err := time.Parse(time.RFC3339, q.Get("before"))
+	if err != nil {
+		http.Error(w, "before must be an RFC 3339 timestamp", http.StatusBadRequest)
+		return
+	}
+
+	params := repository.PurgeItemsParams{
+		Before:    before,
+		KeepSaved: true,
+	}
+	if v := q.Get("feedID"); v != "" {
+		feedID, err := uuid.Parse(v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("%s is not a valid id", v), http.StatusBadRequest)
+			return
+		}
+		params.FeedID = &feedID
+	}
+	if v := q.Get("keepSaved"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			params.KeepSaved = b
+		}
+	}
+
+	resp, err := h.Service.PurgeItems(r.Context(), service.PurgeItemsRequest{PurgeItemsParams: params})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to purge items", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,390 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/export"
+	"github.com/rhajizada/gazette/internal/middleware"
+	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/service"
+)
+
+// ListAnnotations returns the annotations of the user across items.
+// @Summary      List annotations
+// @Description  Retrieves the notes and highlights of the user on all items, newest first, paginated.
+// @Tags         Annotations
+// @Produce      json
+// @Param        limit   query     int32  true  "Max number of annotations"
+// @Param        offset  query     int32  true  "Number of annotations to skip"
+// @Success      200     {object}  service.ListAnnotationsResponse
+// @Failure      400     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/annotations [get]
+func (h *Handler) ListAnnotations(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	params, err := getPageParams(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.ListAnnotations(r.Context(), repository.ListItemAnnotationsByUserParams{
+		UserID: userID,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to list annotations", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ExportAnnotations renders the annotations of the user as Markdown.
+// @Summary      Export annotations
+// @Description  Renders the highlights and notes of the user on all items as a Markdown document, grouped by item.
+// @Tags         Annotations
+// @Produce      text/markdown
+// @Success      200  {file}    file  "Exported annotations"
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/annotations/export [get]
+func (h *Handler) ExportAnnotations(w http.ResponseWriter, r *http.Request) {
+	h.writeAnnotations(w, r, nil)
+}
+
+// ListItemAnnotations returns the annotations of the user on an item.
+// @Summary      List item annotations
+// @Description  Retrieves the notes and highlights of the user on an item in reading order, notes without a highlight last.
+// @Tags         Annotations
+// @Produce      json
+// @Param        itemID  path      string  true  "Item UUID"
+// @Success      200     {object}  service.ListItemAnnotationsResponse
+// @Failure      400     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/items/{itemID}/annotations [get]
+func (h *Handler) ListItemAnnotations(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	itemPath := r.PathValue("itemID")
+	itemID, err := uuid.Parse(itemPath)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", itemPath), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.ListItemAnnotations(r.Context(), repository.ListItemAnnotationsByItemParams{
+		UserID: userID,
+		ItemID: itemID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to list annotations of item %s", itemID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ExportItemAnnotations renders the annotations of the user on an item as
+// Markdown.
+// @Summary      Export item annotations
+// @Description  Renders the highlights and notes of the user on an item as a Markdown document.
+// @Tags         Annotations
+// @Produce      text/markdown
+// @Param        itemID  path      string  true  "Item UUID"
+// @Success      200     {file}    file    "Exported annotations"
+// @Failure      400     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/items/{itemID}/annotations/export [get]
+func (h *Handler) ExportItemAnnotations(w http.ResponseWriter, r *http.Request) {
+	itemPath := r.PathValue("itemID")
+	itemID, err := uuid.Parse(itemPath)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", itemPath), http.StatusBadRequest)
+		return
+	}
+	h.writeAnnotations(w, r, &itemID)
+}
+
+// writeAnnotations renders the annotations of the user on itemID, or on all
+// items when nil, as Markdown.
+func (h *Handler) writeAnnotations(w http.ResponseWriter, r *http.Request, itemID *uuid.UUID) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	// the document is started with the first item, so errors listing the
+	// annotations can still be reported
+	var doc *export.HighlightsWriter
+	start := func() error {
+		w.Header().Set("Content-Type", export.Markdown.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.Markdown.Filename("highlights")))
+		var err error
+		doc, err = export.NewHighlightsWriter(w, "Highlights")
+		return err
+	}
+
+	err := h.Service.ExportAnnotations(r.Context(), userID, itemID, func(item service.ExportAnnotatedItem) error {
+		if doc == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		e := export.AnnotatedItem{Item: export.Item{Link: item.Link}}
+		if item.Title != nil {
+			e.Title = *item.Title
+		}
+		if item.FeedTitle != nil {
+			e.Feed = *item.FeedTitle
+		}
+		for _, a := range item.Annotations {
+			var ea export.Annotation
+			if a.Quote != nil {
+				ea.Quote = *a.Quote
+			}
+			if a.Note != nil {
+				ea.Note = *a.Note
+			}
+			e.Annotations = append(e.Annotations, ea)
+		}
+		return doc.WriteItem(e)
+	})
+	if err != nil {
+		if doc != nil {
+			slog.ErrorContext(r.Context(), "failed to export annotations", slog.Any("error", err))
+			return
+		}
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to export annotations", http.StatusBadRequest)
+			return
+		}
+	}
+	if doc == nil {
+		if err := start(); err != nil {
+			slog.ErrorContext(r.Context(), "failed to export annotations", slog.Any("error", err))
+		}
+	}
+}
+
+// CreateItemAnnotation adds a note or a highlight to an item.
+// @Summary      Create item annotation
+// @Description  Adds a note or a highlight of the user to an item. A highlight is anchored to the text of the sanitized item content by quote, by start_offset and end_offset in characters, or by both. The missing anchor is filled in, and the quote must match the text at the offsets when both are given.
+// @Tags         Annotations
+// @Accept       json
+// @Produce      json
+// @Param        itemID  path      string                   true  "Item UUID"
+// @Param        body    body      CreateAnnotationRequest  true  "Annotation"
+// @Success      201     {object}  service.Annotation
+// @Failure      400     {object}  string
+// @Failure      404     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/items/{itemID}/annotations [post]
+func (h *Handler) CreateItemAnnotation(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	itemPath := r.PathValue("itemID")
+	itemID, err := uuid.Parse(itemPath)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", itemPath), http.StatusBadRequest)
+		return
+	}
+
+	var req CreateAnnotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	annotation, err := h.Service.CreateAnnotation(r.Context(), service.CreateAnnotationRequest{
+		UserID:      userID,
+		ItemID:      itemID,
+		Note:        req.Note,
+		Quote:       req.Quote,
+		StartOffset: req.StartOffset,
+		EndOffset:   req.EndOffset,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to annotate item %s", itemID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(annotation)
+}
+
+// parseAnnotationPath parses the item and annotation IDs of the request.
+func parseAnnotationPath(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	itemPath := r.PathValue("itemID")
+	itemID, err := uuid.Parse(itemPath)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("%s is not a valid id", itemPath)
+	}
+	annotationPath := r.PathValue("annotationID")
+	annotationID, err := uuid.Parse(annotationPath)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("%s is not a valid id", annotationPath)
+	}
+	return itemID, annotationID, nil
+}
+
+// GetItemAnnotation returns an annotation of the user on an item.
+// @Summary      Get item annotation
+// @Description  Retrieves a note or highlight of the user on an item.
+// @Tags         Annotations
+// @Produce      json
+// @Param        itemID        path      string  true  "Item UUID"
+// @Param        annotationID  path      string  true  "Annotation UUID"
+// @Success      200           {object}  service.Annotation
+// @Failure      400           {object}  string
+// @Failure      404           {object}  string
+// @Failure      500           {object}  string
+// @Security     BearerAuth
+// @Router       /api/items/{itemID}/annotations/{annotationID} [get]
+func (h *Handler) GetItemAnnotation(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	itemID, annotationID, err := parseAnnotationPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	annotation, err := h.Service.GetAnnotation(r.Context(), repository.GetItemAnnotationParams{
+		ID:     annotationID,
+		ItemID: itemID,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to fetch annotation %s", annotationID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(annotation)
+}
+
+// UpdateItemAnnotation replaces the note of an annotation.
+// @Summary      Update item annotation
+// @Description  Replaces the note of an annotation of the user. An empty note removes the note of a highlight. Highlights cannot be moved, delete and recreate them instead.
+// @Tags         Annotations
+// @Accept       json
+// @Produce      json
+// @Param        itemID        path      string                   true  "Item UUID"
+// @Param        annotationID  path      string                   true  "Annotation UUID"
+// @Param        body          body      UpdateAnnotationRequest  true  "Note"
+// @Success      200           {object}  service.Annotation
+// @Failure      400           {object}  string
+// @Failure      404           {object}  string
+// @Failure      500           {object}  string
+// @Security     BearerAuth
+// @Router       /api/items/{itemID}/annotations/{annotationID} [patch]
+func (h *Handler) UpdateItemAnnotation(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	itemID, annotationID, err := parseAnnotationPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateAnnotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	annotation, err := h.Service.UpdateAnnotation(r.Context(), service.UpdateAnnotationRequest{
+		UserID:       userID,
+		ItemID:       itemID,
+		AnnotationID: annotationID,
+		Note:         req.Note,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to update annotation %s", annotationID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(annotation)
+}
+
+// DeleteItemAnnotation deletes an annotation.
+// @Summary      Delete item annotation
+// @Description  Deletes a note or highlight of the user on an item.
+// @Tags         Annotations
+// @Param        itemID        path  string  true  "Item UUID"
+// @Param        annotationID  path  string  true  "Annotation UUID"
+// @Success      204           "No Content"
+// @Failure      400           {object}  string
+// @Failure      404           {object}  string
+// @Failure      500           {object}  string
+// @Security     BearerAuth
+// @Router       /api/items/{itemID}/annotations/{annotationID} [delete]
+func (h *Handler) DeleteItemAnnotation(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	itemID, annotationID, err := parseAnnotationPath(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.Service.DeleteAnnotation(r.Context(), repository.DeleteItemAnnotationParams{
+		ID:     annotationID,
+		ItemID: itemID,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to delete annotation %s", annotationID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,354 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/middleware"
+	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/service"
+)
+
+// ListCollectionMembers lists the members of a collection.
+// @Summary      List collection members
+// @Description  Lists the members of a collection the user owns or is a member of, and the emails with pending invitations to it.
+// @Tags         Collections
+// @Produce      json
+// @Param        collectionID  path      string  true  "Collection UUID"
+// @Success      200           {object}  service.ListCollectionMembersResponse
+// @Failure      400           {object}  string
+// @Failure      404           {object}  string
+// @Failure      500           {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/{collectionID}/members [get]
+func (h *Handler) ListCollectionMembers(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("collectionID")
+	colID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.ListCollectionMembers(r.Context(), repository.GetCollectionByIDParams{
+		ID:     colID,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to list members of collection %s", colID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// InviteCollectionMember invites an email to a collection.
+// @Summary      Invite collection member
+// @Description  Invites an email to a collection of the user as viewer or editor. The collection is shared with the user whose sign-in provider verified the email once they accept. Emails are invited whether or not a user has them, the response is the same either way. Inviting an email again changes the role of its invitation, and of its member once accepted.
+// @Tags         Collections
+// @Accept       json
+// @Produce      json
+// @Param        collectionID  path      string                         true  "Collection UUID"
+// @Param        body          body      InviteCollectionMemberRequest  true  "Invitation"
+// @Success      202           {object}  service.CollectionMemberInvitation
+// @Failure      400           {object}  string
+// @Failure      404           {object}  string
+// @Failure      500           {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/{collectionID}/members [post]
+func (h *Handler) InviteCollectionMember(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("collectionID")
+	colID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	var req InviteCollectionMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	invitation, err := h.Service.InviteCollectionMember(r.Context(), service.InviteCollectionMemberRequest{
+		UserID:       userID,
+		CollectionID: colID,
+		Email:        req.Email,
+		Role:         req.Role,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to invite %s to collection %s", req.Email, colID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(invitation)
+}
+
+// WithdrawCollectionInvitation withdraws a pending invitation to a collection.
+// @Summary      Withdraw collection invitation
+// @Description  Withdraws the pending invitation of an email to a collection of the user.
+// @Tags         Collections
+// @Param        collectionID  path  string  true  "Collection UUID"
+// @Param        email         path  string  true  "Invited email"
+// @Success      204
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/{collectionID}/invitations/{email} [delete]
+func (h *Handler) WithdrawCollectionInvitation(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("collectionID")
+	colID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+	email := r.PathValue("email")
+
+	err = h.Service.WithdrawCollectionInvitation(r.Context(), service.WithdrawCollectionInvitationRequest{
+		UserID:       userID,
+		CollectionID: colID,
+		Email:        email,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to withdraw invitation of %s to collection %s", email, colID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UpdateCollectionMember changes the role of a member of a collection.
+// @Summary      Update collection member
+// @Description  Changes the role of a member of a collection of the user.
+// @Tags         Collections
+// @Accept       json
+// @Produce      json
+// @Param        collectionID  path      string                         true  "Collection UUID"
+// @Param        userID        path      string                         true  "Member UUID"
+// @Param        body          body      UpdateCollectionMemberRequest  true  "Role"
+// @Success      200           {object}  service.CollectionMember
+// @Failure      400           {object}  string
+// @Failure      404           {object}  string
+// @Failure      500           {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/{collectionID}/members/{userID} [patch]
+func (h *Handler) UpdateCollectionMember(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("collectionID")
+	colID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+	path = r.PathValue("userID")
+	memberID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateCollectionMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	member, err := h.Service.UpdateCollectionMember(r.Context(), service.UpdateCollectionMemberRequest{
+		UserID:       userID,
+		CollectionID: colID,
+		MemberID:     memberID,
+		Role:         req.Role,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to update member %s of collection %s", memberID, colID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(member)
+}
+
+// RemoveCollectionMember removes a member from a collection.
+// @Summary      Remove collection member
+// @Description  Removes a member from a collection of the user.
+// @Tags         Collections
+// @Param        collectionID  path  string  true  "Collection UUID"
+// @Param        userID        path  string  true  "Member UUID"
+// @Success      204
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/{collectionID}/members/{userID} [delete]
+func (h *Handler) RemoveCollectionMember(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("collectionID")
+	colID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+	path = r.PathValue("userID")
+	memberID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	err = h.Service.RemoveCollectionMember(r.Context(), service.RemoveCollectionMemberRequest{
+		UserID:       userID,
+		CollectionID: colID,
+		MemberID:     memberID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to remove member %s from collection %s", memberID, colID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListCollectionInvitations lists the pending invitations of the user.
+// @Summary      List collection invitations
+// @Description  Lists the invitations to the verified emails of the user to collections of other users that are not accepted yet.
+// @Tags         Collections
+// @Produce      json
+// @Success      200  {object}  service.ListCollectionInvitationsResponse
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/invitations [get]
+func (h *Handler) ListCollectionInvitations(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	resp, err := h.Service.ListCollectionInvitations(r.Context(), userID)
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to list collection invitations", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// AcceptCollectionInvitation accepts an invitation to a collection.
+// @Summary      Accept collection invitation
+// @Description  Accepts an invitation of the user to a collection, which is then listed with their own collections.
+// @Tags         Collections
+// @Produce      json
+// @Param        collectionID  path      string  true  "Collection UUID"
+// @Success      200           {object}  service.Collection
+// @Failure      400           {object}  string
+// @Failure      404           {object}  string
+// @Failure      500           {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/{collectionID}/membership [put]
+func (h *Handler) AcceptCollectionInvitation(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("collectionID")
+	colID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	col, err := h.Service.AcceptCollectionInvitation(r.Context(), repository.AcceptCollectionInvitationParams{
+		CollectionID: colID,
+		UserID:       userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to accept invitation to collection %s", colID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(col)
+}
+
+// LeaveCollection leaves a collection shared with the user.
+// @Summary      Leave collection
+// @Description  Removes the user from a collection shared with them, or declines a pending invitation to it.
+// @Tags         Collections
+// @Param        collectionID  path  string  true  "Collection UUID"
+// @Success      204
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/{collectionID}/membership [delete]
+func (h *Handler) LeaveCollection(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("collectionID")
+	colID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	err = h.Service.LeaveCollection(r.Context(), repository.DeleteCollectionMemberParams{
+		CollectionID: colID,
+		UserID:       userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to leave collection %s", colID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
 
 	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/export"
 	"github.com/rhajizada/gazette/internal/middleware"
 	"github.com/rhajizada/gazette/internal/repository"
 	"github.com/rhajizada/gazette/internal/service"
@@ -107,6 +110,7 @@ func (h *Handler) CreateCollection(w http.ResponseWriter, r *http.Request) {
 // @Security     BearerAuth
 // @Router       /api/collections/{collectionID} [get]
 func (h *Handler) GetCollectionByID(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
 	path := r.PathValue("collectionID")
 	colID, err := uuid.Parse(path)
 	if err != nil {
@@ -114,7 +118,10 @@ func (h *Handler) GetCollectionByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	col, err := h.Service.GetCollectionByID(r.Context(), colID)
+	col, err := h.Service.GetCollectionByID(r.Context(), repository.GetCollectionByIDParams{
+		ID:     colID,
+		UserID: userID,
+	})
 	if err != nil {
 		var serviceErr service.ServiceError
 		if errors.As(err, &serviceErr) {
@@ -130,6 +137,381 @@ func (h *Handler) GetCollectionByID(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(col)
 }
 
+// UpdateCollection updates a collection.
+// @Summary      Update collection
+// @Description  Renames a collection or changes its description, icon, color, pinned state or visibility. Omitted fields are left unchanged. Publishing a collection gives it a slug under /public/collections.
+// @Tags         Collections
+// @Accept       json
+// @Produce      json
+// @Param        collectionID  path      string                   true  "Collection UUID"
+// @Param        body          body      UpdateCollectionRequest  true  "Fields to update"
+// @Success      200           {object}  service.Collection
+// @Failure      400           {object}  string
+// @Failure      404           {object}  string
+// @Failure      409           {object}  string
+// @Failure      500           {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/{collectionID} [patch]
+func (h *Handler) UpdateCollection(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("collectionID")
+	colID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateCollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	col, err := h.Service.UpdateCollection(r.Context(), repository.UpdateCollectionByIDParams{
+		ID:          colID,
+		UserID:      userID,
+		Name:        req.Name,
+		Description: req.Description,
+		Icon:        req.Icon,
+		Color:       req.Color,
+		Pinned:      req.Pinned,
+		Public:      req.Public,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to update collection %s", colID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(col)
+}
+
+// RotateCollectionSlug replaces the public slug of a collection.
+// @Summary      Rotate collection slug
+// @Description  Generates a new slug for the collection. The previous public URL and feeds stop working.
+// @Tags         Collections
+// @Produce      json
+// @Param        collectionID  path      string  true  "Collection UUID"
+// @Success      200           {object}  service.Collection
+// @Failure      400           {object}  string
+// @Failure      404           {object}  string
+// @Failure      500           {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/{collectionID}/slug [post]
+func (h *Handler) RotateCollectionSlug(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("collectionID")
+	colID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	col, err := h.Service.RotateCollectionSlug(r.Context(), repository.GetCollectionByIDParams{
+		ID:     colID,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to rotate slug of collection %s", colID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(col)
+}
+
+// SetCollectionRules turns a collection into a smart collection.
+// @Summary      Set collection rules
+// @Description  Sets the rules of a smart collection. Its items are then matched against the rules when listed instead of being added manually.
+// @Tags         Collections
+// @Accept       json
+// @Produce      json
+// @Param        collectionID  path      string                   true  "Collection UUID"
+// @Param        body          body      service.CollectionRules  true  "Collection rules"
+// @Success      200           {object}  service.Collection
+// @Failure      400           {object}  string
+// @Failure      404           {object}  string
+// @Failure      500           {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/{collectionID}/rules [put]
+func (h *Handler) SetCollectionRules(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("collectionID")
+	colID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	var rules service.CollectionRules
+	if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	col, err := h.Service.SetCollectionRules(r.Context(), service.SetCollectionRulesRequest{
+		UserID:       userID,
+		CollectionID: colID,
+		Rules:        rules,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to set rules of collection %s", colID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(col)
+}
+
+// DeleteCollectionRules turns a smart collection back into a manual one.
+// @Summary      Delete collection rules
+// @Description  Removes the rules of a smart collection. Items added manually before are listed again.
+// @Tags         Collections
+// @Produce      json
+// @Param        collectionID  path      string  true  "Collection UUID"
+// @Success      200           {object}  service.Collection
+// @Failure      400           {object}  string
+// @Failure      404           {object}  string
+// @Failure      500           {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/{collectionID}/rules [delete]
+func (h *Handler) DeleteCollectionRules(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("collectionID")
+	colID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	col, err := h.Service.DeleteCollectionRules(r.Context(), repository.GetCollectionByIDParams{
+		ID:     colID,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to delete rules of collection %s", colID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(col)
+}
+
+// ExportCollection renders a collection as a document for offline reading.
+// @Summary      Export collection
+// @Description  Renders every item of the collection with its title, authors, source feed, publish date and sanitized content as a Markdown document, a self-contained HTML document or an EPUB book. Images are linked unless inlineImages is set. The document is streamed as it is rendered.
+// @Tags         Collections
+// @Produce      text/markdown
+// @Produce      text/html
+// @Produce      application/epub+zip
+// @Param        collectionID  path      string  true   "Collection UUID"
+// @Param        format        query     string  false  "Document format"  Enums(md, html, epub)  default(md)
+// @Param        inlineImages  query     bool    false  "Embed images in the document"
+// @Success      200           {file}    file    "Exported collection"
+// @Failure      400           {object}  string
+// @Failure      404           {object}  string
+// @Failure      500           {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/{collectionID}/export [get]
+func (h *Handler) ExportCollection(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("collectionID")
+	colID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	format := export.Markdown
+	if v := r.URL.Query().Get("format"); v != "" {
+		format, err = export.ParseFormat(v)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	inline := false
+	if v := r.URL.Query().Get("inlineImages"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			inline = b
+		}
+	}
+
+	params := repository.GetCollectionByIDParams{
+		ID:     colID,
+		UserID: userID,
+	}
+	col, err := h.Service.GetCollectionByID(r.Context(), params)
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to export collection %s", colID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	info := export.Collection{
+		ID:      col.ID.String(),
+		Title:   col.Name,
+		Updated: col.LastUpdated,
+	}
+	if col.Description != nil {
+		info.Description = *col.Description
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, format.Filename(col.Name)))
+
+	// the status is sent with the first bytes of the document, later errors
+	// can only be logged and end the document early
+	doc, err := export.New(w, format, info, export.Options{
+		InlineImages: inline,
+		Client:       export.ImageClient,
+	})
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to export collection", slog.Any("error", err))
+		return
+	}
+	err = h.Service.ExportCollection(r.Context(), params, func(item service.ExportItem) error {
+		e := export.Item{
+			Link:      item.Link,
+			Published: item.PublishedParsed,
+		}
+		if item.Title != nil {
+			e.Title = *item.Title
+		}
+		if item.FeedTitle != nil {
+			e.Feed = *item.FeedTitle
+		}
+		if item.Content != nil {
+			e.Content = *item.Content
+		}
+		for _, a := range item.Authors {
+			e.Authors = append(e.Authors, a.Name)
+		}
+		return doc.WriteItem(r.Context(), e)
+	})
+	if err == nil {
+		err = doc.Close()
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to export collection", slog.Any("error", err))
+	}
+}
+
+// ReorderCollections sets the order of the user's collections.
+// @Summary      Reorder collections
+// @Description  Sets the manual order of the user's collections to the order of the given IDs. Pinned collections are still listed first.
+// @Tags         Collections
+// @Accept       json
+// @Param        body  body  ReorderCollectionsRequest  true  "Collection IDs in order"
+// @Success      204  "No Content"
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/order [put]
+func (h *Handler) ReorderCollections(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	var req ReorderCollectionsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := h.Service.ReorderCollections(r.Context(), repository.SetCollectionPositionsParams{
+		Ids:    req.CollectionIDs,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to reorder collections", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReorderCollectionItems sets the order of items in a collection.
+// @Summary      Reorder collection items
+// @Description  Sets the manual order of items in the collection to the order of the given IDs.
+// @Tags         Collections
+// @Accept       json
+// @Param        collectionID  path  string                         true  "Collection UUID"
+// @Param        body          body  ReorderCollectionItemsRequest  true  "Item IDs in order"
+// @Success      204  "No Content"
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/collections/{collectionID}/items/order [put]
+func (h *Handler) ReorderCollectionItems(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("collectionID")
+	colID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	var req ReorderCollectionItemsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.Service.ReorderCollectionItems(r.Context(), service.ReorderCollectionItemsRequest{
+		UserID:       userID,
+		CollectionID: colID,
+		ItemIDs:      req.ItemIDs,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to reorder items in collection %s", colID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // DeleteCollectionByID deletes a collection.
 // @Summary      Delete collection
 // @Description  Deletes a collection by ID.
@@ -142,6 +524,7 @@ func (h *Handler) GetCollectionByID(w http.ResponseWriter, r *http.Request) {
 // @Security     BearerAuth
 // @Router       /api/collections/{collectionID} [delete]
 func (h *Handler) DeleteCollectionByID(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
 	path := r.PathValue("collectionID")
 	colID, err := uuid.Parse(path)
 	if err != nil {
@@ -149,7 +532,10 @@ func (h *Handler) DeleteCollectionByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.Service.DeleteCollection(r.Context(), colID)
+	err = h.Service.DeleteCollection(r.Context(), repository.DeleteCollectionByIDParams{
+		ID:     colID,
+		UserID: userID,
+	})
 	if err != nil {
 		var serviceErr service.ServiceError
 		if errors.As(err, &serviceErr) {
@@ -172,10 +558,12 @@ func (h *Handler) DeleteCollectionByID(w http.ResponseWriter, r *http.Request) {
 // @Param        itemID        path  string  true  "Item UUID"
 // @Success      200           {object}  service.AddItemToCollectionResponse
 // @Failure      400  {object}  string
+// @Failure      404  {object}  string
 // @Failure      500  {object}  string
 // @Security     BearerAuth
 // @Router       /api/collections/{collectionID}/item/{itemID} [post]
 func (h *Handler) AddItemToCollection(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
 	colPath := r.PathValue("collectionID")
 	colID, err := uuid.Parse(colPath)
 	if err != nil {
@@ -193,6 +581,7 @@ func (h *Handler) AddItemToCollection(w http.ResponseWriter, r *http.Request) {
 		repository.AddItemToCollectionParams{
 			CollectionID: colID,
 			ItemID:       itemID,
+			UserID:       userID,
 		})
 	if err != nil {
 		var serviceErr service.ServiceError
@@ -216,10 +605,12 @@ func (h *Handler) AddItemToCollection(w http.ResponseWriter, r *http.Request) {
 // @Param        itemID        path  string  true  "Item UUID"
 // @Success      204  "No Content"
 // @Failure      400  {object}  string
+// @Failure      404  {object}  string
 // @Failure      500  {object}  string
 // @Security     BearerAuth
 // @Router       /api/collections/{collectionID}/item/{itemID} [delete]
 func (h *Handler) RemoveItemFromCollection(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
 	colPath := r.PathValue("collectionID")
 	colID, err := uuid.Parse(colPath)
 	if err != nil {
@@ -237,6 +628,7 @@ func (h *Handler) RemoveItemFromCollection(w http.ResponseWriter, r *http.Reques
 		repository.RemoveItemFromCollectionParams{
 			CollectionID: colID,
 			ItemID:       itemID,
+			UserID:       userID,
 		})
 	if err != nil {
 		var serviceErr service.ServiceError
@@ -254,13 +646,14 @@ func (h *Handler) RemoveItemFromCollection(w http.ResponseWriter, r *http.Reques
 
 // ListItemsByCollectionID returns paginated items in a collection.
 // @Summary      List items in collection
-// @Description  Retrieves items in the collection, including like status.
+// @Description  Retrieves items in the collection, including like status. Items of smart collections are matched against their rules.
 // @Tags         Collections
 // @Param        collectionID  path      string  true   "Collection UUID"
 // @Param        limit         query     int32   true   "Max number of items"
 // @Param        offset        query     int32   true   "Number of items to skip"
 // @Success      200           {object}  service.ListCollectionItemsResponse
 // @Failure      400           {object}  string
+// @Failure      404           {object}  string
 // @Failure      500           {object}  string
 // @Security     BearerAuth
 // @Router       /api/collections/{collectionID}/items [get]
@@ -281,9 +674,9 @@ func (h *Handler) ListItemsByCollectionID(w http.ResponseWriter, r *http.Request
 	var resp *service.ListCollectionItemsResponse
 
 	resp, err = h.Service.ListCollectionItems(r.Context(), service.ListCollectionItemsRequest{
-		UserID: userID,
 		ListItemsInCollectionParams: repository.ListItemsInCollectionParams{
 			CollectionID: colID,
+			UserID:       userID,
 			Limit:        params.Limit,
 			Offset:       params.Offset,
 		},
@@ -16,9 +16,10 @@ import (
 
 // ListFeeds returns a paginated list of feeds.
 // @Summary      List feeds
-// @Description  Returns all feeds or only those the user is subscribed to.
+// @Description  Returns all feeds or only those the user is subscribed to. With tag, returns only the subscribed feeds with that tag of the user.
 // @Tags         Feeds
-// @Param        subscribedOnly  query     bool   false  "Only subscribed feeds"
+// @Param        subscribedOnly  query     bool    false  "Only subscribed feeds"
+// @Param        tag             query     string  false  "Only subscribed feeds with this tag"
 // @Param        limit           query     int32  true   "Max number of feeds to return"
 // @Param        offset          query     int32  true   "Number of feeds to skip"
 // @Success      200             {object}  service.ListFeedsResponse
@@ -42,10 +43,16 @@ func (h *Handler) ListFeeds(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	var tag *string
+	if v := r.URL.Query().Get("tag"); v != "" {
+		tag = &v
+	}
+
 	var resp *service.ListFeedsResponse
 	resp, err = h.Service.ListFeeds(r.Context(), service.ListFeedsRequest{
 		UserID:       userID,
 		SubscbedOnly: subOnly,
+		Tag:          tag,
 		Limit:        params.Limit,
 		Offset:       params.Offset,
 	})
@@ -213,11 +220,12 @@ func (h *Handler) GetFeedByID(w http.ResponseWriter, r *http.Request) {
 
 // DeleteFeedByID deletes a feed entirely.
 // @Summary      Delete feed
-// @Description  Removes a feed and all its subscriptions/items.
+// @Description  Removes a feed and all its subscriptions/items. Requires the admin role.
 // @Tags         Feeds
 // @Param        feedID  path  string  true  "Feed UUID"
 // @Success      204     "No Content"
 // @Failure      400     {object}  string
+// @Failure      403     {object}  string
 // @Failure      500     {object}  string
 // @Security     BearerAuth
 // @Router       /api/feeds/{feedID} [delete]
@@ -321,9 +329,63 @@ func (h *Handler) UnsubscribeFromFeed(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// UpdateSubscription changes the settings of the user for a feed.
+// @Summary      Update subscription
+// @Description  Changes the settings of the user for a subscribed feed. Omitted fields are left unchanged, an empty folder, title or icon clears it. A folder takes precedence over tags when listing feed folders.
+// @Tags         Feeds
+// @Accept       json
+// @Produce      json
+// @Param        feedID  path      string                     true  "Feed UUID"
+// @Param        body    body      UpdateSubscriptionRequest  true  "Subscription settings"
+// @Success      200     {object}  service.Subscription
+// @Failure      400     {object}  string
+// @Failure      404     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/feeds/{feedID}/subscription [patch]
+func (h *Handler) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("feedID")
+	feedID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	var req UpdateSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.Service.UpdateSubscription(r.Context(), service.UpdateSubscriptionRequest{
+		UserID:    userID,
+		FeedID:    feedID,
+		Folder:    req.Folder,
+		Title:     req.Title,
+		Icon:      req.Icon,
+		Notify:    req.Notify,
+		SortOrder: req.SortOrder,
+		Muted:     req.Muted,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to update subscription to feed %s", feedID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sub)
+}
+
 // ListItemsByFeedID returns paginated list of items.
 // @Summary      List feed items
-// @Description  Retrieves feed items.
+// @Description  Retrieves feed items, newest first or oldest first as set on the subscription of the user.
 // @Tags         Items
 // @Param        feedID  path      string  true   "Feed UUID"
 // @Param        limit   query     int32   true   "Max number of items"
@@ -370,3 +432,74 @@ func (h *Handler) ListItemsByFeedID(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
+
+// RefreshFeed queues an immediate sync of a feed.
+// @Summary      Refresh feed
+// @Description  Queues an immediate sync of a feed. Refreshing a feed whose sync is already queued or running returns that task instead of queueing another one.
+// @Tags         Feeds
+// @Param        feedID  path      string  true  "Feed UUID"
+// @Success      202     {object}  service.SyncFeedResponse
+// @Failure      400     {object}  string
+// @Failure      404     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/feeds/{feedID}/refresh [post]
+func (h *Handler) RefreshFeed(w http.ResponseWriter, r *http.Request) {
+	path := r.PathValue("feedID")
+	feedID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.RefreshFeed(r.Context(), feedID)
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to refresh feed %s", feedID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetFeedSyncStatus reports when a feed was and will be synced.
+// @Summary      Get feed sync status
+// @Description  Reports the outcome of the last sync of a feed, the sync task currently queued or running and when feeds are synced next.
+// @Tags         Feeds
+// @Param        feedID  path      string  true  "Feed UUID"
+// @Success      200     {object}  service.SyncStatusResponse
+// @Failure      400     {object}  string
+// @Failure      404     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/feeds/{feedID}/sync-status [get]
+func (h *Handler) GetFeedSyncStatus(w http.ResponseWriter, r *http.Request) {
+	path := r.PathValue("feedID")
+	feedID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.GetSyncStatus(r.Context(), feedID)
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to fetch sync status of feed %s", feedID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
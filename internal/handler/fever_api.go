@@ -0,0 +1,378 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/service"
+)
+
+const (
+	// FeverAPIVersion is the Fever API version implemented by the server.
+	FeverAPIVersion = 3
+	// FeverMaxItems is the number of items returned by a single items request.
+	FeverMaxItems = 50
+)
+
+// feverFaviconID is shared by all feeds, gazette does not store feed icons.
+const feverFaviconID = 1
+
+// feverFavicon is a transparent 1x1 GIF.
+const feverFavicon = "image/gif;base64,R0lGODlhAQABAIAAAAAAAP///yH5BAEAAAAALAAAAAABAAEAAAIBRAA7"
+
+func joinSeqs(seqs []int64) string {
+	parts := make([]string, len(seqs))
+	for i, seq := range seqs {
+		parts[i] = strconv.FormatInt(seq, 10)
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseSeqs(v string) ([]int64, error) {
+	seqs := make([]int64, 0)
+	for _, part := range strings.Split(v, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		seq, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		seqs = append(seqs, seq)
+	}
+	return seqs, nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// feverGroupID derives the Fever group ID of a folder from its name ignoring
+// case, so IDs stay stable as other folders come and go. ID 0 is reserved for
+// the “Kindling” super group.
+func feverGroupID(name string) int64 {
+	h := fnv.New32a()
+	h.Write([]byte(strings.ToLower(name)))
+	return int64(h.Sum32()) + 1
+}
+
+// toFeverGroups maps the folders of the user to Fever groups and the feeds
+// filed in each of them.
+func toFeverGroups(folders []service.FeedFolder, feedSeqs map[uuid.UUID]int64) ([]FeverGroup, []FeverFeedsGroup) {
+	groups := make([]FeverGroup, len(folders))
+	feedsGroups := make([]FeverFeedsGroup, len(folders))
+	for i, folder := range folders {
+		id := feverGroupID(folder.Name)
+		seqs := make([]int64, 0, len(folder.Feeds))
+		for _, f := range folder.Feeds {
+			if seq, ok := feedSeqs[f.ID]; ok {
+				seqs = append(seqs, seq)
+			}
+		}
+		groups[i] = FeverGroup{ID: id, Title: folder.Name}
+		feedsGroups[i] = FeverFeedsGroup{GroupID: id, FeedIDs: joinSeqs(seqs)}
+	}
+	return groups, feedsGroups
+}
+
+func toFeverItem(item service.StreamItem, feedSeqs map[uuid.UUID]int64) FeverItem {
+	var author string
+	if len(item.Authors) > 0 {
+		author = item.Authors[0].Name
+	}
+
+	html := deref(item.Content)
+	if html == "" {
+		html = deref(item.Description)
+	}
+
+	created := item.CreatedAt
+	if item.PublishedParsed != nil {
+		created = *item.PublishedParsed
+	}
+
+	return FeverItem{
+		ID:            item.Seq,
+		FeedID:        feedSeqs[item.FeedID],
+		Title:         deref(item.Title),
+		Author:        author,
+		HTML:          html,
+		URL:           item.Link,
+		IsSaved:       boolToInt(item.Liked),
+		IsRead:        boolToInt(item.Read),
+		CreatedOnTime: created.Unix(),
+	}
+}
+
+// feverMark applies a mark action to items, a feed or a group.
+func (h *Handler) feverMark(ctx context.Context, userID uuid.UUID, form url.Values, feeds []service.FeedRef, folders []service.FeedFolder) error {
+	id, err := strconv.ParseInt(form.Get("id"), 10, 64)
+	if err != nil {
+		return service.NewError("invalid id", http.StatusBadRequest)
+	}
+
+	var before *time.Time
+	if v := form.Get("before"); v != "" {
+		sec, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return service.NewError("invalid before", http.StatusBadRequest)
+		}
+		t := time.Unix(sec, 0)
+		before = &t
+	}
+
+	switch form.Get("mark") {
+	case "item":
+		ids, err := h.Service.ResolveItemSeqs(ctx, []int64{id})
+		if err != nil {
+			return err
+		}
+		for _, itemID := range ids {
+			switch form.Get("as") {
+			case "read":
+				err = h.Service.MarkItemRead(ctx, repository.CreateUserReadParams{UserID: userID, ItemID: itemID})
+			case "unread":
+				err = h.Service.MarkItemUnread(ctx, repository.DeleteUserReadParams{UserID: userID, ItemID: itemID})
+			case "saved":
+				_, err = h.Service.LikeItem(ctx, repository.CreateUserLikeParams{UserID: userID, ItemID: itemID})
+			case "unsaved":
+				err = h.Service.UnlikeItem(ctx, repository.DeleteUserLikeParams{UserID: userID, ItemID: itemID})
+			default:
+				return service.NewError("unsupported mark", http.StatusBadRequest)
+			}
+		}
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) && serviceErr.Code < http.StatusInternalServerError {
+			return nil
+		}
+		return err
+	case "feed":
+		if form.Get("as") != "read" {
+			return service.NewError("unsupported mark", http.StatusBadRequest)
+		}
+		for _, f := range feeds {
+			if f.Seq == id {
+				_, err := h.Service.MarkStreamRead(ctx, repository.MarkStreamItemsReadParams{
+					UserID: userID,
+					FeedID: &f.ID,
+					Before: before,
+				})
+				return err
+			}
+		}
+		return nil
+	case "group":
+		if form.Get("as") != "read" {
+			return service.NewError("unsupported mark", http.StatusBadRequest)
+		}
+		// group 0 is the “Kindling” super group containing every feed
+		if id == 0 {
+			_, err := h.Service.MarkStreamRead(ctx, repository.MarkStreamItemsReadParams{
+				UserID: userID,
+				Before: before,
+			})
+			return err
+		}
+		for _, folder := range folders {
+			if feverGroupID(folder.Name) != id {
+				continue
+			}
+			for _, f := range folder.Feeds {
+				_, err := h.Service.MarkStreamRead(ctx, repository.MarkStreamItemsReadParams{
+					UserID: userID,
+					FeedID: &f.ID,
+					Before: before,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return nil
+	default:
+		return service.NewError("unsupported mark", http.StatusBadRequest)
+	}
+}
+
+// Fever implements the Fever API. Clients authenticate with api_key, the MD5
+// digest of “email:password”, and select data with query parameters.
+func (h *Handler) Fever(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := map[string]any{
+		"api_version": FeverAPIVersion,
+		"auth":        0,
+	}
+
+	userID, err := h.Service.AuthenticateFeverKey(r.Context(), r.Form.Get("api_key"))
+	if err != nil {
+		json.NewEncoder(w).Encode(resp)
+		return
+	}
+	resp["auth"] = 1
+
+	feeds, err := h.Service.ListSubscribedFeedRefs(r.Context(), userID)
+	if err != nil {
+		writeServiceError(w, err, "failed to list feeds")
+		return
+	}
+	feedSeqs := make(map[uuid.UUID]int64, len(feeds))
+	var lastRefreshed time.Time
+	for _, f := range feeds {
+		feedSeqs[f.ID] = f.Seq
+		if f.LastUpdatedAt.After(lastRefreshed) {
+			lastRefreshed = f.LastUpdatedAt
+		}
+	}
+	resp["last_refreshed_on_time"] = lastRefreshed.Unix()
+
+	// folders of the user are their Fever groups
+	var folders []service.FeedFolder
+	if r.Form.Has("groups") || r.Form.Has("feeds") || r.Form.Get("mark") == "group" {
+		list, err := h.Service.ListFeedFolders(r.Context(), userID)
+		if err != nil {
+			writeServiceError(w, err, "failed to list groups")
+			return
+		}
+		folders = list.Folders
+	}
+
+	if r.Form.Has("mark") {
+		if err := h.feverMark(r.Context(), userID, r.Form, feeds, folders); err != nil {
+			writeServiceError(w, err, "failed to mark")
+			return
+		}
+	}
+
+	if r.Form.Has("groups") || r.Form.Has("feeds") {
+		groups, feedsGroups := toFeverGroups(folders, feedSeqs)
+		resp["feeds_groups"] = feedsGroups
+		if r.Form.Has("groups") {
+			resp["groups"] = groups
+		}
+	}
+
+	if r.Form.Has("feeds") {
+		out := make([]FeverFeed, len(feeds))
+		for i, f := range feeds {
+			title := deref(f.Title)
+			if title == "" {
+				title = f.FeedLink
+			}
+			out[i] = FeverFeed{
+				ID:                f.Seq,
+				FaviconID:         feverFaviconID,
+				Title:             title,
+				URL:               f.FeedLink,
+				SiteURL:           deref(f.Link),
+				LastUpdatedOnTime: f.LastUpdatedAt.Unix(),
+			}
+		}
+		resp["feeds"] = out
+	}
+
+	if r.Form.Has("favicons") {
+		resp["favicons"] = []FeverFavicon{{ID: feverFaviconID, Data: feverFavicon}}
+	}
+
+	if r.Form.Has("items") {
+		var items []service.StreamItem
+		if withIDs := r.Form.Get("with_ids"); withIDs != "" {
+			seqs, err := parseSeqs(withIDs)
+			if err != nil || len(seqs) > FeverMaxItems {
+				http.Error(w, "invalid with_ids", http.StatusBadRequest)
+				return
+			}
+			items, err = h.Service.ListStreamItemsBySeqs(r.Context(), repository.ListStreamItemsBySeqsParams{
+				UserID: userID,
+				Seqs:   seqs,
+			})
+			if err != nil {
+				writeServiceError(w, err, "failed to list items")
+				return
+			}
+		} else {
+			params := repository.ListItemsBySeqRangeParams{
+				UserID: userID,
+				Limit:  FeverMaxItems,
+			}
+			if v := r.Form.Get("since_id"); v != "" {
+				seq, err := strconv.ParseInt(v, 10, 64)
+				if err != nil {
+					http.Error(w, "invalid since_id", http.StatusBadRequest)
+					return
+				}
+				params.SinceSeq = &seq
+			}
+			if v := r.Form.Get("max_id"); v != "" {
+				seq, err := strconv.ParseInt(v, 10, 64)
+				if err != nil {
+					http.Error(w, "invalid max_id", http.StatusBadRequest)
+					return
+				}
+				params.MaxSeq = &seq
+			}
+			items, err = h.Service.ListItemsBySeqRange(r.Context(), params)
+			if err != nil {
+				writeServiceError(w, err, "failed to list items")
+				return
+			}
+		}
+
+		total, err := h.Service.CountSubscribedItems(r.Context(), userID)
+		if err != nil {
+			writeServiceError(w, err, "failed to count items")
+			return
+		}
+
+		out := make([]FeverItem, len(items))
+		for i, item := range items {
+			out[i] = toFeverItem(item, feedSeqs)
+		}
+		resp["items"] = out
+		resp["total_items"] = total
+	}
+
+	if r.Form.Has("links") {
+		resp["links"] = []any{}
+	}
+
+	if r.Form.Has("unread_item_ids") || r.Form.Get("as") == "read" || r.Form.Get("as") == "unread" {
+		seqs, err := h.Service.ListUnreadItemSeqs(r.Context(), userID)
+		if err != nil {
+			writeServiceError(w, err, "failed to list unread items")
+			return
+		}
+		resp["unread_item_ids"] = joinSeqs(seqs)
+	}
+
+	if r.Form.Has("saved_item_ids") || r.Form.Get("as") == "saved" || r.Form.Get("as") == "unsaved" {
+		seqs, err := h.Service.ListLikedItemSeqs(r.Context(), userID)
+		if err != nil {
+			writeServiceError(w, err, "failed to list saved items")
+			return
+		}
+		resp["saved_item_ids"] = joinSeqs(seqs)
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
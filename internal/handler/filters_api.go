@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/middleware"
+	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/service"
+)
+
+func (req FilterRuleRequest) toService(userID uuid.UUID) service.FilterRuleRequest {
+	return service.FilterRuleRequest{
+		UserID:       userID,
+		Name:         req.Name,
+		FeedID:       req.FeedID,
+		Field:        req.Field,
+		MatchType:    req.MatchType,
+		Pattern:      req.Pattern,
+		Action:       req.Action,
+		CollectionID: req.CollectionID,
+		Enabled:      req.Enabled,
+	}
+}
+
+// ListFilterRules returns the filter rules of the user.
+// @Summary      List filter rules
+// @Description  Lists the filter rules of the user with their hit counters, oldest first.
+// @Tags         Filters
+// @Produce      json
+// @Success      200  {object}  service.ListFilterRulesResponse
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/filters [get]
+func (h *Handler) ListFilterRules(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	resp, err := h.Service.ListFilterRules(r.Context(), userID)
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to list filter rules", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// CreateFilterRule creates a filter rule of the user.
+// @Summary      Create filter rule
+// @Description  Creates a keyword or regex rule run on the items of subscribed feeds as they are synced, hiding them, marking them read or adding them to a manual collection. A hide rule also hides the recent items it matches.
+// @Tags         Filters
+// @Accept       json
+// @Produce      json
+// @Param        body  body      FilterRuleRequest  true  "Filter rule"
+// @Success      201   {object}  service.FilterRule
+// @Failure      400   {object}  string
+// @Failure      403   {object}  string
+// @Failure      404   {object}  string
+// @Failure      500   {object}  string
+// @Security     BearerAuth
+// @Router       /api/filters [post]
+func (h *Handler) CreateFilterRule(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	var req FilterRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.Service.CreateFilterRule(r.Context(), req.toService(userID))
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to create filter rule", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+// PreviewFilterRule reports the recent items a filter rule would match.
+// @Summary      Preview filter rule
+// @Description  Matches a filter rule against the most recent items of subscribed feeds without saving it or running its action.
+// @Tags         Filters
+// @Accept       json
+// @Produce      json
+// @Param        body  body      FilterRuleRequest  true  "Filter rule"
+// @Success      200   {object}  service.PreviewFilterRuleResponse
+// @Failure      400   {object}  string
+// @Failure      403   {object}  string
+// @Failure      404   {object}  string
+// @Failure      500   {object}  string
+// @Security     BearerAuth
+// @Router       /api/filters/preview [post]
+func (h *Handler) PreviewFilterRule(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	var req FilterRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.PreviewFilterRule(r.Context(), req.toService(userID))
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to preview filter rule", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetFilterRule returns a filter rule of the user.
+// @Summary      Get filter rule
+// @Description  Retrieves a filter rule of the user with its hit counter.
+// @Tags         Filters
+// @Produce      json
+// @Param        filterID  path      string  true  "Filter rule UUID"
+// @Success      200       {object}  service.FilterRule
+// @Failure      400       {object}  string
+// @Failure      404       {object}  string
+// @Failure      500       {object}  string
+// @Security     BearerAuth
+// @Router       /api/filters/{filterID} [get]
+func (h *Handler) GetFilterRule(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("filterID")
+	ruleID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.Service.GetFilterRule(r.Context(), repository.GetFilterRuleParams{
+		ID:     ruleID,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to get filter rule %s", ruleID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// UpdateFilterRule replaces a filter rule of the user.
+// @Summary      Update filter rule
+// @Description  Replaces the definition of a filter rule of the user. Items the rule hid are shown again and, for an enabled hide rule, the recent items it now matches hidden.
+// @Tags         Filters
+// @Accept       json
+// @Produce      json
+// @Param        filterID  path      string             true  "Filter rule UUID"
+// @Param        body      body      FilterRuleRequest  true  "Filter rule"
+// @Success      200       {object}  service.FilterRule
+// @Failure      400       {object}  string
+// @Failure      403       {object}  string
+// @Failure      404       {object}  string
+// @Failure      500       {object}  string
+// @Security     BearerAuth
+// @Router       /api/filters/{filterID} [put]
+func (h *Handler) UpdateFilterRule(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("filterID")
+	ruleID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	var req FilterRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rule, err := h.Service.UpdateFilterRule(r.Context(), service.UpdateFilterRuleRequest{
+		RuleID:            ruleID,
+		FilterRuleRequest: req.toService(userID),
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to update filter rule %s", ruleID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// DeleteFilterRule deletes a filter rule of the user.
+// @Summary      Delete filter rule
+// @Description  Deletes a filter rule of the user and shows the items it hid again.
+// @Tags         Filters
+// @Param        filterID  path  string  true  "Filter rule UUID"
+// @Success      204
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/filters/{filterID} [delete]
+func (h *Handler) DeleteFilterRule(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("filterID")
+	ruleID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	err = h.Service.DeleteFilterRule(r.Context(), repository.DeleteFilterRuleParams{
+		ID:     ruleID,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to delete filter rule %s", ruleID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,25 +1,47 @@
 package handler
 
 import (
-	"github.com/coreos/go-oidc/v3/oidc"
+	"strings"
+
+	"github.com/rhajizada/gazette/internal/config"
+	"github.com/rhajizada/gazette/internal/oauth"
 	"github.com/rhajizada/gazette/internal/service"
-	"golang.org/x/oauth2"
 )
 
 // Handler encapsulates dependencies for HTTP handlers.
 type Handler struct {
-	Service  *service.Service
-	Secret   []byte
-	Verifier *oidc.IDTokenVerifier
-	OAuth    *oauth2.Config
+	Service   *service.Service
+	Secret    []byte
+	Providers []*oauth.Provider
+	Session   *config.SessionConfig
+	Roles     *config.RolesConfig
+	// PublicURL is the external base URL of the server, empty to use the
+	// host of each request.
+	PublicURL string
 }
 
 // New creates a new Handler.
-func New(service *service.Service, jwtSecret []byte, jwtVerifier *oidc.IDTokenVerifier, oauthConfig *oauth2.Config) *Handler {
+func New(service *service.Service, jwtSecret []byte, providers []*oauth.Provider, sessionConfig *config.SessionConfig, rolesConfig *config.RolesConfig, publicURL string) *Handler {
 	return &Handler{
-		Service:  service,
-		Secret:   jwtSecret,
-		Verifier: jwtVerifier,
-		OAuth:    oauthConfig,
+		Service:   service,
+		Secret:    jwtSecret,
+		Providers: providers,
+		Session:   sessionConfig,
+		Roles:     rolesConfig,
+		PublicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// provider returns the provider with the given name, an empty name selects
+// the first configured provider.
+func (h *Handler) provider(name string) *oauth.Provider {
+	if name == "" && len(h.Providers) > 0 {
+		return h.Providers[0]
+	}
+	for _, p := range h.Providers {
+		if p.Name == name {
+			return p
+		}
 	}
+	return nil
 }
@@ -1,5 +1,12 @@
 package handler
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/service"
+)
+
 type CreateFeedRequest struct {
 	FeedURL string `json:"feed_url"`
 }
@@ -7,3 +14,179 @@ type CreateFeedRequest struct {
 type CreateCollectionRequest struct {
 	Name string `json:"name"`
 }
+
+// UpdateCollectionRequest holds the collection fields to change, omitted
+// fields are left unchanged and empty description, icon or color clear them.
+type UpdateCollectionRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+	Icon        *string `json:"icon,omitempty"`
+	Color       *string `json:"color,omitempty"`
+	Pinned      *bool   `json:"pinned,omitempty"`
+	Public      *bool   `json:"public,omitempty"`
+}
+
+// InviteCollectionMemberRequest invites an email to a collection.
+type InviteCollectionMemberRequest struct {
+	Email string `json:"email"`
+	// Role is viewer or editor.
+	Role string `json:"role"`
+}
+
+// UpdateCollectionMemberRequest changes the role of a member of a collection.
+type UpdateCollectionMemberRequest struct {
+	// Role is viewer or editor.
+	Role string `json:"role"`
+}
+
+// CreateAnnotationRequest holds a note, a highlight or both. A highlight is
+// anchored by quote, by offsets or by both.
+type CreateAnnotationRequest struct {
+	Note  *string `json:"note,omitempty"`
+	Quote *string `json:"quote,omitempty"`
+	// StartOffset and EndOffset count characters of the text of the sanitized
+	// item content.
+	StartOffset *int32 `json:"start_offset,omitempty"`
+	EndOffset   *int32 `json:"end_offset,omitempty"`
+}
+
+// UpdateAnnotationRequest holds the new note of an annotation.
+type UpdateAnnotationRequest struct {
+	Note string `json:"note"`
+}
+
+// UpdateSubscriptionRequest holds the settings of a subscription to change.
+type UpdateSubscriptionRequest struct {
+	Folder *string `json:"folder,omitempty"`
+	Title  *string `json:"title,omitempty"`
+	Icon   *string `json:"icon,omitempty"`
+	// Notify is none or all, none leaves new items of the feed out of
+	// feed.item webhooks.
+	Notify *string `json:"notify,omitempty"`
+	// SortOrder is newest or oldest.
+	SortOrder *string `json:"sort_order,omitempty"`
+	Muted     *bool   `json:"muted,omitempty"`
+}
+
+// SetTagsRequest holds the tags replacing those of an item or feed.
+type SetTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// RenameTagRequest holds the new name of a tag. Renaming a tag to the name
+// of another tag merges both.
+type RenameTagRequest struct {
+	Name string `json:"name"`
+}
+
+// BulkTagRequest adds and removes tags on many items and feeds at once.
+type BulkTagRequest struct {
+	Add     []string    `json:"add,omitempty"`
+	Remove  []string    `json:"remove,omitempty"`
+	ItemIDs []uuid.UUID `json:"item_ids,omitempty"`
+	FeedIDs []uuid.UUID `json:"feed_ids,omitempty"`
+}
+
+// FilterRuleRequest defines a filter rule. Enabled defaults to true.
+type FilterRuleRequest struct {
+	Name string `json:"name"`
+	// FeedID limits the rule to one subscribed feed, all feeds when omitted.
+	FeedID *uuid.UUID `json:"feed_id,omitempty"`
+	// Field is any, title, content, author, category or link, any when
+	// omitted.
+	Field string `json:"field,omitempty"`
+	// MatchType is keyword or regex.
+	MatchType string `json:"match_type"`
+	Pattern   string `json:"pattern"`
+	// Action is hide, mark_read or add_to_collection.
+	Action string `json:"action"`
+	// CollectionID is the manual collection add_to_collection adds items to.
+	CollectionID *uuid.UUID `json:"collection_id,omitempty"`
+	Enabled      *bool      `json:"enabled,omitempty"`
+}
+
+// WebhookRequest defines a webhook. Empty feed_ids, collection_ids and
+// rule_ids select all feeds, collections and filter rules of the user.
+// Enabled defaults to true.
+type WebhookRequest struct {
+	Name string `json:"name,omitempty"`
+	URL  string `json:"url"`
+	// Secret signs deliveries. It is generated when omitted on creation and
+	// kept when omitted on update, an empty secret is generated anew.
+	Secret *string `json:"secret,omitempty"`
+	// Events are feed.item, collection.item or rule.match.
+	Events        []string    `json:"events"`
+	FeedIDs       []uuid.UUID `json:"feed_ids,omitempty"`
+	CollectionIDs []uuid.UUID `json:"collection_ids,omitempty"`
+	RuleIDs       []uuid.UUID `json:"rule_ids,omitempty"`
+	Enabled       *bool       `json:"enabled,omitempty"`
+}
+
+// PublicCollectionResponse is a published collection with links to its feeds.
+type PublicCollectionResponse struct {
+	service.PublicCollection
+	Feeds PublicCollectionFeeds `json:"feeds"`
+}
+
+type PublicCollectionFeeds struct {
+	RSS      string `json:"rss"`
+	Atom     string `json:"atom"`
+	JSONFeed string `json:"json_feed"`
+}
+
+type ReorderCollectionsRequest struct {
+	CollectionIDs []uuid.UUID `json:"collection_ids"`
+}
+
+type ReorderCollectionItemsRequest struct {
+	ItemIDs []uuid.UUID `json:"item_ids"`
+}
+
+type CreateAppPasswordRequest struct {
+	Name string `json:"name"`
+}
+
+type SetFeverPasswordRequest struct {
+	Password string `json:"password"`
+}
+
+type CreateAccessTokenRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type TokenResponse struct {
+	Token        string `json:"token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type LogoutResponse struct {
+	RedirectURL string `json:"redirect_url"`
+}
+
+type Provider struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+type ListProvidersResponse struct {
+	Providers []Provider `json:"providers"`
+}
+
+type LinkIdentityRequest struct {
+	Provider string `json:"provider"`
+}
+
+type LinkIdentityResponse struct {
+	URL string `json:"url"`
+}
+
+type MergeFeedsRequest struct {
+	TargetFeedID uuid.UUID `json:"target_feed_id"`
+}
@@ -1,7 +1,7 @@
 package handler
 
 import (
-	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -9,36 +9,125 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/config"
 	"github.com/rhajizada/gazette/internal/oauth"
 	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/service"
 )
 
-func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
-	state, err := randomState()
+// refreshCookie holds the refresh token of browser sessions.
+const refreshCookie = "gazette_refresh"
+
+func (h *Handler) setRefreshCookie(w http.ResponseWriter, token string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookie,
+		Value:    token,
+		Path:     "/oauth",
+		HttpOnly: true,
+		Secure:   h.Session.SecureCookies,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   int(h.Session.RefreshTokenTTL.Seconds()),
+	})
+}
+
+func (h *Handler) clearRefreshCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookie,
+		Value:    "",
+		Path:     "/oauth",
+		HttpOnly: true,
+		Secure:   h.Session.SecureCookies,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   -1,
+	})
+}
+
+// stateCookie binds the login state to the browser that started the login.
+const stateCookie = "oidc_state"
+
+// loginStateTTL bounds how long a user may take at the provider.
+const loginStateTTL = 5 * time.Minute
+
+// startLogin creates a login state for provider and returns the URL of the
+// provider's authorization endpoint.
+func (h *Handler) startLogin(w http.ResponseWriter, provider *oauth.Provider, linkUserID *uuid.UUID) (string, error) {
+	state, err := oauth.NewLoginState(provider.Name, linkUserID, h.Secret, loginStateTTL)
 	if err != nil {
-		msg := fmt.Sprintf("failed generating state: %v", err)
-		http.Error(w, msg, http.StatusInternalServerError)
-		return
+		return "", err
 	}
 	http.SetCookie(w, &http.Cookie{
-		Name:     "oidc_state",
+		Name:     stateCookie,
 		Value:    state,
 		Path:     "/",
 		HttpOnly: true,
-		MaxAge:   300,
+		Secure:   h.Session.SecureCookies,
+		MaxAge:   int(loginStateTTL.Seconds()),
 	})
-	url := h.OAuth.AuthCodeURL(state)
+	return provider.OAuth.AuthCodeURL(state), nil
+}
+
+// ListProviders lists the identity providers users can sign in with.
+// @Summary      List login providers
+// @Description  Lists the identity providers users can sign in with, the first one is the default.
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {object}  ListProvidersResponse
+// @Router       /oauth/providers [get]
+func (h *Handler) ListProviders(w http.ResponseWriter, r *http.Request) {
+	resp := ListProvidersResponse{Providers: make([]Provider, len(h.Providers))}
+	for i, p := range h.Providers {
+		resp.Providers[i] = Provider{Name: p.Name, DisplayName: p.DisplayName}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	provider := h.provider(r.URL.Query().Get("provider"))
+	if provider == nil {
+		http.Error(w, "unknown provider", http.StatusBadRequest)
+		return
+	}
+
+	url, err := h.startLogin(w, provider, nil)
+	if err != nil {
+		msg := fmt.Sprintf("failed generating state: %v", err)
+		http.Error(w, msg, http.StatusInternalServerError)
+		return
+	}
 	http.Redirect(w, r, url, http.StatusFound)
 }
 
 func (h *Handler) Callback(w http.ResponseWriter, r *http.Request) {
-	stateCookie, err := r.Cookie("oidc_state")
-	if err != nil || r.URL.Query().Get("state") != stateCookie.Value {
+	rawState := r.URL.Query().Get("state")
+	cookie, err := r.Cookie(stateCookie)
+	if err != nil || rawState != cookie.Value {
 		http.Error(w, "invalid state", http.StatusBadRequest)
 		return
 	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     stateCookie,
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   h.Session.SecureCookies,
+		MaxAge:   -1,
+	})
 
-	oauthToken, err := h.OAuth.Exchange(r.Context(), r.URL.Query().Get("code"))
+	state, err := oauth.VerifyLoginState(rawState, h.Secret)
+	if err != nil {
+		http.Error(w, "invalid state", http.StatusBadRequest)
+		return
+	}
+	provider := h.provider(state.Provider)
+	if provider == nil {
+		http.Error(w, "unknown provider", http.StatusBadRequest)
+		return
+	}
+
+	oauthToken, err := provider.OAuth.Exchange(r.Context(), r.URL.Query().Get("code"))
 	if err != nil {
 		msg := fmt.Sprintf("token exchange failed: %v", err)
 		http.Error(w, msg, http.StatusInternalServerError)
@@ -50,7 +139,8 @@ func (h *Handler) Callback(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "no 'id_token' in token response", http.StatusInternalServerError)
 		return
 	}
-	idToken, err := h.Verifier.Verify(r.Context(), rawID.(string))
+	rawIDToken := rawID.(string)
+	idToken, err := provider.Verifier.Verify(r.Context(), rawIDToken)
 	if err != nil {
 		msg := fmt.Sprintf("invalid id_token: %v", err)
 		http.Error(w, msg, http.StatusUnauthorized)
@@ -64,39 +154,93 @@ func (h *Handler) Callback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.Service.Repo.GetUserBySub(r.Context(), claims.Sub)
-	userExists := true
+	if state.LinkUserID != nil {
+		_, err := h.Service.LinkIdentity(r.Context(), service.LinkIdentityRequest{
+			UserID:        *state.LinkUserID,
+			Provider:      provider.Name,
+			Issuer:        idToken.Issuer,
+			Sub:           idToken.Subject,
+			Email:         claims.Email,
+			EmailVerified: bool(claims.EmailVerified),
+		})
+		if err != nil {
+			var serviceErr service.ServiceError
+			if errors.As(err, &serviceErr) {
+				http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+				return
+			} else {
+				http.Error(w, "failed to link identity", http.StatusBadRequest)
+				return
+			}
+		}
+		http.Redirect(w, r, "/user", http.StatusSeeOther)
+		return
+	}
+
+	claims.Groups, err = oauth.GetGroups(idToken, h.Roles)
+	if err != nil {
+		msg := fmt.Sprintf("could not parse groups: %v", err)
+		http.Error(w, msg, http.StatusInternalServerError)
+		return
+	}
+	role, allowed := oauth.GetRole(claims.Groups, h.Roles)
+	if !allowed {
+		http.Error(w, "you are not a member of a group allowed to use gazette", http.StatusForbidden)
+		return
+	}
+
+	// accounts of releases supporting a single provider are claimed only
+	// through the default provider
+	var legacyIssuer string
+	if p := h.provider(config.DefaultProviderName); p != nil {
+		legacyIssuer = p.Issuer
+	}
+	user, err := h.Service.SignIn(r.Context(), service.SignInRequest{
+		Provider:      provider.Name,
+		Issuer:        idToken.Issuer,
+		Sub:           idToken.Subject,
+		Name:          claims.Name,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Role:          role,
+		LegacyIssuer:  legacyIssuer,
+	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			userExists = false
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
 		} else {
-			http.Error(w, "failed to fetch user", http.StatusInternalServerError)
+			http.Error(w, "failed to sign in", http.StatusBadRequest)
 			return
 		}
 	}
 
-	if !userExists {
-		user, err = h.Service.Repo.CreateUser(r.Context(), repository.CreateUserParams{
-			Sub:   claims.Sub,
-			Name:  claims.Name,
-			Email: claims.Email,
-		})
-		if err != nil {
-			msg := fmt.Sprintf("failed to create user: %v", err)
-			http.Error(w, msg, http.StatusInternalServerError)
-			return
-		}
+	session, err := h.Service.CreateSession(r.Context(), service.CreateSessionRequest{
+		UserID:    user.ID,
+		Device:    deviceName(r.UserAgent()),
+		IP:        clientIP(r),
+		UserAgent: r.UserAgent(),
+		IDToken:   &rawIDToken,
+		Provider:  provider.Name,
+		TTL:       h.Session.RefreshTokenTTL,
+	})
+	if err != nil {
+		msg := fmt.Sprintf("failed to create session: %v", err)
+		http.Error(w, msg, http.StatusInternalServerError)
+		return
 	}
 
 	appToken, err := jwt.NewWithClaims(
 		jwt.SigningMethodHS256,
-		claims.GetAppClaims(user.ID, time.Hour),
+		claims.GetSessionClaims(user.ID, session.Session.ID, user.Role, h.Session.AccessTokenTTL),
 	).SignedString(h.Secret)
 	if err != nil {
 		msg := fmt.Sprintf("failed to sign app token: %v", err)
 		http.Error(w, msg, http.StatusInternalServerError)
 		return
 	}
+	h.setRefreshCookie(w, session.RefreshToken)
 
 	baseURL := "/callback"
 	params := url.Values{}
@@ -111,3 +255,130 @@ func (h *Handler) Callback(w http.ResponseWriter, r *http.Request) {
 	url.RawQuery = params.Encode()
 	http.Redirect(w, r, url.String(), http.StatusSeeOther)
 }
+
+// Refresh exchanges a refresh token for a new access token and rotates the
+// refresh token. Browsers send the token in a cookie, other clients in the
+// request body.
+// @Summary      Refresh access token
+// @Description  Exchanges a refresh token, from the session cookie or request body, for a new access token. The refresh token is rotated on every use and reusing an old one revokes the session.
+// @Tags         Auth
+// @Param        body    body      RefreshTokenRequest  false  "Refresh token for non-browser clients"
+// @Success      200     {object}  TokenResponse
+// @Failure      400     {object}  string
+// @Failure      401     {object}  string
+// @Failure      500     {object}  string
+// @Router       /oauth/refresh [post]
+func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshTokenRequest
+	fromCookie := false
+	if cookie, err := r.Cookie(refreshCookie); err == nil && cookie.Value != "" {
+		req.RefreshToken = cookie.Value
+		fromCookie = true
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RefreshToken == "" {
+		http.Error(w, "refresh token not found", http.StatusBadRequest)
+		return
+	}
+
+	session, err := h.Service.RefreshSession(r.Context(), service.RefreshSessionRequest{
+		RefreshToken: req.RefreshToken,
+		IP:           clientIP(r),
+		UserAgent:    r.UserAgent(),
+		TTL:          h.Session.RefreshTokenTTL,
+	})
+	if err != nil {
+		if fromCookie {
+			h.clearRefreshCookie(w)
+		}
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to refresh session", http.StatusBadRequest)
+			return
+		}
+	}
+
+	claims := oauth.ProviderClaims{
+		Name:  session.User.Name,
+		Email: session.User.Email,
+		Sub:   session.User.Sub,
+	}
+	appToken, err := jwt.NewWithClaims(
+		jwt.SigningMethodHS256,
+		claims.GetSessionClaims(session.User.ID, session.Session.ID, session.User.Role, h.Session.AccessTokenTTL),
+	).SignedString(h.Secret)
+	if err != nil {
+		msg := fmt.Sprintf("failed to sign app token: %v", err)
+		http.Error(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	resp := TokenResponse{
+		Token:     appToken,
+		ExpiresIn: int64(h.Session.AccessTokenTTL.Seconds()),
+	}
+	if fromCookie {
+		h.setRefreshCookie(w, session.RefreshToken)
+	} else {
+		resp.RefreshToken = session.RefreshToken
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Logout revokes the current session and returns the identity provider's
+// end session URL, the session is identified by the refresh cookie or the
+// access token.
+// @Summary      Log out
+// @Description  Revokes the current session and returns the URL that ends the session at the identity provider.
+// @Tags         Auth
+// @Success      200     {object}  LogoutResponse
+// @Failure      500     {object}  string
+// @Router       /oauth/logout [post]
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	var session *service.Session
+	var userID uuid.UUID
+	var err error
+
+	if cookie, cookieErr := r.Cookie(refreshCookie); cookieErr == nil && cookie.Value != "" {
+		session, userID, err = h.Service.GetSessionByRefreshToken(r.Context(), cookie.Value)
+	} else if rawToken, tokenErr := oauth.ExtractTokenFromHeaders(r); tokenErr == nil {
+		var claims *oauth.ApplicationClaims
+		if claims, err = oauth.VerifyToken(rawToken, h.Secret); err == nil {
+			session, userID, err = h.Service.GetSession(r.Context(), claims.SessionID)
+		}
+	}
+	h.clearRefreshCookie(w)
+
+	var idToken, sessionProvider string
+	if err == nil && session != nil {
+		err = h.Service.RevokeSession(r.Context(), repository.RevokeSessionParams{
+			ID:     session.ID,
+			UserID: userID,
+		})
+		var serviceErr service.ServiceError
+		if err != nil && (!errors.As(err, &serviceErr) || serviceErr.Code != http.StatusNotFound) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if session.IDToken != nil {
+			idToken = *session.IDToken
+		}
+		sessionProvider = session.Provider
+	}
+
+	var endSession *oauth.EndSession
+	if provider := h.provider(sessionProvider); provider != nil {
+		endSession = provider.EndSession
+	}
+
+	resp := LogoutResponse{RedirectURL: endSession.URL(idToken)}
+	if resp.RedirectURL == "" {
+		resp.RedirectURL = "/login"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/feeds"
+	"github.com/rhajizada/gazette/internal/service"
+)
+
+// publicFeedLimit is the number of items included in the feeds of a public
+// collection.
+const publicFeedLimit = 50
+
+// publicCacheControl lets readers and proxies cache public collections for a
+// few minutes.
+const publicCacheControl = "public, max-age=300"
+
+// publicCollectionURL returns the absolute URL of a public collection under
+// the configured public URL, or the host of the request when none is set.
+// Responses are cached by proxies, so X-Forwarded-* headers, which any
+// client can send, are not used.
+func (h *Handler) publicCollectionURL(r *http.Request, slug string) string {
+	base := h.PublicURL
+	if base == "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		base = scheme + "://" + r.Host
+	}
+	return fmt.Sprintf("%s/public/collections/%s", base, slug)
+}
+
+// GetPublicCollection returns a published collection.
+// @Summary      Get public collection
+// @Description  Retrieves a published collection and its items by slug without authentication. limit and offset are optional and default to the first 100 items.
+// @Tags         Public
+// @Produce      json
+// @Param        slug    path      string  true   "Collection slug"
+// @Param        limit   query     int32   false  "Max number of items"
+// @Param        offset  query     int32   false  "Number of items to skip"
+// @Success      200     {object}  PublicCollectionResponse
+// @Failure      400     {object}  string
+// @Failure      404     {object}  string
+// @Failure      500     {object}  string
+// @Router       /public/collections/{slug} [get]
+func (h *Handler) GetPublicCollection(w http.ResponseWriter, r *http.Request) {
+	slug := r.PathValue("slug")
+	params := PageParams{Limit: MaxLimit}
+	if q := r.URL.Query(); q.Has("limit") || q.Has("offset") {
+		var err error
+		params, err = getPageParams(q)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	col, err := h.Service.GetPublicCollection(r.Context(), service.GetPublicCollectionRequest{
+		Slug:   slug,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to get collection %s", slug), http.StatusBadRequest)
+			return
+		}
+	}
+
+	base := h.publicCollectionURL(r, slug)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", publicCacheControl)
+	json.NewEncoder(w).Encode(PublicCollectionResponse{
+		PublicCollection: *col,
+		Feeds: PublicCollectionFeeds{
+			RSS:      base + "/rss.xml",
+			Atom:     base + "/atom.xml",
+			JSONFeed: base + "/feed.json",
+		},
+	})
+}
+
+// GetPublicCollectionRSS returns a published collection as an RSS 2.0 feed.
+// @Summary      Public collection RSS feed
+// @Description  Returns the latest items of a published collection as an RSS 2.0 feed.
+// @Tags         Public
+// @Produce      application/rss+xml
+// @Param        slug  path      string  true  "Collection slug"
+// @Success      200   {file}    file    "RSS feed"
+// @Failure      404   {object}  string
+// @Failure      500   {object}  string
+// @Router       /public/collections/{slug}/rss.xml [get]
+func (h *Handler) GetPublicCollectionRSS(w http.ResponseWriter, r *http.Request) {
+	h.writePublicFeed(w, r, "application/rss+xml; charset=utf-8", (*feeds.Feed).WriteRss)
+}
+
+// GetPublicCollectionAtom returns a published collection as an Atom feed.
+// @Summary      Public collection Atom feed
+// @Description  Returns the latest items of a published collection as an Atom feed.
+// @Tags         Public
+// @Produce      application/atom+xml
+// @Param        slug  path      string  true  "Collection slug"
+// @Success      200   {file}    file    "Atom feed"
+// @Failure      404   {object}  string
+// @Failure      500   {object}  string
+// @Router       /public/collections/{slug}/atom.xml [get]
+func (h *Handler) GetPublicCollectionAtom(w http.ResponseWriter, r *http.Request) {
+	h.writePublicFeed(w, r, "application/atom+xml; charset=utf-8", (*feeds.Feed).WriteAtom)
+}
+
+// GetPublicCollectionJSONFeed returns a published collection as a JSON Feed.
+// @Summary      Public collection JSON Feed
+// @Description  Returns the latest items of a published collection as a JSON Feed.
+// @Tags         Public
+// @Produce      application/feed+json
+// @Param        slug  path      string  true  "Collection slug"
+// @Success      200   {file}    file    "JSON Feed"
+// @Failure      404   {object}  string
+// @Failure      500   {object}  string
+// @Router       /public/collections/{slug}/feed.json [get]
+func (h *Handler) GetPublicCollectionJSONFeed(w http.ResponseWriter, r *http.Request) {
+	h.writePublicFeed(w, r, "application/feed+json; charset=utf-8", (*feeds.Feed).WriteJSON)
+}
+
+// writePublicFeed renders the latest items of a published collection with
+// write.
+func (h *Handler) writePublicFeed(w http.ResponseWriter, r *http.Request, contentType string, write func(*feeds.Feed, io.Writer) error) {
+	slug := r.PathValue("slug")
+	col, err := h.Service.GetPublicCollection(r.Context(), service.GetPublicCollectionRequest{
+		Slug:  slug,
+		Limit: publicFeedLimit,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to get collection %s", slug), http.StatusBadRequest)
+			return
+		}
+	}
+
+	link := h.publicCollectionURL(r, slug)
+	feed := &feeds.Feed{
+		Title:   col.Name,
+		Link:    &feeds.Link{Href: link},
+		Id:      link,
+		Updated: col.LastUpdated,
+	}
+	if col.Description != nil {
+		feed.Description = *col.Description
+	}
+	for _, item := range col.Items {
+		fi := &feeds.Item{
+			Link:    &feeds.Link{Href: item.Link},
+			Id:      "urn:uuid:" + item.ID.String(),
+			Created: item.AddedAt,
+			Updated: item.AddedAt,
+		}
+		if item.Title != nil {
+			fi.Title = *item.Title
+		}
+		if item.Description != nil {
+			fi.Description = *item.Description
+		}
+		if item.Content != nil {
+			fi.Content = *item.Content
+		}
+		if item.UpdatedParsed != nil {
+			fi.Updated = *item.UpdatedParsed
+		}
+		if len(item.Authors) > 0 {
+			fi.Author = &feeds.Author{Name: item.Authors[0].Name, Email: item.Authors[0].Email}
+		}
+		feed.Add(fi)
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", publicCacheControl)
+	if err := write(feed, w); err != nil {
+		http.Error(w, fmt.Sprintf("failed to write feed: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
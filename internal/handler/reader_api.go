@@ -0,0 +1,729 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/middleware"
+	"github.com/rhajizada/gazette/internal/oauth"
+	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/service"
+)
+
+const (
+	// ReaderTokenTTL is the lifetime of tokens issued by ClientLogin.
+	ReaderTokenTTL = 7 * 24 * time.Hour
+	// ReaderMaxItems caps the number of items returned by a single stream request.
+	ReaderMaxItems = 1000
+	// ReaderDefaultItems is the number of items returned when n is not set.
+	ReaderDefaultItems = 20
+)
+
+const (
+	readerItemPrefix  = "tag:google.com,2005:reader/item/"
+	readerFeedPrefix  = "feed/"
+	readerLabelPrefix = "user/-/label/"
+	readerStatePrefix = "user/-/state/com.google/"
+	readerReadingList = readerStatePrefix + "reading-list"
+	readerRead        = readerStatePrefix + "read"
+	readerStarred     = readerStatePrefix + "starred"
+	readerKeptUnread  = readerStatePrefix + "kept-unread"
+)
+
+// readerStream is a Google Reader stream resolved to item filters.
+type readerStream struct {
+	FeedID       *uuid.UUID
+	CollectionID *uuid.UUID
+	LikedOnly    bool
+	Read         *bool
+}
+
+// normalizeStreamID replaces the user ID in user scoped stream IDs with “-”.
+func normalizeStreamID(id string) string {
+	if !strings.HasPrefix(id, "user/") {
+		return id
+	}
+	rest := strings.TrimPrefix(id, "user/")
+	if i := strings.Index(rest, "/"); i >= 0 {
+		return "user/-" + rest[i:]
+	}
+	return id
+}
+
+// resolveStream maps a stream ID to item filters.
+func (h *Handler) resolveStream(ctx context.Context, userID uuid.UUID, id string) (readerStream, error) {
+	var stream readerStream
+	id = normalizeStreamID(id)
+	switch {
+	case id == "" || id == readerReadingList:
+	case id == readerStarred:
+		stream.LikedOnly = true
+	case id == readerRead:
+		read := true
+		stream.Read = &read
+	case id == readerKeptUnread:
+		read := false
+		stream.Read = &read
+	case strings.HasPrefix(id, readerFeedPrefix):
+		feedID, err := uuid.Parse(strings.TrimPrefix(id, readerFeedPrefix))
+		if err != nil {
+			return stream, service.NewError(fmt.Sprintf("unknown stream %s", id), http.StatusBadRequest)
+		}
+		stream.FeedID = &feedID
+	case strings.HasPrefix(id, readerLabelPrefix):
+		col, err := h.Service.GetCollectionByName(ctx, repository.GetCollectionByNameParams{
+			UserID: userID,
+			Name:   strings.TrimPrefix(id, readerLabelPrefix),
+		})
+		if err != nil {
+			return stream, err
+		}
+		stream.CollectionID = &col.ID
+	default:
+		return stream, service.NewError(fmt.Sprintf("unknown stream %s", id), http.StatusBadRequest)
+	}
+	return stream, nil
+}
+
+// parseItemID accepts item IDs in the long form, hexadecimal or decimal form.
+func parseItemID(id string) (int64, error) {
+	if strings.HasPrefix(id, readerItemPrefix) {
+		v, err := strconv.ParseUint(strings.TrimPrefix(id, readerItemPrefix), 16, 64)
+		return int64(v), err
+	}
+	if v, err := strconv.ParseInt(id, 10, 64); err == nil {
+		return v, nil
+	}
+	v, err := strconv.ParseUint(id, 16, 64)
+	return int64(v), err
+}
+
+func formatItemID(seq int64) string {
+	return fmt.Sprintf("%s%016x", readerItemPrefix, uint64(seq))
+}
+
+func parseItemIDs(values []string) ([]int64, error) {
+	seqs := make([]int64, 0, len(values))
+	for _, v := range values {
+		seq, err := parseItemID(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid item id", v)
+		}
+		seqs = append(seqs, seq)
+	}
+	return seqs, nil
+}
+
+func usec(t time.Time) string {
+	return strconv.FormatInt(t.UnixMicro(), 10)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// streamParams holds common stream request parameters.
+type streamParams struct {
+	StreamID    string
+	Count       int32
+	Offset      int32
+	OldestFirst bool
+	Read        *bool
+	NewerThan   *time.Time
+	OlderThan   *time.Time
+}
+
+func parseUnix(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	sec, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	t := time.Unix(sec, 0)
+	return &t, nil
+}
+
+func getStreamParams(r *http.Request, streamID string) (streamParams, error) {
+	params := streamParams{
+		StreamID: streamID,
+		Count:    ReaderDefaultItems,
+	}
+	if params.StreamID == "" {
+		params.StreamID = r.Form.Get("s")
+	}
+
+	if n := r.Form.Get("n"); n != "" {
+		v, err := strconv.ParseInt(n, 10, 32)
+		if err != nil || v < 1 {
+			return params, errors.New("invalid n")
+		}
+		params.Count = int32(min(v, ReaderMaxItems))
+	}
+	if c := r.Form.Get("c"); c != "" {
+		v, err := strconv.ParseInt(c, 10, 32)
+		if err != nil || v < 0 {
+			return params, errors.New("invalid continuation")
+		}
+		params.Offset = int32(v)
+	}
+	params.OldestFirst = r.Form.Get("r") == "o"
+
+	switch normalizeStreamID(r.Form.Get("xt")) {
+	case "":
+	case readerRead:
+		read := false
+		params.Read = &read
+	default:
+		return params, errors.New("unsupported xt")
+	}
+	switch normalizeStreamID(r.Form.Get("it")) {
+	case "":
+	case readerRead:
+		read := true
+		params.Read = &read
+	default:
+		return params, errors.New("unsupported it")
+	}
+
+	var err error
+	if params.NewerThan, err = parseUnix(r.Form.Get("ot")); err != nil {
+		return params, errors.New("invalid ot")
+	}
+	if params.OlderThan, err = parseUnix(r.Form.Get("nt")); err != nil {
+		return params, errors.New("invalid nt")
+	}
+	return params, nil
+}
+
+func continuation(params streamParams, count int) string {
+	if count < int(params.Count) {
+		return ""
+	}
+	return strconv.FormatInt(int64(params.Offset)+int64(count), 10)
+}
+
+func writeServiceError(w http.ResponseWriter, err error, fallback string) {
+	var serviceErr service.ServiceError
+	if errors.As(err, &serviceErr) {
+		http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+		return
+	}
+	http.Error(w, fallback, http.StatusBadRequest)
+}
+
+func toReaderItem(item service.StreamItem, labels []string) ReaderItem {
+	published := item.CreatedAt
+	if item.PublishedParsed != nil {
+		published = *item.PublishedParsed
+	}
+
+	categories := []string{readerReadingList}
+	if item.Read {
+		categories = append(categories, readerRead)
+	}
+	if item.Liked {
+		categories = append(categories, readerStarred)
+	}
+	categories = append(categories, labels...)
+
+	var author string
+	if len(item.Authors) > 0 {
+		author = item.Authors[0].Name
+	}
+
+	content := deref(item.Content)
+	if content == "" {
+		content = deref(item.Description)
+	}
+
+	return ReaderItem{
+		ID:            formatItemID(item.Seq),
+		CrawlTimeMsec: strconv.FormatInt(item.CreatedAt.UnixMilli(), 10),
+		TimestampUsec: usec(item.CreatedAt),
+		Published:     published.Unix(),
+		Updated:       item.UpdatedAt.Unix(),
+		Title:         deref(item.Title),
+		Author:        author,
+		Canonical:     []ReaderLink{{Href: item.Link}},
+		Alternate:     []ReaderLink{{Href: item.Link, Type: "text/html"}},
+		Categories:    categories,
+		Origin: ReaderOrigin{
+			StreamID: readerFeedPrefix + item.FeedID.String(),
+			Title:    deref(item.FeedTitle),
+			HTMLURL:  deref(item.FeedSiteLink),
+		},
+		Summary: ReaderContent{
+			Direction: "ltr",
+			Content:   content,
+		},
+	}
+}
+
+// ReaderClientLogin authenticates a reader client with the user's email and
+// an app password, and returns a token for the GoogleLogin auth scheme.
+func (h *Handler) ReaderClientLogin(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Error=BadAuthentication", http.StatusBadRequest)
+		return
+	}
+
+	login, err := h.Service.AuthenticateAppPassword(r.Context(), service.AuthenticateAppPasswordRequest{
+		Email:    r.Form.Get("Email"),
+		Password: r.Form.Get("Passwd"),
+	})
+	if err != nil {
+		http.Error(w, "Error=BadAuthentication", http.StatusUnauthorized)
+		return
+	}
+
+	user := login.User
+	claims := oauth.ProviderClaims{
+		Name:  user.Name,
+		Email: user.Email,
+		Sub:   user.Sub,
+	}
+	token, err := jwt.NewWithClaims(
+		jwt.SigningMethodHS256,
+		claims.GetAppPasswordClaims(user.ID, login.AppPasswordID, user.Role, ReaderTokenTTL),
+	).SignedString(h.Secret)
+	if err != nil {
+		http.Error(w, "Error=Unknown", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "SID=%s\nLSID=%s\nAuth=%s\n", token, token, token)
+}
+
+// ReaderToken returns a short lived token for write requests, gazette relies
+// on the Authorization header instead so the token is informational only.
+func (h *Handler) ReaderToken(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, strings.ReplaceAll(userID.String(), "-", ""))
+}
+
+// ReaderUserInfo returns the current user.
+func (h *Handler) ReaderUserInfo(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	user, err := h.Service.GetUserByID(r.Context(), userID)
+	if err != nil {
+		writeServiceError(w, err, "failed to fetch user")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ReaderUserInfo{
+		UserID:        user.ID.String(),
+		UserName:      user.Name,
+		UserProfileID: user.ID.String(),
+		UserEmail:     user.Email,
+		SignupTimeSec: user.CreatedAt.Unix(),
+	})
+}
+
+// ReaderSubscriptionList returns feeds the user is subscribed to.
+func (h *Handler) ReaderSubscriptionList(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	subs := make([]ReaderSubscription, 0)
+	for offset := int32(0); ; offset += MaxLimit {
+		resp, err := h.Service.ListFeeds(r.Context(), service.ListFeedsRequest{
+			UserID:       userID,
+			SubscbedOnly: true,
+			Limit:        MaxLimit,
+			Offset:       offset,
+		})
+		if err != nil {
+			writeServiceError(w, err, "failed to list subscriptions")
+			return
+		}
+		for _, f := range resp.Feeds {
+			title := deref(f.Title)
+			if f.Subscription != nil && f.Subscription.Title != nil {
+				title = *f.Subscription.Title
+			}
+			if title == "" {
+				title = f.FeedLink
+			}
+			subs = append(subs, ReaderSubscription{
+				ID:         readerFeedPrefix + f.ID.String(),
+				Title:      title,
+				Categories: []ReaderCategory{},
+				URL:        f.FeedLink,
+				HTMLURL:    deref(f.Link),
+			})
+		}
+		if len(resp.Feeds) < MaxLimit {
+			break
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ReaderSubscriptionList{Subscriptions: subs})
+}
+
+// ReaderTagList returns the starred state and the user's collections as labels.
+func (h *Handler) ReaderTagList(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	tags := []ReaderTag{{ID: readerStarred}}
+	for offset := int32(0); ; offset += MaxLimit {
+		resp, err := h.Service.ListCollections(r.Context(), repository.ListCollectionsByUserParams{
+			UserID: userID,
+			Limit:  MaxLimit,
+			Offset: offset,
+		})
+		if err != nil {
+			writeServiceError(w, err, "failed to list tags")
+			return
+		}
+		for _, c := range resp.Collections {
+			// labels are looked up by name among the user's own collections
+			if c.Role != service.CollectionRoleOwner {
+				continue
+			}
+			tags = append(tags, ReaderTag{ID: readerLabelPrefix + c.Name, Type: "tag"})
+		}
+		if len(resp.Collections) < MaxLimit {
+			break
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ReaderTagList{Tags: tags})
+}
+
+// ReaderUnreadCount returns the number of unread items per subscription.
+func (h *Handler) ReaderUnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	counts, err := h.Service.CountUnreadItems(r.Context(), userID)
+	if err != nil {
+		writeServiceError(w, err, "failed to count unread items")
+		return
+	}
+
+	resp := ReaderUnreadCounts{
+		Max:          ReaderMaxItems,
+		UnreadCounts: make([]ReaderUnreadCount, 0, len(counts)+1),
+	}
+	var total int64
+	var newest time.Time
+	for _, c := range counts {
+		total += c.Count
+		if c.NewestItemAt.After(newest) {
+			newest = c.NewestItemAt
+		}
+		resp.UnreadCounts = append(resp.UnreadCounts, ReaderUnreadCount{
+			ID:                      readerFeedPrefix + c.FeedID.String(),
+			Count:                   c.Count,
+			NewestItemTimestampUsec: usec(c.NewestItemAt),
+		})
+	}
+	resp.UnreadCounts = append(resp.UnreadCounts, ReaderUnreadCount{
+		ID:                      readerReadingList,
+		Count:                   total,
+		NewestItemTimestampUsec: usec(newest),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ReaderStreamContents returns items of a stream, the stream is taken from the
+// path or from the s parameter.
+func (h *Handler) ReaderStreamContents(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params, err := getStreamParams(r, r.PathValue("stream"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if params.StreamID == "" {
+		params.StreamID = readerReadingList
+	}
+
+	stream, err := h.resolveStream(r.Context(), userID, params.StreamID)
+	if err != nil {
+		writeServiceError(w, err, "failed to resolve stream")
+		return
+	}
+	if stream.Read == nil {
+		stream.Read = params.Read
+	}
+
+	items, err := h.Service.ListStreamItems(r.Context(), repository.ListStreamItemsParams{
+		UserID:       userID,
+		FeedID:       stream.FeedID,
+		CollectionID: stream.CollectionID,
+		LikedOnly:    stream.LikedOnly,
+		Read:         stream.Read,
+		NewerThan:    params.NewerThan,
+		OlderThan:    params.OlderThan,
+		OldestFirst:  params.OldestFirst,
+		Limit:        params.Count,
+		Offset:       params.Offset,
+	})
+	if err != nil {
+		writeServiceError(w, err, "failed to list items")
+		return
+	}
+
+	var labels []string
+	if stream.CollectionID != nil {
+		labels = []string{normalizeStreamID(params.StreamID)}
+	}
+	resp := ReaderStreamContents{
+		ID:           params.StreamID,
+		Updated:      time.Now().Unix(),
+		Items:        make([]ReaderItem, len(items)),
+		Continuation: continuation(params, len(items)),
+	}
+	for i, item := range items {
+		resp.Items[i] = toReaderItem(item, labels)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ReaderStreamItemIDs returns item references of a stream.
+func (h *Handler) ReaderStreamItemIDs(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params, err := getStreamParams(r, "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stream, err := h.resolveStream(r.Context(), userID, params.StreamID)
+	if err != nil {
+		writeServiceError(w, err, "failed to resolve stream")
+		return
+	}
+	if stream.Read == nil {
+		stream.Read = params.Read
+	}
+
+	refs, err := h.Service.ListStreamItemRefs(r.Context(), repository.ListStreamItemRefsParams{
+		UserID:       userID,
+		FeedID:       stream.FeedID,
+		CollectionID: stream.CollectionID,
+		LikedOnly:    stream.LikedOnly,
+		Read:         stream.Read,
+		NewerThan:    params.NewerThan,
+		OlderThan:    params.OlderThan,
+		OldestFirst:  params.OldestFirst,
+		Limit:        params.Count,
+		Offset:       params.Offset,
+	})
+	if err != nil {
+		writeServiceError(w, err, "failed to list items")
+		return
+	}
+
+	resp := ReaderItemRefs{
+		ItemRefs:     make([]ReaderItemRef, len(refs)),
+		Continuation: continuation(params, len(refs)),
+	}
+	for i, ref := range refs {
+		resp.ItemRefs[i] = ReaderItemRef{
+			ID:              strconv.FormatInt(ref.Seq, 10),
+			DirectStreamIDs: []string{},
+			TimestampUsec:   usec(ref.CreatedAt),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ReaderStreamItemContents returns items by their IDs, passed as i parameters.
+func (h *Handler) ReaderStreamItemContents(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	seqs, err := parseItemIDs(r.Form["i"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(seqs) > ReaderMaxItems {
+		http.Error(w, fmt.Sprintf("max number of items is %d", ReaderMaxItems), http.StatusBadRequest)
+		return
+	}
+
+	items, err := h.Service.ListStreamItemsBySeqs(r.Context(), repository.ListStreamItemsBySeqsParams{
+		UserID: userID,
+		Seqs:   seqs,
+	})
+	if err != nil {
+		writeServiceError(w, err, "failed to list items")
+		return
+	}
+
+	resp := ReaderStreamContents{
+		ID:      readerReadingList,
+		Updated: time.Now().Unix(),
+		Items:   make([]ReaderItem, len(items)),
+	}
+	for i, item := range items {
+		resp.Items[i] = toReaderItem(item, nil)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// applyTag adds or removes a single tag from an item. Errors caused by the
+// state already being applied are ignored.
+func (h *Handler) applyTag(ctx context.Context, userID, itemID uuid.UUID, tag string, add bool) error {
+	var err error
+	tag = normalizeStreamID(tag)
+	switch {
+	case tag == readerRead && add:
+		err = h.Service.MarkItemRead(ctx, repository.CreateUserReadParams{UserID: userID, ItemID: itemID})
+	case tag == readerRead:
+		err = h.Service.MarkItemUnread(ctx, repository.DeleteUserReadParams{UserID: userID, ItemID: itemID})
+	case tag == readerKeptUnread && add:
+		err = h.Service.MarkItemUnread(ctx, repository.DeleteUserReadParams{UserID: userID, ItemID: itemID})
+	case tag == readerStarred && add:
+		_, err = h.Service.LikeItem(ctx, repository.CreateUserLikeParams{UserID: userID, ItemID: itemID})
+	case tag == readerStarred:
+		err = h.Service.UnlikeItem(ctx, repository.DeleteUserLikeParams{UserID: userID, ItemID: itemID})
+	case strings.HasPrefix(tag, readerLabelPrefix):
+		name := strings.TrimPrefix(tag, readerLabelPrefix)
+		var col *service.Collection
+		col, err = h.Service.GetCollectionByName(ctx, repository.GetCollectionByNameParams{UserID: userID, Name: name})
+		if err != nil {
+			var serviceErr service.ServiceError
+			if !add || !errors.As(err, &serviceErr) || serviceErr.Code != http.StatusNotFound {
+				break
+			}
+			col, err = h.Service.CreateCollection(ctx, repository.CreateCollectionParams{UserID: userID, Name: name})
+			if err != nil {
+				break
+			}
+		}
+		if add {
+			_, err = h.Service.AddItemToCollection(ctx, repository.AddItemToCollectionParams{CollectionID: col.ID, ItemID: itemID, UserID: userID})
+		} else {
+			err = h.Service.RemoveItemFromCollection(ctx, repository.RemoveItemFromCollectionParams{CollectionID: col.ID, ItemID: itemID, UserID: userID})
+		}
+	}
+
+	var serviceErr service.ServiceError
+	if errors.As(err, &serviceErr) && serviceErr.Code < http.StatusInternalServerError {
+		return nil
+	}
+	return err
+}
+
+// ReaderEditTag adds (a) and removes (r) tags on items (i).
+func (h *Handler) ReaderEditTag(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	seqs, err := parseItemIDs(r.Form["i"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ids, err := h.Service.ResolveItemSeqs(r.Context(), seqs)
+	if err != nil {
+		writeServiceError(w, err, "failed to resolve items")
+		return
+	}
+
+	for _, id := range ids {
+		for _, tag := range r.Form["a"] {
+			if err := h.applyTag(r.Context(), userID, id, tag, true); err != nil {
+				writeServiceError(w, err, "failed to edit tags")
+				return
+			}
+		}
+		for _, tag := range r.Form["r"] {
+			if err := h.applyTag(r.Context(), userID, id, tag, false); err != nil {
+				writeServiceError(w, err, "failed to edit tags")
+				return
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, "OK")
+}
+
+// ReaderMarkAllAsRead marks all items of a stream (s) crawled before ts
+// (microseconds) as read.
+func (h *Handler) ReaderMarkAllAsRead(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	stream, err := h.resolveStream(r.Context(), userID, r.Form.Get("s"))
+	if err != nil {
+		writeServiceError(w, err, "failed to resolve stream")
+		return
+	}
+
+	var before *time.Time
+	if ts := r.Form.Get("ts"); ts != "" {
+		v, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid ts", http.StatusBadRequest)
+			return
+		}
+		t := time.UnixMicro(v)
+		before = &t
+	}
+
+	_, err = h.Service.MarkStreamRead(r.Context(), repository.MarkStreamItemsReadParams{
+		UserID:       userID,
+		FeedID:       stream.FeedID,
+		CollectionID: stream.CollectionID,
+		LikedOnly:    stream.LikedOnly,
+		Before:       before,
+	})
+	if err != nil {
+		writeServiceError(w, err, "failed to mark items as read")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, "OK")
+}
@@ -0,0 +1,100 @@
+package handler
+
+// Google Reader API response bodies, field names follow the original protocol.
+
+type ReaderUserInfo struct {
+	UserID              string `json:"userId"`
+	UserName            string `json:"userName"`
+	UserProfileID       string `json:"userProfileId"`
+	UserEmail           string `json:"userEmail"`
+	IsBloggerUser       bool   `json:"isBloggerUser"`
+	SignupTimeSec       int64  `json:"signupTimeSec"`
+	IsMultiLoginEnabled bool   `json:"isMultiLoginEnabled"`
+}
+
+type ReaderCategory struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+type ReaderSubscription struct {
+	ID         string           `json:"id"`
+	Title      string           `json:"title"`
+	Categories []ReaderCategory `json:"categories"`
+	URL        string           `json:"url"`
+	HTMLURL    string           `json:"htmlUrl"`
+	IconURL    string           `json:"iconUrl"`
+}
+
+type ReaderSubscriptionList struct {
+	Subscriptions []ReaderSubscription `json:"subscriptions"`
+}
+
+type ReaderTag struct {
+	ID   string `json:"id"`
+	Type string `json:"type,omitempty"`
+}
+
+type ReaderTagList struct {
+	Tags []ReaderTag `json:"tags"`
+}
+
+type ReaderUnreadCount struct {
+	ID                      string `json:"id"`
+	Count                   int64  `json:"count"`
+	NewestItemTimestampUsec string `json:"newestItemTimestampUsec"`
+}
+
+type ReaderUnreadCounts struct {
+	Max          int                 `json:"max"`
+	UnreadCounts []ReaderUnreadCount `json:"unreadcounts"`
+}
+
+type ReaderLink struct {
+	Href string `json:"href"`
+	Type string `json:"type,omitempty"`
+}
+
+type ReaderContent struct {
+	Direction string `json:"direction"`
+	Content   string `json:"content"`
+}
+
+type ReaderOrigin struct {
+	StreamID string `json:"streamId"`
+	Title    string `json:"title"`
+	HTMLURL  string `json:"htmlUrl"`
+}
+
+type ReaderItem struct {
+	ID            string        `json:"id"`
+	CrawlTimeMsec string        `json:"crawlTimeMsec"`
+	TimestampUsec string        `json:"timestampUsec"`
+	Published     int64         `json:"published"`
+	Updated       int64         `json:"updated"`
+	Title         string        `json:"title"`
+	Author        string        `json:"author,omitempty"`
+	Canonical     []ReaderLink  `json:"canonical"`
+	Alternate     []ReaderLink  `json:"alternate"`
+	Categories    []string      `json:"categories"`
+	Origin        ReaderOrigin  `json:"origin"`
+	Summary       ReaderContent `json:"summary"`
+}
+
+type ReaderStreamContents struct {
+	ID           string       `json:"id"`
+	Updated      int64        `json:"updated"`
+	Items        []ReaderItem `json:"items"`
+	Continuation string       `json:"continuation,omitempty"`
+}
+
+type ReaderItemRef struct {
+	ID              string   `json:"id"`
+	DirectStreamIDs []string `json:"directStreamIds"`
+	TimestampUsec   string   `json:"timestampUsec"`
+}
+
+type ReaderItemRefs struct {
+	ItemRefs     []ReaderItemRef `json:"itemRefs"`
+	Continuation string          `json:"continuation,omitempty"`
+}
@@ -0,0 +1,449 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/middleware"
+	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/service"
+)
+
+// ListTags returns the tags of the user.
+// @Summary      List tags
+// @Description  Lists the tags of the user, most used first. With q, lists only tags starting with q, to complete tag names.
+// @Tags         Tags
+// @Produce      json
+// @Param        q      query     string  false  "Tag name prefix"
+// @Param        limit  query     int32   false  "Max number of tags"
+// @Success      200    {object}  service.ListTagsResponse
+// @Failure      400    {object}  string
+// @Failure      500    {object}  string
+// @Security     BearerAuth
+// @Router       /api/tags [get]
+func (h *Handler) ListTags(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	limit := int32(MaxLimit)
+	if v := r.URL.Query().Get("limit"); v != "" {
+		l, err := strconv.ParseInt(v, 10, 32)
+		if err != nil || l <= 0 {
+			http.Error(w, "invalid limit type", http.StatusBadRequest)
+			return
+		}
+		if l > MaxLimit {
+			http.Error(w, fmt.Sprintf("max limit size is %d", MaxLimit), http.StatusBadRequest)
+			return
+		}
+		limit = int32(l)
+	}
+	var prefix *string
+	if v := r.URL.Query().Get("q"); v != "" {
+		prefix = &v
+	}
+
+	resp, err := h.Service.ListTags(r.Context(), service.ListTagsRequest{
+		UserID: userID,
+		Prefix: prefix,
+		Limit:  limit,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to list tags", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RenameTag renames a tag of the user.
+// @Summary      Rename tag
+// @Description  Renames a tag of the user. Renaming a tag to the name of another tag merges it into the other tag, which is returned.
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Param        tagID  path      string            true  "Tag UUID"
+// @Param        body   body      RenameTagRequest  true  "New name"
+// @Success      200    {object}  service.Tag
+// @Failure      400    {object}  string
+// @Failure      404    {object}  string
+// @Failure      500    {object}  string
+// @Security     BearerAuth
+// @Router       /api/tags/{tagID} [patch]
+func (h *Handler) RenameTag(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("tagID")
+	tagID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	var req RenameTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tag, err := h.Service.RenameTag(r.Context(), service.RenameTagRequest{
+		UserID: userID,
+		TagID:  tagID,
+		Name:   req.Name,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to rename tag %s", tagID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tag)
+}
+
+// DeleteTag deletes a tag of the user.
+// @Summary      Delete tag
+// @Description  Deletes a tag of the user and removes it from all items and feeds.
+// @Tags         Tags
+// @Param        tagID  path  string  true  "Tag UUID"
+// @Success      204
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/tags/{tagID} [delete]
+func (h *Handler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("tagID")
+	tagID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	err = h.Service.DeleteTag(r.Context(), repository.DeleteTagParams{
+		ID:     tagID,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to delete tag %s", tagID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListItemsByTag returns the items with a tag.
+// @Summary      List tagged items
+// @Description  Retrieves the items with a tag of the user and the items of subscribed feeds with the tag, newest first, paginated.
+// @Tags         Tags
+// @Produce      json
+// @Param        tagID   path      string  true  "Tag UUID"
+// @Param        limit   query     int32   true  "Max number of items"
+// @Param        offset  query     int32   true  "Number of items to skip"
+// @Success      200     {object}  service.ListItemsResponse
+// @Failure      400     {object}  string
+// @Failure      404     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/tags/{tagID}/items [get]
+func (h *Handler) ListItemsByTag(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("tagID")
+	tagID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+	params, err := getPageParams(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.ListItemsByTag(r.Context(), repository.ListItemsByTagParams{
+		UserID: userID,
+		TagID:  tagID,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to list items with tag %s", tagID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// BulkTag adds and removes tags on many items and feeds.
+// @Summary      Bulk tag
+// @Description  Adds and removes tags of the user on many items and subscribed feeds at once, creating tags that do not exist yet. Items that do not exist and feeds the user is not subscribed to are skipped.
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Param        body  body      BulkTagRequest  true  "Tags and targets"
+// @Success      200   {object}  service.BulkTagResponse
+// @Failure      400   {object}  string
+// @Failure      500   {object}  string
+// @Security     BearerAuth
+// @Router       /api/tags/bulk [post]
+func (h *Handler) BulkTag(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	var req BulkTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.BulkTag(r.Context(), service.BulkTagRequest{
+		UserID:  userID,
+		Add:     req.Add,
+		Remove:  req.Remove,
+		ItemIDs: req.ItemIDs,
+		FeedIDs: req.FeedIDs,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to tag items and feeds", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ListItemTags returns the tags of the user on an item.
+// @Summary      List item tags
+// @Description  Lists the tags of the user on an item.
+// @Tags         Tags
+// @Produce      json
+// @Param        itemID  path      string  true  "Item UUID"
+// @Success      200     {object}  service.TagsResponse
+// @Failure      400     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/items/{itemID}/tags [get]
+func (h *Handler) ListItemTags(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("itemID")
+	itemID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.ListItemTags(r.Context(), repository.ListItemTagNamesParams{
+		UserID:  userID,
+		ItemIds: []uuid.UUID{itemID},
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to list tags of item %s", itemID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// SetItemTags replaces the tags of the user on an item.
+// @Summary      Set item tags
+// @Description  Replaces the tags of the user on an item, creating tags that do not exist yet.
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Param        itemID  path      string          true  "Item UUID"
+// @Param        body    body      SetTagsRequest  true  "Tags"
+// @Success      200     {object}  service.TagsResponse
+// @Failure      400     {object}  string
+// @Failure      404     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/items/{itemID}/tags [put]
+func (h *Handler) SetItemTags(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("itemID")
+	itemID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	var req SetTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.SetItemTags(r.Context(), service.SetTagsRequest{
+		UserID: userID,
+		ID:     itemID,
+		Tags:   req.Tags,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to tag item %s", itemID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ListFeedTags returns the tags of the user on a feed.
+// @Summary      List feed tags
+// @Description  Lists the tags of the user on a subscribed feed.
+// @Tags         Tags
+// @Produce      json
+// @Param        feedID  path      string  true  "Feed UUID"
+// @Success      200     {object}  service.TagsResponse
+// @Failure      400     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/feeds/{feedID}/tags [get]
+func (h *Handler) ListFeedTags(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("feedID")
+	feedID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.ListFeedTags(r.Context(), repository.ListFeedTagNamesParams{
+		UserID:  userID,
+		FeedIds: []uuid.UUID{feedID},
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to list tags of feed %s", feedID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// SetFeedTags replaces the tags of the user on a feed.
+// @Summary      Set feed tags
+// @Description  Replaces the tags of the user on a subscribed feed, creating tags that do not exist yet. Feed tags act as folders.
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Param        feedID  path      string          true  "Feed UUID"
+// @Param        body    body      SetTagsRequest  true  "Tags"
+// @Success      200     {object}  service.TagsResponse
+// @Failure      400     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/feeds/{feedID}/tags [put]
+func (h *Handler) SetFeedTags(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("feedID")
+	feedID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	var req SetTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.SetFeedTags(r.Context(), service.SetTagsRequest{
+		UserID: userID,
+		ID:     feedID,
+		Tags:   req.Tags,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to tag feed %s", feedID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ListFeedFolders returns the subscribed feeds grouped into folders.
+// @Summary      List feed folders
+// @Description  Lists the subscribed feeds of the user grouped by the folder set on their subscription, or else by their tags, which act as folders. A feed with several tags is listed in each folder, feeds without folder or tags are unfiled.
+// @Tags         Tags
+// @Produce      json
+// @Success      200  {object}  service.ListFeedFoldersResponse
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/feeds/folders [get]
+func (h *Handler) ListFeedFolders(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	resp, err := h.Service.ListFeedFolders(r.Context(), userID)
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to list feed folders", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -3,8 +3,11 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/repository"
 	"github.com/rhajizada/gazette/internal/service"
 
 	"github.com/rhajizada/gazette/internal/middleware"
@@ -37,3 +40,501 @@ func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(user)
 }
+
+// ListAppPasswords returns app passwords of the current user.
+// @Summary      List app passwords
+// @Description  Retrieves app passwords used by third-party reader clients.
+// @Tags         Users
+// @Success      200     {object}  service.ListAppPasswordsResponse
+// @Failure      400     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/app-passwords [get]
+func (h *Handler) ListAppPasswords(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	resp, err := h.Service.ListAppPasswords(r.Context(), userID)
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to list app passwords", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// CreateAppPassword creates a new app password.
+// @Summary      Create app password
+// @Description  Generates an app password for third-party reader clients. The password is only returned once.
+// @Tags         Users
+// @Param        body    body      CreateAppPasswordRequest  true  "App password name"
+// @Success      200     {object}  service.CreateAppPasswordResponse
+// @Failure      400     {object}  string
+// @Failure      409     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/app-passwords [post]
+func (h *Handler) CreateAppPassword(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	var req CreateAppPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.CreateAppPassword(r.Context(), service.CreateAppPasswordRequest{
+		UserID: userID,
+		Name:   req.Name,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to create app password %s", req.Name), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DeleteAppPassword revokes an app password.
+// @Summary      Delete app password
+// @Description  Revokes an app password of the current user.
+// @Tags         Users
+// @Param        appPasswordID  path  string  true  "App password UUID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/app-passwords/{appPasswordID} [delete]
+func (h *Handler) DeleteAppPassword(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("appPasswordID")
+	id, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	err = h.Service.DeleteAppPassword(r.Context(), repository.DeleteAppPasswordParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to delete app password %s", id), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetFeverCredential returns the Fever API access of the current user.
+// @Summary      Get Fever API access
+// @Description  Retrieves whether the Fever API is enabled for the current user.
+// @Tags         Users
+// @Success      200     {object}  service.FeverCredential
+// @Failure      404     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/fever [get]
+func (h *Handler) GetFeverCredential(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	resp, err := h.Service.GetFeverCredential(r.Context(), userID)
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to fetch fever credentials", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// SetFeverPassword enables the Fever API for the current user.
+// @Summary      Set Fever API password
+// @Description  Enables the Fever API. Clients log in with the user's email and this password.
+// @Tags         Users
+// @Param        body    body      SetFeverPasswordRequest  true  "Fever password"
+// @Success      200     {object}  service.FeverCredential
+// @Failure      400     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/fever [put]
+func (h *Handler) SetFeverPassword(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	var req SetFeverPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.SetFeverPassword(r.Context(), service.SetFeverPasswordRequest{
+		UserID:   userID,
+		Password: req.Password,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to set fever password", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DeleteFeverPassword disables the Fever API for the current user.
+// @Summary      Delete Fever API password
+// @Description  Disables the Fever API for the current user.
+// @Tags         Users
+// @Success      204  "No Content"
+// @Failure      404  {object}  string
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/fever [delete]
+func (h *Handler) DeleteFeverPassword(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	if err := h.Service.DeleteFeverPassword(r.Context(), userID); err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to delete fever password", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAccessTokens returns personal access tokens of the current user.
+// @Summary      List personal access tokens
+// @Description  Retrieves personal access tokens of the current user.
+// @Tags         Users
+// @Success      200     {object}  service.ListAccessTokensResponse
+// @Failure      400     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/tokens [get]
+func (h *Handler) ListAccessTokens(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	resp, err := h.Service.ListAccessTokens(r.Context(), userID)
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to list tokens", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// CreateAccessToken creates a new personal access token.
+// @Summary      Create personal access token
+// @Description  Generates a personal access token with the given scopes (read, write, admin) and optional expiry. The token is only returned once.
+// @Tags         Users
+// @Param        body    body      CreateAccessTokenRequest  true  "Token name, scopes and expiry"
+// @Success      200     {object}  service.CreateAccessTokenResponse
+// @Failure      400     {object}  string
+// @Failure      403     {object}  string
+// @Failure      409     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/tokens [post]
+func (h *Handler) CreateAccessToken(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r)
+
+	var req CreateAccessTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.CreateAccessToken(r.Context(), service.CreateAccessTokenRequest{
+		UserID:        claims.UserID,
+		Name:          req.Name,
+		Scopes:        req.Scopes,
+		ExpiresAt:     req.ExpiresAt,
+		GrantedScopes: claims.Scopes,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to create token %s", req.Name), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DeleteAccessToken revokes a personal access token.
+// @Summary      Delete personal access token
+// @Description  Revokes a personal access token of the current user.
+// @Tags         Users
+// @Param        tokenID  path  string  true  "Token UUID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/tokens/{tokenID} [delete]
+func (h *Handler) DeleteAccessToken(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("tokenID")
+	id, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	err = h.Service.DeleteAccessToken(r.Context(), repository.DeleteAccessTokenParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to delete token %s", id), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSessions returns active login sessions of the current user.
+// @Summary      List sessions
+// @Description  Retrieves active login sessions of the current user, the session making the request is marked as current.
+// @Tags         Users
+// @Success      200     {object}  service.ListSessionsResponse
+// @Failure      400     {object}  string
+// @Failure      500     {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/sessions [get]
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r)
+	resp, err := h.Service.ListSessions(r.Context(), claims.UserID, claims.SessionID)
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to list sessions", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DeleteOtherSessions logs out every other session of the current user.
+// @Summary      Log out other sessions
+// @Description  Revokes every login session of the current user except the one making the request.
+// @Tags         Users
+// @Success      204  "No Content"
+// @Failure      400  {object}  string
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/sessions [delete]
+func (h *Handler) DeleteOtherSessions(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r)
+	_, err := h.Service.RevokeOtherSessions(r.Context(), repository.RevokeOtherSessionsParams{
+		UserID: claims.UserID,
+		ID:     claims.SessionID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to revoke sessions", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteSession logs out a session of the current user.
+// @Summary      Log out session
+// @Description  Revokes a login session of the current user.
+// @Tags         Users
+// @Param        sessionID  path  string  true  "Session UUID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/sessions/{sessionID} [delete]
+func (h *Handler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("sessionID")
+	id, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	err = h.Service.RevokeSession(r.Context(), repository.RevokeSessionParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to revoke session %s", id), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListIdentities lists identities linked to the current user.
+// @Summary      List identities
+// @Description  Lists provider identities the current user can sign in with.
+// @Tags         Users
+// @Produce      json
+// @Success      200  {object}  service.ListUserIdentitiesResponse
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/identities [get]
+func (h *Handler) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	resp, err := h.Service.ListUserIdentities(r.Context(), userID)
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to list identities", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// LinkIdentity starts linking a provider identity to the current user.
+// @Summary      Link identity
+// @Description  Starts a login at the provider, the identity is linked to the current user once the login completes. The returned URL must be opened in the browser that made this request.
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        body  body      LinkIdentityRequest  true  "Provider to link"
+// @Success      200   {object}  LinkIdentityResponse
+// @Failure      400   {object}  string
+// @Failure      500   {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/identities [post]
+func (h *Handler) LinkIdentity(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	var req LinkIdentityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	provider := h.provider(req.Provider)
+	if req.Provider == "" || provider == nil {
+		http.Error(w, "unknown provider", http.StatusBadRequest)
+		return
+	}
+
+	url, err := h.startLogin(w, provider, &userID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed generating state: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(LinkIdentityResponse{URL: url})
+}
+
+// UnlinkIdentity removes an identity from the current user.
+// @Summary      Unlink identity
+// @Description  Removes a provider identity from the current user, the last identity can not be removed.
+// @Tags         Users
+// @Param        identityID  path  string  true  "Identity UUID"
+// @Success      204  "No Content"
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Failure      409  {object}  string
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/user/identities/{identityID} [delete]
+func (h *Handler) UnlinkIdentity(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("identityID")
+	id, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	err = h.Service.UnlinkIdentity(r.Context(), repository.DeleteUserIdentityParams{
+		ID:     id,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to unlink identity %s", id), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,310 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/middleware"
+	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/service"
+)
+
+func (req WebhookRequest) toService(userID uuid.UUID) service.WebhookRequest {
+	return service.WebhookRequest{
+		UserID:        userID,
+		Name:          req.Name,
+		URL:           req.URL,
+		Secret:        req.Secret,
+		Events:        req.Events,
+		FeedIDs:       req.FeedIDs,
+		CollectionIDs: req.CollectionIDs,
+		RuleIDs:       req.RuleIDs,
+		Enabled:       req.Enabled,
+	}
+}
+
+// ListWebhooks returns the webhooks of the user.
+// @Summary      List webhooks
+// @Description  Lists the webhooks of the user with their failure counters, oldest first. Secrets are not returned.
+// @Tags         Webhooks
+// @Produce      json
+// @Success      200  {object}  service.ListWebhooksResponse
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/webhooks [get]
+func (h *Handler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	resp, err := h.Service.ListWebhooks(r.Context(), userID)
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to list webhooks", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// CreateWebhook creates a webhook of the user.
+// @Summary      Create webhook
+// @Description  Creates a webhook posting signed events when new items arrive in feeds or collections of the user or match their filter rules. URLs must resolve to public addresses unless the instance allows private ones. Deliveries carry an X-Gazette-Signature header, sha256= followed by the hex HMAC-SHA256 of the X-Gazette-Timestamp header, a dot and the body, keyed by the secret. The secret is only returned here and when replaced.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        body  body      WebhookRequest  true  "Webhook"
+// @Success      201   {object}  service.Webhook
+// @Failure      400   {object}  string
+// @Failure      404   {object}  string
+// @Failure      500   {object}  string
+// @Security     BearerAuth
+// @Router       /api/webhooks [post]
+func (h *Handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+
+	var req WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	hook, err := h.Service.CreateWebhook(r.Context(), req.toService(userID))
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, "failed to create webhook", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hook)
+}
+
+// GetWebhook returns a webhook of the user.
+// @Summary      Get webhook
+// @Description  Retrieves a webhook of the user without its secret.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        webhookID  path      string  true  "Webhook UUID"
+// @Success      200        {object}  service.Webhook
+// @Failure      400        {object}  string
+// @Failure      404        {object}  string
+// @Failure      500        {object}  string
+// @Security     BearerAuth
+// @Router       /api/webhooks/{webhookID} [get]
+func (h *Handler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("webhookID")
+	webhookID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	hook, err := h.Service.GetWebhook(r.Context(), repository.GetWebhookParams{
+		ID:     webhookID,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to get webhook %s", webhookID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hook)
+}
+
+// UpdateWebhook replaces a webhook of the user.
+// @Summary      Update webhook
+// @Description  Replaces the definition of a webhook of the user. Enabling a webhook disabled after failed deliveries resets its failures. The secret is returned when replaced.
+// @Tags         Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        webhookID  path      string          true  "Webhook UUID"
+// @Param        body       body      WebhookRequest  true  "Webhook"
+// @Success      200        {object}  service.Webhook
+// @Failure      400        {object}  string
+// @Failure      404        {object}  string
+// @Failure      500        {object}  string
+// @Security     BearerAuth
+// @Router       /api/webhooks/{webhookID} [put]
+func (h *Handler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("webhookID")
+	webhookID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	var req WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	hook, err := h.Service.UpdateWebhook(r.Context(), service.UpdateWebhookRequest{
+		WebhookID:      webhookID,
+		WebhookRequest: req.toService(userID),
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to update webhook %s", webhookID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hook)
+}
+
+// DeleteWebhook deletes a webhook of the user.
+// @Summary      Delete webhook
+// @Description  Deletes a webhook of the user with its delivery log. Queued deliveries are dropped.
+// @Tags         Webhooks
+// @Param        webhookID  path  string  true  "Webhook UUID"
+// @Success      204
+// @Failure      400  {object}  string
+// @Failure      404  {object}  string
+// @Failure      500  {object}  string
+// @Security     BearerAuth
+// @Router       /api/webhooks/{webhookID} [delete]
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("webhookID")
+	webhookID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	err = h.Service.DeleteWebhook(r.Context(), repository.DeleteWebhookParams{
+		ID:     webhookID,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to delete webhook %s", webhookID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TestWebhook queues a ping event to a webhook of the user.
+// @Summary      Test webhook
+// @Description  Queues a ping event to a webhook of the user, delivered, retried and logged like other events. Its outcome is listed in the delivery log.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        webhookID  path      string  true  "Webhook UUID"
+// @Success      202        {object}  service.WebhookDelivery
+// @Failure      400        {object}  string
+// @Failure      404        {object}  string
+// @Failure      500        {object}  string
+// @Security     BearerAuth
+// @Router       /api/webhooks/{webhookID}/test [post]
+func (h *Handler) TestWebhook(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("webhookID")
+	webhookID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+
+	delivery, err := h.Service.TestWebhook(r.Context(), repository.GetWebhookParams{
+		ID:     webhookID,
+		UserID: userID,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to test webhook %s", webhookID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+// ListWebhookDeliveries returns the delivery log of a webhook.
+// @Summary      List webhook deliveries
+// @Description  Lists the deliveries of a webhook of the user with their status, attempts and last response, newest first, paginated. Deliveries are kept for 30 days.
+// @Tags         Webhooks
+// @Produce      json
+// @Param        webhookID  path      string  true  "Webhook UUID"
+// @Param        limit      query     int32   true  "Max number of deliveries"
+// @Param        offset     query     int32   true  "Number of deliveries to skip"
+// @Success      200        {object}  service.ListWebhookDeliveriesResponse
+// @Failure      400        {object}  string
+// @Failure      404        {object}  string
+// @Failure      500        {object}  string
+// @Security     BearerAuth
+// @Router       /api/webhooks/{webhookID}/deliveries [get]
+func (h *Handler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserClaims(r).UserID
+	path := r.PathValue("webhookID")
+	webhookID, err := uuid.Parse(path)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%s is not a valid id", path), http.StatusBadRequest)
+		return
+	}
+	params, err := getPageParams(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := h.Service.ListWebhookDeliveries(r.Context(), service.ListWebhookDeliveriesRequest{
+		UserID:    userID,
+		WebhookID: webhookID,
+		Limit:     params.Limit,
+		Offset:    params.Offset,
+	})
+	if err != nil {
+		var serviceErr service.ServiceError
+		if errors.As(err, &serviceErr) {
+			http.Error(w, serviceErr.Error(), int(serviceErr.Code))
+			return
+		} else {
+			http.Error(w, fmt.Sprintf("failed to list deliveries of webhook %s", webhookID), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,117 @@
+// Package health serves liveness and readiness probes.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds how long the readiness checks may take.
+const DefaultTimeout = 5 * time.Second
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Check reports whether a dependency is usable.
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker runs the readiness checks of a binary.
+type Checker struct {
+	timeout time.Duration
+	checks  []namedCheck
+}
+
+// CheckResult is the outcome of a single check.
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Report is the body of a probe response.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// New creates a Checker which gives up on each check after timeout.
+func New(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a readiness check.
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// Run runs every check concurrently and reports their outcome.
+func (c *Checker) Run(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResult, len(c.checks)),
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, nc := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := CheckResult{Status: StatusOK}
+			if err := nc.check(ctx); err != nil {
+				result = CheckResult{Status: StatusUnavailable, Error: err.Error()}
+				slog.WarnContext(ctx, "readiness check failed",
+					slog.String("check", nc.name),
+					slog.Any("error", err),
+				)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[nc.name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusUnavailable
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// Liveness reports that the process is up, it does not check dependencies
+// so a dependency outage does not get the process restarted.
+func (c *Checker) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, Report{Status: StatusOK})
+}
+
+// Readiness reports whether every dependency is usable, responding with 503
+// when one is not.
+func (c *Checker) Readiness(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, c.Run(r.Context()))
+}
+
+// Register mounts the probes on mux at /healthz and /readyz.
+func (c *Checker) Register(mux *http.ServeMux) {
+	mux.HandleFunc("GET /healthz", c.Liveness)
+	mux.HandleFunc("GET /readyz", c.Readiness)
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Status != StatusOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
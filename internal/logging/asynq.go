@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/hibiken/asynq"
+)
+
+// AsynqLogger adapts a slog.Logger to the logger interface of asynq.
+type AsynqLogger struct {
+	Logger *slog.Logger
+}
+
+func (l AsynqLogger) Debug(args ...any) { l.Logger.Debug(fmt.Sprint(args...)) }
+
+func (l AsynqLogger) Info(args ...any) { l.Logger.Info(fmt.Sprint(args...)) }
+
+func (l AsynqLogger) Warn(args ...any) { l.Logger.Warn(fmt.Sprint(args...)) }
+
+func (l AsynqLogger) Error(args ...any) { l.Logger.Error(fmt.Sprint(args...)) }
+
+func (l AsynqLogger) Fatal(args ...any) {
+	l.Logger.Error(fmt.Sprint(args...))
+	os.Exit(1)
+}
+
+// AsynqLevel converts a slog level to the closest asynq log level.
+func AsynqLevel(level slog.Level) asynq.LogLevel {
+	switch {
+	case level <= slog.LevelDebug:
+		return asynq.DebugLevel
+	case level <= slog.LevelInfo:
+		return asynq.InfoLevel
+	case level <= slog.LevelWarn:
+		return asynq.WarnLevel
+	default:
+		return asynq.ErrorLevel
+	}
+}
+
+// Fatal logs err with msg and exits, it is used by the binaries when they
+// fail to start.
+func Fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
+}
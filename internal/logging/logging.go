@@ -0,0 +1,86 @@
+// Package logging configures structured logging and carries log attributes,
+// such as request and task IDs, through contexts.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/rhajizada/gazette/internal/config"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type attrsContextKey struct{}
+
+type requestIDContextKey struct{}
+
+// Setup installs the default slog logger for a binary. Output of the standard
+// log package is routed through it as well.
+func Setup(cfg *config.LoggingConfig, service string) *slog.Logger {
+	logger := slog.New(NewHandler(os.Stderr, cfg)).With("service", service)
+	slog.SetDefault(logger)
+	return logger
+}
+
+// NewHandler creates a handler writing records in the configured format and
+// decorating them with the attributes stored in their context.
+func NewHandler(w io.Writer, cfg *config.LoggingConfig) slog.Handler {
+	opts := &slog.HandlerOptions{Level: cfg.Level}
+	var h slog.Handler
+	if strings.EqualFold(cfg.Format, "text") {
+		h = slog.NewTextHandler(w, opts)
+	} else {
+		h = slog.NewJSONHandler(w, opts)
+	}
+	return &contextHandler{Handler: h}
+}
+
+// With returns a copy of ctx whose log records carry attrs.
+func With(ctx context.Context, attrs ...slog.Attr) context.Context {
+	existing, _ := ctx.Value(attrsContextKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, attrsContextKey{}, merged)
+}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request being
+// served, it is added to every record logged with that context.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDContextKey{}, id)
+	return With(ctx, slog.String("request_id", id))
+}
+
+// RequestID returns the ID of the request being served, if any.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(attrsContextKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"log/slog"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// QueueCollector reports the number of tasks in each asynq queue by state.
+type QueueCollector struct {
+	inspector *asynq.Inspector
+	depth     *prometheus.Desc
+}
+
+// NewQueueCollector creates a QueueCollector backed by inspector.
+func NewQueueCollector(inspector *asynq.Inspector) *QueueCollector {
+	return &QueueCollector{
+		inspector: inspector,
+		depth: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "queue", "tasks"),
+			"Number of tasks in an asynq queue by state.",
+			[]string{"queue", "state"}, nil,
+		),
+	}
+}
+
+func (c *QueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.depth
+}
+
+func (c *QueueCollector) Collect(ch chan<- prometheus.Metric) {
+	queues, err := c.inspector.Queues()
+	if err != nil {
+		slog.Error("failed to list queues", slog.Any("error", err))
+		return
+	}
+	for _, queue := range queues {
+		info, err := c.inspector.GetQueueInfo(queue)
+		if err != nil {
+			slog.Error("failed to inspect queue", slog.String("queue", queue), slog.Any("error", err))
+			continue
+		}
+		for state, n := range map[string]int{
+			"pending":     info.Pending,
+			"active":      info.Active,
+			"scheduled":   info.Scheduled,
+			"retry":       info.Retry,
+			"archived":    info.Archived,
+			"completed":   info.Completed,
+			"aggregating": info.Aggregating,
+		} {
+			ch <- prometheus.MustNewConstMetric(c.depth, prometheus.GaugeValue, float64(n), queue, state)
+		}
+	}
+}
+
+// PoolCollector reports pgxpool connection statistics.
+type PoolCollector struct {
+	pool             *pgxpool.Pool
+	acquiredConns    *prometheus.Desc
+	idleConns        *prometheus.Desc
+	totalConns       *prometheus.Desc
+	maxConns         *prometheus.Desc
+	acquires         *prometheus.Desc
+	emptyAcquires    *prometheus.Desc
+	canceledAcquires *prometheus.Desc
+	acquireDuration  *prometheus.Desc
+}
+
+// NewPoolCollector creates a PoolCollector for pool.
+func NewPoolCollector(pool *pgxpool.Pool) *PoolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	return &PoolCollector{
+		pool:             pool,
+		acquiredConns:    desc("acquired_connections", "Number of connections currently acquired from the pool."),
+		idleConns:        desc("idle_connections", "Number of idle connections in the pool."),
+		totalConns:       desc("total_connections", "Total number of connections in the pool."),
+		maxConns:         desc("max_connections", "Maximum size of the pool."),
+		acquires:         desc("acquires_total", "Total number of successful connection acquires."),
+		emptyAcquires:    desc("empty_acquires_total", "Total number of acquires that waited for a connection."),
+		canceledAcquires: desc("canceled_acquires_total", "Total number of acquires canceled by their context."),
+		acquireDuration:  desc("acquire_duration_seconds_total", "Total time spent acquiring connections."),
+	}
+}
+
+func (c *PoolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquires
+	ch <- c.emptyAcquires
+	ch <- c.canceledAcquires
+	ch <- c.acquireDuration
+}
+
+func (c *PoolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquires, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, s.AcquireDuration().Seconds())
+}
@@ -0,0 +1,95 @@
+// Package metrics defines the Prometheus collectors exported by Gazette.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "gazette"
+
+var (
+	// HTTPRequests counts HTTP requests by method, route and status code.
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Total number of HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes HTTP request latency by method, route and status code.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// FeedFetchDuration observes how long fetching a feed took by response status.
+	FeedFetchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "feed",
+		Name:      "fetch_duration_seconds",
+		Help:      "Feed fetch latency by response status.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"status"})
+
+	// FeedFetchBytes counts bytes downloaded while fetching feeds.
+	FeedFetchBytes = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "feed",
+		Name:      "fetch_bytes_total",
+		Help:      "Total number of bytes downloaded while fetching feeds.",
+	})
+
+	// ItemsIngested counts items created by feed syncs.
+	ItemsIngested = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "feed",
+		Name:      "items_ingested_total",
+		Help:      "Total number of items ingested by feed.",
+	}, []string{"feed_id"})
+
+	// FilterRuleHits counts items matched by filter rules at ingest.
+	FilterRuleHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "filter",
+		Name:      "rule_hits_total",
+		Help:      "Total number of items matched by filter rules at ingest by action.",
+	}, []string{"action"})
+
+	// WebhookDeliveries counts webhook delivery attempts by outcome.
+	WebhookDeliveries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhook",
+		Name:      "deliveries_total",
+		Help:      "Total number of webhook delivery attempts by outcome.",
+	}, []string{"status"})
+
+	// WebhookDeliveryDuration observes how long webhook delivery attempts took.
+	WebhookDeliveryDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "webhook",
+		Name:      "delivery_duration_seconds",
+		Help:      "Webhook delivery attempt latency.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	})
+
+	// EmbeddingDuration observes how long generating an item embedding took.
+	EmbeddingDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "embedding",
+		Name:      "duration_seconds",
+		Help:      "Item embedding generation latency.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	})
+
+	// EmbeddingFailures counts failed embedding requests.
+	EmbeddingFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "embedding",
+		Name:      "failures_total",
+		Help:      "Total number of failed item embedding requests.",
+	})
+)
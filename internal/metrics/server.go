@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Handler returns the HTTP handler exposing the registered metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Serve exposes /metrics, along with the routes of mux such as health probes,
+// on port in the background. It is used by the binaries that do not
+// otherwise run an HTTP server, which shut the returned server down on exit.
+func Serve(port int, mux *http.ServeMux) *http.Server {
+	mux.Handle("GET /metrics", Handler())
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%v", port),
+		Handler: mux,
+	}
+	go func() {
+		slog.Info("serving metrics", slog.Int("port", port))
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("could not start metrics server", slog.Any("error", err))
+			os.Exit(1)
+		}
+	}()
+	return srv
+}
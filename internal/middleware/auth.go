@@ -2,9 +2,12 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/google/uuid"
 	"github.com/rhajizada/gazette/internal/oauth"
 )
 
@@ -14,12 +17,39 @@ type contextKey string
 // UserContextKey is the key under which JWT claims are stored in the request context.
 var UserContextKey = contextKey("user")
 
+// TokenAuthenticator resolves personal access tokens to user claims and
+// checks that login sessions have not been revoked.
+type TokenAuthenticator interface {
+	AuthenticateAccessToken(ctx context.Context, token string) (*oauth.ApplicationClaims, error)
+	ValidateSession(ctx context.Context, sessionID uuid.UUID) error
+}
+
+// ReaderAuthenticator checks that the app passwords or login sessions Reader
+// API tokens were issued with have not been deleted or revoked, and that
+// their user is not disabled.
+type ReaderAuthenticator interface {
+	ValidateAppPassword(ctx context.Context, userID, appPasswordID uuid.UUID) error
+	ValidateSession(ctx context.Context, sessionID uuid.UUID) error
+}
+
+// requiredScope returns the scope a personal access token needs for a request.
+func requiredScope(r *http.Request) string {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return oauth.ScopeRead
+	default:
+		return oauth.ScopeWrite
+	}
+}
+
 // APIAuthMiddleware returns a middleware that:
-// 1) extracts the JWT from the Authorization header
-// 2) verifies it using the provided secret
-// 3) injects the resulting ApplicationClaims into the request context
+// 1) extracts the JWT or personal access token from the Authorization header
+// 2) verifies it using the provided secret or token authenticator
+// 3) checks that the login session of a JWT is still active
+// 4) checks that personal access tokens have the scope the request needs
+// 5) injects the resulting ApplicationClaims into the request context
 // and calls the next handler if successful, or returns 401 otherwise.
-func APIAuthMiddleware(secret []byte) func(http.Handler) http.Handler {
+func APIAuthMiddleware(secret []byte, tokens TokenAuthenticator) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			rawToken, err := oauth.ExtractTokenFromHeaders(r)
@@ -28,13 +58,85 @@ func APIAuthMiddleware(secret []byte) func(http.Handler) http.Handler {
 				return
 			}
 
-			claims, err := oauth.VerifyToken(rawToken, secret)
+			var claims *oauth.ApplicationClaims
+			if strings.HasPrefix(rawToken, oauth.AccessTokenPrefix) {
+				claims, err = tokens.AuthenticateAccessToken(r.Context(), rawToken)
+			} else {
+				claims, err = oauth.VerifyToken(rawToken, secret)
+				if err == nil {
+					if claims.SessionID == uuid.Nil {
+						err = errors.New("token is not bound to a session")
+					} else {
+						err = tokens.ValidateSession(r.Context(), claims.SessionID)
+					}
+				}
+			}
 			if err != nil {
 				msg := fmt.Sprintf("unauthorized: %v", err)
 				http.Error(w, msg, http.StatusUnauthorized)
 				return
 			}
 
+			if scope := requiredScope(r); !claims.HasScope(scope) {
+				msg := fmt.Sprintf("forbidden: token lacks %s scope", scope)
+				http.Error(w, msg, http.StatusForbidden)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), UserContextKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireAdmin returns a middleware that only lets admins through, it must
+// run after APIAuthMiddleware.
+func RequireAdmin() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := GetUserClaims(r)
+			if claims == nil || claims.Role != oauth.RoleAdmin {
+				http.Error(w, "forbidden: admin role required", http.StatusForbidden)
+				return
+			}
+			if !claims.IsAdmin() {
+				msg := fmt.Sprintf("forbidden: token lacks %s scope", oauth.ScopeAdmin)
+				http.Error(w, msg, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ReaderAuthMiddleware is the Google Reader API counterpart of
+// APIAuthMiddleware, it accepts tokens issued by ClientLogin as long as their
+// app password still exists, and tokens of active login sessions.
+func ReaderAuthMiddleware(secret []byte, tokens ReaderAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rawToken, err := oauth.ExtractReaderTokenFromHeaders(r)
+			if err != nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := oauth.VerifyToken(rawToken, secret)
+			if err == nil {
+				switch {
+				case claims.AppPasswordID != uuid.Nil:
+					err = tokens.ValidateAppPassword(r.Context(), claims.UserID, claims.AppPasswordID)
+				case claims.SessionID != uuid.Nil:
+					err = tokens.ValidateSession(r.Context(), claims.SessionID)
+				default:
+					err = errors.New("token is not bound to a session")
+				}
+			}
+			if err != nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+
 			ctx := context.WithValue(r.Context(), UserContextKey, claims)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -1,9 +1,16 @@
 package middleware
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/rhajizada/gazette/internal/metrics"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type wrappedWriter struct {
@@ -16,6 +23,43 @@ func (w *wrappedWriter) WriteHeader(statusCode int) {
 	w.statusCode = statusCode
 }
 
+type routeContextKey struct{}
+
+// route holds the pattern of the handler that served a request.
+type route struct {
+	pattern string
+}
+
+// Route records the pattern matched by a mounted router so request metrics
+// and spans are named by route rather than by path. prefix is the path the
+// router is mounted under.
+func Route(prefix string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		if r.Pattern == "" {
+			return
+		}
+		pattern := prefix + patternPath(r.Pattern)
+		if rt, ok := r.Context().Value(routeContextKey{}).(*route); ok {
+			rt.pattern = pattern
+		}
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Method + " " + pattern)
+		span.SetAttributes(semconv.HTTPRoute(pattern))
+	})
+}
+
+// patternPath strips the method and host from a ServeMux pattern.
+func patternPath(pattern string) string {
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		pattern = path
+	}
+	if i := strings.Index(pattern, "/"); i > 0 {
+		pattern = pattern[i:]
+	}
+	return pattern
+}
+
 func Logging() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -25,10 +69,35 @@ func Logging() func(http.Handler) http.Handler {
 				ResponseWriter: w,
 				statusCode:     http.StatusOK,
 			}
+			rt := &route{}
+			r = r.WithContext(context.WithValue(r.Context(), routeContextKey{}, rt))
 
 			next.ServeHTTP(wrapped, r)
 
-			log.Println(wrapped.statusCode, r.Method, r.URL.Path, time.Since(start))
+			elapsed := time.Since(start)
+			pattern := rt.pattern
+			if pattern == "" {
+				pattern = patternPath(r.Pattern)
+			}
+			if pattern == "" {
+				pattern = "unmatched"
+			}
+
+			level := slog.LevelInfo
+			if wrapped.statusCode >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			slog.LogAttrs(r.Context(), level, "request",
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("route", pattern),
+				slog.Int("status", wrapped.statusCode),
+				slog.Float64("duration_ms", float64(elapsed.Microseconds())/1000),
+				slog.String("remote_addr", r.RemoteAddr),
+			)
+			status := strconv.Itoa(wrapped.statusCode)
+			metrics.HTTPRequests.WithLabelValues(r.Method, pattern, status).Inc()
+			metrics.HTTPRequestDuration.WithLabelValues(r.Method, pattern, status).Observe(elapsed.Seconds())
 		})
 	}
 }
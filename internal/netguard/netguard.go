@@ -0,0 +1,93 @@
+// Package netguard keeps outbound requests to URLs that come from users or
+// feeds away from the network of the server: loopback, private, link-local
+// and other non-public addresses, cloud metadata endpoints included.
+//
+// Addresses are checked as connections are made, after DNS resolution, so
+// neither redirects nor DNS rebinding get around the check.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress is returned when dialing a non-public address.
+var ErrPrivateAddress = errors.New("connections to private addresses are not allowed")
+
+// reserved lists non-public ranges the methods of netip.Addr do not cover.
+var reserved = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // this network
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, may map to private IPv4
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local NAT64
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+}
+
+// IsPublic reports whether addr is a public unicast address.
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, p := range reserved {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// PublicHost reports whether host, the host of a URL without its port, may
+// name a public address: it is neither a non-public IP address nor
+// localhost. Names are not resolved, the addresses they resolve to are
+// checked on connecting.
+func PublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		return IsPublic(addr)
+	}
+	return host != "localhost" && !strings.HasSuffix(host, ".localhost")
+}
+
+// control rejects connections to non-public addresses, it runs once the
+// address to connect to is resolved.
+func control(_, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !IsPublic(ap.Addr()) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, ap.Addr())
+	}
+	return nil
+}
+
+// Dialer returns a dialer that only connects to public addresses.
+func Dialer() *net.Dialer {
+	return &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   control,
+	}
+}
+
+// Transport returns an HTTP transport that only connects to public
+// addresses. Proxies are not used, they would hide the address requested.
+func Transport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil
+	t.DialContext = Dialer().DialContext
+	return t
+}
@@ -1,17 +1,69 @@
 package oauth
 
 import (
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
+// AccessTokenPrefix marks personal access tokens so they can be told apart from JWTs.
+const AccessTokenPrefix = "gzt_"
+
+// Personal access token scopes, each scope includes the ones before it.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+var scopeRank = map[string]int{
+	ScopeRead:  1,
+	ScopeWrite: 2,
+	ScopeAdmin: 3,
+}
+
+// ValidScope reports whether scope is a known scope.
+func ValidScope(scope string) bool {
+	_, ok := scopeRank[scope]
+	return ok
+}
+
+// Application roles, admins may manage resources shared by all users.
+const (
+	RoleAdmin  = "admin"
+	RoleMember = "member"
+)
+
 type ProviderClaims struct {
-	Name   string   `json:"name"`
-	Email  string   `json:"email"`
-	Sub    string   `json:"sub"`
-	Groups []string `json:"groups"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// EmailVerified is set when the provider checked that the user owns
+	// Email, only verified emails are matched to collection invitations.
+	EmailVerified ClaimBool `json:"email_verified"`
+	Sub           string    `json:"sub"`
+	Groups        []string  `json:"groups"`
+}
+
+// ClaimBool is a boolean claim, some providers send booleans as strings.
+type ClaimBool bool
+
+func (b *ClaimBool) UnmarshalJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case bool:
+		*b = ClaimBool(v)
+	case string:
+		*b = ClaimBool(strings.EqualFold(v, "true"))
+	default:
+		*b = false
+	}
+	return nil
 }
 
 type ApplicationClaims struct {
@@ -20,18 +72,103 @@ type ApplicationClaims struct {
 	Email  string    `json:"email"`
 	Sub    string    `json:"sub"`
 	Groups []string  `json:"groups"`
+	Role   string    `json:"role"`
+	// Scopes is only set for personal access tokens, sessions are unrestricted.
+	Scopes []string `json:"scopes,omitempty"`
+	// SessionID is set for tokens issued to a login session.
+	SessionID uuid.UUID `json:"sid,omitempty"`
+	// AppPasswordID is set for Reader API tokens issued by ClientLogin.
+	AppPasswordID uuid.UUID `json:"apw,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func (c *ProviderClaims) GetAppClaims(userID uuid.UUID, expiration time.Duration) jwt.MapClaims {
+// HasScope reports whether the claims grant scope.
+func (c *ApplicationClaims) HasScope(scope string) bool {
+	if c.Scopes == nil {
+		return true
+	}
+	for _, s := range c.Scopes {
+		if scopeRank[s] >= scopeRank[scope] {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin reports whether the claims carry the admin role, personal access
+// tokens also need the admin scope.
+func (c *ApplicationClaims) IsAdmin() bool {
+	return c.Role == RoleAdmin && c.HasScope(ScopeAdmin)
+}
+
+func (c *ProviderClaims) GetAppClaims(userID uuid.UUID, role string, expiration time.Duration) jwt.MapClaims {
 	return jwt.MapClaims{
 		"user_id": userID.String(),
 		"name":    c.Name,
 		"email":   c.Email,
 		"sub":     c.Sub,
 		"groups":  c.Groups,
+		"role":    role,
 		"iss":     "gazette",
 		"iat":     time.Now().Unix(),
 		"exp":     time.Now().Add(expiration).Unix(),
 	}
 }
+
+// GetSessionClaims returns app claims bound to a login session, so the token
+// stops working once the session is revoked.
+func (c *ProviderClaims) GetSessionClaims(userID, sessionID uuid.UUID, role string, expiration time.Duration) jwt.MapClaims {
+	claims := c.GetAppClaims(userID, role, expiration)
+	claims["sid"] = sessionID.String()
+	return claims
+}
+
+// GetAppPasswordClaims returns app claims bound to an app password, so the
+// token stops working once the app password is deleted.
+func (c *ProviderClaims) GetAppPasswordClaims(userID, appPasswordID uuid.UUID, role string, expiration time.Duration) jwt.MapClaims {
+	claims := c.GetAppClaims(userID, role, expiration)
+	claims["apw"] = appPasswordID.String()
+	return claims
+}
+
+// LoginState is carried through the provider redirect as the OAuth state, it
+// records which provider the login started with and, when linking, the user
+// the identity is linked to.
+type LoginState struct {
+	Provider   string     `json:"provider"`
+	LinkUserID *uuid.UUID `json:"link_user_id,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// loginStateKey derives the key login states are signed with, so a state can
+// never be mistaken for an access token.
+func loginStateKey(secret []byte) []byte {
+	return append([]byte("login-state:"), secret...)
+}
+
+// NewLoginState returns a signed login state valid for ttl.
+func NewLoginState(provider string, linkUserID *uuid.UUID, secret []byte, ttl time.Duration) (string, error) {
+	now := time.Now()
+	state := LoginState{
+		Provider:   provider,
+		LinkUserID: linkUserID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, state).SignedString(loginStateKey(secret))
+}
+
+// VerifyLoginState verifies a login state returned by a provider.
+func VerifyLoginState(rawState string, secret []byte) (*LoginState, error) {
+	var state LoginState
+	_, err := jwt.ParseWithClaims(rawState, &state, func(t *jwt.Token) (interface{}, error) {
+		return loginStateKey(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
@@ -17,6 +17,21 @@ func ExtractTokenFromHeaders(r *http.Request) (string, error) {
 	return strings.TrimPrefix(auth, "Bearer "), nil
 }
 
+// ExtractReaderTokenFromHeaders returns the token sent by Google Reader
+// clients, which use the “GoogleLogin auth={token}” scheme, bearer tokens are
+// accepted as well.
+func ExtractReaderTokenFromHeaders(r *http.Request) (string, error) {
+	auth := r.Header.Get("Authorization")
+	switch {
+	case strings.HasPrefix(auth, "GoogleLogin auth="):
+		return strings.TrimPrefix(auth, "GoogleLogin auth="), nil
+	case strings.HasPrefix(auth, "Bearer "):
+		return strings.TrimPrefix(auth, "Bearer "), nil
+	default:
+		return "", errors.New("authorization header must be in format “GoogleLogin auth={token}”")
+	}
+}
+
 func VerifyToken(rawToken string, secret []byte) (*ApplicationClaims, error) {
 	token, err := jwt.ParseWithClaims(rawToken, &ApplicationClaims{}, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -2,34 +2,105 @@ package oauth
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/rhajizada/gazette/internal/config"
 	"golang.org/x/oauth2"
 )
 
-func GetConfig(cfg *config.OAuthConfig) (*oauth2.Config, error) {
-	ctx := context.Background()
+// Provider bundles everything needed to sign users in with an OpenID
+// Connect provider.
+type Provider struct {
+	Name        string
+	DisplayName string
+	Issuer      string
+	OAuth       *oauth2.Config
+	Verifier    *oidc.IDTokenVerifier
+	EndSession  *EndSession
+}
+
+// EndSession holds settings for RP-initiated logout at the provider.
+type EndSession struct {
+	EndSessionURL         string
+	PostLogoutRedirectURL string
+	ClientID              string
+}
+
+// GetProvider discovers the provider configuration and builds a Provider.
+func GetProvider(ctx context.Context, cfg *config.OAuthProviderConfig) (*Provider, error) {
 	provider, err := oidc.NewProvider(ctx, cfg.IssuerURL)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("provider %s: %w", cfg.Name, err)
 	}
-	oauthCfg := &oauth2.Config{
-		ClientID:     cfg.ClientID,
-		ClientSecret: cfg.ClientSecret,
-		Endpoint:     provider.Endpoint(),
-		Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
-		RedirectURL:  cfg.RedirectURL,
+
+	var metadata struct {
+		Issuer             string `json:"issuer"`
+		EndSessionEndpoint string `json:"end_session_endpoint"`
+	}
+	if err := provider.Claims(&metadata); err != nil {
+		return nil, fmt.Errorf("provider %s: %w", cfg.Name, err)
+	}
+
+	displayName := cfg.DisplayName
+	if displayName == "" {
+		displayName = cfg.Name
 	}
-	return oauthCfg, nil
+
+	return &Provider{
+		Name:        cfg.Name,
+		DisplayName: displayName,
+		Issuer:      metadata.Issuer,
+		OAuth: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
+			RedirectURL:  cfg.RedirectURL,
+		},
+		Verifier: provider.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+		EndSession: &EndSession{
+			EndSessionURL:         metadata.EndSessionEndpoint,
+			PostLogoutRedirectURL: cfg.PostLogoutRedirectURL,
+			ClientID:              cfg.ClientID,
+		},
+	}, nil
 }
 
-func GetVerifier(cfg *config.OAuthConfig) (*oidc.IDTokenVerifier, error) {
+// GetProviders builds a Provider for every configured provider, keeping the
+// configured order.
+func GetProviders(cfgs []config.OAuthProviderConfig) ([]*Provider, error) {
 	ctx := context.Background()
-	provider, err := oidc.NewProvider(ctx, cfg.IssuerURL)
+	providers := make([]*Provider, 0, len(cfgs))
+	for i := range cfgs {
+		provider, err := GetProvider(ctx, &cfgs[i])
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// URL returns the provider logout URL for a session, or an empty string if
+// the provider does not support RP-initiated logout.
+func (l *EndSession) URL(idToken string) string {
+	if l == nil || l.EndSessionURL == "" {
+		return ""
+	}
+	u, err := url.Parse(l.EndSessionURL)
 	if err != nil {
-		return nil, err
+		return ""
+	}
+	params := u.Query()
+	params.Set("client_id", l.ClientID)
+	if idToken != "" {
+		params.Set("id_token_hint", idToken)
+	}
+	if l.PostLogoutRedirectURL != "" {
+		params.Set("post_logout_redirect_uri", l.PostLogoutRedirectURL)
 	}
-	verifier := provider.Verifier(&oidc.Config{ClientID: cfg.ClientID})
-	return verifier, nil
+	u.RawQuery = params.Encode()
+	return u.String()
 }
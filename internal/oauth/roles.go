@@ -0,0 +1,53 @@
+package oauth
+
+import (
+	"slices"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/rhajizada/gazette/internal/config"
+)
+
+// GetGroups reads the user's groups from the claim configured in cfg,
+// providers send either a list of groups or a single group.
+func GetGroups(idToken *oidc.IDToken, cfg *config.RolesConfig) ([]string, error) {
+	var raw map[string]any
+	if err := idToken.Claims(&raw); err != nil {
+		return nil, err
+	}
+
+	switch v := raw[cfg.GroupsClaim].(type) {
+	case string:
+		return []string{v}, nil
+	case []any:
+		groups := make([]string, 0, len(v))
+		for _, g := range v {
+			if s, ok := g.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+		return groups, nil
+	default:
+		return nil, nil
+	}
+}
+
+// GetRole maps groups to an application role. The second value is false when
+// member groups are configured and the user is in none of them.
+func GetRole(groups []string, cfg *config.RolesConfig) (string, bool) {
+	inAny := func(allowed []string) bool {
+		for _, g := range groups {
+			if slices.Contains(allowed, g) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if inAny(cfg.AdminGroups) {
+		return RoleAdmin, true
+	}
+	if len(cfg.MemberGroups) > 0 && !inAny(cfg.MemberGroups) {
+		return "", false
+	}
+	return RoleMember, true
+}
@@ -106,3 +106,28 @@ func (q *Queries) UpdateCollectionEmbeddingByID(ctx context.Context, arg UpdateC
 	)
 	return i, err
 }
+
+const upsertCollectionEmbedding = `-- name: UpsertCollectionEmbedding :exec
+INSERT INTO collection_embeddings (
+  collection_id,
+  embedding
+)
+VALUES (
+  $1,
+  $2
+)
+ON CONFLICT (collection_id) DO UPDATE
+SET
+  embedding  = EXCLUDED.embedding,
+  updated_at = now()
+`
+
+type UpsertCollectionEmbeddingParams struct {
+	CollectionID uuid.UUID        `json:"collectionId"`
+	Embedding    *pgvector.Vector `json:"embedding"`
+}
+
+func (q *Queries) UpsertCollectionEmbedding(ctx context.Context, arg UpsertCollectionEmbeddingParams) error {
+	_, err := q.db.Exec(ctx, upsertCollectionEmbedding, arg.CollectionID, arg.Embedding)
+	return err
+}
@@ -15,38 +15,148 @@ import (
 )
 
 const addItemToCollection = `-- name: AddItemToCollection :one
-INSERT INTO collection_items (collection_id, item_id)
-VALUES ($1, $2)
-RETURNING collection_id, item_id, added_at
+INSERT INTO collection_items (collection_id, item_id, added_by)
+SELECT c.id, $1::uuid, $2::uuid
+FROM collections c
+WHERE c.id      = $3
+  AND c.user_id = $4
+RETURNING collection_id, item_id, added_at, position, added_by
 `
 
 type AddItemToCollectionParams struct {
-	CollectionID uuid.UUID `json:"collectionId"`
 	ItemID       uuid.UUID `json:"itemId"`
+	AddedBy      uuid.UUID `json:"addedBy"`
+	CollectionID uuid.UUID `json:"collectionId"`
+	UserID       uuid.UUID `json:"userId"`
 }
 
+// user_id is the owner of the collection, added_by the user adding the item.
 func (q *Queries) AddItemToCollection(ctx context.Context, arg AddItemToCollectionParams) (CollectionItem, error) {
-	row := q.db.QueryRow(ctx, addItemToCollection, arg.CollectionID, arg.ItemID)
+	row := q.db.QueryRow(ctx, addItemToCollection,
+		arg.ItemID,
+		arg.AddedBy,
+		arg.CollectionID,
+		arg.UserID,
+	)
 	var i CollectionItem
-	err := row.Scan(&i.CollectionID, &i.ItemID, &i.AddedAt)
+	err := row.Scan(
+		&i.CollectionID,
+		&i.ItemID,
+		&i.AddedAt,
+		&i.Position,
+		&i.AddedBy,
+	)
 	return i, err
 }
 
 const countItemsInCollection = `-- name: CountItemsInCollection :one
 SELECT COUNT(*) AS count
-FROM collection_items
-WHERE collection_id = $1
+FROM collection_items ci
+JOIN collections c ON c.id = ci.collection_id
+WHERE ci.collection_id = $1
+  AND c.user_id        = $2
 `
 
-func (q *Queries) CountItemsInCollection(ctx context.Context, collectionID uuid.UUID) (int64, error) {
-	row := q.db.QueryRow(ctx, countItemsInCollection, collectionID)
+type CountItemsInCollectionParams struct {
+	CollectionID uuid.UUID `json:"collectionId"`
+	UserID       uuid.UUID `json:"userId"`
+}
+
+func (q *Queries) CountItemsInCollection(ctx context.Context, arg CountItemsInCollectionParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countItemsInCollection, arg.CollectionID, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countItemsMatchingRules = `-- name: CountItemsMatchingRules :one
+SELECT COUNT(*) AS count
+FROM items i
+LEFT JOIN user_likes ul
+  ON ul.item_id = i.id
+  AND ul.user_id = $1
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = $1
+WHERE
+  EXISTS (
+    SELECT 1 FROM user_feeds uf
+    WHERE uf.user_id = $1
+      AND uf.feed_id = i.feed_id
+  )
+  AND ($2::uuid[] IS NULL OR i.feed_id = ANY($2::uuid[]))
+  AND ($3::text[] IS NULL OR i.categories && $3::text[])
+  AND (
+    $4::text[] IS NULL
+    OR EXISTS (
+      SELECT 1 FROM unnest($4::text[]) AS k(keyword)
+      WHERE i.title       ILIKE '%' || k.keyword || '%'
+         OR i.description ILIKE '%' || k.keyword || '%'
+         OR i.content     ILIKE '%' || k.keyword || '%'
+    )
+  )
+  AND (
+    $5::text IS NULL
+    OR EXISTS (
+      SELECT 1
+      FROM jsonb_array_elements(CASE WHEN jsonb_typeof(i.authors) = 'array' THEN i.authors ELSE '[]'::jsonb END) AS a(author)
+      WHERE a.author->>'name' ILIKE '%' || $5::text || '%'
+    )
+  )
+  AND ($6::timestamptz IS NULL OR COALESCE(i.published_parsed, i.created_at) >= $6)
+  AND ($7::timestamptz IS NULL OR COALESCE(i.published_parsed, i.created_at) < $7)
+  AND ($8::boolean IS NULL OR (ul.user_id IS NOT NULL) = $8)
+  AND ($9::boolean IS NULL OR (ur.user_id IS NULL) = $9)
+  AND (
+    $10::float8 IS NULL
+    OR EXISTS (
+      SELECT 1
+      FROM item_embeddings ie
+      JOIN collection_embeddings ce
+        ON ce.collection_id = $11
+      WHERE ie.item_id = i.id
+        AND 1 - (ie.embedding <=> ce.embedding) >= $10
+    )
+  )
+`
+
+type CountItemsMatchingRulesParams struct {
+	UserID          uuid.UUID   `json:"userId"`
+	FeedIds         []uuid.UUID `json:"feedIds"`
+	Categories      []string    `json:"categories"`
+	Keywords        []string    `json:"keywords"`
+	Author          *string     `json:"author"`
+	PublishedAfter  *time.Time  `json:"publishedAfter"`
+	PublishedBefore *time.Time  `json:"publishedBefore"`
+	Liked           *bool       `json:"liked"`
+	Unread          *bool       `json:"unread"`
+	MinSimilarity   *float64    `json:"minSimilarity"`
+	CollectionID    uuid.UUID   `json:"collectionId"`
+}
+
+// Rules of smart collections are matched against items of the feeds the
+// user is subscribed to. NULL filters match every item.
+func (q *Queries) CountItemsMatchingRules(ctx context.Context, arg CountItemsMatchingRulesParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countItemsMatchingRules,
+		arg.UserID,
+		arg.FeedIds,
+		arg.Categories,
+		arg.Keywords,
+		arg.Author,
+		arg.PublishedAfter,
+		arg.PublishedBefore,
+		arg.Liked,
+		arg.Unread,
+		arg.MinSimilarity,
+		arg.CollectionID,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
 const getCollectionItem = `-- name: GetCollectionItem :one
-SELECT collection_id, item_id, added_at
+SELECT collection_id, item_id, added_at, position, added_by
 FROM collection_items
 WHERE collection_id = $1
   AND item_id       = $2
@@ -60,22 +170,32 @@ type GetCollectionItemParams struct {
 func (q *Queries) GetCollectionItem(ctx context.Context, arg GetCollectionItemParams) (CollectionItem, error) {
 	row := q.db.QueryRow(ctx, getCollectionItem, arg.CollectionID, arg.ItemID)
 	var i CollectionItem
-	err := row.Scan(&i.CollectionID, &i.ItemID, &i.AddedAt)
+	err := row.Scan(
+		&i.CollectionID,
+		&i.ItemID,
+		&i.AddedAt,
+		&i.Position,
+		&i.AddedBy,
+	)
 	return i, err
 }
 
 const listItemsInCollection = `-- name: ListItemsInCollection :many
-SELECT ci.collection_id, ci.item_id, ci.added_at, i.id, i.feed_id, i.title, i.description, i.content, i.link, i.links, i.updated_parsed, i.published_parsed, i.authors, i.guid, i.image, i.categories, i.enclosures, i.created_at, i.updated_at
+SELECT ci.collection_id, ci.item_id, ci.added_at, i.id, i.feed_id, i.title, i.description, i.content, i.link, i.links, i.updated_parsed, i.published_parsed, i.authors, i.guid, i.image, i.categories, i.enclosures, i.created_at, i.updated_at, i.seq, ci.added_by, u.name AS added_by_name
 FROM collection_items ci
+JOIN collections c ON c.id = ci.collection_id
 JOIN items i ON i.id = ci.item_id
+LEFT JOIN users u ON u.id = ci.added_by
 WHERE ci.collection_id = $1
-ORDER BY ci.added_at DESC
-LIMIT  $2
-OFFSET $3
+  AND c.user_id        = $2
+ORDER BY ci.position ASC NULLS FIRST, ci.added_at DESC
+LIMIT  $3
+OFFSET $4
 `
 
 type ListItemsInCollectionParams struct {
 	CollectionID uuid.UUID `json:"collectionId"`
+	UserID       uuid.UUID `json:"userId"`
 	Limit        int32     `json:"limit"`
 	Offset       int32     `json:"offset"`
 }
@@ -100,10 +220,18 @@ type ListItemsInCollectionRow struct {
 	Enclosures      typeext.Enclosures `json:"enclosures"`
 	CreatedAt       time.Time          `json:"createdAt"`
 	UpdatedAt       time.Time          `json:"updatedAt"`
+	Seq             int64              `json:"seq"`
+	AddedBy         *uuid.UUID         `json:"addedBy"`
+	AddedByName     *string            `json:"addedByName"`
 }
 
 func (q *Queries) ListItemsInCollection(ctx context.Context, arg ListItemsInCollectionParams) ([]ListItemsInCollectionRow, error) {
-	rows, err := q.db.Query(ctx, listItemsInCollection, arg.CollectionID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listItemsInCollection,
+		arg.CollectionID,
+		arg.UserID,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -131,6 +259,9 @@ func (q *Queries) ListItemsInCollection(ctx context.Context, arg ListItemsInColl
 			&i.Enclosures,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Seq,
+			&i.AddedBy,
+			&i.AddedByName,
 		); err != nil {
 			return nil, err
 		}
@@ -142,18 +273,213 @@ func (q *Queries) ListItemsInCollection(ctx context.Context, arg ListItemsInColl
 	return items, nil
 }
 
-const removeItemFromCollection = `-- name: RemoveItemFromCollection :exec
-DELETE FROM collection_items
-WHERE collection_id = $1
-  AND item_id       = $2
+const listItemsMatchingRules = `-- name: ListItemsMatchingRules :many
+SELECT
+  $1::uuid                          AS collection_id,
+  i.id                                                     AS item_id,
+  COALESCE(i.published_parsed, i.created_at)::timestamptz  AS added_at,
+  i.id, i.feed_id, i.title, i.description, i.content, i.link, i.links, i.updated_parsed, i.published_parsed, i.authors, i.guid, i.image, i.categories, i.enclosures, i.created_at, i.updated_at, i.seq,
+  NULL::uuid                                               AS added_by,
+  NULL::text                                               AS added_by_name
+FROM items i
+LEFT JOIN user_likes ul
+  ON ul.item_id = i.id
+  AND ul.user_id = $2
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = $2
+WHERE
+  EXISTS (
+    SELECT 1 FROM user_feeds uf
+    WHERE uf.user_id = $2
+      AND uf.feed_id = i.feed_id
+  )
+  AND ($3::uuid[] IS NULL OR i.feed_id = ANY($3::uuid[]))
+  AND ($4::text[] IS NULL OR i.categories && $4::text[])
+  AND (
+    $5::text[] IS NULL
+    OR EXISTS (
+      SELECT 1 FROM unnest($5::text[]) AS k(keyword)
+      WHERE i.title       ILIKE '%' || k.keyword || '%'
+         OR i.description ILIKE '%' || k.keyword || '%'
+         OR i.content     ILIKE '%' || k.keyword || '%'
+    )
+  )
+  AND (
+    $6::text IS NULL
+    OR EXISTS (
+      SELECT 1
+      FROM jsonb_array_elements(CASE WHEN jsonb_typeof(i.authors) = 'array' THEN i.authors ELSE '[]'::jsonb END) AS a(author)
+      WHERE a.author->>'name' ILIKE '%' || $6::text || '%'
+    )
+  )
+  AND ($7::timestamptz IS NULL OR COALESCE(i.published_parsed, i.created_at) >= $7)
+  AND ($8::timestamptz IS NULL OR COALESCE(i.published_parsed, i.created_at) < $8)
+  AND ($9::boolean IS NULL OR (ul.user_id IS NOT NULL) = $9)
+  AND ($10::boolean IS NULL OR (ur.user_id IS NULL) = $10)
+  AND (
+    $11::float8 IS NULL
+    OR EXISTS (
+      SELECT 1
+      FROM item_embeddings ie
+      JOIN collection_embeddings ce
+        ON ce.collection_id = $1
+      WHERE ie.item_id = i.id
+        AND 1 - (ie.embedding <=> ce.embedding) >= $11
+    )
+  )
+ORDER BY COALESCE(i.published_parsed, i.created_at) DESC, i.seq DESC
+LIMIT  $12
+OFFSET $13
+`
+
+type ListItemsMatchingRulesParams struct {
+	CollectionID    uuid.UUID   `json:"collectionId"`
+	UserID          uuid.UUID   `json:"userId"`
+	FeedIds         []uuid.UUID `json:"feedIds"`
+	Categories      []string    `json:"categories"`
+	Keywords        []string    `json:"keywords"`
+	Author          *string     `json:"author"`
+	PublishedAfter  *time.Time  `json:"publishedAfter"`
+	PublishedBefore *time.Time  `json:"publishedBefore"`
+	Liked           *bool       `json:"liked"`
+	Unread          *bool       `json:"unread"`
+	MinSimilarity   *float64    `json:"minSimilarity"`
+	Limit           int32       `json:"limit"`
+	Offset          int32       `json:"offset"`
+}
+
+type ListItemsMatchingRulesRow struct {
+	CollectionID    uuid.UUID          `json:"collectionId"`
+	ItemID          uuid.UUID          `json:"itemId"`
+	AddedAt         time.Time          `json:"addedAt"`
+	ID              uuid.UUID          `json:"id"`
+	FeedID          uuid.UUID          `json:"feedId"`
+	Title           *string            `json:"title"`
+	Description     *string            `json:"description"`
+	Content         *string            `json:"content"`
+	Link            string             `json:"link"`
+	Links           []string           `json:"links"`
+	UpdatedParsed   *time.Time         `json:"updatedParsed"`
+	PublishedParsed *time.Time         `json:"publishedParsed"`
+	Authors         typeext.Authors    `json:"authors"`
+	Guid            *string            `json:"guid"`
+	Image           *gofeed.Image      `json:"image"`
+	Categories      []string           `json:"categories"`
+	Enclosures      typeext.Enclosures `json:"enclosures"`
+	CreatedAt       time.Time          `json:"createdAt"`
+	UpdatedAt       time.Time          `json:"updatedAt"`
+	Seq             int64              `json:"seq"`
+	AddedBy         *uuid.UUID         `json:"addedBy"`
+	AddedByName     *string            `json:"addedByName"`
+}
+
+// Items are returned in the shape of ListItemsInCollection, with the publish
+// date as the date they were added.
+func (q *Queries) ListItemsMatchingRules(ctx context.Context, arg ListItemsMatchingRulesParams) ([]ListItemsMatchingRulesRow, error) {
+	rows, err := q.db.Query(ctx, listItemsMatchingRules,
+		arg.CollectionID,
+		arg.UserID,
+		arg.FeedIds,
+		arg.Categories,
+		arg.Keywords,
+		arg.Author,
+		arg.PublishedAfter,
+		arg.PublishedBefore,
+		arg.Liked,
+		arg.Unread,
+		arg.MinSimilarity,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListItemsMatchingRulesRow
+	for rows.Next() {
+		var i ListItemsMatchingRulesRow
+		if err := rows.Scan(
+			&i.CollectionID,
+			&i.ItemID,
+			&i.AddedAt,
+			&i.ID,
+			&i.FeedID,
+			&i.Title,
+			&i.Description,
+			&i.Content,
+			&i.Link,
+			&i.Links,
+			&i.UpdatedParsed,
+			&i.PublishedParsed,
+			&i.Authors,
+			&i.Guid,
+			&i.Image,
+			&i.Categories,
+			&i.Enclosures,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Seq,
+			&i.AddedBy,
+			&i.AddedByName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeItemFromCollection = `-- name: RemoveItemFromCollection :execrows
+DELETE FROM collection_items ci
+USING collections c
+WHERE c.id              = ci.collection_id
+  AND ci.collection_id  = $1
+  AND ci.item_id        = $2
+  AND c.user_id         = $3
 `
 
 type RemoveItemFromCollectionParams struct {
 	CollectionID uuid.UUID `json:"collectionId"`
 	ItemID       uuid.UUID `json:"itemId"`
+	UserID       uuid.UUID `json:"userId"`
+}
+
+func (q *Queries) RemoveItemFromCollection(ctx context.Context, arg RemoveItemFromCollectionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, removeItemFromCollection, arg.CollectionID, arg.ItemID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
-func (q *Queries) RemoveItemFromCollection(ctx context.Context, arg RemoveItemFromCollectionParams) error {
-	_, err := q.db.Exec(ctx, removeItemFromCollection, arg.CollectionID, arg.ItemID)
-	return err
+const setCollectionItemPositions = `-- name: SetCollectionItemPositions :execrows
+UPDATE collection_items ci
+SET position = o.position::integer
+FROM unnest($1::uuid[]) WITH ORDINALITY AS o(item_id, position)
+WHERE ci.collection_id = $2
+  AND ci.item_id       = o.item_id
+  AND (
+    SELECT COUNT(*) FROM collection_items
+    WHERE collection_id = $2
+      AND item_id = ANY($1::uuid[])
+  ) = cardinality($1::uuid[])
+`
+
+type SetCollectionItemPositionsParams struct {
+	ItemIds      []uuid.UUID `json:"itemIds"`
+	CollectionID uuid.UUID   `json:"collectionId"`
+}
+
+// Positions follow the order of item_ids. Nothing is updated unless every
+// item is in the collection.
+func (q *Queries) SetCollectionItemPositions(ctx context.Context, arg SetCollectionItemPositionsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setCollectionItemPositions, arg.ItemIds, arg.CollectionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
@@ -17,18 +17,22 @@ SELECT
 FROM collection_items ci
 JOIN collections c
   ON ci.collection_id = c.id
+LEFT JOIN collection_members cm
+  ON cm.collection_id = c.id
+  AND cm.user_id = $1
+  AND cm.accepted_at IS NOT NULL
 WHERE
-  ci.item_id = $1
-  AND c.user_id = $2
+  ci.item_id = $2
+  AND (c.user_id = $1 OR cm.user_id IS NOT NULL)
 `
 
 type CountCollectionsByItemIDParams struct {
-	ItemID uuid.UUID `json:"itemId"`
 	UserID uuid.UUID `json:"userId"`
+	ItemID uuid.UUID `json:"itemId"`
 }
 
 func (q *Queries) CountCollectionsByItemID(ctx context.Context, arg CountCollectionsByItemIDParams) (int64, error) {
-	row := q.db.QueryRow(ctx, countCollectionsByItemID, arg.ItemID, arg.UserID)
+	row := q.db.QueryRow(ctx, countCollectionsByItemID, arg.UserID, arg.ItemID)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -36,10 +40,16 @@ func (q *Queries) CountCollectionsByItemID(ctx context.Context, arg CountCollect
 
 const countCollectionsByUserID = `-- name: CountCollectionsByUserID :one
 SELECT COUNT(*) AS count
-FROM collections
-WHERE user_id = $1
+FROM collections c
+LEFT JOIN collection_members cm
+  ON cm.collection_id = c.id
+  AND cm.user_id = $1
+  AND cm.accepted_at IS NOT NULL
+WHERE c.user_id = $1
+   OR cm.user_id IS NOT NULL
 `
 
+// Counts the collections of the user and those shared with them.
 func (q *Queries) CountCollectionsByUserID(ctx context.Context, userID uuid.UUID) (int64, error) {
 	row := q.db.QueryRow(ctx, countCollectionsByUserID, userID)
 	var count int64
@@ -50,7 +60,7 @@ func (q *Queries) CountCollectionsByUserID(ctx context.Context, userID uuid.UUID
 const createCollection = `-- name: CreateCollection :one
 INSERT INTO collections (user_id, name)
 VALUES ($1, $2)
-RETURNING id, user_id, name, created_at, last_updated
+RETURNING id, user_id, name, created_at, last_updated, description, icon, color, pinned, position, public, slug, rules
 `
 
 type CreateCollectionParams struct {
@@ -67,28 +77,184 @@ func (q *Queries) CreateCollection(ctx context.Context, arg CreateCollectionPara
 		&i.Name,
 		&i.CreatedAt,
 		&i.LastUpdated,
+		&i.Description,
+		&i.Icon,
+		&i.Color,
+		&i.Pinned,
+		&i.Position,
+		&i.Public,
+		&i.Slug,
+		&i.Rules,
 	)
 	return i, err
 }
 
-const deleteCollectionByID = `-- name: DeleteCollectionByID :exec
+const deleteCollectionByID = `-- name: DeleteCollectionByID :execrows
 DELETE FROM collections
-WHERE id = $1
+WHERE id      = $1
+  AND user_id = $2
 `
 
-func (q *Queries) DeleteCollectionByID(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteCollectionByID, id)
-	return err
+type DeleteCollectionByIDParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"userId"`
+}
+
+func (q *Queries) DeleteCollectionByID(ctx context.Context, arg DeleteCollectionByIDParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteCollectionByID, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const getCollectionByID = `-- name: GetCollectionByID :one
-SELECT id, user_id, name, created_at, last_updated
+SELECT id, user_id, name, created_at, last_updated, description, icon, color, pinned, position, public, slug, rules
+FROM collections
+WHERE id      = $1
+  AND user_id = $2
+`
+
+type GetCollectionByIDParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"userId"`
+}
+
+func (q *Queries) GetCollectionByID(ctx context.Context, arg GetCollectionByIDParams) (Collection, error) {
+	row := q.db.QueryRow(ctx, getCollectionByID, arg.ID, arg.UserID)
+	var i Collection
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.LastUpdated,
+		&i.Description,
+		&i.Icon,
+		&i.Color,
+		&i.Pinned,
+		&i.Position,
+		&i.Public,
+		&i.Slug,
+		&i.Rules,
+	)
+	return i, err
+}
+
+const getCollectionByName = `-- name: GetCollectionByName :one
+SELECT id, user_id, name, created_at, last_updated, description, icon, color, pinned, position, public, slug, rules
+FROM collections
+WHERE user_id = $1
+  AND name    = $2
+`
+
+type GetCollectionByNameParams struct {
+	UserID uuid.UUID `json:"userId"`
+	Name   string    `json:"name"`
+}
+
+func (q *Queries) GetCollectionByName(ctx context.Context, arg GetCollectionByNameParams) (Collection, error) {
+	row := q.db.QueryRow(ctx, getCollectionByName, arg.UserID, arg.Name)
+	var i Collection
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.LastUpdated,
+		&i.Description,
+		&i.Icon,
+		&i.Color,
+		&i.Pinned,
+		&i.Position,
+		&i.Public,
+		&i.Slug,
+		&i.Rules,
+	)
+	return i, err
+}
+
+const getCollectionForUser = `-- name: GetCollectionForUser :one
+SELECT c.id, c.user_id, c.name, c.created_at, c.last_updated, c.description, c.icon, c.color, c.pinned, c.position, c.public, c.slug, c.rules,
+  (CASE WHEN c.user_id = $1 THEN 'owner' ELSE cm.role END)::text AS role
+FROM collections c
+LEFT JOIN collection_members cm
+  ON cm.collection_id = c.id
+  AND cm.user_id = $1
+  AND cm.accepted_at IS NOT NULL
+WHERE c.id = $2
+  AND (c.user_id = $1 OR cm.user_id IS NOT NULL)
+`
+
+type GetCollectionForUserParams struct {
+	UserID uuid.UUID `json:"userId"`
+	ID     uuid.UUID `json:"id"`
+}
+
+type GetCollectionForUserRow struct {
+	Collection Collection `json:"collection"`
+	Role       string     `json:"role"`
+}
+
+// Returns a collection the user owns or is a member of, with the role of the
+// user in it.
+func (q *Queries) GetCollectionForUser(ctx context.Context, arg GetCollectionForUserParams) (GetCollectionForUserRow, error) {
+	row := q.db.QueryRow(ctx, getCollectionForUser, arg.UserID, arg.ID)
+	var i GetCollectionForUserRow
+	err := row.Scan(
+		&i.Collection.ID,
+		&i.Collection.UserID,
+		&i.Collection.Name,
+		&i.Collection.CreatedAt,
+		&i.Collection.LastUpdated,
+		&i.Collection.Description,
+		&i.Collection.Icon,
+		&i.Collection.Color,
+		&i.Collection.Pinned,
+		&i.Collection.Position,
+		&i.Collection.Public,
+		&i.Collection.Slug,
+		&i.Collection.Rules,
+		&i.Role,
+	)
+	return i, err
+}
+
+const getCollectionNameByID = `-- name: GetCollectionNameByID :one
+SELECT name
 FROM collections
 WHERE id = $1
 `
 
-func (q *Queries) GetCollectionByID(ctx context.Context, id uuid.UUID) (Collection, error) {
-	row := q.db.QueryRow(ctx, getCollectionByID, id)
+func (q *Queries) GetCollectionNameByID(ctx context.Context, id uuid.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, getCollectionNameByID, id)
+	var name string
+	err := row.Scan(&name)
+	return name, err
+}
+
+const getCollectionRulesByID = `-- name: GetCollectionRulesByID :one
+SELECT rules
+FROM collections
+WHERE id = $1
+`
+
+func (q *Queries) GetCollectionRulesByID(ctx context.Context, id uuid.UUID) ([]byte, error) {
+	row := q.db.QueryRow(ctx, getCollectionRulesByID, id)
+	var rules []byte
+	err := row.Scan(&rules)
+	return rules, err
+}
+
+const getPublicCollectionBySlug = `-- name: GetPublicCollectionBySlug :one
+SELECT id, user_id, name, created_at, last_updated, description, icon, color, pinned, position, public, slug, rules
+FROM collections
+WHERE slug   = $1
+  AND public = TRUE
+`
+
+func (q *Queries) GetPublicCollectionBySlug(ctx context.Context, slug *string) (Collection, error) {
+	row := q.db.QueryRow(ctx, getPublicCollectionBySlug, slug)
 	var i Collection
 	err := row.Scan(
 		&i.ID,
@@ -96,23 +262,32 @@ func (q *Queries) GetCollectionByID(ctx context.Context, id uuid.UUID) (Collecti
 		&i.Name,
 		&i.CreatedAt,
 		&i.LastUpdated,
+		&i.Description,
+		&i.Icon,
+		&i.Color,
+		&i.Pinned,
+		&i.Position,
+		&i.Public,
+		&i.Slug,
+		&i.Rules,
 	)
 	return i, err
 }
 
 const listCollectionsByItemID = `-- name: ListCollectionsByItemID :many
 SELECT
-  c.id,
-  c.user_id,
-  c.name,
-  c.created_at,
-  c.last_updated
+  c.id, c.user_id, c.name, c.created_at, c.last_updated, c.description, c.icon, c.color, c.pinned, c.position, c.public, c.slug, c.rules,
+  (CASE WHEN c.user_id = $1 THEN 'owner' ELSE cm.role END)::text AS role
 FROM collections c
 JOIN collection_items ci
   ON ci.collection_id = c.id
+LEFT JOIN collection_members cm
+  ON cm.collection_id = c.id
+  AND cm.user_id = $1
+  AND cm.accepted_at IS NOT NULL
 WHERE
-  ci.item_id = $1
-  AND c.user_id = $2
+  ci.item_id = $2
+  AND (c.user_id = $1 OR cm.user_id IS NOT NULL)
 ORDER BY
   ci.added_at DESC
 LIMIT  $3
@@ -120,16 +295,21 @@ OFFSET $4
 `
 
 type ListCollectionsByItemIDParams struct {
-	ItemID uuid.UUID `json:"itemId"`
 	UserID uuid.UUID `json:"userId"`
+	ItemID uuid.UUID `json:"itemId"`
 	Limit  int32     `json:"limit"`
 	Offset int32     `json:"offset"`
 }
 
-func (q *Queries) ListCollectionsByItemID(ctx context.Context, arg ListCollectionsByItemIDParams) ([]Collection, error) {
+type ListCollectionsByItemIDRow struct {
+	Collection Collection `json:"collection"`
+	Role       string     `json:"role"`
+}
+
+func (q *Queries) ListCollectionsByItemID(ctx context.Context, arg ListCollectionsByItemIDParams) ([]ListCollectionsByItemIDRow, error) {
 	rows, err := q.db.Query(ctx, listCollectionsByItemID,
-		arg.ItemID,
 		arg.UserID,
+		arg.ItemID,
 		arg.Limit,
 		arg.Offset,
 	)
@@ -137,15 +317,24 @@ func (q *Queries) ListCollectionsByItemID(ctx context.Context, arg ListCollectio
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Collection
+	var items []ListCollectionsByItemIDRow
 	for rows.Next() {
-		var i Collection
+		var i ListCollectionsByItemIDRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.Name,
-			&i.CreatedAt,
-			&i.LastUpdated,
+			&i.Collection.ID,
+			&i.Collection.UserID,
+			&i.Collection.Name,
+			&i.Collection.CreatedAt,
+			&i.Collection.LastUpdated,
+			&i.Collection.Description,
+			&i.Collection.Icon,
+			&i.Collection.Color,
+			&i.Collection.Pinned,
+			&i.Collection.Position,
+			&i.Collection.Public,
+			&i.Collection.Slug,
+			&i.Collection.Rules,
+			&i.Role,
 		); err != nil {
 			return nil, err
 		}
@@ -158,10 +347,16 @@ func (q *Queries) ListCollectionsByItemID(ctx context.Context, arg ListCollectio
 }
 
 const listCollectionsByUser = `-- name: ListCollectionsByUser :many
-SELECT id, user_id, name, created_at, last_updated
-FROM collections
-WHERE user_id = $1
-ORDER BY created_at DESC
+SELECT c.id, c.user_id, c.name, c.created_at, c.last_updated, c.description, c.icon, c.color, c.pinned, c.position, c.public, c.slug, c.rules,
+  (CASE WHEN c.user_id = $1 THEN 'owner' ELSE cm.role END)::text AS role
+FROM collections c
+LEFT JOIN collection_members cm
+  ON cm.collection_id = c.id
+  AND cm.user_id = $1
+  AND cm.accepted_at IS NOT NULL
+WHERE c.user_id = $1
+   OR cm.user_id IS NOT NULL
+ORDER BY (c.user_id = $1) DESC, c.pinned DESC, c.position ASC NULLS FIRST, c.created_at DESC
 LIMIT  $2
 OFFSET $3
 `
@@ -172,21 +367,36 @@ type ListCollectionsByUserParams struct {
 	Offset int32     `json:"offset"`
 }
 
-func (q *Queries) ListCollectionsByUser(ctx context.Context, arg ListCollectionsByUserParams) ([]Collection, error) {
+type ListCollectionsByUserRow struct {
+	Collection Collection `json:"collection"`
+	Role       string     `json:"role"`
+}
+
+// Lists the collections of the user followed by those shared with them.
+func (q *Queries) ListCollectionsByUser(ctx context.Context, arg ListCollectionsByUserParams) ([]ListCollectionsByUserRow, error) {
 	rows, err := q.db.Query(ctx, listCollectionsByUser, arg.UserID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Collection
+	var items []ListCollectionsByUserRow
 	for rows.Next() {
-		var i Collection
+		var i ListCollectionsByUserRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.UserID,
-			&i.Name,
-			&i.CreatedAt,
-			&i.LastUpdated,
+			&i.Collection.ID,
+			&i.Collection.UserID,
+			&i.Collection.Name,
+			&i.Collection.CreatedAt,
+			&i.Collection.LastUpdated,
+			&i.Collection.Description,
+			&i.Collection.Icon,
+			&i.Collection.Color,
+			&i.Collection.Pinned,
+			&i.Collection.Position,
+			&i.Collection.Public,
+			&i.Collection.Slug,
+			&i.Collection.Rules,
+			&i.Role,
 		); err != nil {
 			return nil, err
 		}
@@ -198,21 +408,150 @@ func (q *Queries) ListCollectionsByUser(ctx context.Context, arg ListCollections
 	return items, nil
 }
 
+const rotateCollectionSlug = `-- name: RotateCollectionSlug :one
+UPDATE collections
+SET slug = $3
+WHERE id      = $1
+  AND user_id = $2
+RETURNING id, user_id, name, created_at, last_updated, description, icon, color, pinned, position, public, slug, rules
+`
+
+type RotateCollectionSlugParams struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"userId"`
+	Slug   *string   `json:"slug"`
+}
+
+func (q *Queries) RotateCollectionSlug(ctx context.Context, arg RotateCollectionSlugParams) (Collection, error) {
+	row := q.db.QueryRow(ctx, rotateCollectionSlug, arg.ID, arg.UserID, arg.Slug)
+	var i Collection
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.LastUpdated,
+		&i.Description,
+		&i.Icon,
+		&i.Color,
+		&i.Pinned,
+		&i.Position,
+		&i.Public,
+		&i.Slug,
+		&i.Rules,
+	)
+	return i, err
+}
+
+const setCollectionPositions = `-- name: SetCollectionPositions :execrows
+UPDATE collections c
+SET position = o.position::integer
+FROM unnest($1::uuid[]) WITH ORDINALITY AS o(id, position)
+WHERE c.id      = o.id
+  AND c.user_id = $2
+  AND (
+    SELECT COUNT(*) FROM collections
+    WHERE user_id = $2
+      AND id = ANY($1::uuid[])
+  ) = cardinality($1::uuid[])
+`
+
+type SetCollectionPositionsParams struct {
+	Ids    []uuid.UUID `json:"ids"`
+	UserID uuid.UUID   `json:"userId"`
+}
+
+// Positions follow the order of ids. Nothing is updated unless every id is a
+// collection of the user.
+func (q *Queries) SetCollectionPositions(ctx context.Context, arg SetCollectionPositionsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setCollectionPositions, arg.Ids, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setCollectionRules = `-- name: SetCollectionRules :one
+UPDATE collections
+SET rules        = $1,
+    last_updated = now()
+WHERE id      = $2
+  AND user_id = $3
+RETURNING id, user_id, name, created_at, last_updated, description, icon, color, pinned, position, public, slug, rules
+`
+
+type SetCollectionRulesParams struct {
+	Rules  []byte    `json:"rules"`
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"userId"`
+}
+
+// NULL rules turn a smart collection back into a manual one.
+func (q *Queries) SetCollectionRules(ctx context.Context, arg SetCollectionRulesParams) (Collection, error) {
+	row := q.db.QueryRow(ctx, setCollectionRules, arg.Rules, arg.ID, arg.UserID)
+	var i Collection
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.LastUpdated,
+		&i.Description,
+		&i.Icon,
+		&i.Color,
+		&i.Pinned,
+		&i.Position,
+		&i.Public,
+		&i.Slug,
+		&i.Rules,
+	)
+	return i, err
+}
+
 const updateCollectionByID = `-- name: UpdateCollectionByID :one
 UPDATE collections
-SET name         = $2,
+SET name         = COALESCE($1, name),
+    description  = CASE WHEN $2::text IS NULL THEN description
+                        ELSE NULLIF($2::text, '') END,
+    icon         = CASE WHEN $3::text IS NULL THEN icon
+                        ELSE NULLIF($3::text, '') END,
+    color        = CASE WHEN $4::text IS NULL THEN color
+                        ELSE NULLIF($4::text, '') END,
+    pinned       = COALESCE($5, pinned),
+    public       = COALESCE($6, public),
+    slug         = COALESCE(slug, $7),
     last_updated = now()
-WHERE id = $1
-RETURNING id, user_id, name, created_at, last_updated
+WHERE id      = $8
+  AND user_id = $9
+RETURNING id, user_id, name, created_at, last_updated, description, icon, color, pinned, position, public, slug, rules
 `
 
 type UpdateCollectionByIDParams struct {
-	ID   uuid.UUID `json:"id"`
-	Name string    `json:"name"`
+	Name        *string   `json:"name"`
+	Description *string   `json:"description"`
+	Icon        *string   `json:"icon"`
+	Color       *string   `json:"color"`
+	Pinned      *bool     `json:"pinned"`
+	Public      *bool     `json:"public"`
+	Slug        *string   `json:"slug"`
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"userId"`
 }
 
+// NULL leaves a field unchanged, an empty description, icon or color clears it.
+// slug is only set when the collection has none yet.
 func (q *Queries) UpdateCollectionByID(ctx context.Context, arg UpdateCollectionByIDParams) (Collection, error) {
-	row := q.db.QueryRow(ctx, updateCollectionByID, arg.ID, arg.Name)
+	row := q.db.QueryRow(ctx, updateCollectionByID,
+		arg.Name,
+		arg.Description,
+		arg.Icon,
+		arg.Color,
+		arg.Pinned,
+		arg.Public,
+		arg.Slug,
+		arg.ID,
+		arg.UserID,
+	)
 	var i Collection
 	err := row.Scan(
 		&i.ID,
@@ -220,6 +559,14 @@ func (q *Queries) UpdateCollectionByID(ctx context.Context, arg UpdateCollection
 		&i.Name,
 		&i.CreatedAt,
 		&i.LastUpdated,
+		&i.Description,
+		&i.Icon,
+		&i.Color,
+		&i.Pinned,
+		&i.Position,
+		&i.Public,
+		&i.Slug,
+		&i.Rules,
 	)
 	return i, err
 }
@@ -51,7 +51,7 @@ VALUES
 RETURNING
   id, title, description, link, feed_link, links, updated_parsed, published_parsed,
   authors, language, image, copyright, generator,
-  categories, feed_type, feed_version, created_at, last_updated_at
+  categories, feed_type, feed_version, created_at, last_updated_at, seq
 `
 
 type CreateFeedParams struct {
@@ -110,6 +110,7 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.FeedVersion,
 		&i.CreatedAt,
 		&i.LastUpdatedAt,
+		&i.Seq,
 	)
 	return i, err
 }
@@ -164,7 +165,7 @@ const getFeedByFeedLink = `-- name: GetFeedByFeedLink :one
 SELECT
   id, title, description, link, feed_link, links, updated_parsed, published_parsed,
   authors, language, image, copyright, generator,
-  categories, feed_type, feed_version, created_at, last_updated_at
+  categories, feed_type, feed_version, created_at, last_updated_at, seq
 FROM feeds
 WHERE feed_link = $1
 `
@@ -191,6 +192,7 @@ func (q *Queries) GetFeedByFeedLink(ctx context.Context, feedLink string) (Feed,
 		&i.FeedVersion,
 		&i.CreatedAt,
 		&i.LastUpdatedAt,
+		&i.Seq,
 	)
 	return i, err
 }
@@ -199,7 +201,7 @@ const getFeedByID = `-- name: GetFeedByID :one
 SELECT
   id, title, description, link, feed_link, links, updated_parsed, published_parsed,
   authors, language, image, copyright, generator,
-  categories, feed_type, feed_version, created_at, last_updated_at
+  categories, feed_type, feed_version, created_at, last_updated_at, seq
 FROM feeds
 WHERE id = $1
 `
@@ -226,6 +228,7 @@ func (q *Queries) GetFeedByID(ctx context.Context, id uuid.UUID) (Feed, error) {
 		&i.FeedVersion,
 		&i.CreatedAt,
 		&i.LastUpdatedAt,
+		&i.Seq,
 	)
 	return i, err
 }
@@ -302,7 +305,7 @@ const listFeeds = `-- name: ListFeeds :many
 SELECT
   id, title, description, link, feed_link, links, updated_parsed, published_parsed,
   authors, language, image, copyright, generator,
-  categories, feed_type, feed_version, created_at, last_updated_at
+  categories, feed_type, feed_version, created_at, last_updated_at, seq
 FROM feeds
 ORDER BY created_at DESC
 LIMIT  $1
@@ -342,6 +345,7 @@ func (q *Queries) ListFeeds(ctx context.Context, arg ListFeedsParams) ([]Feed, e
 			&i.FeedVersion,
 			&i.CreatedAt,
 			&i.LastUpdatedAt,
+			&i.Seq,
 		); err != nil {
 			return nil, err
 		}
@@ -448,6 +452,56 @@ func (q *Queries) ListFeedsByUserID(ctx context.Context, arg ListFeedsByUserIDPa
 	return items, nil
 }
 
+const listSubscribedFeedRefs = `-- name: ListSubscribedFeedRefs :many
+SELECT
+  f.id,
+  f.seq,
+  COALESCE(uf.title, f.title) AS title,
+  f.link,
+  f.feed_link,
+  f.last_updated_at
+FROM feeds f
+JOIN user_feeds uf ON uf.feed_id = f.id
+WHERE uf.user_id = $1
+ORDER BY f.seq
+`
+
+type ListSubscribedFeedRefsRow struct {
+	ID            uuid.UUID `json:"id"`
+	Seq           int64     `json:"seq"`
+	Title         *string   `json:"title"`
+	Link          *string   `json:"link"`
+	FeedLink      string    `json:"feedLink"`
+	LastUpdatedAt time.Time `json:"lastUpdatedAt"`
+}
+
+func (q *Queries) ListSubscribedFeedRefs(ctx context.Context, userID uuid.UUID) ([]ListSubscribedFeedRefsRow, error) {
+	rows, err := q.db.Query(ctx, listSubscribedFeedRefs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSubscribedFeedRefsRow
+	for rows.Next() {
+		var i ListSubscribedFeedRefsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Seq,
+			&i.Title,
+			&i.Link,
+			&i.FeedLink,
+			&i.LastUpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateFeedByID = `-- name: UpdateFeedByID :one
 UPDATE feeds
 SET
@@ -471,7 +525,7 @@ WHERE id = $1
 RETURNING
   id, title, description, link, feed_link, links, updated_parsed, published_parsed,
   authors, language, image, copyright, generator,
-  categories, feed_type, feed_version, created_at, last_updated_at
+  categories, feed_type, feed_version, created_at, last_updated_at, seq
 `
 
 type UpdateFeedByIDParams struct {
@@ -532,6 +586,7 @@ func (q *Queries) UpdateFeedByID(ctx context.Context, arg UpdateFeedByIDParams)
 		&i.FeedVersion,
 		&i.CreatedAt,
 		&i.LastUpdatedAt,
+		&i.Seq,
 	)
 	return i, err
 }
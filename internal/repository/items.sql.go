@@ -27,6 +27,30 @@ func (q *Queries) CountItemsByFeedID(ctx context.Context, feedID uuid.UUID) (int
 	return count, err
 }
 
+const countItemsByFeedIDForUser = `-- name: CountItemsByFeedIDForUser :one
+SELECT COUNT(*) AS count
+FROM items i
+WHERE i.feed_id = $1
+  AND NOT EXISTS (
+    SELECT 1 FROM hidden_items h
+    WHERE h.user_id = $2
+      AND h.item_id = i.id
+  )
+`
+
+type CountItemsByFeedIDForUserParams struct {
+	FeedID uuid.UUID `json:"feedId"`
+	UserID uuid.UUID `json:"userId"`
+}
+
+// Counts the items of a feed not hidden from the user.
+func (q *Queries) CountItemsByFeedIDForUser(ctx context.Context, arg CountItemsByFeedIDForUserParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countItemsByFeedIDForUser, arg.FeedID, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countLikedItems = `-- name: CountLikedItems :one
 SELECT
   COUNT(*) AS count
@@ -41,6 +65,68 @@ func (q *Queries) CountLikedItems(ctx context.Context, userID uuid.UUID) (int64,
 	return count, err
 }
 
+const countSubscribedItems = `-- name: CountSubscribedItems :one
+SELECT COUNT(*) AS count
+FROM items i
+JOIN user_feeds uf
+  ON uf.feed_id = i.feed_id
+  AND uf.user_id = $1
+`
+
+func (q *Queries) CountSubscribedItems(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countSubscribedItems, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUnreadItemsByFeed = `-- name: CountUnreadItemsByFeed :many
+SELECT
+  i.feed_id,
+  COUNT(*)                       AS count,
+  MAX(i.created_at)::timestamptz AS newest_item_at
+FROM items i
+JOIN user_feeds uf
+  ON uf.feed_id = i.feed_id
+  AND uf.user_id = $1
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = $1
+WHERE ur.user_id IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM hidden_items h
+    WHERE h.user_id = $1
+      AND h.item_id = i.id
+  )
+GROUP BY i.feed_id
+`
+
+type CountUnreadItemsByFeedRow struct {
+	FeedID       uuid.UUID `json:"feedId"`
+	Count        int64     `json:"count"`
+	NewestItemAt time.Time `json:"newestItemAt"`
+}
+
+func (q *Queries) CountUnreadItemsByFeed(ctx context.Context, userID uuid.UUID) ([]CountUnreadItemsByFeedRow, error) {
+	rows, err := q.db.Query(ctx, countUnreadItemsByFeed, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountUnreadItemsByFeedRow
+	for rows.Next() {
+		var i CountUnreadItemsByFeedRow
+		if err := rows.Scan(&i.FeedID, &i.Count, &i.NewestItemAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createItem = `-- name: CreateItem :one
 INSERT INTO items
   (feed_id, title, description, content, link, links, updated_parsed, published_parsed,
@@ -50,7 +136,7 @@ VALUES
    $9, $10, $11, $12, $13)
 RETURNING
   id, feed_id, title, description, content, link, links, updated_parsed, published_parsed,
-  authors, guid, image, categories, enclosures, created_at, updated_at
+  authors, guid, image, categories, enclosures, created_at, updated_at, seq
 `
 
 type CreateItemParams struct {
@@ -103,6 +189,7 @@ func (q *Queries) CreateItem(ctx context.Context, arg CreateItemParams) (Item, e
 		&i.Enclosures,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Seq,
 	)
 	return i, err
 }
@@ -119,7 +206,7 @@ func (q *Queries) DeleteItemByID(ctx context.Context, id uuid.UUID) error {
 const getItemByID = `-- name: GetItemByID :one
 SELECT
   id, feed_id, title, description, content, link, links, updated_parsed, published_parsed,
-  authors, guid, image, categories, enclosures, created_at, updated_at
+  authors, guid, image, categories, enclosures, created_at, updated_at, seq
 FROM items
 WHERE id = $1
 `
@@ -144,6 +231,7 @@ func (q *Queries) GetItemByID(ctx context.Context, id uuid.UUID) (Item, error) {
 		&i.Enclosures,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Seq,
 	)
 	return i, err
 }
@@ -152,7 +240,7 @@ const getLastItem = `-- name: GetLastItem :one
 SELECT
   id, feed_id, title, description, content, link, links,
   updated_parsed, published_parsed, authors, guid, image,
-  categories, enclosures, created_at, updated_at
+  categories, enclosures, created_at, updated_at, seq
 FROM items
 WHERE feed_id = $1
 ORDER BY published_parsed DESC
@@ -179,14 +267,46 @@ func (q *Queries) GetLastItem(ctx context.Context, feedID uuid.UUID) (Item, erro
 		&i.Enclosures,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Seq,
 	)
 	return i, err
 }
 
+const listItemIDsBySeqs = `-- name: ListItemIDsBySeqs :many
+SELECT id, seq
+FROM items
+WHERE seq = ANY($1::bigint[])
+`
+
+type ListItemIDsBySeqsRow struct {
+	ID  uuid.UUID `json:"id"`
+	Seq int64     `json:"seq"`
+}
+
+func (q *Queries) ListItemIDsBySeqs(ctx context.Context, seqs []int64) ([]ListItemIDsBySeqsRow, error) {
+	rows, err := q.db.Query(ctx, listItemIDsBySeqs, seqs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListItemIDsBySeqsRow
+	for rows.Next() {
+		var i ListItemIDsBySeqsRow
+		if err := rows.Scan(&i.ID, &i.Seq); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listItemsByFeedID = `-- name: ListItemsByFeedID :many
 SELECT
   id, feed_id, title, description, content, link, links, updated_parsed, published_parsed,
-  authors, guid, image, categories, enclosures, created_at, updated_at
+  authors, guid, image, categories, enclosures, created_at, updated_at, seq
 FROM items
 WHERE feed_id = $1
 ORDER BY published_parsed DESC
@@ -226,6 +346,7 @@ func (q *Queries) ListItemsByFeedID(ctx context.Context, arg ListItemsByFeedIDPa
 			&i.Enclosures,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Seq,
 		); err != nil {
 			return nil, err
 		}
@@ -261,8 +382,19 @@ FROM items i
 LEFT JOIN user_likes ul
   ON ul.item_id = i.id
   AND ul.user_id = $2
+LEFT JOIN user_feeds uf
+  ON uf.feed_id = i.feed_id
+  AND uf.user_id = $2
 WHERE i.feed_id = $1
-ORDER BY i.published_parsed DESC
+  AND NOT EXISTS (
+    SELECT 1 FROM hidden_items h
+    WHERE h.user_id = $2
+      AND h.item_id = i.id
+  )
+ORDER BY
+  -- subscribers may prefer to read a feed oldest first
+  CASE WHEN uf.sort_order = 'oldest' THEN i.published_parsed END ASC,
+  i.published_parsed DESC
 LIMIT  $3
 OFFSET $4
 `
@@ -339,6 +471,532 @@ func (q *Queries) ListItemsByFeedIDForUser(ctx context.Context, arg ListItemsByF
 	return items, nil
 }
 
+const listItemsBySeqRange = `-- name: ListItemsBySeqRange :many
+SELECT
+  i.id,
+  i.seq,
+  i.feed_id,
+  i.title,
+  i.description,
+  i.content,
+  i.link,
+  i.published_parsed,
+  i.authors,
+  i.categories,
+  i.created_at,
+  i.updated_at,
+  f.title                            AS feed_title,
+  f.link                             AS feed_site_link,
+  (ul.user_id IS NOT NULL)::boolean  AS liked,
+  (ur.user_id IS NOT NULL)::boolean  AS read
+FROM items i
+JOIN feeds f
+  ON f.id = i.feed_id
+JOIN user_feeds uf
+  ON uf.feed_id = i.feed_id
+  AND uf.user_id = $1
+LEFT JOIN user_likes ul
+  ON ul.item_id = i.id
+  AND ul.user_id = $1
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = $1
+WHERE
+  ($2::bigint IS NULL OR i.seq > $2)
+  AND ($3::bigint IS NULL OR i.seq < $3)
+  AND NOT EXISTS (
+    SELECT 1 FROM hidden_items h
+    WHERE h.user_id = $1
+      AND h.item_id = i.id
+  )
+ORDER BY
+  -- items after since_seq are returned oldest first, items before max_seq newest first
+  CASE WHEN $3::bigint IS NULL THEN i.seq END ASC,
+  i.seq DESC
+LIMIT $4
+`
+
+type ListItemsBySeqRangeParams struct {
+	UserID   uuid.UUID `json:"userId"`
+	SinceSeq *int64    `json:"sinceSeq"`
+	MaxSeq   *int64    `json:"maxSeq"`
+	Limit    int32     `json:"limit"`
+}
+
+type ListItemsBySeqRangeRow struct {
+	ID              uuid.UUID       `json:"id"`
+	Seq             int64           `json:"seq"`
+	FeedID          uuid.UUID       `json:"feedId"`
+	Title           *string         `json:"title"`
+	Description     *string         `json:"description"`
+	Content         *string         `json:"content"`
+	Link            string          `json:"link"`
+	PublishedParsed *time.Time      `json:"publishedParsed"`
+	Authors         typeext.Authors `json:"authors"`
+	Categories      []string        `json:"categories"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	UpdatedAt       time.Time       `json:"updatedAt"`
+	FeedTitle       *string         `json:"feedTitle"`
+	FeedSiteLink    *string         `json:"feedSiteLink"`
+	Liked           bool            `json:"liked"`
+	Read            bool            `json:"read"`
+}
+
+func (q *Queries) ListItemsBySeqRange(ctx context.Context, arg ListItemsBySeqRangeParams) ([]ListItemsBySeqRangeRow, error) {
+	rows, err := q.db.Query(ctx, listItemsBySeqRange,
+		arg.UserID,
+		arg.SinceSeq,
+		arg.MaxSeq,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListItemsBySeqRangeRow
+	for rows.Next() {
+		var i ListItemsBySeqRangeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Seq,
+			&i.FeedID,
+			&i.Title,
+			&i.Description,
+			&i.Content,
+			&i.Link,
+			&i.PublishedParsed,
+			&i.Authors,
+			&i.Categories,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FeedTitle,
+			&i.FeedSiteLink,
+			&i.Liked,
+			&i.Read,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLikedItemSeqs = `-- name: ListLikedItemSeqs :many
+SELECT i.seq
+FROM items i
+JOIN user_likes ul
+  ON ul.item_id = i.id
+WHERE ul.user_id = $1
+ORDER BY i.seq
+`
+
+func (q *Queries) ListLikedItemSeqs(ctx context.Context, userID uuid.UUID) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listLikedItemSeqs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var seq int64
+		if err := rows.Scan(&seq); err != nil {
+			return nil, err
+		}
+		items = append(items, seq)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStreamItemRefs = `-- name: ListStreamItemRefs :many
+SELECT
+  i.seq,
+  i.created_at
+FROM items i
+LEFT JOIN user_likes ul
+  ON ul.item_id = i.id
+  AND ul.user_id = $1
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = $1
+WHERE
+  (
+    $2::uuid IS NOT NULL
+    OR $3::uuid IS NOT NULL
+    OR $4::boolean
+    OR EXISTS (
+      SELECT 1 FROM user_feeds uf
+      WHERE uf.user_id = $1
+        AND uf.feed_id = i.feed_id
+        AND NOT uf.muted
+    )
+  )
+  AND ($2::uuid IS NULL OR i.feed_id = $2)
+  AND (
+    $3::uuid IS NULL
+    OR EXISTS (
+      SELECT 1 FROM collection_items ci
+      WHERE ci.collection_id = $3
+        AND ci.item_id = i.id
+    )
+  )
+  AND (NOT $4::boolean OR ul.user_id IS NOT NULL)
+  -- items hidden by rules stay in collections and liked items
+  AND (
+    $3::uuid IS NOT NULL
+    OR $4::boolean
+    OR NOT EXISTS (
+      SELECT 1 FROM hidden_items h
+      WHERE h.user_id = $1
+        AND h.item_id = i.id
+    )
+  )
+  AND ($5::boolean IS NULL OR (ur.user_id IS NOT NULL) = $5)
+  AND ($6::timestamptz IS NULL OR i.created_at >= $6)
+  AND ($7::timestamptz IS NULL OR i.created_at < $7)
+ORDER BY
+  CASE WHEN $8::boolean THEN i.seq END ASC,
+  i.seq DESC
+LIMIT  $9
+OFFSET $10
+`
+
+type ListStreamItemRefsParams struct {
+	UserID       uuid.UUID  `json:"userId"`
+	FeedID       *uuid.UUID `json:"feedId"`
+	CollectionID *uuid.UUID `json:"collectionId"`
+	LikedOnly    bool       `json:"likedOnly"`
+	Read         *bool      `json:"read"`
+	NewerThan    *time.Time `json:"newerThan"`
+	OlderThan    *time.Time `json:"olderThan"`
+	OldestFirst  bool       `json:"oldestFirst"`
+	Limit        int32      `json:"limit"`
+	Offset       int32      `json:"offset"`
+}
+
+type ListStreamItemRefsRow struct {
+	Seq       int64     `json:"seq"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (q *Queries) ListStreamItemRefs(ctx context.Context, arg ListStreamItemRefsParams) ([]ListStreamItemRefsRow, error) {
+	rows, err := q.db.Query(ctx, listStreamItemRefs,
+		arg.UserID,
+		arg.FeedID,
+		arg.CollectionID,
+		arg.LikedOnly,
+		arg.Read,
+		arg.NewerThan,
+		arg.OlderThan,
+		arg.OldestFirst,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStreamItemRefsRow
+	for rows.Next() {
+		var i ListStreamItemRefsRow
+		if err := rows.Scan(&i.Seq, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStreamItems = `-- name: ListStreamItems :many
+SELECT
+  i.id,
+  i.seq,
+  i.feed_id,
+  i.title,
+  i.description,
+  i.content,
+  i.link,
+  i.published_parsed,
+  i.authors,
+  i.categories,
+  i.created_at,
+  i.updated_at,
+  f.title                            AS feed_title,
+  f.link                             AS feed_site_link,
+  (ul.user_id IS NOT NULL)::boolean  AS liked,
+  (ur.user_id IS NOT NULL)::boolean  AS read
+FROM items i
+JOIN feeds f
+  ON f.id = i.feed_id
+LEFT JOIN user_likes ul
+  ON ul.item_id = i.id
+  AND ul.user_id = $1
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = $1
+WHERE
+  -- feed, collection and liked streams may contain items from feeds the
+  -- user is not subscribed to; everything else is limited to subscriptions
+  -- that are not muted
+  (
+    $2::uuid IS NOT NULL
+    OR $3::uuid IS NOT NULL
+    OR $4::boolean
+    OR EXISTS (
+      SELECT 1 FROM user_feeds uf
+      WHERE uf.user_id = $1
+        AND uf.feed_id = i.feed_id
+        AND NOT uf.muted
+    )
+  )
+  AND ($2::uuid IS NULL OR i.feed_id = $2)
+  AND (
+    $3::uuid IS NULL
+    OR EXISTS (
+      SELECT 1 FROM collection_items ci
+      WHERE ci.collection_id = $3
+        AND ci.item_id = i.id
+    )
+  )
+  AND (NOT $4::boolean OR ul.user_id IS NOT NULL)
+  -- items hidden by rules stay in collections and liked items
+  AND (
+    $3::uuid IS NOT NULL
+    OR $4::boolean
+    OR NOT EXISTS (
+      SELECT 1 FROM hidden_items h
+      WHERE h.user_id = $1
+        AND h.item_id = i.id
+    )
+  )
+  AND ($5::boolean IS NULL OR (ur.user_id IS NOT NULL) = $5)
+  AND ($6::timestamptz IS NULL OR i.created_at >= $6)
+  AND ($7::timestamptz IS NULL OR i.created_at < $7)
+ORDER BY
+  CASE WHEN $8::boolean THEN i.seq END ASC,
+  i.seq DESC
+LIMIT  $9
+OFFSET $10
+`
+
+type ListStreamItemsParams struct {
+	UserID       uuid.UUID  `json:"userId"`
+	FeedID       *uuid.UUID `json:"feedId"`
+	CollectionID *uuid.UUID `json:"collectionId"`
+	LikedOnly    bool       `json:"likedOnly"`
+	Read         *bool      `json:"read"`
+	NewerThan    *time.Time `json:"newerThan"`
+	OlderThan    *time.Time `json:"olderThan"`
+	OldestFirst  bool       `json:"oldestFirst"`
+	Limit        int32      `json:"limit"`
+	Offset       int32      `json:"offset"`
+}
+
+type ListStreamItemsRow struct {
+	ID              uuid.UUID       `json:"id"`
+	Seq             int64           `json:"seq"`
+	FeedID          uuid.UUID       `json:"feedId"`
+	Title           *string         `json:"title"`
+	Description     *string         `json:"description"`
+	Content         *string         `json:"content"`
+	Link            string          `json:"link"`
+	PublishedParsed *time.Time      `json:"publishedParsed"`
+	Authors         typeext.Authors `json:"authors"`
+	Categories      []string        `json:"categories"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	UpdatedAt       time.Time       `json:"updatedAt"`
+	FeedTitle       *string         `json:"feedTitle"`
+	FeedSiteLink    *string         `json:"feedSiteLink"`
+	Liked           bool            `json:"liked"`
+	Read            bool            `json:"read"`
+}
+
+func (q *Queries) ListStreamItems(ctx context.Context, arg ListStreamItemsParams) ([]ListStreamItemsRow, error) {
+	rows, err := q.db.Query(ctx, listStreamItems,
+		arg.UserID,
+		arg.FeedID,
+		arg.CollectionID,
+		arg.LikedOnly,
+		arg.Read,
+		arg.NewerThan,
+		arg.OlderThan,
+		arg.OldestFirst,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStreamItemsRow
+	for rows.Next() {
+		var i ListStreamItemsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Seq,
+			&i.FeedID,
+			&i.Title,
+			&i.Description,
+			&i.Content,
+			&i.Link,
+			&i.PublishedParsed,
+			&i.Authors,
+			&i.Categories,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FeedTitle,
+			&i.FeedSiteLink,
+			&i.Liked,
+			&i.Read,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStreamItemsBySeqs = `-- name: ListStreamItemsBySeqs :many
+SELECT
+  i.id,
+  i.seq,
+  i.feed_id,
+  i.title,
+  i.description,
+  i.content,
+  i.link,
+  i.published_parsed,
+  i.authors,
+  i.categories,
+  i.created_at,
+  i.updated_at,
+  f.title                            AS feed_title,
+  f.link                             AS feed_site_link,
+  (ul.user_id IS NOT NULL)::boolean  AS liked,
+  (ur.user_id IS NOT NULL)::boolean  AS read
+FROM items i
+JOIN feeds f
+  ON f.id = i.feed_id
+LEFT JOIN user_likes ul
+  ON ul.item_id = i.id
+  AND ul.user_id = $1
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = $1
+WHERE i.seq = ANY($2::bigint[])
+ORDER BY i.seq DESC
+`
+
+type ListStreamItemsBySeqsParams struct {
+	UserID uuid.UUID `json:"userId"`
+	Seqs   []int64   `json:"seqs"`
+}
+
+type ListStreamItemsBySeqsRow struct {
+	ID              uuid.UUID       `json:"id"`
+	Seq             int64           `json:"seq"`
+	FeedID          uuid.UUID       `json:"feedId"`
+	Title           *string         `json:"title"`
+	Description     *string         `json:"description"`
+	Content         *string         `json:"content"`
+	Link            string          `json:"link"`
+	PublishedParsed *time.Time      `json:"publishedParsed"`
+	Authors         typeext.Authors `json:"authors"`
+	Categories      []string        `json:"categories"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	UpdatedAt       time.Time       `json:"updatedAt"`
+	FeedTitle       *string         `json:"feedTitle"`
+	FeedSiteLink    *string         `json:"feedSiteLink"`
+	Liked           bool            `json:"liked"`
+	Read            bool            `json:"read"`
+}
+
+func (q *Queries) ListStreamItemsBySeqs(ctx context.Context, arg ListStreamItemsBySeqsParams) ([]ListStreamItemsBySeqsRow, error) {
+	rows, err := q.db.Query(ctx, listStreamItemsBySeqs, arg.UserID, arg.Seqs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListStreamItemsBySeqsRow
+	for rows.Next() {
+		var i ListStreamItemsBySeqsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Seq,
+			&i.FeedID,
+			&i.Title,
+			&i.Description,
+			&i.Content,
+			&i.Link,
+			&i.PublishedParsed,
+			&i.Authors,
+			&i.Categories,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FeedTitle,
+			&i.FeedSiteLink,
+			&i.Liked,
+			&i.Read,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnreadItemSeqs = `-- name: ListUnreadItemSeqs :many
+SELECT i.seq
+FROM items i
+JOIN user_feeds uf
+  ON uf.feed_id = i.feed_id
+  AND uf.user_id = $1
+LEFT JOIN user_reads ur
+  ON ur.item_id = i.id
+  AND ur.user_id = $1
+WHERE ur.user_id IS NULL
+  AND NOT EXISTS (
+    SELECT 1 FROM hidden_items h
+    WHERE h.user_id = $1
+      AND h.item_id = i.id
+  )
+ORDER BY i.seq
+`
+
+func (q *Queries) ListUnreadItemSeqs(ctx context.Context, userID uuid.UUID) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listUnreadItemSeqs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var seq int64
+		if err := rows.Scan(&seq); err != nil {
+			return nil, err
+		}
+		items = append(items, seq)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUserLikedItems = `-- name: ListUserLikedItems :many
 SELECT
   i.id,
@@ -453,7 +1111,7 @@ SET
 WHERE id = $1
 RETURNING
   id, feed_id, title, description, content, link, links, updated_parsed, published_parsed,
-  authors, guid, image, categories, enclosures, created_at, updated_at
+  authors, guid, image, categories, enclosures, created_at, updated_at, seq
 `
 
 type UpdateItemByIDParams struct {
@@ -506,6 +1164,7 @@ func (q *Queries) UpdateItemByID(ctx context.Context, arg UpdateItemByIDParams)
 		&i.Enclosures,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Seq,
 	)
 	return i, err
 }
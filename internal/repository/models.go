@@ -13,12 +13,40 @@ import (
 	typeext "github.com/rhajizada/gazette/internal/typeext"
 )
 
+type AccessToken struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"userId"`
+	Name       string     `json:"name"`
+	TokenHash  string     `json:"tokenHash"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+type AppPassword struct {
+	ID           uuid.UUID  `json:"id"`
+	UserID       uuid.UUID  `json:"userId"`
+	Name         string     `json:"name"`
+	PasswordHash string     `json:"passwordHash"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	LastUsedAt   *time.Time `json:"lastUsedAt"`
+}
+
 type Collection struct {
 	ID          uuid.UUID `json:"id"`
 	UserID      uuid.UUID `json:"userId"`
 	Name        string    `json:"name"`
 	CreatedAt   time.Time `json:"createdAt"`
 	LastUpdated time.Time `json:"lastUpdated"`
+	Description *string   `json:"description"`
+	Icon        *string   `json:"icon"`
+	Color       *string   `json:"color"`
+	Pinned      bool      `json:"pinned"`
+	Position    *int32    `json:"position"`
+	Public      bool      `json:"public"`
+	Slug        *string   `json:"slug"`
+	Rules       []byte    `json:"rules"`
 }
 
 type CollectionEmbedding struct {
@@ -28,10 +56,29 @@ type CollectionEmbedding struct {
 	UpdatedAt    time.Time        `json:"updatedAt"`
 }
 
+type CollectionInvitation struct {
+	CollectionID uuid.UUID  `json:"collectionId"`
+	Email        string     `json:"email"`
+	Role         string     `json:"role"`
+	InvitedBy    *uuid.UUID `json:"invitedBy"`
+	InvitedAt    time.Time  `json:"invitedAt"`
+}
+
 type CollectionItem struct {
-	CollectionID uuid.UUID `json:"collectionId"`
-	ItemID       uuid.UUID `json:"itemId"`
-	AddedAt      time.Time `json:"addedAt"`
+	CollectionID uuid.UUID  `json:"collectionId"`
+	ItemID       uuid.UUID  `json:"itemId"`
+	AddedAt      time.Time  `json:"addedAt"`
+	Position     *int32     `json:"position"`
+	AddedBy      *uuid.UUID `json:"addedBy"`
+}
+
+type CollectionMember struct {
+	CollectionID uuid.UUID  `json:"collectionId"`
+	UserID       uuid.UUID  `json:"userId"`
+	Role         string     `json:"role"`
+	InvitedBy    *uuid.UUID `json:"invitedBy"`
+	InvitedAt    time.Time  `json:"invitedAt"`
+	AcceptedAt   *time.Time `json:"acceptedAt"`
 }
 
 type Feed struct {
@@ -53,6 +100,51 @@ type Feed struct {
 	FeedVersion     *string         `json:"feedVersion"`
 	CreatedAt       time.Time       `json:"createdAt"`
 	LastUpdatedAt   time.Time       `json:"lastUpdatedAt"`
+	Seq             int64           `json:"seq"`
+}
+
+type FeedHealth struct {
+	FeedID        uuid.UUID  `json:"feedId"`
+	LastSuccessAt *time.Time `json:"lastSuccessAt"`
+	LastFailureAt *time.Time `json:"lastFailureAt"`
+	LastError     *string    `json:"lastError"`
+	FailureCount  int32      `json:"failureCount"`
+}
+
+type FeedTag struct {
+	TagID    uuid.UUID `json:"tagId"`
+	FeedID   uuid.UUID `json:"feedId"`
+	TaggedAt time.Time `json:"taggedAt"`
+}
+
+type FeverCredential struct {
+	UserID    uuid.UUID `json:"userId"`
+	ApiKey    string    `json:"apiKey"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type FilterRule struct {
+	ID           uuid.UUID  `json:"id"`
+	UserID       uuid.UUID  `json:"userId"`
+	Name         string     `json:"name"`
+	FeedID       *uuid.UUID `json:"feedId"`
+	Field        string     `json:"field"`
+	MatchType    string     `json:"matchType"`
+	Pattern      string     `json:"pattern"`
+	Action       string     `json:"action"`
+	CollectionID *uuid.UUID `json:"collectionId"`
+	Enabled      bool       `json:"enabled"`
+	HitCount     int64      `json:"hitCount"`
+	LastHitAt    *time.Time `json:"lastHitAt"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+type HiddenItem struct {
+	UserID   uuid.UUID `json:"userId"`
+	ItemID   uuid.UUID `json:"itemId"`
+	RuleID   uuid.UUID `json:"ruleId"`
+	HiddenAt time.Time `json:"hiddenAt"`
 }
 
 type Item struct {
@@ -72,6 +164,19 @@ type Item struct {
 	Enclosures      typeext.Enclosures `json:"enclosures"`
 	CreatedAt       time.Time          `json:"createdAt"`
 	UpdatedAt       time.Time          `json:"updatedAt"`
+	Seq             int64              `json:"seq"`
+}
+
+type ItemAnnotation struct {
+	ID          uuid.UUID `json:"id"`
+	UserID      uuid.UUID `json:"userId"`
+	ItemID      uuid.UUID `json:"itemId"`
+	Note        *string   `json:"note"`
+	Quote       *string   `json:"quote"`
+	StartOffset *int32    `json:"startOffset"`
+	EndOffset   *int32    `json:"endOffset"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
 }
 
 type ItemEmbedding struct {
@@ -81,13 +186,50 @@ type ItemEmbedding struct {
 	UpdatedAt time.Time        `json:"updatedAt"`
 }
 
+type ItemTag struct {
+	TagID    uuid.UUID `json:"tagId"`
+	ItemID   uuid.UUID `json:"itemId"`
+	TaggedAt time.Time `json:"taggedAt"`
+}
+
+type RefreshToken struct {
+	ID        uuid.UUID  `json:"id"`
+	SessionID uuid.UUID  `json:"sessionId"`
+	TokenHash string     `json:"tokenHash"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UsedAt    *time.Time `json:"usedAt"`
+}
+
+type Session struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"userId"`
+	Device     string     `json:"device"`
+	Ip         string     `json:"ip"`
+	UserAgent  string     `json:"userAgent"`
+	IDToken    *string    `json:"idToken"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastSeenAt time.Time  `json:"lastSeenAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	Provider   *string    `json:"provider"`
+}
+
+type Tag struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"userId"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 type User struct {
-	ID            uuid.UUID `json:"id"`
-	Sub           string    `json:"sub"`
-	Name          string    `json:"name"`
-	Email         string    `json:"email"`
-	CreatedAt     time.Time `json:"createdAt"`
-	LastUpdatedAt time.Time `json:"lastUpdatedAt"`
+	ID            uuid.UUID  `json:"id"`
+	Sub           string     `json:"sub"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	LastUpdatedAt time.Time  `json:"lastUpdatedAt"`
+	Role          string     `json:"role"`
+	DisabledAt    *time.Time `json:"disabledAt"`
 }
 
 type UserEmbedding struct {
@@ -101,6 +243,24 @@ type UserFeed struct {
 	UserID       uuid.UUID `json:"userId"`
 	FeedID       uuid.UUID `json:"feedId"`
 	SubscribedAt time.Time `json:"subscribedAt"`
+	Folder       *string   `json:"folder"`
+	Title        *string   `json:"title"`
+	Icon         *string   `json:"icon"`
+	Notify       string    `json:"notify"`
+	SortOrder    string    `json:"sortOrder"`
+	Muted        bool      `json:"muted"`
+}
+
+type UserIdentity struct {
+	ID            uuid.UUID `json:"id"`
+	UserID        uuid.UUID `json:"userId"`
+	Provider      string    `json:"provider"`
+	Issuer        string    `json:"issuer"`
+	Sub           string    `json:"sub"`
+	Email         *string   `json:"email"`
+	CreatedAt     time.Time `json:"createdAt"`
+	LastLoginAt   time.Time `json:"lastLoginAt"`
+	EmailVerified bool      `json:"emailVerified"`
 }
 
 type UserLike struct {
@@ -108,3 +268,41 @@ type UserLike struct {
 	ItemID  uuid.UUID `json:"itemId"`
 	LikedAt time.Time `json:"likedAt"`
 }
+
+type UserRead struct {
+	UserID uuid.UUID `json:"userId"`
+	ItemID uuid.UUID `json:"itemId"`
+	ReadAt time.Time `json:"readAt"`
+}
+
+type Webhook struct {
+	ID             uuid.UUID   `json:"id"`
+	UserID         uuid.UUID   `json:"userId"`
+	Name           string      `json:"name"`
+	Url            string      `json:"url"`
+	Secret         string      `json:"secret"`
+	Events         []string    `json:"events"`
+	FeedIds        []uuid.UUID `json:"feedIds"`
+	CollectionIds  []uuid.UUID `json:"collectionIds"`
+	RuleIds        []uuid.UUID `json:"ruleIds"`
+	Enabled        bool        `json:"enabled"`
+	FailureCount   int32       `json:"failureCount"`
+	DisabledReason *string     `json:"disabledReason"`
+	LastDeliveryAt *time.Time  `json:"lastDeliveryAt"`
+	CreatedAt      time.Time   `json:"createdAt"`
+	UpdatedAt      time.Time   `json:"updatedAt"`
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID  `json:"id"`
+	WebhookID      uuid.UUID  `json:"webhookId"`
+	Event          string     `json:"event"`
+	Payload        []byte     `json:"payload"`
+	Status         string     `json:"status"`
+	Attempts       int32      `json:"attempts"`
+	ResponseStatus *int32     `json:"responseStatus"`
+	Error          *string    `json:"error"`
+	DurationMs     *int32     `json:"durationMs"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	AttemptedAt    *time.Time `json:"attemptedAt"`
+}
@@ -6,74 +6,219 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AcceptCollectionInvitation(ctx context.Context, arg AcceptCollectionInvitationParams) (int64, error)
 	AddItemToCollection(ctx context.Context, arg AddItemToCollectionParams) (CollectionItem, error)
+	AddItemToCollectionByRule(ctx context.Context, arg AddItemToCollectionByRuleParams) (int64, error)
+	AppPasswordIsActive(ctx context.Context, arg AppPasswordIsActiveParams) (bool, error)
 	CountCollectionsByItemID(ctx context.Context, arg CountCollectionsByItemIDParams) (int64, error)
 	CountCollectionsByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 	CountFeeds(ctx context.Context) (int64, error)
+	CountFeedsByTag(ctx context.Context, arg CountFeedsByTagParams) (int64, error)
 	CountFeedsByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountFeedsWithHealth(ctx context.Context, failingOnly bool) (int64, error)
+	CountItemAnnotationsByUser(ctx context.Context, userID uuid.UUID) (int64, error)
 	CountItemsByFeedID(ctx context.Context, feedID uuid.UUID) (int64, error)
-	CountItemsInCollection(ctx context.Context, collectionID uuid.UUID) (int64, error)
+	CountItemsByFeedIDForUser(ctx context.Context, arg CountItemsByFeedIDForUserParams) (int64, error)
+	CountItemsByTag(ctx context.Context, arg CountItemsByTagParams) (int64, error)
+	CountItemsInCollection(ctx context.Context, arg CountItemsInCollectionParams) (int64, error)
+	CountItemsMatchingRules(ctx context.Context, arg CountItemsMatchingRulesParams) (int64, error)
 	CountLikedItems(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountSubscribedItems(ctx context.Context, userID uuid.UUID) (int64, error)
+	CountUnreadItemsByFeed(ctx context.Context, userID uuid.UUID) ([]CountUnreadItemsByFeedRow, error)
+	CountUserIdentitiesByUserID(ctx context.Context, userID uuid.UUID) (int64, error)
 	CountUsers(ctx context.Context) (int64, error)
+	CountUsersByQuery(ctx context.Context, query string) (int64, error)
+	CountWebhookDeliveries(ctx context.Context, webhookID uuid.UUID) (int64, error)
+	CountWebhooks(ctx context.Context, userID uuid.UUID) (int64, error)
+	CreateAccessToken(ctx context.Context, arg CreateAccessTokenParams) (AccessToken, error)
+	CreateAppPassword(ctx context.Context, arg CreateAppPasswordParams) (AppPassword, error)
 	CreateCollection(ctx context.Context, arg CreateCollectionParams) (Collection, error)
 	CreateCollectionEmbedding(ctx context.Context, arg CreateCollectionEmbeddingParams) (CollectionEmbedding, error)
+	CreateCollectionInvitation(ctx context.Context, arg CreateCollectionInvitationParams) (CollectionInvitation, error)
 	CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error)
+	CreateFilterRule(ctx context.Context, arg CreateFilterRuleParams) (FilterRule, error)
 	CreateItem(ctx context.Context, arg CreateItemParams) (Item, error)
+	CreateItemAnnotation(ctx context.Context, arg CreateItemAnnotationParams) (ItemAnnotation, error)
 	CreateItemEmbedding(ctx context.Context, arg CreateItemEmbeddingParams) (ItemEmbedding, error)
+	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateUserEmbedding(ctx context.Context, arg CreateUserEmbeddingParams) (UserEmbedding, error)
 	CreateUserFeedSubscription(ctx context.Context, arg CreateUserFeedSubscriptionParams) (UserFeed, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
 	CreateUserLike(ctx context.Context, arg CreateUserLikeParams) (UserLike, error)
-	DeleteCollectionByID(ctx context.Context, id uuid.UUID) error
+	CreateUserRead(ctx context.Context, arg CreateUserReadParams) error
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error)
+	CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error)
+	DeclineCollectionInvitation(ctx context.Context, arg DeclineCollectionInvitationParams) (int64, error)
+	DeleteAccessToken(ctx context.Context, arg DeleteAccessTokenParams) (int64, error)
+	DeleteAppPassword(ctx context.Context, arg DeleteAppPasswordParams) (int64, error)
+	DeleteCollectionByID(ctx context.Context, arg DeleteCollectionByIDParams) (int64, error)
 	DeleteCollectionEmbeddingByID(ctx context.Context, collectionID uuid.UUID) error
+	DeleteCollectionInvitation(ctx context.Context, arg DeleteCollectionInvitationParams) (int64, error)
+	DeleteCollectionMember(ctx context.Context, arg DeleteCollectionMemberParams) (int64, error)
 	DeleteFeedByID(ctx context.Context, id uuid.UUID) error
+	DeleteFeverCredential(ctx context.Context, userID uuid.UUID) (int64, error)
+	DeleteFilterRule(ctx context.Context, arg DeleteFilterRuleParams) (int64, error)
+	DeleteFilterRuleHides(ctx context.Context, ruleID uuid.UUID) error
+	DeleteItemAnnotation(ctx context.Context, arg DeleteItemAnnotationParams) (int64, error)
 	DeleteItemByID(ctx context.Context, id uuid.UUID) error
 	DeleteItemEmbeddingByID(ctx context.Context, itemID uuid.UUID) error
+	DeleteTag(ctx context.Context, arg DeleteTagParams) (int64, error)
 	DeleteUserByID(ctx context.Context, id uuid.UUID) error
 	DeleteUserEmbedding(ctx context.Context, userID uuid.UUID) error
 	DeleteUserFeedSubscription(ctx context.Context, arg DeleteUserFeedSubscriptionParams) error
+	DeleteUserIdentity(ctx context.Context, arg DeleteUserIdentityParams) (int64, error)
 	DeleteUserLike(ctx context.Context, arg DeleteUserLikeParams) error
+	DeleteUserRead(ctx context.Context, arg DeleteUserReadParams) error
+	DeleteWebhook(ctx context.Context, arg DeleteWebhookParams) (int64, error)
+	DeleteWebhookDeliveriesBefore(ctx context.Context, createdAt time.Time) (int64, error)
 	ExportFeedsByUserID(ctx context.Context, arg ExportFeedsByUserIDParams) ([]string, error)
-	GetCollectionByID(ctx context.Context, id uuid.UUID) (Collection, error)
+	ExtendSession(ctx context.Context, arg ExtendSessionParams) error
+	GetAccessTokenByHash(ctx context.Context, tokenHash string) (AccessToken, error)
+	GetAppPasswordByHash(ctx context.Context, passwordHash string) (AppPassword, error)
+	GetCollectionByID(ctx context.Context, arg GetCollectionByIDParams) (Collection, error)
+	GetCollectionByName(ctx context.Context, arg GetCollectionByNameParams) (Collection, error)
 	GetCollectionEmbeddingByID(ctx context.Context, collectionID uuid.UUID) (CollectionEmbedding, error)
+	GetCollectionForUser(ctx context.Context, arg GetCollectionForUserParams) (GetCollectionForUserRow, error)
 	GetCollectionItem(ctx context.Context, arg GetCollectionItemParams) (CollectionItem, error)
+	GetCollectionNameByID(ctx context.Context, id uuid.UUID) (string, error)
+	GetCollectionRulesByID(ctx context.Context, id uuid.UUID) ([]byte, error)
 	GetFeedByFeedLink(ctx context.Context, feedLink string) (Feed, error)
 	GetFeedByID(ctx context.Context, id uuid.UUID) (Feed, error)
+	GetFeedHealth(ctx context.Context, feedID uuid.UUID) (FeedHealth, error)
+	GetFeverCredentialByAPIKey(ctx context.Context, apiKey string) (FeverCredential, error)
+	GetFeverCredentialByUserID(ctx context.Context, userID uuid.UUID) (FeverCredential, error)
+	GetFilterRule(ctx context.Context, arg GetFilterRuleParams) (FilterRule, error)
+	GetItemAnnotation(ctx context.Context, arg GetItemAnnotationParams) (ItemAnnotation, error)
 	GetItemByID(ctx context.Context, id uuid.UUID) (Item, error)
 	GetItemEmbeddingByID(ctx context.Context, itemID uuid.UUID) (ItemEmbedding, error)
 	GetLastItem(ctx context.Context, feedID uuid.UUID) (Item, error)
+	GetPublicCollectionBySlug(ctx context.Context, slug *string) (Collection, error)
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (RefreshToken, error)
+	GetSessionByID(ctx context.Context, id uuid.UUID) (Session, error)
+	GetTagByID(ctx context.Context, arg GetTagByIDParams) (Tag, error)
+	GetTagByName(ctx context.Context, arg GetTagByNameParams) (Tag, error)
+	GetUnlinkedUserBySub(ctx context.Context, sub string) (User, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
 	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
 	GetUserBySub(ctx context.Context, sub string) (User, error)
 	GetUserEmbedding(ctx context.Context, userID uuid.UUID) (UserEmbedding, error)
 	GetUserFeedByID(ctx context.Context, arg GetUserFeedByIDParams) (GetUserFeedByIDRow, error)
 	GetUserFeedSubscription(ctx context.Context, arg GetUserFeedSubscriptionParams) (UserFeed, error)
+	GetUserIdentityByIssuerSub(ctx context.Context, arg GetUserIdentityByIssuerSubParams) (UserIdentity, error)
 	GetUserLike(ctx context.Context, arg GetUserLikeParams) (UserLike, error)
-	ListCollectionsByItemID(ctx context.Context, arg ListCollectionsByItemIDParams) ([]Collection, error)
-	ListCollectionsByUser(ctx context.Context, arg ListCollectionsByUserParams) ([]Collection, error)
+	GetUserWithStats(ctx context.Context, id uuid.UUID) (GetUserWithStatsRow, error)
+	GetWebhook(ctx context.Context, arg GetWebhookParams) (Webhook, error)
+	GetWebhookByID(ctx context.Context, id uuid.UUID) (Webhook, error)
+	GetWebhookDelivery(ctx context.Context, id uuid.UUID) (WebhookDelivery, error)
+	HideItems(ctx context.Context, arg HideItemsParams) (int64, error)
+	ListAccessTokensByUserID(ctx context.Context, userID uuid.UUID) ([]AccessToken, error)
+	ListActiveSessionsByUserID(ctx context.Context, userID uuid.UUID) ([]Session, error)
+	ListAppPasswordsByUserID(ctx context.Context, userID uuid.UUID) ([]AppPassword, error)
+	ListCollectionInvitations(ctx context.Context, userID uuid.UUID) ([]ListCollectionInvitationsRow, error)
+	ListCollectionInvitationsByCollection(ctx context.Context, collectionID uuid.UUID) ([]CollectionInvitation, error)
+	ListCollectionMembers(ctx context.Context, collectionID uuid.UUID) ([]ListCollectionMembersRow, error)
+	ListCollectionsByItemID(ctx context.Context, arg ListCollectionsByItemIDParams) ([]ListCollectionsByItemIDRow, error)
+	ListCollectionsByUser(ctx context.Context, arg ListCollectionsByUserParams) ([]ListCollectionsByUserRow, error)
+	ListFeedFolders(ctx context.Context, userID uuid.UUID) ([]ListFeedFoldersRow, error)
+	ListFeedTagNames(ctx context.Context, arg ListFeedTagNamesParams) ([]ListFeedTagNamesRow, error)
 	ListFeeds(ctx context.Context, arg ListFeedsParams) ([]Feed, error)
+	ListFeedsByTag(ctx context.Context, arg ListFeedsByTagParams) ([]ListFeedsByTagRow, error)
 	ListFeedsByUserID(ctx context.Context, arg ListFeedsByUserIDParams) ([]ListFeedsByUserIDRow, error)
+	ListFeedsWithHealth(ctx context.Context, arg ListFeedsWithHealthParams) ([]ListFeedsWithHealthRow, error)
+	ListFilterRules(ctx context.Context, userID uuid.UUID) ([]FilterRule, error)
+	ListFilterRulesForFeed(ctx context.Context, feedID uuid.UUID) ([]FilterRule, error)
+	ListItemAnnotationsByItem(ctx context.Context, arg ListItemAnnotationsByItemParams) ([]ItemAnnotation, error)
+	ListItemAnnotationsByUser(ctx context.Context, arg ListItemAnnotationsByUserParams) ([]ListItemAnnotationsByUserRow, error)
+	ListItemAnnotationsForExport(ctx context.Context, arg ListItemAnnotationsForExportParams) ([]ListItemAnnotationsForExportRow, error)
+	ListItemIDsBySeqs(ctx context.Context, seqs []int64) ([]ListItemIDsBySeqsRow, error)
+	ListItemTagNames(ctx context.Context, arg ListItemTagNamesParams) ([]ListItemTagNamesRow, error)
 	ListItemsByFeedID(ctx context.Context, arg ListItemsByFeedIDParams) ([]Item, error)
 	ListItemsByFeedIDForUser(ctx context.Context, arg ListItemsByFeedIDForUserParams) ([]ListItemsByFeedIDForUserRow, error)
+	ListItemsBySeqRange(ctx context.Context, arg ListItemsBySeqRangeParams) ([]ListItemsBySeqRangeRow, error)
+	ListItemsByTag(ctx context.Context, arg ListItemsByTagParams) ([]ListItemsByTagRow, error)
 	ListItemsInCollection(ctx context.Context, arg ListItemsInCollectionParams) ([]ListItemsInCollectionRow, error)
+	ListItemsMatchingRules(ctx context.Context, arg ListItemsMatchingRulesParams) ([]ListItemsMatchingRulesRow, error)
+	ListLikedItemSeqs(ctx context.Context, userID uuid.UUID) ([]int64, error)
+	ListRecentItemsForFilters(ctx context.Context, arg ListRecentItemsForFiltersParams) ([]ListRecentItemsForFiltersRow, error)
+	ListStreamItemRefs(ctx context.Context, arg ListStreamItemRefsParams) ([]ListStreamItemRefsRow, error)
+	ListStreamItems(ctx context.Context, arg ListStreamItemsParams) ([]ListStreamItemsRow, error)
+	ListStreamItemsBySeqs(ctx context.Context, arg ListStreamItemsBySeqsParams) ([]ListStreamItemsBySeqsRow, error)
+	ListSubscribedFeedRefs(ctx context.Context, userID uuid.UUID) ([]ListSubscribedFeedRefsRow, error)
+	ListTags(ctx context.Context, arg ListTagsParams) ([]ListTagsRow, error)
+	ListTagsByNames(ctx context.Context, arg ListTagsByNamesParams) ([]Tag, error)
+	ListUnreadItemSeqs(ctx context.Context, userID uuid.UUID) ([]int64, error)
 	ListUserFeedSubscriptions(ctx context.Context, arg ListUserFeedSubscriptionsParams) ([]UserFeed, error)
+	ListUserFeedSubscriptionsByFeedIDs(ctx context.Context, arg ListUserFeedSubscriptionsByFeedIDsParams) ([]UserFeed, error)
+	ListUserIdentitiesByUserID(ctx context.Context, userID uuid.UUID) ([]UserIdentity, error)
 	ListUserLikedItems(ctx context.Context, arg ListUserLikedItemsParams) ([]ListUserLikedItemsRow, error)
 	ListUserLikesByItem(ctx context.Context, arg ListUserLikesByItemParams) ([]UserLike, error)
 	ListUserLikesByUser(ctx context.Context, arg ListUserLikesByUserParams) ([]ListUserLikesByUserRow, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
-	RemoveItemFromCollection(ctx context.Context, arg RemoveItemFromCollectionParams) error
+	ListUsersWithStats(ctx context.Context, arg ListUsersWithStatsParams) ([]ListUsersWithStatsRow, error)
+	ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	ListWebhooks(ctx context.Context, userID uuid.UUID) ([]Webhook, error)
+	ListWebhooksForCollectionItem(ctx context.Context, collectionID uuid.UUID) ([]Webhook, error)
+	ListWebhooksForFeedItem(ctx context.Context, arg ListWebhooksForFeedItemParams) ([]Webhook, error)
+	ListWebhooksForRuleMatch(ctx context.Context, arg ListWebhooksForRuleMatchParams) ([]Webhook, error)
+	MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) (int64, error)
+	MarkStreamItemsRead(ctx context.Context, arg MarkStreamItemsReadParams) (int64, error)
+	MoveFeedFilterRules(ctx context.Context, arg MoveFeedFilterRulesParams) error
+	MoveFeedItems(ctx context.Context, arg MoveFeedItemsParams) (int64, error)
+	MoveFeedSubscriptions(ctx context.Context, arg MoveFeedSubscriptionsParams) error
+	MoveFeedTags(ctx context.Context, arg MoveFeedTagsParams) error
+	MoveTagFeeds(ctx context.Context, arg MoveTagFeedsParams) error
+	MoveTagItems(ctx context.Context, arg MoveTagItemsParams) error
+	PurgeItems(ctx context.Context, arg PurgeItemsParams) (int64, error)
+	RecordFeedSyncFailure(ctx context.Context, arg RecordFeedSyncFailureParams) error
+	RecordFeedSyncSuccess(ctx context.Context, feedID uuid.UUID) error
+	RecordFilterRuleHit(ctx context.Context, id uuid.UUID) error
+	RecordWebhookAttempt(ctx context.Context, arg RecordWebhookAttemptParams) error
+	RecordWebhookFailure(ctx context.Context, arg RecordWebhookFailureParams) (RecordWebhookFailureRow, error)
+	RecordWebhookSuccess(ctx context.Context, id uuid.UUID) error
+	RemoveItemFromCollection(ctx context.Context, arg RemoveItemFromCollectionParams) (int64, error)
+	RenameTag(ctx context.Context, arg RenameTagParams) (Tag, error)
+	RevokeOtherSessions(ctx context.Context, arg RevokeOtherSessionsParams) (int64, error)
+	RevokeSession(ctx context.Context, arg RevokeSessionParams) (int64, error)
+	RevokeUserSessions(ctx context.Context, userID uuid.UUID) (int64, error)
+	RotateCollectionSlug(ctx context.Context, arg RotateCollectionSlugParams) (Collection, error)
+	SetCollectionItemPositions(ctx context.Context, arg SetCollectionItemPositionsParams) (int64, error)
+	SetCollectionPositions(ctx context.Context, arg SetCollectionPositionsParams) (int64, error)
+	SetCollectionRules(ctx context.Context, arg SetCollectionRulesParams) (Collection, error)
+	SetUserDisabled(ctx context.Context, arg SetUserDisabledParams) (int64, error)
+	TagFeeds(ctx context.Context, arg TagFeedsParams) (int64, error)
+	TagItems(ctx context.Context, arg TagItemsParams) (int64, error)
+	TouchAccessToken(ctx context.Context, id uuid.UUID) error
+	TouchAppPassword(ctx context.Context, id uuid.UUID) error
+	TouchSession(ctx context.Context, id uuid.UUID) (bool, error)
+	TouchUserIdentity(ctx context.Context, arg TouchUserIdentityParams) error
+	UntagFeedExcept(ctx context.Context, arg UntagFeedExceptParams) (int64, error)
+	UntagFeeds(ctx context.Context, arg UntagFeedsParams) (int64, error)
+	UntagItemExcept(ctx context.Context, arg UntagItemExceptParams) (int64, error)
+	UntagItems(ctx context.Context, arg UntagItemsParams) (int64, error)
 	UpdateCollectionByID(ctx context.Context, arg UpdateCollectionByIDParams) (Collection, error)
 	UpdateCollectionEmbeddingByID(ctx context.Context, arg UpdateCollectionEmbeddingByIDParams) (CollectionEmbedding, error)
+	UpdateCollectionMemberRole(ctx context.Context, arg UpdateCollectionMemberRoleParams) (CollectionMember, error)
 	UpdateFeedByID(ctx context.Context, arg UpdateFeedByIDParams) (Feed, error)
+	UpdateFilterRule(ctx context.Context, arg UpdateFilterRuleParams) (FilterRule, error)
+	UpdateItemAnnotationNote(ctx context.Context, arg UpdateItemAnnotationNoteParams) (ItemAnnotation, error)
 	UpdateItemByID(ctx context.Context, arg UpdateItemByIDParams) (Item, error)
 	UpdateItemEmbeddingByID(ctx context.Context, arg UpdateItemEmbeddingByIDParams) (ItemEmbedding, error)
 	UpdateUserByID(ctx context.Context, arg UpdateUserByIDParams) (User, error)
 	UpdateUserEmbedding(ctx context.Context, arg UpdateUserEmbeddingParams) (UserEmbedding, error)
+	UpdateUserFeedSubscription(ctx context.Context, arg UpdateUserFeedSubscriptionParams) (UserFeed, error)
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) error
+	UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (Webhook, error)
+	UpsertCollectionEmbedding(ctx context.Context, arg UpsertCollectionEmbeddingParams) error
+	UpsertFeverCredential(ctx context.Context, arg UpsertFeverCredentialParams) (FeverCredential, error)
+	UpsertTags(ctx context.Context, arg UpsertTagsParams) ([]Tag, error)
 }
 
 var _ Querier = (*Queries)(nil)
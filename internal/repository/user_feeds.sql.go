@@ -14,7 +14,7 @@ import (
 const createUserFeedSubscription = `-- name: CreateUserFeedSubscription :one
 INSERT INTO user_feeds (user_id, feed_id)
 VALUES ($1, $2)
-RETURNING user_id, feed_id, subscribed_at
+RETURNING user_id, feed_id, subscribed_at, folder, title, icon, notify, sort_order, muted
 `
 
 type CreateUserFeedSubscriptionParams struct {
@@ -25,7 +25,17 @@ type CreateUserFeedSubscriptionParams struct {
 func (q *Queries) CreateUserFeedSubscription(ctx context.Context, arg CreateUserFeedSubscriptionParams) (UserFeed, error) {
 	row := q.db.QueryRow(ctx, createUserFeedSubscription, arg.UserID, arg.FeedID)
 	var i UserFeed
-	err := row.Scan(&i.UserID, &i.FeedID, &i.SubscribedAt)
+	err := row.Scan(
+		&i.UserID,
+		&i.FeedID,
+		&i.SubscribedAt,
+		&i.Folder,
+		&i.Title,
+		&i.Icon,
+		&i.Notify,
+		&i.SortOrder,
+		&i.Muted,
+	)
 	return i, err
 }
 
@@ -46,7 +56,7 @@ func (q *Queries) DeleteUserFeedSubscription(ctx context.Context, arg DeleteUser
 }
 
 const getUserFeedSubscription = `-- name: GetUserFeedSubscription :one
-SELECT user_id, feed_id, subscribed_at
+SELECT user_id, feed_id, subscribed_at, folder, title, icon, notify, sort_order, muted
 FROM user_feeds
 WHERE user_id = $1
   AND feed_id = $2
@@ -60,12 +70,22 @@ type GetUserFeedSubscriptionParams struct {
 func (q *Queries) GetUserFeedSubscription(ctx context.Context, arg GetUserFeedSubscriptionParams) (UserFeed, error) {
 	row := q.db.QueryRow(ctx, getUserFeedSubscription, arg.UserID, arg.FeedID)
 	var i UserFeed
-	err := row.Scan(&i.UserID, &i.FeedID, &i.SubscribedAt)
+	err := row.Scan(
+		&i.UserID,
+		&i.FeedID,
+		&i.SubscribedAt,
+		&i.Folder,
+		&i.Title,
+		&i.Icon,
+		&i.Notify,
+		&i.SortOrder,
+		&i.Muted,
+	)
 	return i, err
 }
 
 const listUserFeedSubscriptions = `-- name: ListUserFeedSubscriptions :many
-SELECT user_id, feed_id, subscribed_at
+SELECT user_id, feed_id, subscribed_at, folder, title, icon, notify, sort_order, muted
 FROM user_feeds
 WHERE user_id = $1
 ORDER BY subscribed_at DESC
@@ -88,7 +108,17 @@ func (q *Queries) ListUserFeedSubscriptions(ctx context.Context, arg ListUserFee
 	var items []UserFeed
 	for rows.Next() {
 		var i UserFeed
-		if err := rows.Scan(&i.UserID, &i.FeedID, &i.SubscribedAt); err != nil {
+		if err := rows.Scan(
+			&i.UserID,
+			&i.FeedID,
+			&i.SubscribedAt,
+			&i.Folder,
+			&i.Title,
+			&i.Icon,
+			&i.Notify,
+			&i.SortOrder,
+			&i.Muted,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -98,3 +128,99 @@ func (q *Queries) ListUserFeedSubscriptions(ctx context.Context, arg ListUserFee
 	}
 	return items, nil
 }
+
+const listUserFeedSubscriptionsByFeedIDs = `-- name: ListUserFeedSubscriptionsByFeedIDs :many
+SELECT user_id, feed_id, subscribed_at, folder, title, icon, notify, sort_order, muted
+FROM user_feeds
+WHERE user_id = $1
+  AND feed_id = ANY($2::uuid[])
+`
+
+type ListUserFeedSubscriptionsByFeedIDsParams struct {
+	UserID  uuid.UUID   `json:"userId"`
+	FeedIds []uuid.UUID `json:"feedIds"`
+}
+
+func (q *Queries) ListUserFeedSubscriptionsByFeedIDs(ctx context.Context, arg ListUserFeedSubscriptionsByFeedIDsParams) ([]UserFeed, error) {
+	rows, err := q.db.Query(ctx, listUserFeedSubscriptionsByFeedIDs, arg.UserID, arg.FeedIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserFeed
+	for rows.Next() {
+		var i UserFeed
+		if err := rows.Scan(
+			&i.UserID,
+			&i.FeedID,
+			&i.SubscribedAt,
+			&i.Folder,
+			&i.Title,
+			&i.Icon,
+			&i.Notify,
+			&i.SortOrder,
+			&i.Muted,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUserFeedSubscription = `-- name: UpdateUserFeedSubscription :one
+UPDATE user_feeds
+SET folder       = CASE WHEN $1::text IS NULL THEN folder
+                        ELSE NULLIF($1::text, '') END,
+    title        = CASE WHEN $2::text IS NULL THEN title
+                        ELSE NULLIF($2::text, '') END,
+    icon         = CASE WHEN $3::text IS NULL THEN icon
+                        ELSE NULLIF($3::text, '') END,
+    notify       = COALESCE($4, notify),
+    sort_order   = COALESCE($5, sort_order),
+    muted        = COALESCE($6, muted)
+WHERE user_id = $7
+  AND feed_id = $8
+RETURNING user_id, feed_id, subscribed_at, folder, title, icon, notify, sort_order, muted
+`
+
+type UpdateUserFeedSubscriptionParams struct {
+	Folder    *string   `json:"folder"`
+	Title     *string   `json:"title"`
+	Icon      *string   `json:"icon"`
+	Notify    *string   `json:"notify"`
+	SortOrder *string   `json:"sortOrder"`
+	Muted     *bool     `json:"muted"`
+	UserID    uuid.UUID `json:"userId"`
+	FeedID    uuid.UUID `json:"feedId"`
+}
+
+// NULL leaves a field unchanged, an empty folder, title or icon clears it.
+func (q *Queries) UpdateUserFeedSubscription(ctx context.Context, arg UpdateUserFeedSubscriptionParams) (UserFeed, error) {
+	row := q.db.QueryRow(ctx, updateUserFeedSubscription,
+		arg.Folder,
+		arg.Title,
+		arg.Icon,
+		arg.Notify,
+		arg.SortOrder,
+		arg.Muted,
+		arg.UserID,
+		arg.FeedID,
+	)
+	var i UserFeed
+	err := row.Scan(
+		&i.UserID,
+		&i.FeedID,
+		&i.SubscribedAt,
+		&i.Folder,
+		&i.Title,
+		&i.Icon,
+		&i.Notify,
+		&i.SortOrder,
+		&i.Muted,
+	)
+	return i, err
+}
@@ -138,6 +138,7 @@ type ListUserLikesByUserRow struct {
 	Enclosures      typeext.Enclosures `json:"enclosures"`
 	CreatedAt       time.Time          `json:"createdAt"`
 	UpdatedAt       time.Time          `json:"updatedAt"`
+	Seq             int64              `json:"seq"`
 }
 
 func (q *Queries) ListUserLikesByUser(ctx context.Context, arg ListUserLikesByUserParams) ([]ListUserLikesByUserRow, error) {
@@ -169,6 +170,7 @@ func (q *Queries) ListUserLikesByUser(ctx context.Context, arg ListUserLikesByUs
 			&i.Enclosures,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Seq,
 		); err != nil {
 			return nil, err
 		}
@@ -24,20 +24,26 @@ func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
 }
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (sub, name, email)
-VALUES ($1, $2, $3)
+INSERT INTO users (sub, name, email, role)
+VALUES ($1, $2, $3, $4)
 RETURNING
-  id, sub, name, email, created_at, last_updated_at
+  id, sub, name, email, created_at, last_updated_at, role, disabled_at
 `
 
 type CreateUserParams struct {
 	Sub   string `json:"sub"`
 	Name  string `json:"name"`
 	Email string `json:"email"`
+	Role  string `json:"role"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, createUser, arg.Sub, arg.Name, arg.Email)
+	row := q.db.QueryRow(ctx, createUser,
+		arg.Sub,
+		arg.Name,
+		arg.Email,
+		arg.Role,
+	)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -46,6 +52,8 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.CreatedAt,
 		&i.LastUpdatedAt,
+		&i.Role,
+		&i.DisabledAt,
 	)
 	return i, err
 }
@@ -60,9 +68,38 @@ func (q *Queries) DeleteUserByID(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const getUnlinkedUserBySub = `-- name: GetUnlinkedUserBySub :one
+SELECT
+  u.id, u.sub, u.name, u.email, u.created_at, u.last_updated_at, u.role, u.disabled_at
+FROM users u
+WHERE u.sub = $1
+  AND NOT EXISTS (
+    SELECT 1
+    FROM user_identities ui
+    WHERE ui.user_id = u.id
+  )
+LIMIT 1
+`
+
+func (q *Queries) GetUnlinkedUserBySub(ctx context.Context, sub string) (User, error) {
+	row := q.db.QueryRow(ctx, getUnlinkedUserBySub, sub)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Sub,
+		&i.Name,
+		&i.Email,
+		&i.CreatedAt,
+		&i.LastUpdatedAt,
+		&i.Role,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
 const getUserByEmail = `-- name: GetUserByEmail :one
 SELECT
-  id, sub, name, email, created_at, last_updated_at
+  id, sub, name, email, created_at, last_updated_at, role, disabled_at
 FROM users
 WHERE email = $1
 `
@@ -77,13 +114,15 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Email,
 		&i.CreatedAt,
 		&i.LastUpdatedAt,
+		&i.Role,
+		&i.DisabledAt,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
 SELECT
-  id, sub, name, email, created_at, last_updated_at
+  id, sub, name, email, created_at, last_updated_at, role, disabled_at
 FROM users
 WHERE id = $1
 `
@@ -98,13 +137,15 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.Email,
 		&i.CreatedAt,
 		&i.LastUpdatedAt,
+		&i.Role,
+		&i.DisabledAt,
 	)
 	return i, err
 }
 
 const getUserBySub = `-- name: GetUserBySub :one
 SELECT
-  id, sub, name, email, created_at, last_updated_at
+  id, sub, name, email, created_at, last_updated_at, role, disabled_at
 FROM users
 WHERE sub = $1
 `
@@ -119,13 +160,15 @@ func (q *Queries) GetUserBySub(ctx context.Context, sub string) (User, error) {
 		&i.Email,
 		&i.CreatedAt,
 		&i.LastUpdatedAt,
+		&i.Role,
+		&i.DisabledAt,
 	)
 	return i, err
 }
 
 const listUsers = `-- name: ListUsers :many
 SELECT
-  id, sub, name, email, created_at, last_updated_at
+  id, sub, name, email, created_at, last_updated_at, role, disabled_at
 FROM users
 ORDER BY created_at DESC
 LIMIT  $1
@@ -153,6 +196,8 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.Email,
 			&i.CreatedAt,
 			&i.LastUpdatedAt,
+			&i.Role,
+			&i.DisabledAt,
 		); err != nil {
 			return nil, err
 		}
@@ -164,6 +209,27 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	return items, nil
 }
 
+const setUserDisabled = `-- name: SetUserDisabled :execrows
+UPDATE users
+SET
+  disabled_at     = CASE WHEN $1::boolean THEN COALESCE(disabled_at, now()) END,
+  last_updated_at = now()
+WHERE id = $2
+`
+
+type SetUserDisabledParams struct {
+	Disabled bool      `json:"disabled"`
+	ID       uuid.UUID `json:"id"`
+}
+
+func (q *Queries) SetUserDisabled(ctx context.Context, arg SetUserDisabledParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setUserDisabled, arg.Disabled, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateUserByID = `-- name: UpdateUserByID :one
 UPDATE users
 SET
@@ -173,7 +239,7 @@ SET
   last_updated_at = now()
 WHERE id = $1
 RETURNING
-  id, sub, name, email, created_at, last_updated_at
+  id, sub, name, email, created_at, last_updated_at, role, disabled_at
 `
 
 type UpdateUserByIDParams struct {
@@ -198,6 +264,26 @@ func (q *Queries) UpdateUserByID(ctx context.Context, arg UpdateUserByIDParams)
 		&i.Email,
 		&i.CreatedAt,
 		&i.LastUpdatedAt,
+		&i.Role,
+		&i.DisabledAt,
 	)
 	return i, err
 }
+
+const updateUserRole = `-- name: UpdateUserRole :exec
+UPDATE users
+SET
+  role            = $2,
+  last_updated_at = now()
+WHERE id = $1
+`
+
+type UpdateUserRoleParams struct {
+	ID   uuid.UUID `json:"id"`
+	Role string    `json:"role"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) error {
+	_, err := q.db.Exec(ctx, updateUserRole, arg.ID, arg.Role)
+	return err
+}
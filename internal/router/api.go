@@ -5,30 +5,105 @@ import (
 
 	_ "github.com/rhajizada/gazette/docs"
 	"github.com/rhajizada/gazette/internal/handler"
+	"github.com/rhajizada/gazette/internal/middleware"
 )
 
 func RegisterAPI(h *handler.Handler) *http.ServeMux {
 	router := http.NewServeMux()
+	adminOnly := middleware.RequireAdmin()
 	router.HandleFunc("GET /feeds", h.ListFeeds)
 	router.HandleFunc("POST /feeds", h.CreateFeed)
 	router.HandleFunc("GET /feeds/export", h.ExportFeeds)
+	router.HandleFunc("GET /feeds/folders", h.ListFeedFolders)
 	router.HandleFunc("GET /feeds/{feedID}", h.GetFeedByID)
-	router.HandleFunc("DELETE /feeds/{feedID}", h.DeleteFeedByID)
+	router.Handle("DELETE /feeds/{feedID}", adminOnly(http.HandlerFunc(h.DeleteFeedByID)))
 	router.HandleFunc("PUT /feeds/{feedID}/subscribe", h.SubscribeToFeed)
 	router.HandleFunc("DELETE /feeds/{feedID}/subscribe", h.UnsubscribeFromFeed)
+	router.HandleFunc("PATCH /feeds/{feedID}/subscription", h.UpdateSubscription)
 	router.HandleFunc("GET /feeds/{feedID}/items", h.ListItemsByFeedID)
+	router.HandleFunc("POST /feeds/{feedID}/refresh", h.RefreshFeed)
+	router.HandleFunc("GET /feeds/{feedID}/sync-status", h.GetFeedSyncStatus)
+	router.HandleFunc("GET /feeds/{feedID}/tags", h.ListFeedTags)
+	router.HandleFunc("PUT /feeds/{feedID}/tags", h.SetFeedTags)
 	router.HandleFunc("GET /items/", h.ListUserLikedItems)
 	router.HandleFunc("GET /items/{itemID}", h.GetItemByID)
 	router.HandleFunc("POST /items/{itemID}/like", h.LikeItem)
 	router.HandleFunc("DELETE /items/{itemID}/like", h.UnlikeItem)
 	router.HandleFunc("GET /items/{itemID}/collections", h.ListItemCollections)
+	router.HandleFunc("GET /items/{itemID}/annotations", h.ListItemAnnotations)
+	router.HandleFunc("POST /items/{itemID}/annotations", h.CreateItemAnnotation)
+	router.HandleFunc("GET /items/{itemID}/annotations/export", h.ExportItemAnnotations)
+	router.HandleFunc("GET /items/{itemID}/annotations/{annotationID}", h.GetItemAnnotation)
+	router.HandleFunc("PATCH /items/{itemID}/annotations/{annotationID}", h.UpdateItemAnnotation)
+	router.HandleFunc("DELETE /items/{itemID}/annotations/{annotationID}", h.DeleteItemAnnotation)
+	router.HandleFunc("GET /annotations", h.ListAnnotations)
+	router.HandleFunc("GET /annotations/export", h.ExportAnnotations)
+	router.HandleFunc("GET /items/{itemID}/tags", h.ListItemTags)
+	router.HandleFunc("PUT /items/{itemID}/tags", h.SetItemTags)
+	router.HandleFunc("GET /tags", h.ListTags)
+	router.HandleFunc("POST /tags/bulk", h.BulkTag)
+	router.HandleFunc("PATCH /tags/{tagID}", h.RenameTag)
+	router.HandleFunc("DELETE /tags/{tagID}", h.DeleteTag)
+	router.HandleFunc("GET /tags/{tagID}/items", h.ListItemsByTag)
+	router.HandleFunc("GET /filters", h.ListFilterRules)
+	router.HandleFunc("POST /filters", h.CreateFilterRule)
+	router.HandleFunc("POST /filters/preview", h.PreviewFilterRule)
+	router.HandleFunc("GET /filters/{filterID}", h.GetFilterRule)
+	router.HandleFunc("PUT /filters/{filterID}", h.UpdateFilterRule)
+	router.HandleFunc("DELETE /filters/{filterID}", h.DeleteFilterRule)
+	router.HandleFunc("GET /webhooks", h.ListWebhooks)
+	router.HandleFunc("POST /webhooks", h.CreateWebhook)
+	router.HandleFunc("GET /webhooks/{webhookID}", h.GetWebhook)
+	router.HandleFunc("PUT /webhooks/{webhookID}", h.UpdateWebhook)
+	router.HandleFunc("DELETE /webhooks/{webhookID}", h.DeleteWebhook)
+	router.HandleFunc("POST /webhooks/{webhookID}/test", h.TestWebhook)
+	router.HandleFunc("GET /webhooks/{webhookID}/deliveries", h.ListWebhookDeliveries)
 	router.HandleFunc("GET /collections", h.ListCollections)
 	router.HandleFunc("POST /collections", h.CreateCollection)
+	router.HandleFunc("PUT /collections/order", h.ReorderCollections)
+	router.HandleFunc("GET /collections/invitations", h.ListCollectionInvitations)
 	router.HandleFunc("GET /collections/{collectionID}", h.GetCollectionByID)
+	router.HandleFunc("PATCH /collections/{collectionID}", h.UpdateCollection)
 	router.HandleFunc("DELETE /collections/{collectionID}", h.DeleteCollectionByID)
+	router.HandleFunc("POST /collections/{collectionID}/slug", h.RotateCollectionSlug)
+	router.HandleFunc("PUT /collections/{collectionID}/rules", h.SetCollectionRules)
+	router.HandleFunc("DELETE /collections/{collectionID}/rules", h.DeleteCollectionRules)
+	router.HandleFunc("GET /collections/{collectionID}/members", h.ListCollectionMembers)
+	router.HandleFunc("POST /collections/{collectionID}/members", h.InviteCollectionMember)
+	router.HandleFunc("PATCH /collections/{collectionID}/members/{userID}", h.UpdateCollectionMember)
+	router.HandleFunc("DELETE /collections/{collectionID}/members/{userID}", h.RemoveCollectionMember)
+	router.HandleFunc("DELETE /collections/{collectionID}/invitations/{email}", h.WithdrawCollectionInvitation)
+	router.HandleFunc("PUT /collections/{collectionID}/membership", h.AcceptCollectionInvitation)
+	router.HandleFunc("DELETE /collections/{collectionID}/membership", h.LeaveCollection)
 	router.HandleFunc("GET /collections/{collectionID}/items", h.ListItemsByCollectionID)
+	router.HandleFunc("GET /collections/{collectionID}/export", h.ExportCollection)
+	router.HandleFunc("PUT /collections/{collectionID}/items/order", h.ReorderCollectionItems)
 	router.HandleFunc("POST /collections/{collectionID}/item/{itemID}", h.AddItemToCollection)
 	router.HandleFunc("DELETE /collections/{collectionID}/item/{itemID}", h.RemoveItemFromCollection)
 	router.HandleFunc("GET /user", h.GetUser)
+	router.HandleFunc("GET /user/app-passwords", h.ListAppPasswords)
+	router.HandleFunc("POST /user/app-passwords", h.CreateAppPassword)
+	router.HandleFunc("DELETE /user/app-passwords/{appPasswordID}", h.DeleteAppPassword)
+	router.HandleFunc("GET /user/identities", h.ListIdentities)
+	router.HandleFunc("POST /user/identities", h.LinkIdentity)
+	router.HandleFunc("DELETE /user/identities/{identityID}", h.UnlinkIdentity)
+	router.HandleFunc("GET /user/sessions", h.ListSessions)
+	router.HandleFunc("DELETE /user/sessions", h.DeleteOtherSessions)
+	router.HandleFunc("DELETE /user/sessions/{sessionID}", h.DeleteSession)
+	router.HandleFunc("GET /user/tokens", h.ListAccessTokens)
+	router.HandleFunc("POST /user/tokens", h.CreateAccessToken)
+	router.HandleFunc("DELETE /user/tokens/{tokenID}", h.DeleteAccessToken)
+	router.HandleFunc("GET /user/fever", h.GetFeverCredential)
+	router.HandleFunc("PUT /user/fever", h.SetFeverPassword)
+	router.HandleFunc("DELETE /user/fever", h.DeleteFeverPassword)
+	router.Handle("GET /admin/users", adminOnly(http.HandlerFunc(h.AdminListUsers)))
+	router.Handle("GET /admin/users/{userID}", adminOnly(http.HandlerFunc(h.AdminGetUser)))
+	router.Handle("DELETE /admin/users/{userID}", adminOnly(http.HandlerFunc(h.AdminDeleteUser)))
+	router.Handle("PUT /admin/users/{userID}/disable", adminOnly(http.HandlerFunc(h.AdminDisableUser)))
+	router.Handle("DELETE /admin/users/{userID}/disable", adminOnly(http.HandlerFunc(h.AdminEnableUser)))
+	router.Handle("GET /admin/feeds", adminOnly(http.HandlerFunc(h.AdminListFeeds)))
+	router.Handle("POST /admin/feeds/{feedID}/sync", adminOnly(http.HandlerFunc(h.AdminSyncFeed)))
+	router.Handle("POST /admin/feeds/{feedID}/merge", adminOnly(http.HandlerFunc(h.AdminMergeFeeds)))
+	router.Handle("DELETE /admin/items", adminOnly(http.HandlerFunc(h.AdminPurgeItems)))
 	return router
 }
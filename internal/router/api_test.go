@@ -0,0 +1,364 @@
+package router_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/rhajizada/gazette/internal/config"
+	"github.com/rhajizada/gazette/internal/handler"
+	"github.com/rhajizada/gazette/internal/middleware"
+	"github.com/rhajizada/gazette/internal/oauth"
+	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/router"
+	"github.com/rhajizada/gazette/internal/service"
+)
+
+// testDatabaseEnv names the Postgres database, with the pgvector extension
+// available, the API tests run against. They are skipped when it is unset.
+const testDatabaseEnv = "GAZETTE_TEST_POSTGRES_URL"
+
+type apiTest struct {
+	t      *testing.T
+	repo   *repository.Queries
+	routes http.Handler
+}
+
+func newAPITest(t *testing.T) *apiTest {
+	t.Helper()
+	dsn := os.Getenv(testDatabaseEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseEnv)
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	if err := goose.SetDialect("postgres"); err != nil {
+		t.Fatal(err)
+	}
+	db := stdlib.OpenDBFromPool(pool)
+	t.Cleanup(func() { db.Close() })
+	if err := goose.Up(db, "../../data/sql/migrations"); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	// none of the requests made queue tasks, so Redis is never reached
+	client := asynq.NewClient(asynq.RedisClientOpt{Addr: "127.0.0.1:6379"})
+	t.Cleanup(func() { client.Close() })
+	svc := service.New(pool, client, nil)
+	h := handler.New(svc, []byte("secret"), nil, &config.SessionConfig{}, &config.RolesConfig{}, "")
+
+	return &apiTest{
+		t:      t,
+		repo:   repository.New(pool),
+		routes: router.RegisterAPI(h),
+	}
+}
+
+// createUser creates a member with two identities, so either can be
+// unlinked.
+func (a *apiTest) createUser() repository.User {
+	a.t.Helper()
+	ctx := context.Background()
+	id := uuid.NewString()
+	user, err := a.repo.CreateUser(ctx, repository.CreateUserParams{
+		Sub:   id,
+		Name:  "user-" + id,
+		Email: id + "@example.com",
+		Role:  oauth.RoleMember,
+	})
+	if err != nil {
+		a.t.Fatalf("failed to create user: %v", err)
+	}
+	for _, issuer := range []string{"https://a.example.com", "https://b.example.com"} {
+		if _, err := a.repo.CreateUserIdentity(ctx, repository.CreateUserIdentityParams{
+			UserID:   user.ID,
+			Provider: "test",
+			Issuer:   issuer,
+			Sub:      id,
+			Email:    &user.Email,
+			// invitations are only matched to verified emails
+			EmailVerified: true,
+		}); err != nil {
+			a.t.Fatalf("failed to create identity: %v", err)
+		}
+	}
+	return user
+}
+
+// do sends a request to the API as user and returns the response.
+func (a *apiTest) do(user repository.User, method, path string, body any) *httptest.ResponseRecorder {
+	a.t.Helper()
+	return a.doWithClaims(&oauth.ApplicationClaims{UserID: user.ID, Role: user.Role}, method, path, body)
+}
+
+// doWithClaims sends a request to the API with claims and returns the
+// response.
+func (a *apiTest) doWithClaims(claims *oauth.ApplicationClaims, method, path string, body any) *httptest.ResponseRecorder {
+	a.t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			a.t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserContextKey, claims))
+
+	rec := httptest.NewRecorder()
+	a.routes.ServeHTTP(rec, req)
+	return rec
+}
+
+// create sends a request to the API as user, expects it to succeed and
+// returns the ID of the resource in the response.
+func (a *apiTest) create(user repository.User, method, path string, body any) uuid.UUID {
+	a.t.Helper()
+	rec := a.do(user, method, path, body)
+	if rec.Code >= 300 {
+		a.t.Fatalf("%s %s: got %d: %s", method, path, rec.Code, rec.Body)
+	}
+	var resp struct {
+		ID uuid.UUID `json:"id"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		a.t.Fatalf("%s %s: failed to decode response: %v", method, path, err)
+	}
+	return resp.ID
+}
+
+// TestAPIHidesResourcesOfOtherUsers checks that the resources of a user are
+// reported as not found to another user, whether read or changed, and are
+// left intact.
+func TestAPIHidesResourcesOfOtherUsers(t *testing.T) {
+	a := newAPITest(t)
+	ctx := context.Background()
+	owner, other, member := a.createUser(), a.createUser(), a.createUser()
+
+	feed, err := a.repo.CreateFeed(ctx, repository.CreateFeedParams{
+		FeedLink: fmt.Sprintf("https://example.com/%s/feed.xml", uuid.NewString()),
+	})
+	if err != nil {
+		t.Fatalf("failed to create feed: %v", err)
+	}
+	item, err := a.repo.CreateItem(ctx, repository.CreateItemParams{
+		FeedID: feed.ID,
+		Link:   fmt.Sprintf("https://example.com/%s", uuid.NewString()),
+	})
+	if err != nil {
+		t.Fatalf("failed to create item: %v", err)
+	}
+	if _, err := a.repo.CreateUserFeedSubscription(ctx, repository.CreateUserFeedSubscriptionParams{
+		UserID: owner.ID,
+		FeedID: feed.ID,
+	}); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	collection := a.create(owner, http.MethodPost, "/collections", map[string]any{"name": "reading"})
+	if rec := a.do(owner, http.MethodPost, fmt.Sprintf("/collections/%s/item/%s", collection, item.ID), nil); rec.Code != http.StatusOK {
+		t.Fatalf("failed to add item to collection: got %d: %s", rec.Code, rec.Body)
+	}
+	invitee := uuid.NewString() + "@example.com"
+	if rec := a.do(owner, http.MethodPost, fmt.Sprintf("/collections/%s/members", collection), map[string]any{"email": invitee, "role": "viewer"}); rec.Code != http.StatusAccepted {
+		t.Fatalf("failed to invite to collection: got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := a.do(owner, http.MethodPost, fmt.Sprintf("/collections/%s/members", collection), map[string]any{"email": member.Email, "role": "viewer"}); rec.Code != http.StatusAccepted {
+		t.Fatalf("failed to invite to collection: got %d: %s", rec.Code, rec.Body)
+	}
+	if rec := a.do(member, http.MethodPut, fmt.Sprintf("/collections/%s/membership", collection), nil); rec.Code != http.StatusOK {
+		t.Fatalf("failed to accept invitation: got %d: %s", rec.Code, rec.Body)
+	}
+	annotation := a.create(owner, http.MethodPost, fmt.Sprintf("/items/%s/annotations", item.ID), map[string]any{"note": "note"})
+	if rec := a.do(owner, http.MethodPut, fmt.Sprintf("/items/%s/tags", item.ID), map[string]any{"tags": []string{"go"}}); rec.Code != http.StatusOK {
+		t.Fatalf("failed to tag item: got %d: %s", rec.Code, rec.Body)
+	}
+	var tags service.ListTagsResponse
+	if err := json.NewDecoder(a.do(owner, http.MethodGet, "/tags", nil).Body).Decode(&tags); err != nil || len(tags.Tags) != 1 {
+		t.Fatalf("failed to list tags: %v", err)
+	}
+	tag := tags.Tags[0].ID
+	rule := map[string]any{"name": "go", "match_type": "keyword", "pattern": "go", "action": "mark_read"}
+	filter := a.create(owner, http.MethodPost, "/filters", rule)
+	hook := map[string]any{"url": "https://hooks.example.com/gazette", "events": []string{"feed.item"}}
+	webhook := a.create(owner, http.MethodPost, "/webhooks", hook)
+	appPassword := a.create(owner, http.MethodPost, "/user/app-passwords", map[string]any{"name": "reader"})
+	token := a.create(owner, http.MethodPost, "/user/tokens", map[string]any{"name": "cli", "scopes": []string{oauth.ScopeRead}})
+	session, err := a.repo.CreateSession(ctx, repository.CreateSessionParams{
+		UserID:    owner.ID,
+		Device:    "test",
+		Ip:        "127.0.0.1",
+		UserAgent: "test",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("failed to create session: %v", err)
+	}
+	identities, err := a.repo.ListUserIdentitiesByUserID(ctx, owner.ID)
+	if err != nil || len(identities) == 0 {
+		t.Fatalf("failed to list identities: %v", err)
+	}
+	identity := identities[0].ID
+
+	page := "?limit=10&offset=0"
+	tests := []struct {
+		method string
+		path   string
+		body   any
+	}{
+		{http.MethodGet, fmt.Sprintf("/collections/%s", collection), nil},
+		{http.MethodPatch, fmt.Sprintf("/collections/%s", collection), map[string]any{"name": "mine"}},
+		{http.MethodDelete, fmt.Sprintf("/collections/%s", collection), nil},
+		{http.MethodPost, fmt.Sprintf("/collections/%s/slug", collection), nil},
+		{http.MethodPut, fmt.Sprintf("/collections/%s/rules", collection), map[string]any{"keywords": []string{"go"}}},
+		{http.MethodDelete, fmt.Sprintf("/collections/%s/rules", collection), nil},
+		{http.MethodGet, fmt.Sprintf("/collections/%s/items%s", collection, page), nil},
+		{http.MethodGet, fmt.Sprintf("/collections/%s/export", collection), nil},
+		{http.MethodPut, fmt.Sprintf("/collections/%s/items/order", collection), map[string]any{"item_ids": []uuid.UUID{item.ID}}},
+		{http.MethodPost, fmt.Sprintf("/collections/%s/item/%s", collection, item.ID), nil},
+		{http.MethodDelete, fmt.Sprintf("/collections/%s/item/%s", collection, item.ID), nil},
+		{http.MethodGet, fmt.Sprintf("/collections/%s/members", collection), nil},
+		{http.MethodPost, fmt.Sprintf("/collections/%s/members", collection), map[string]any{"email": other.Email, "role": "editor"}},
+		{http.MethodPatch, fmt.Sprintf("/collections/%s/members/%s", collection, member.ID), map[string]any{"role": "editor"}},
+		{http.MethodDelete, fmt.Sprintf("/collections/%s/members/%s", collection, member.ID), nil},
+		{http.MethodDelete, fmt.Sprintf("/collections/%s/invitations/%s", collection, invitee), nil},
+		{http.MethodPut, fmt.Sprintf("/collections/%s/membership", collection), nil},
+		{http.MethodDelete, fmt.Sprintf("/collections/%s/membership", collection), nil},
+		{http.MethodPut, "/collections/order", map[string]any{"collection_ids": []uuid.UUID{collection}}},
+		{http.MethodGet, fmt.Sprintf("/items/%s/annotations/%s", item.ID, annotation), nil},
+		{http.MethodPatch, fmt.Sprintf("/items/%s/annotations/%s", item.ID, annotation), map[string]any{"note": "mine"}},
+		{http.MethodDelete, fmt.Sprintf("/items/%s/annotations/%s", item.ID, annotation), nil},
+		{http.MethodPatch, fmt.Sprintf("/tags/%s", tag), map[string]any{"name": "mine"}},
+		{http.MethodDelete, fmt.Sprintf("/tags/%s", tag), nil},
+		{http.MethodGet, fmt.Sprintf("/tags/%s/items%s", tag, page), nil},
+		{http.MethodGet, fmt.Sprintf("/filters/%s", filter), nil},
+		{http.MethodPut, fmt.Sprintf("/filters/%s", filter), rule},
+		{http.MethodDelete, fmt.Sprintf("/filters/%s", filter), nil},
+		{http.MethodPatch, fmt.Sprintf("/feeds/%s/subscription", feed.ID), map[string]any{"muted": true}},
+		{http.MethodPut, fmt.Sprintf("/feeds/%s/tags", feed.ID), map[string]any{"tags": []string{"go"}}},
+		{http.MethodGet, fmt.Sprintf("/webhooks/%s", webhook), nil},
+		{http.MethodPut, fmt.Sprintf("/webhooks/%s", webhook), hook},
+		{http.MethodDelete, fmt.Sprintf("/webhooks/%s", webhook), nil},
+		{http.MethodPost, fmt.Sprintf("/webhooks/%s/test", webhook), nil},
+		{http.MethodGet, fmt.Sprintf("/webhooks/%s/deliveries%s", webhook, page), nil},
+		{http.MethodDelete, fmt.Sprintf("/user/app-passwords/%s", appPassword), nil},
+		{http.MethodDelete, fmt.Sprintf("/user/tokens/%s", token), nil},
+		{http.MethodDelete, fmt.Sprintf("/user/sessions/%s", session.ID), nil},
+		{http.MethodDelete, fmt.Sprintf("/user/identities/%s", identity), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := a.do(other, tt.method, tt.path, tt.body)
+			if rec.Code != http.StatusNotFound {
+				t.Errorf("got %d, want %d: %s", rec.Code, http.StatusNotFound, rec.Body)
+			}
+		})
+	}
+
+	// nothing the other user sent changed the resources of the owner
+	for _, path := range []string{
+		fmt.Sprintf("/collections/%s", collection),
+		fmt.Sprintf("/collections/%s/items%s", collection, page),
+		fmt.Sprintf("/items/%s/annotations/%s", item.ID, annotation),
+		fmt.Sprintf("/tags/%s/items%s", tag, page),
+		fmt.Sprintf("/filters/%s", filter),
+		fmt.Sprintf("/webhooks/%s", webhook),
+	} {
+		if rec := a.do(owner, http.MethodGet, path, nil); rec.Code != http.StatusOK {
+			t.Errorf("GET %s as owner: got %d: %s", path, rec.Code, rec.Body)
+		}
+	}
+	var col service.Collection
+	if err := json.NewDecoder(a.do(owner, http.MethodGet, fmt.Sprintf("/collections/%s", collection), nil).Body).Decode(&col); err != nil {
+		t.Fatal(err)
+	}
+	if col.Name != "reading" {
+		t.Errorf("collection was renamed to %s", col.Name)
+	}
+	var members service.ListCollectionMembersResponse
+	if err := json.NewDecoder(a.do(owner, http.MethodGet, fmt.Sprintf("/collections/%s/members", collection), nil).Body).Decode(&members); err != nil {
+		t.Fatal(err)
+	}
+	if len(members.Members) != 1 || members.Members[0].UserID != member.ID || members.Members[0].Role != "viewer" {
+		t.Errorf("got members %+v, want %s as viewer", members.Members, member.ID)
+	}
+	if len(members.Invitations) != 1 {
+		t.Errorf("got %d invitations, want 1", len(members.Invitations))
+	}
+	var passwords service.ListAppPasswordsResponse
+	var tokens service.ListAccessTokensResponse
+	var linked service.ListUserIdentitiesResponse
+	for _, list := range []struct {
+		path string
+		resp any
+	}{
+		{"/user/app-passwords", &passwords},
+		{"/user/tokens", &tokens},
+		{"/user/identities", &linked},
+	} {
+		if err := json.NewDecoder(a.do(owner, http.MethodGet, list.path, nil).Body).Decode(list.resp); err != nil {
+			t.Fatalf("GET %s as owner: %v", list.path, err)
+		}
+	}
+	if len(passwords.AppPasswords) != 1 || len(tokens.AccessTokens) != 1 || len(linked.Identities) != 2 {
+		t.Errorf("got %d app passwords, %d tokens and %d identities, want 1, 1 and 2",
+			len(passwords.AppPasswords), len(tokens.AccessTokens), len(linked.Identities))
+	}
+	if active, err := a.repo.TouchSession(ctx, session.ID); err != nil || !active {
+		t.Errorf("session of owner was revoked: %v", err)
+	}
+}
+
+// TestAPIAdminRoutesRequireAdmin checks that admin routes are forbidden to
+// members, and to admins using a token without the admin scope.
+func TestAPIAdminRoutesRequireAdmin(t *testing.T) {
+	a := newAPITest(t)
+	user := a.createUser()
+	id := uuid.New()
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodDelete, fmt.Sprintf("/feeds/%s", id)},
+		{http.MethodGet, "/admin/users"},
+		{http.MethodGet, fmt.Sprintf("/admin/users/%s", id)},
+		{http.MethodDelete, fmt.Sprintf("/admin/users/%s", id)},
+		{http.MethodPut, fmt.Sprintf("/admin/users/%s/disable", id)},
+		{http.MethodDelete, fmt.Sprintf("/admin/users/%s/disable", id)},
+		{http.MethodGet, "/admin/feeds"},
+		{http.MethodPost, fmt.Sprintf("/admin/feeds/%s/sync", id)},
+		{http.MethodPost, fmt.Sprintf("/admin/feeds/%s/merge", id)},
+		{http.MethodDelete, "/admin/items?before=2000-01-01T00:00:00Z"},
+	}
+	claims := map[string]*oauth.ApplicationClaims{
+		"member":      {UserID: user.ID, Role: oauth.RoleMember},
+		"admin token": {UserID: user.ID, Role: oauth.RoleAdmin, Scopes: []string{oauth.ScopeRead, oauth.ScopeWrite}},
+	}
+	for name, c := range claims {
+		for _, tt := range tests {
+			t.Run(name+" "+tt.method+" "+tt.path, func(t *testing.T) {
+				rec := a.doWithClaims(c, tt.method, tt.path, nil)
+				if rec.Code != http.StatusForbidden {
+					t.Errorf("got %d, want %d: %s", rec.Code, http.StatusForbidden, rec.Body)
+				}
+			})
+		}
+	}
+}
@@ -11,5 +11,8 @@ func RegisterOAuthRoutes(h *handler.Handler) *http.ServeMux {
 	router := http.NewServeMux()
 	router.HandleFunc("GET /callback", h.Callback)
 	router.HandleFunc("GET /login", h.Login)
+	router.HandleFunc("GET /providers", h.ListProviders)
+	router.HandleFunc("POST /refresh", h.Refresh)
+	router.HandleFunc("POST /logout", h.Logout)
 	return router
 }
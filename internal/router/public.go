@@ -0,0 +1,16 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/rhajizada/gazette/internal/handler"
+)
+
+func RegisterPublicRoutes(h *handler.Handler) *http.ServeMux {
+	router := http.NewServeMux()
+	router.HandleFunc("GET /collections/{slug}", h.GetPublicCollection)
+	router.HandleFunc("GET /collections/{slug}/rss.xml", h.GetPublicCollectionRSS)
+	router.HandleFunc("GET /collections/{slug}/atom.xml", h.GetPublicCollectionAtom)
+	router.HandleFunc("GET /collections/{slug}/feed.json", h.GetPublicCollectionJSONFeed)
+	return router
+}
@@ -0,0 +1,36 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/rhajizada/gazette/internal/handler"
+)
+
+func RegisterReaderAuthRoutes(h *handler.Handler) *http.ServeMux {
+	router := http.NewServeMux()
+	router.HandleFunc("GET /accounts/ClientLogin", h.ReaderClientLogin)
+	router.HandleFunc("POST /accounts/ClientLogin", h.ReaderClientLogin)
+	return router
+}
+
+func RegisterReaderAPI(h *handler.Handler) *http.ServeMux {
+	router := http.NewServeMux()
+	router.HandleFunc("GET /token", h.ReaderToken)
+	router.HandleFunc("GET /user-info", h.ReaderUserInfo)
+	router.HandleFunc("GET /subscription/list", h.ReaderSubscriptionList)
+	router.HandleFunc("GET /tag/list", h.ReaderTagList)
+	router.HandleFunc("GET /unread-count", h.ReaderUnreadCount)
+	router.HandleFunc("/stream/contents", h.ReaderStreamContents)
+	router.HandleFunc("/stream/contents/{stream...}", h.ReaderStreamContents)
+	router.HandleFunc("GET /stream/items/ids", h.ReaderStreamItemIDs)
+	router.HandleFunc("/stream/items/contents", h.ReaderStreamItemContents)
+	router.HandleFunc("POST /edit-tag", h.ReaderEditTag)
+	router.HandleFunc("POST /mark-all-as-read", h.ReaderMarkAllAsRead)
+	return router
+}
+
+func RegisterFeverAPI(h *handler.Handler) *http.ServeMux {
+	router := http.NewServeMux()
+	router.HandleFunc("/{$}", h.Fever)
+	return router
+}
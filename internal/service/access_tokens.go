@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rhajizada/gazette/internal/oauth"
+	"github.com/rhajizada/gazette/internal/repository"
+)
+
+func newAccessToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return oauth.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func accessTokenFromRecord(rec repository.AccessToken) AccessToken {
+	return AccessToken{
+		ID:         rec.ID,
+		Name:       rec.Name,
+		Scopes:     rec.Scopes,
+		ExpiresAt:  rec.ExpiresAt,
+		CreatedAt:  rec.CreatedAt,
+		LastUsedAt: rec.LastUsedAt,
+	}
+}
+
+// CreateAccessToken generates a new personal access token for the user.
+func (s *Service) CreateAccessToken(ctx context.Context, r CreateAccessTokenRequest) (*CreateAccessTokenResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateAccessToken")
+	defer span.End()
+
+	if strings.TrimSpace(r.Name) == "" {
+		return nil, NewError("token name is required", http.StatusBadRequest)
+	}
+	if len(r.Scopes) == 0 {
+		return nil, NewError("at least one scope is required", http.StatusBadRequest)
+	}
+
+	granted := &oauth.ApplicationClaims{Scopes: r.GrantedScopes}
+	for _, scope := range r.Scopes {
+		if !oauth.ValidScope(scope) {
+			return nil, NewError(fmt.Sprintf("unknown scope %s", scope), http.StatusBadRequest)
+		}
+		if !granted.HasScope(scope) {
+			return nil, NewError(fmt.Sprintf("cannot grant scope %s", scope), http.StatusForbidden)
+		}
+	}
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		return nil, NewError("expiry must be in the future", http.StatusBadRequest)
+	}
+
+	token, err := newAccessToken()
+	if err != nil {
+		return nil, NewError("failed to generate token", http.StatusInternalServerError)
+	}
+
+	rec, err := s.Repo.CreateAccessToken(ctx, repository.CreateAccessTokenParams{
+		UserID:    r.UserID,
+		Name:      r.Name,
+		TokenHash: hashSecret(token),
+		Scopes:    r.Scopes,
+		ExpiresAt: r.ExpiresAt,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return nil, NewError(
+				fmt.Sprintf("token %s already exists", r.Name),
+				http.StatusConflict,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to create token %s", r.Name),
+			http.StatusInternalServerError,
+		)
+	}
+
+	return &CreateAccessTokenResponse{
+		AccessToken: accessTokenFromRecord(rec),
+		Token:       token,
+	}, nil
+}
+
+// ListAccessTokens returns all personal access tokens of the user.
+func (s *Service) ListAccessTokens(ctx context.Context, userID uuid.UUID) (*ListAccessTokensResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListAccessTokens")
+	defer span.End()
+
+	rows, err := s.Repo.ListAccessTokensByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to list tokens", http.StatusInternalServerError)
+	}
+
+	tokens := make([]AccessToken, len(rows))
+	for i, row := range rows {
+		tokens[i] = accessTokenFromRecord(row)
+	}
+
+	return &ListAccessTokensResponse{AccessTokens: tokens}, nil
+}
+
+// DeleteAccessToken revokes a personal access token of the user.
+func (s *Service) DeleteAccessToken(ctx context.Context, r repository.DeleteAccessTokenParams) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteAccessToken")
+	defer span.End()
+
+	count, err := s.Repo.DeleteAccessToken(ctx, r)
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to delete token %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	if count == 0 {
+		return NewError(
+			fmt.Sprintf("token %s not found", r.ID),
+			http.StatusNotFound,
+		)
+	}
+	return nil
+}
+
+// AuthenticateAccessToken returns the claims of the user owning a personal access token.
+func (s *Service) AuthenticateAccessToken(ctx context.Context, token string) (*oauth.ApplicationClaims, error) {
+	ctx, span := tracer.Start(ctx, "Service.AuthenticateAccessToken")
+	defer span.End()
+
+	rec, err := s.Repo.GetAccessTokenByHash(ctx, hashSecret(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError("invalid token", http.StatusUnauthorized)
+		}
+		return nil, NewError("failed to verify token", http.StatusInternalServerError)
+	}
+	if rec.ExpiresAt != nil && rec.ExpiresAt.Before(time.Now()) {
+		return nil, NewError("token expired", http.StatusUnauthorized)
+	}
+
+	user, err := s.GetUserByID(ctx, rec.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkUserActive(user); err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.TouchAccessToken(ctx, rec.ID); err != nil {
+		slog.WarnContext(ctx, "failed to update last use of token",
+			slog.String("token_id", rec.ID.String()),
+			slog.Any("error", err),
+		)
+	}
+
+	return &oauth.ApplicationClaims{
+		UserID: user.ID,
+		Name:   user.Name,
+		Email:  user.Email,
+		Sub:    user.Sub,
+		Role:   user.Role,
+		Scopes: rec.Scopes,
+	}, nil
+}
@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/repository"
+)
+
+func adminUserFromRow(row repository.GetUserWithStatsRow) AdminUser {
+	return AdminUser{
+		ID:            row.ID,
+		Sub:           row.Sub,
+		Name:          row.Name,
+		Email:         row.Email,
+		Role:          row.Role,
+		CreatedAt:     row.CreatedAt,
+		LastUpdatedAt: row.LastUpdatedAt,
+		DisabledAt:    row.DisabledAt,
+		Stats: UserStats{
+			Feeds:       row.Feeds,
+			Likes:       row.Likes,
+			Collections: row.Collections,
+			Sessions:    row.Sessions,
+		},
+	}
+}
+
+// ListAdminUsers lists users matching a name or email query, with stats.
+func (s *Service) ListAdminUsers(ctx context.Context, r ListAdminUsersRequest) (*ListAdminUsersResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListAdminUsers")
+	defer span.End()
+
+	total, err := s.Repo.CountUsersByQuery(ctx, r.Query)
+	if err != nil {
+		return nil, NewError("failed to count users", http.StatusInternalServerError)
+	}
+
+	rows, err := s.Repo.ListUsersWithStats(ctx, repository.ListUsersWithStatsParams{
+		Query:  r.Query,
+		Limit:  r.Limit,
+		Offset: r.Offset,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to list users", http.StatusInternalServerError)
+	}
+
+	users := make([]AdminUser, len(rows))
+	for i, row := range rows {
+		users[i] = adminUserFromRow(repository.GetUserWithStatsRow(row))
+	}
+
+	return &ListAdminUsersResponse{
+		Limit:      r.Limit,
+		Offset:     r.Offset,
+		TotalCount: total,
+		Users:      users,
+	}, nil
+}
+
+// GetAdminUser gets a user with stats.
+func (s *Service) GetAdminUser(ctx context.Context, userID uuid.UUID) (*AdminUser, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetAdminUser")
+	defer span.End()
+
+	row, err := s.Repo.GetUserWithStats(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("user %s not found", userID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to fetch user %s", userID),
+			http.StatusInternalServerError,
+		)
+	}
+
+	user := adminUserFromRow(row)
+	return &user, nil
+}
+
+// SetUserDisabled disables or re-enables a user. Disabling a user logs out
+// all their sessions.
+func (s *Service) SetUserDisabled(ctx context.Context, r repository.SetUserDisabledParams) (*AdminUser, error) {
+	ctx, span := tracer.Start(ctx, "Service.SetUserDisabled")
+	defer span.End()
+
+	count, err := s.Repo.SetUserDisabled(ctx, r)
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to update user %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	if count == 0 {
+		return nil, NewError(
+			fmt.Sprintf("user %s not found", r.ID),
+			http.StatusNotFound,
+		)
+	}
+
+	if r.Disabled {
+		if _, err := s.Repo.RevokeUserSessions(ctx, r.ID); err != nil {
+			return nil, NewError(
+				fmt.Sprintf("failed to revoke sessions of user %s", r.ID),
+				http.StatusInternalServerError,
+			)
+		}
+	}
+
+	return s.GetAdminUser(ctx, r.ID)
+}
+
+// DeleteUser deletes a user and everything they own.
+func (s *Service) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteUser")
+	defer span.End()
+
+	if _, err := s.GetUserByID(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.Repo.DeleteUserByID(ctx, userID); err != nil {
+		return NewError(
+			fmt.Sprintf("failed to delete user %s", userID),
+			http.StatusInternalServerError,
+		)
+	}
+	return nil
+}
+
+// ListAdminFeeds lists feeds with subscriber counts and sync health.
+func (s *Service) ListAdminFeeds(ctx context.Context, r ListAdminFeedsRequest) (*ListAdminFeedsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListAdminFeeds")
+	defer span.End()
+
+	total, err := s.Repo.CountFeedsWithHealth(ctx, r.FailingOnly)
+	if err != nil {
+		return nil, NewError("failed to count feeds", http.StatusInternalServerError)
+	}
+
+	rows, err := s.Repo.ListFeedsWithHealth(ctx, repository.ListFeedsWithHealthParams{
+		FailingOnly: r.FailingOnly,
+		Limit:       r.Limit,
+		Offset:      r.Offset,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to list feeds", http.StatusInternalServerError)
+	}
+
+	feeds := make([]AdminFeed, len(rows))
+	for i, row := range rows {
+		feeds[i] = AdminFeed{
+			ID:            row.ID,
+			Title:         row.Title,
+			FeedLink:      row.FeedLink,
+			CreatedAt:     row.CreatedAt,
+			LastUpdatedAt: row.LastUpdatedAt,
+			Subscribers:   row.Subscribers,
+			Items:         row.Items,
+			Health: FeedHealth{
+				LastSuccessAt: row.LastSuccessAt,
+				LastFailureAt: row.LastFailureAt,
+				LastError:     row.LastError,
+				FailureCount:  row.FailureCount,
+			},
+		}
+	}
+
+	return &ListAdminFeedsResponse{
+		Limit:      r.Limit,
+		Offset:     r.Offset,
+		TotalCount: total,
+		Feeds:      feeds,
+	}, nil
+}
+
+// getFeed checks that a feed exists.
+func (s *Service) getFeed(ctx context.Context, feedID uuid.UUID) (*repository.Feed, error) {
+	feed, err := s.Repo.GetFeedByID(ctx, feedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("feed %s not found", feedID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to fetch feed %s", feedID),
+			http.StatusInternalServerError,
+		)
+	}
+	return &feed, nil
+}
+
+// MergeFeeds moves the items, subscribers, tags and filter rules of a
+// duplicate feed to another feed and deletes the duplicate. The merge runs in
+// a transaction, it either completes or changes nothing.
+func (s *Service) MergeFeeds(ctx context.Context, r MergeFeedsRequest) (*MergeFeedsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.MergeFeeds")
+	defer span.End()
+
+	if r.SourceID == r.TargetID {
+		return nil, NewError("cannot merge a feed into itself", http.StatusBadRequest)
+	}
+	if _, err := s.getFeed(ctx, r.SourceID); err != nil {
+		return nil, err
+	}
+	if _, err := s.getFeed(ctx, r.TargetID); err != nil {
+		return nil, err
+	}
+
+	// the source feed is only deleted once everything referencing it moved
+	var moved int64
+	err := s.withTx(ctx, func(repo *repository.Queries) error {
+		var err error
+		moved, err = repo.MoveFeedItems(ctx, repository.MoveFeedItemsParams{
+			TargetID: r.TargetID,
+			SourceID: r.SourceID,
+		})
+		if err != nil {
+			return NewError("failed to move items", http.StatusInternalServerError)
+		}
+
+		err = repo.MoveFeedSubscriptions(ctx, repository.MoveFeedSubscriptionsParams{
+			TargetID: r.TargetID,
+			SourceID: r.SourceID,
+		})
+		if err != nil {
+			return NewError("failed to move subscriptions", http.StatusInternalServerError)
+		}
+
+		err = repo.MoveFeedTags(ctx, repository.MoveFeedTagsParams{
+			TargetID: r.TargetID,
+			SourceID: r.SourceID,
+		})
+		if err != nil {
+			return NewError("failed to move feed tags", http.StatusInternalServerError)
+		}
+
+		err = repo.MoveFeedFilterRules(ctx, repository.MoveFeedFilterRulesParams{
+			TargetID: r.TargetID,
+			SourceID: r.SourceID,
+		})
+		if err != nil {
+			return NewError("failed to move filter rules", http.StatusInternalServerError)
+		}
+
+		if err := repo.DeleteFeedByID(ctx, r.SourceID); err != nil {
+			return NewError(
+				fmt.Sprintf("failed to delete feed %s", r.SourceID),
+				http.StatusInternalServerError,
+			)
+		}
+		return nil
+	})
+	if err != nil {
+		var serviceErr ServiceError
+		if errors.As(err, &serviceErr) {
+			return nil, err
+		}
+		return nil, NewError("failed to merge feeds", http.StatusInternalServerError)
+	}
+	slog.InfoContext(ctx, "merged feeds",
+		slog.String("source_feed_id", r.SourceID.String()),
+		slog.String("target_feed_id", r.TargetID.String()),
+		slog.Int64("moved_items", moved),
+	)
+
+	return &MergeFeedsResponse{TargetID: r.TargetID, MovedItems: moved}, nil
+}
+
+// PurgeItems deletes items published before a date, optionally keeping items
+// users liked, saved to collections, annotated or tagged.
+func (s *Service) PurgeItems(ctx context.Context, r PurgeItemsRequest) (*PurgeItemsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.PurgeItems")
+	defer span.End()
+
+	if r.FeedID != nil {
+		if _, err := s.getFeed(ctx, *r.FeedID); err != nil {
+			return nil, err
+		}
+	}
+
+	deleted, err := s.Repo.PurgeItems(ctx, r.PurgeItemsParams)
+	if err != nil {
+		return nil, NewError("failed to purge items", http.StatusInternalServerError)
+	}
+	return &PurgeItemsResponse{Deleted: deleted}, nil
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/export"
+	"github.com/rhajizada/gazette/internal/repository"
+)
+
+// maxAnnotationNoteLength bounds the length of a note in characters.
+const maxAnnotationNoteLength = 10000
+
+func annotationFromRecord(rec repository.ItemAnnotation) Annotation {
+	return Annotation{
+		ID:          rec.ID,
+		ItemID:      rec.ItemID,
+		Note:        rec.Note,
+		Quote:       rec.Quote,
+		StartOffset: rec.StartOffset,
+		EndOffset:   rec.EndOffset,
+		CreatedAt:   rec.CreatedAt,
+		UpdatedAt:   rec.UpdatedAt,
+	}
+}
+
+// normalizeNote trims a note, empty notes are returned as nil.
+func normalizeNote(note *string) (*string, error) {
+	if note == nil {
+		return nil, nil
+	}
+	n := strings.TrimSpace(*note)
+	if n == "" {
+		return nil, nil
+	}
+	if utf8.RuneCountInString(n) > maxAnnotationNoteLength {
+		return nil, NewError(
+			fmt.Sprintf("note must be at most %d characters", maxAnnotationNoteLength),
+			http.StatusBadRequest,
+		)
+	}
+	return &n, nil
+}
+
+// anchorHighlight anchors a highlight in text, filling in the quote from the
+// offsets or the offsets from the first occurrence of the quote.
+func anchorHighlight(r *CreateAnnotationRequest, text string) error {
+	runes := []rune(text)
+	if r.StartOffset != nil {
+		start, end := *r.StartOffset, *r.EndOffset
+		if int(end) > len(runes) {
+			return NewError(
+				fmt.Sprintf("end_offset %d is past the end of the item text of %d characters", end, len(runes)),
+				http.StatusBadRequest,
+			)
+		}
+		quote := string(runes[start:end])
+		if r.Quote != nil && *r.Quote != quote {
+			return NewError("quote does not match the item text at the given offsets", http.StatusBadRequest)
+		}
+		r.Quote = &quote
+		return nil
+	}
+
+	i := strings.Index(text, *r.Quote)
+	if i < 0 {
+		return NewError("quote not found in the item text", http.StatusBadRequest)
+	}
+	start := int32(utf8.RuneCountInString(text[:i]))
+	end := start + int32(utf8.RuneCountInString(*r.Quote))
+	r.StartOffset, r.EndOffset = &start, &end
+	return nil
+}
+
+// CreateAnnotation adds a note or a highlight of the user to an item.
+func (s *Service) CreateAnnotation(ctx context.Context, r CreateAnnotationRequest) (*Annotation, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateAnnotation")
+	defer span.End()
+
+	var err error
+	if r.Note, err = normalizeNote(r.Note); err != nil {
+		return nil, err
+	}
+	if r.Quote != nil && *r.Quote == "" {
+		r.Quote = nil
+	}
+	if (r.StartOffset == nil) != (r.EndOffset == nil) {
+		return nil, NewError("start_offset and end_offset must be set together", http.StatusBadRequest)
+	}
+	if r.StartOffset != nil && (*r.StartOffset < 0 || *r.StartOffset >= *r.EndOffset) {
+		return nil, NewError("start_offset must be at least 0 and before end_offset", http.StatusBadRequest)
+	}
+	highlight := r.Quote != nil || r.StartOffset != nil
+	if !highlight && r.Note == nil {
+		return nil, NewError("annotation must have a note or a highlight", http.StatusBadRequest)
+	}
+
+	item, err := s.Repo.GetItemByID(ctx, r.ItemID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("item %s not found", r.ItemID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to fetch item %s", r.ItemID),
+			http.StatusInternalServerError,
+		)
+	}
+	if highlight {
+		content := item.Content
+		if content == nil || *content == "" {
+			content = item.Description
+		}
+		var text string
+		if content != nil {
+			text = export.Text(*content)
+		}
+		if err := anchorHighlight(&r, text); err != nil {
+			return nil, err
+		}
+	}
+
+	rec, err := s.Repo.CreateItemAnnotation(ctx, repository.CreateItemAnnotationParams{
+		UserID:      r.UserID,
+		ItemID:      r.ItemID,
+		Note:        r.Note,
+		Quote:       r.Quote,
+		StartOffset: r.StartOffset,
+		EndOffset:   r.EndOffset,
+	})
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to annotate item %s", r.ItemID),
+			http.StatusInternalServerError,
+		)
+	}
+	a := annotationFromRecord(rec)
+	return &a, nil
+}
+
+// GetAnnotation retrieves an annotation of the user on an item.
+func (s *Service) GetAnnotation(ctx context.Context, r repository.GetItemAnnotationParams) (*Annotation, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetAnnotation")
+	defer span.End()
+
+	rec, err := s.Repo.GetItemAnnotation(ctx, r)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("annotation %s not found", r.ID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to fetch annotation %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	a := annotationFromRecord(rec)
+	return &a, nil
+}
+
+// UpdateAnnotation replaces the note of an annotation of the user. The note
+// of a highlight can be removed, a note without a highlight cannot.
+func (s *Service) UpdateAnnotation(ctx context.Context, r UpdateAnnotationRequest) (*Annotation, error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateAnnotation")
+	defer span.End()
+
+	note, err := normalizeNote(&r.Note)
+	if err != nil {
+		return nil, err
+	}
+	old, err := s.GetAnnotation(ctx, repository.GetItemAnnotationParams{
+		ID:     r.AnnotationID,
+		ItemID: r.ItemID,
+		UserID: r.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if note == nil && old.Quote == nil {
+		return nil, NewError("note must not be empty, delete the annotation instead", http.StatusBadRequest)
+	}
+
+	rec, err := s.Repo.UpdateItemAnnotationNote(ctx, repository.UpdateItemAnnotationNoteParams{
+		Note:   note,
+		ID:     r.AnnotationID,
+		ItemID: r.ItemID,
+		UserID: r.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("annotation %s not found", r.AnnotationID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to update annotation %s", r.AnnotationID),
+			http.StatusInternalServerError,
+		)
+	}
+	a := annotationFromRecord(rec)
+	return &a, nil
+}
+
+// DeleteAnnotation deletes an annotation of the user.
+func (s *Service) DeleteAnnotation(ctx context.Context, r repository.DeleteItemAnnotationParams) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteAnnotation")
+	defer span.End()
+
+	deleted, err := s.Repo.DeleteItemAnnotation(ctx, r)
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to delete annotation %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	if deleted == 0 {
+		return NewError(
+			fmt.Sprintf("annotation %s not found", r.ID),
+			http.StatusNotFound,
+		)
+	}
+	return nil
+}
+
+// ListItemAnnotations lists the annotations of the user on an item in reading
+// order.
+func (s *Service) ListItemAnnotations(ctx context.Context, r repository.ListItemAnnotationsByItemParams) (*ListItemAnnotationsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListItemAnnotations")
+	defer span.End()
+
+	rows, err := s.Repo.ListItemAnnotationsByItem(ctx, r)
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to list annotations of item %s", r.ItemID),
+			http.StatusInternalServerError,
+		)
+	}
+	annotations := make([]Annotation, len(rows))
+	for i, row := range rows {
+		annotations[i] = annotationFromRecord(row)
+	}
+	return &ListItemAnnotationsResponse{Annotations: annotations}, nil
+}
+
+// ListAnnotations retrieves a paginated list of the annotations of the user
+// across items, newest first.
+func (s *Service) ListAnnotations(ctx context.Context, r repository.ListItemAnnotationsByUserParams) (*ListAnnotationsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListAnnotations")
+	defer span.End()
+
+	total, err := s.Repo.CountItemAnnotationsByUser(ctx, r.UserID)
+	if err != nil {
+		return nil, NewError("failed to count annotations", http.StatusInternalServerError)
+	}
+
+	annotations := make([]Annotation, 0)
+	if total > 0 {
+		rows, err := s.Repo.ListItemAnnotationsByUser(ctx, r)
+		if err != nil {
+			return nil, NewError("failed to list annotations", http.StatusInternalServerError)
+		}
+		for _, row := range rows {
+			a := annotationFromRecord(row.ItemAnnotation)
+			a.Item = &AnnotatedItem{
+				FeedID: row.FeedID,
+				Title:  row.ItemTitle,
+				Link:   row.ItemLink,
+			}
+			annotations = append(annotations, a)
+		}
+	}
+
+	return &ListAnnotationsResponse{
+		Limit:       r.Limit,
+		Offset:      r.Offset,
+		TotalCount:  total,
+		Annotations: annotations,
+	}, nil
+}
+
+// ExportAnnotations calls visit with every item the user annotated, or only
+// itemID when set, and its annotations in reading order.
+func (s *Service) ExportAnnotations(ctx context.Context, userID uuid.UUID, itemID *uuid.UUID, visit func(ExportAnnotatedItem) error) error {
+	ctx, span := tracer.Start(ctx, "Service.ExportAnnotations")
+	defer span.End()
+
+	rows, err := s.Repo.ListItemAnnotationsForExport(ctx, repository.ListItemAnnotationsForExportParams{
+		UserID: userID,
+		ItemID: itemID,
+	})
+	if err != nil {
+		return NewError("failed to list annotations", http.StatusInternalServerError)
+	}
+
+	var item *ExportAnnotatedItem
+	var current uuid.UUID
+	for _, row := range rows {
+		if item == nil || row.ItemAnnotation.ItemID != current {
+			if item != nil {
+				if err := visit(*item); err != nil {
+					return err
+				}
+			}
+			current = row.ItemAnnotation.ItemID
+			item = &ExportAnnotatedItem{
+				Title:     row.ItemTitle,
+				Link:      row.ItemLink,
+				FeedTitle: row.FeedTitle,
+			}
+		}
+		item.Annotations = append(item.Annotations, annotationFromRecord(row.ItemAnnotation))
+	}
+	if item != nil {
+		return visit(*item)
+	}
+	return nil
+}
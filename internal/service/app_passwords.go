@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rhajizada/gazette/internal/repository"
+)
+
+// hashSecret returns the hex encoded SHA-256 digest of a generated secret.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// newAppPassword generates a random password formatted in groups of four
+// characters so it can be typed on mobile devices.
+func newAppPassword() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	raw := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))
+	groups := make([]string, 0, len(raw)/4)
+	for i := 0; i < len(raw); i += 4 {
+		groups = append(groups, raw[i:i+4])
+	}
+	return strings.Join(groups, "-"), nil
+}
+
+// CreateAppPassword generates a new app password for the user.
+func (s *Service) CreateAppPassword(ctx context.Context, r CreateAppPasswordRequest) (*CreateAppPasswordResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateAppPassword")
+	defer span.End()
+
+	if strings.TrimSpace(r.Name) == "" {
+		return nil, NewError("app password name is required", http.StatusBadRequest)
+	}
+
+	password, err := newAppPassword()
+	if err != nil {
+		return nil, NewError("failed to generate app password", http.StatusInternalServerError)
+	}
+
+	rec, err := s.Repo.CreateAppPassword(ctx, repository.CreateAppPasswordParams{
+		UserID:       r.UserID,
+		Name:         r.Name,
+		PasswordHash: hashSecret(password),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return nil, NewError(
+				fmt.Sprintf("app password %s already exists", r.Name),
+				http.StatusConflict,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to create app password %s", r.Name),
+			http.StatusInternalServerError,
+		)
+	}
+
+	return &CreateAppPasswordResponse{
+		AppPassword: AppPassword{
+			ID:         rec.ID,
+			Name:       rec.Name,
+			CreatedAt:  rec.CreatedAt,
+			LastUsedAt: rec.LastUsedAt,
+		},
+		Password: password,
+	}, nil
+}
+
+// ListAppPasswords returns all app passwords of the user.
+func (s *Service) ListAppPasswords(ctx context.Context, userID uuid.UUID) (*ListAppPasswordsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListAppPasswords")
+	defer span.End()
+
+	rows, err := s.Repo.ListAppPasswordsByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to list app passwords", http.StatusInternalServerError)
+	}
+
+	passwords := make([]AppPassword, len(rows))
+	for i, row := range rows {
+		passwords[i] = AppPassword{
+			ID:         row.ID,
+			Name:       row.Name,
+			CreatedAt:  row.CreatedAt,
+			LastUsedAt: row.LastUsedAt,
+		}
+	}
+
+	return &ListAppPasswordsResponse{AppPasswords: passwords}, nil
+}
+
+// DeleteAppPassword revokes an app password of the user.
+func (s *Service) DeleteAppPassword(ctx context.Context, r repository.DeleteAppPasswordParams) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteAppPassword")
+	defer span.End()
+
+	count, err := s.Repo.DeleteAppPassword(ctx, r)
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to delete app password %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	if count == 0 {
+		return NewError(
+			fmt.Sprintf("app password %s not found", r.ID),
+			http.StatusNotFound,
+		)
+	}
+	return nil
+}
+
+// AuthenticateAppPassword returns the user owning the given email and app password.
+func (s *Service) AuthenticateAppPassword(ctx context.Context, r AuthenticateAppPasswordRequest) (*AuthenticateAppPasswordResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.AuthenticateAppPassword")
+	defer span.End()
+
+	invalid := NewError("invalid credentials", http.StatusUnauthorized)
+
+	rec, err := s.Repo.GetAppPasswordByHash(ctx, hashSecret(r.Password))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, invalid
+		}
+		return nil, NewError("failed to verify app password", http.StatusInternalServerError)
+	}
+
+	user, err := s.GetUserByID(ctx, rec.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, r.Email) {
+		return nil, invalid
+	}
+	if err := checkUserActive(user); err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.TouchAppPassword(ctx, rec.ID); err != nil {
+		slog.WarnContext(ctx, "failed to update last use of app password",
+			slog.String("app_password_id", rec.ID.String()),
+			slog.Any("error", err),
+		)
+	}
+
+	return &AuthenticateAppPasswordResponse{
+		User:          *user,
+		AppPasswordID: rec.ID,
+	}, nil
+}
+
+// ValidateAppPassword checks that the app password a Reader API token was
+// issued with has not been deleted and that its user is not disabled.
+func (s *Service) ValidateAppPassword(ctx context.Context, userID, appPasswordID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "Service.ValidateAppPassword")
+	defer span.End()
+
+	active, err := s.Repo.AppPasswordIsActive(ctx, repository.AppPasswordIsActiveParams{
+		ID:     appPasswordID,
+		UserID: userID,
+	})
+	if err != nil {
+		return NewError("failed to verify app password", http.StatusInternalServerError)
+	}
+	if !active {
+		return NewError("app password revoked or user disabled", http.StatusUnauthorized)
+	}
+	return nil
+}
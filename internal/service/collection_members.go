@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/repository"
+)
+
+// Roles of users in a collection. Viewers can read a collection, editors can
+// also add, remove and reorder its items, only the owner can change the
+// collection itself and its members.
+const (
+	CollectionRoleOwner  = "owner"
+	CollectionRoleEditor = "editor"
+	CollectionRoleViewer = "viewer"
+)
+
+// collectionRoleRanks orders roles by the access they grant.
+var collectionRoleRanks = map[string]int{
+	CollectionRoleViewer: 1,
+	CollectionRoleEditor: 2,
+	CollectionRoleOwner:  3,
+}
+
+// checkMemberRole validates the role a member is invited with.
+func checkMemberRole(role string) error {
+	if role != CollectionRoleViewer && role != CollectionRoleEditor {
+		return NewError(
+			fmt.Sprintf("invalid role %s, expected viewer or editor", role),
+			http.StatusBadRequest,
+		)
+	}
+	return nil
+}
+
+// accessCollection fetches a collection the user owns or is a member of and
+// checks that their role grants at least role. Collections the user cannot
+// access are reported as not found, so their IDs cannot be probed.
+func (s *Service) accessCollection(ctx context.Context, userID, collectionID uuid.UUID, role string) (*repository.Collection, string, error) {
+	row, err := s.Repo.GetCollectionForUser(ctx, repository.GetCollectionForUserParams{
+		UserID: userID,
+		ID:     collectionID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, "", NewError(
+				fmt.Sprintf("collection %s not found", collectionID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, "", NewError(
+			fmt.Sprintf("failed to fetch collection %s", collectionID),
+			http.StatusInternalServerError,
+		)
+	}
+	if collectionRoleRanks[row.Role] < collectionRoleRanks[role] {
+		return nil, "", NewError(
+			fmt.Sprintf("%ss of collection %s cannot change it", row.Role, collectionID),
+			http.StatusForbidden,
+		)
+	}
+	return &row.Collection, row.Role, nil
+}
+
+// ListCollectionMembers lists the members of a collection and its pending
+// invitations. Members can see each other.
+func (s *Service) ListCollectionMembers(ctx context.Context, r repository.GetCollectionByIDParams) (*ListCollectionMembersResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListCollectionMembers")
+	defer span.End()
+
+	if _, _, err := s.accessCollection(ctx, r.UserID, r.ID, CollectionRoleViewer); err != nil {
+		return nil, err
+	}
+	rows, err := s.Repo.ListCollectionMembers(ctx, r.ID)
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to list members of collection %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	pending, err := s.Repo.ListCollectionInvitationsByCollection(ctx, r.ID)
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to list invitations to collection %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+
+	members := make([]CollectionMember, len(rows))
+	for i, row := range rows {
+		members[i] = CollectionMember{
+			UserID:     row.CollectionMember.UserID,
+			Name:       row.Name,
+			Email:      row.Email,
+			Role:       row.CollectionMember.Role,
+			InvitedAt:  row.CollectionMember.InvitedAt,
+			AcceptedAt: row.CollectionMember.AcceptedAt,
+		}
+	}
+	invitations := make([]CollectionMemberInvitation, len(pending))
+	for i, rec := range pending {
+		invitations[i] = CollectionMemberInvitation{
+			Email:     rec.Email,
+			Role:      rec.Role,
+			InvitedAt: rec.InvitedAt,
+		}
+	}
+	return &ListCollectionMembersResponse{Members: members, Invitations: invitations}, nil
+}
+
+// InviteCollectionMember invites r.Email to a collection of the user, a user
+// whose provider verified the email becomes a member once they accept. Emails
+// are invited whether or not a user has them, so which emails have accounts
+// cannot be probed. Inviting an email again changes the role of its invitation, and of
+// its member once accepted.
+func (s *Service) InviteCollectionMember(ctx context.Context, r InviteCollectionMemberRequest) (*CollectionMemberInvitation, error) {
+	ctx, span := tracer.Start(ctx, "Service.InviteCollectionMember")
+	defer span.End()
+
+	if err := checkMemberRole(r.Role); err != nil {
+		return nil, err
+	}
+	email := strings.ToLower(strings.TrimSpace(r.Email))
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return nil, NewError(fmt.Sprintf("%s is not a valid email", r.Email), http.StatusBadRequest)
+	}
+	if _, err := s.getCollection(ctx, r.UserID, r.CollectionID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.Repo.GetUserByID(ctx, r.UserID)
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to fetch user %s", r.UserID),
+			http.StatusInternalServerError,
+		)
+	}
+	if strings.EqualFold(user.Email, email) {
+		return nil, NewError("you cannot invite yourself to your collection", http.StatusBadRequest)
+	}
+
+	rec, err := s.Repo.CreateCollectionInvitation(ctx, repository.CreateCollectionInvitationParams{
+		CollectionID: r.CollectionID,
+		Email:        email,
+		Role:         r.Role,
+		InvitedBy:    &r.UserID,
+	})
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to invite %s to collection %s", email, r.CollectionID),
+			http.StatusInternalServerError,
+		)
+	}
+	return &CollectionMemberInvitation{
+		Email:     rec.Email,
+		Role:      rec.Role,
+		InvitedAt: rec.InvitedAt,
+	}, nil
+}
+
+// WithdrawCollectionInvitation withdraws a pending invitation to a collection
+// of the user.
+func (s *Service) WithdrawCollectionInvitation(ctx context.Context, r WithdrawCollectionInvitationRequest) error {
+	ctx, span := tracer.Start(ctx, "Service.WithdrawCollectionInvitation")
+	defer span.End()
+
+	if _, err := s.getCollection(ctx, r.UserID, r.CollectionID); err != nil {
+		return err
+	}
+	email := strings.ToLower(strings.TrimSpace(r.Email))
+	deleted, err := s.Repo.DeleteCollectionInvitation(ctx, repository.DeleteCollectionInvitationParams{
+		CollectionID: r.CollectionID,
+		Email:        email,
+	})
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to withdraw invitation of %s to collection %s", email, r.CollectionID),
+			http.StatusInternalServerError,
+		)
+	}
+	if deleted == 0 {
+		return NewError(
+			fmt.Sprintf("no pending invitation of %s to collection %s", email, r.CollectionID),
+			http.StatusNotFound,
+		)
+	}
+	return nil
+}
+
+// UpdateCollectionMember changes the role of a member of a collection of the
+// user.
+func (s *Service) UpdateCollectionMember(ctx context.Context, r UpdateCollectionMemberRequest) (*CollectionMember, error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateCollectionMember")
+	defer span.End()
+
+	if err := checkMemberRole(r.Role); err != nil {
+		return nil, err
+	}
+	if _, err := s.getCollection(ctx, r.UserID, r.CollectionID); err != nil {
+		return nil, err
+	}
+	rec, err := s.Repo.UpdateCollectionMemberRole(ctx, repository.UpdateCollectionMemberRoleParams{
+		CollectionID: r.CollectionID,
+		UserID:       r.MemberID,
+		Role:         r.Role,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("user %s is not a member of collection %s", r.MemberID, r.CollectionID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to update member %s of collection %s", r.MemberID, r.CollectionID),
+			http.StatusInternalServerError,
+		)
+	}
+
+	member := CollectionMember{
+		UserID:     rec.UserID,
+		Role:       rec.Role,
+		InvitedAt:  rec.InvitedAt,
+		AcceptedAt: rec.AcceptedAt,
+	}
+	if user, err := s.Repo.GetUserByID(ctx, rec.UserID); err == nil {
+		member.Name = user.Name
+		member.Email = user.Email
+	}
+	return &member, nil
+}
+
+// RemoveCollectionMember removes a member from a collection of the user.
+func (s *Service) RemoveCollectionMember(ctx context.Context, r RemoveCollectionMemberRequest) error {
+	ctx, span := tracer.Start(ctx, "Service.RemoveCollectionMember")
+	defer span.End()
+
+	if _, err := s.getCollection(ctx, r.UserID, r.CollectionID); err != nil {
+		return err
+	}
+	return s.deleteCollectionMember(ctx, repository.DeleteCollectionMemberParams{
+		CollectionID: r.CollectionID,
+		UserID:       r.MemberID,
+	})
+}
+
+// LeaveCollection removes the user from a collection shared with them. It
+// also declines a pending invitation.
+func (s *Service) LeaveCollection(ctx context.Context, r repository.DeleteCollectionMemberParams) error {
+	ctx, span := tracer.Start(ctx, "Service.LeaveCollection")
+	defer span.End()
+
+	left, err := s.Repo.DeleteCollectionMember(ctx, r)
+	if err == nil && left == 0 {
+		left, err = s.Repo.DeclineCollectionInvitation(ctx, repository.DeclineCollectionInvitationParams{
+			CollectionID: r.CollectionID,
+			UserID:       r.UserID,
+		})
+	}
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to leave collection %s", r.CollectionID),
+			http.StatusInternalServerError,
+		)
+	}
+	if left == 0 {
+		return NewError(
+			fmt.Sprintf("user %s is not a member of collection %s", r.UserID, r.CollectionID),
+			http.StatusNotFound,
+		)
+	}
+	return nil
+}
+
+func (s *Service) deleteCollectionMember(ctx context.Context, r repository.DeleteCollectionMemberParams) error {
+	deleted, err := s.Repo.DeleteCollectionMember(ctx, r)
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to remove member %s from collection %s", r.UserID, r.CollectionID),
+			http.StatusInternalServerError,
+		)
+	}
+	if deleted == 0 {
+		return NewError(
+			fmt.Sprintf("user %s is not a member of collection %s", r.UserID, r.CollectionID),
+			http.StatusNotFound,
+		)
+	}
+	return nil
+}
+
+// ListCollectionInvitations lists the invitations to the verified emails of
+// the user that are not accepted yet.
+func (s *Service) ListCollectionInvitations(ctx context.Context, userID uuid.UUID) (*ListCollectionInvitationsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListCollectionInvitations")
+	defer span.End()
+
+	rows, err := s.Repo.ListCollectionInvitations(ctx, userID)
+	if err != nil {
+		return nil, NewError("failed to list collection invitations", http.StatusInternalServerError)
+	}
+
+	invitations := make([]CollectionInvitation, len(rows))
+	for i, row := range rows {
+		invitations[i] = CollectionInvitation{
+			CollectionID:   row.CollectionInvitation.CollectionID,
+			CollectionName: row.CollectionName,
+			Role:           row.CollectionInvitation.Role,
+			InvitedAt:      row.CollectionInvitation.InvitedAt,
+		}
+		if row.CollectionInvitation.InvitedBy != nil && row.InvitedByName != nil {
+			invitations[i].InvitedBy = &UserSummary{
+				ID:   *row.CollectionInvitation.InvitedBy,
+				Name: *row.InvitedByName,
+			}
+		}
+	}
+	return &ListCollectionInvitationsResponse{Invitations: invitations}, nil
+}
+
+// AcceptCollectionInvitation accepts an invitation of the user to a
+// collection, which then lists with their own.
+func (s *Service) AcceptCollectionInvitation(ctx context.Context, r repository.AcceptCollectionInvitationParams) (*Collection, error) {
+	ctx, span := tracer.Start(ctx, "Service.AcceptCollectionInvitation")
+	defer span.End()
+
+	accepted, err := s.Repo.AcceptCollectionInvitation(ctx, r)
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to accept invitation to collection %s", r.CollectionID),
+			http.StatusInternalServerError,
+		)
+	}
+	if accepted == 0 {
+		return nil, NewError(
+			fmt.Sprintf("no pending invitation to collection %s", r.CollectionID),
+			http.StatusNotFound,
+		)
+	}
+
+	col, role, err := s.accessCollection(ctx, r.UserID, r.CollectionID, CollectionRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	c := collectionFromRecord(*col, role)
+	return &c, nil
+}
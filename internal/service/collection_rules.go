@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/workers"
+)
+
+const (
+	// defaultMinSimilarity is used when rules have a seed text but no
+	// similarity threshold.
+	defaultMinSimilarity = 0.5
+
+	// maxCollectionRuleValues bounds the number of values of a list rule.
+	maxCollectionRuleValues = 50
+
+	// embedQueue is the queue embeddings are computed on.
+	embedQueue = "default"
+)
+
+// normalizeRuleValues trims values and drops empty ones.
+func normalizeRuleValues(name string, values []string) ([]string, error) {
+	if len(values) > maxCollectionRuleValues {
+		return nil, NewError(
+			fmt.Sprintf("%s must have at most %d values", name, maxCollectionRuleValues),
+			http.StatusBadRequest,
+		)
+	}
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out, nil
+}
+
+// normalize validates the rules and trims their values.
+func (r *CollectionRules) normalize() error {
+	var err error
+	if len(r.FeedIDs) > maxCollectionRuleValues {
+		return NewError(
+			fmt.Sprintf("feed_ids must have at most %d values", maxCollectionRuleValues),
+			http.StatusBadRequest,
+		)
+	}
+	if r.Categories, err = normalizeRuleValues("categories", r.Categories); err != nil {
+		return err
+	}
+	if r.Keywords, err = normalizeRuleValues("keywords", r.Keywords); err != nil {
+		return err
+	}
+	if r.Author != nil {
+		if author := strings.TrimSpace(*r.Author); author != "" {
+			r.Author = &author
+		} else {
+			r.Author = nil
+		}
+	}
+	if r.SimilarTo != nil {
+		if seed := strings.TrimSpace(*r.SimilarTo); seed != "" {
+			r.SimilarTo = &seed
+		} else {
+			r.SimilarTo = nil
+		}
+	}
+	if r.WithinDays != nil && *r.WithinDays <= 0 {
+		return NewError("within_days must be positive", http.StatusBadRequest)
+	}
+	if r.PublishedAfter != nil && r.PublishedBefore != nil && !r.PublishedAfter.Before(*r.PublishedBefore) {
+		return NewError("published_after must be before published_before", http.StatusBadRequest)
+	}
+	if r.MinSimilarity != nil {
+		if r.SimilarTo == nil {
+			return NewError("min_similarity requires similar_to", http.StatusBadRequest)
+		}
+		if *r.MinSimilarity <= 0 || *r.MinSimilarity > 1 {
+			return NewError("min_similarity must be greater than 0 and at most 1", http.StatusBadRequest)
+		}
+	}
+	if len(r.FeedIDs) == 0 && len(r.Categories) == 0 && len(r.Keywords) == 0 && r.Author == nil &&
+		r.PublishedAfter == nil && r.PublishedBefore == nil && r.WithinDays == nil &&
+		r.Liked == nil && r.Unread == nil && r.SimilarTo == nil {
+		return NewError("rules must not be empty", http.StatusBadRequest)
+	}
+	return nil
+}
+
+// matchParams returns the parameters matching items against the rules of the
+// collection col at time now.
+func (r *CollectionRules) matchParams(col *repository.Collection, now time.Time) repository.CountItemsMatchingRulesParams {
+	p := repository.CountItemsMatchingRulesParams{
+		UserID:          col.UserID,
+		CollectionID:    col.ID,
+		FeedIds:         r.FeedIDs,
+		Categories:      r.Categories,
+		Keywords:        r.Keywords,
+		Author:          r.Author,
+		PublishedAfter:  r.PublishedAfter,
+		PublishedBefore: r.PublishedBefore,
+		Liked:           r.Liked,
+		Unread:          r.Unread,
+	}
+	if r.WithinDays != nil {
+		since := now.AddDate(0, 0, -int(*r.WithinDays))
+		if p.PublishedAfter == nil || p.PublishedAfter.Before(since) {
+			p.PublishedAfter = &since
+		}
+	}
+	if r.SimilarTo != nil {
+		minSimilarity := defaultMinSimilarity
+		if r.MinSimilarity != nil {
+			minSimilarity = *r.MinSimilarity
+		}
+		p.MinSimilarity = &minSimilarity
+	}
+	return p
+}
+
+// collectionRules decodes the rules of a smart collection, it returns nil for
+// manual collections.
+func collectionRules(col *repository.Collection) *CollectionRules {
+	if len(col.Rules) == 0 {
+		return nil
+	}
+	var rules CollectionRules
+	if err := json.Unmarshal(col.Rules, &rules); err != nil {
+		return nil
+	}
+	return &rules
+}
+
+// listCollectionRows returns the number of items in a collection and a page
+// of them. Items of smart collections are matched against their rules.
+func (s *Service) listCollectionRows(ctx context.Context, col *repository.Collection, limit, offset int32) (int64, []repository.ListItemsInCollectionRow, error) {
+	rules := collectionRules(col)
+	if rules == nil {
+		total, err := s.Repo.CountItemsInCollection(ctx, repository.CountItemsInCollectionParams{
+			CollectionID: col.ID,
+			UserID:       col.UserID,
+		})
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return 0, nil, NewError(
+				fmt.Sprintf("failed counting items in collection %s", col.ID),
+				http.StatusInternalServerError,
+			)
+		}
+		if total == 0 {
+			return 0, make([]repository.ListItemsInCollectionRow, 0), nil
+		}
+		rows, err := s.Repo.ListItemsInCollection(ctx, repository.ListItemsInCollectionParams{
+			CollectionID: col.ID,
+			UserID:       col.UserID,
+			Limit:        limit,
+			Offset:       offset,
+		})
+		if err != nil {
+			return 0, nil, NewError(
+				fmt.Sprintf("failed to list items in collection %s", col.ID),
+				http.StatusInternalServerError,
+			)
+		}
+		return total, rows, nil
+	}
+
+	p := rules.matchParams(col, time.Now())
+	total, err := s.Repo.CountItemsMatchingRules(ctx, p)
+	if err != nil {
+		return 0, nil, NewError(
+			fmt.Sprintf("failed counting items in collection %s", col.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	rows := make([]repository.ListItemsInCollectionRow, 0)
+	if total == 0 {
+		return 0, rows, nil
+	}
+	matched, err := s.Repo.ListItemsMatchingRules(ctx, repository.ListItemsMatchingRulesParams{
+		CollectionID:    p.CollectionID,
+		UserID:          p.UserID,
+		FeedIds:         p.FeedIds,
+		Categories:      p.Categories,
+		Keywords:        p.Keywords,
+		Author:          p.Author,
+		PublishedAfter:  p.PublishedAfter,
+		PublishedBefore: p.PublishedBefore,
+		Liked:           p.Liked,
+		Unread:          p.Unread,
+		MinSimilarity:   p.MinSimilarity,
+		Limit:           limit,
+		Offset:          offset,
+	})
+	if err != nil {
+		return 0, nil, NewError(
+			fmt.Sprintf("failed to list items in collection %s", col.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	for _, row := range matched {
+		rows = append(rows, repository.ListItemsInCollectionRow(row))
+	}
+	return total, rows, nil
+}
+
+// enqueueCollectionEmbedding queues embedding the seed text of a smart
+// collection, or removing its embedding once it has none.
+func (s *Service) enqueueCollectionEmbedding(ctx context.Context, col *repository.Collection) {
+	task, err := workers.NewEmbedCollectionTask(ctx, col.ID)
+	if err == nil {
+		_, _, err = workers.Enqueue(ctx, s.Client, s.Inspector, task, workers.EmbedCollectionTaskID(col.ID), embedQueue)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to queue collection embedding task",
+			slog.String("collection_id", col.ID.String()),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// SetCollectionRules turns a collection of the user into a smart collection,
+// or replaces its rules. Items added manually are kept but not listed while
+// the collection has rules.
+func (s *Service) SetCollectionRules(ctx context.Context, r SetCollectionRulesRequest) (*Collection, error) {
+	ctx, span := tracer.Start(ctx, "Service.SetCollectionRules")
+	defer span.End()
+
+	if err := r.Rules.normalize(); err != nil {
+		return nil, err
+	}
+	old, err := s.getCollection(ctx, r.UserID, r.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := json.Marshal(r.Rules)
+	if err != nil {
+		return nil, NewError("failed to encode collection rules", http.StatusInternalServerError)
+	}
+
+	col, err := s.Repo.SetCollectionRules(ctx, repository.SetCollectionRulesParams{
+		Rules:  rules,
+		ID:     r.CollectionID,
+		UserID: r.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("collection %s not found", r.CollectionID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to set rules of collection %s", r.CollectionID),
+			http.StatusInternalServerError,
+		)
+	}
+
+	var oldSeed string
+	if prev := collectionRules(old); prev != nil && prev.SimilarTo != nil {
+		oldSeed = *prev.SimilarTo
+	}
+	var seed string
+	if r.Rules.SimilarTo != nil {
+		seed = *r.Rules.SimilarTo
+	}
+	if seed != oldSeed {
+		s.enqueueCollectionEmbedding(ctx, &col)
+	}
+
+	c := collectionFromRecord(col, CollectionRoleOwner)
+	return &c, nil
+}
+
+// DeleteCollectionRules turns a smart collection of the user back into a
+// manual one.
+func (s *Service) DeleteCollectionRules(ctx context.Context, r repository.GetCollectionByIDParams) (*Collection, error) {
+	ctx, span := tracer.Start(ctx, "Service.DeleteCollectionRules")
+	defer span.End()
+
+	col, err := s.Repo.SetCollectionRules(ctx, repository.SetCollectionRulesParams{
+		ID:     r.ID,
+		UserID: r.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("collection %s not found", r.ID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to delete rules of collection %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	if err := s.Repo.DeleteCollectionEmbeddingByID(ctx, col.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to delete collection embedding",
+			slog.String("collection_id", col.ID.String()),
+			slog.Any("error", err),
+		)
+	}
+
+	c := collectionFromRecord(col, CollectionRoleOwner)
+	return &c, nil
+}
+
+// requireManual rejects changes to the items of smart collections.
+func requireManual(col *repository.Collection) error {
+	if col.Rules != nil {
+		return NewError(
+			fmt.Sprintf("items of smart collection %s are defined by its rules", col.ID),
+			http.StatusBadRequest,
+		)
+	}
+	return nil
+}
@@ -2,20 +2,69 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"regexp"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/workers"
 )
 
-// ListCollections retrieves a paginated list of collections for a user.
+// maxCollectionIconLength bounds the icon of a collection, an emoji or the
+// name of an icon.
+const maxCollectionIconLength = 64
+
+// collectionColor matches hex colors such as #1e90ff.
+var collectionColor = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// newCollectionSlug returns an unguessable slug for the public URL of a
+// collection.
+func newCollectionSlug() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// collectionFromRecord converts a collection the user has role in.
+func collectionFromRecord(rec repository.Collection, role string) Collection {
+	return Collection{
+		ID:          rec.ID,
+		Name:        rec.Name,
+		Description: rec.Description,
+		Icon:        rec.Icon,
+		Color:       rec.Color,
+		Pinned:      rec.Pinned,
+		Position:    rec.Position,
+		Public:      rec.Public,
+		Slug:        rec.Slug,
+		Smart:       rec.Rules != nil,
+		Rules:       collectionRules(&rec),
+		OwnerID:     rec.UserID,
+		Role:        role,
+		CreatedAt:   rec.CreatedAt,
+		LastUpdated: rec.LastUpdated,
+	}
+}
+
+// ListCollections retrieves a paginated list of the collections of a user
+// followed by those shared with them.
 func (s *Service) ListCollections(ctx context.Context, r repository.ListCollectionsByUserParams) (*ListCollectionsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListCollections")
+	defer span.End()
+
 	total, err := s.Repo.CountCollectionsByUserID(ctx, r.UserID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -28,10 +77,10 @@ func (s *Service) ListCollections(ctx context.Context, r repository.ListCollecti
 		}
 	}
 
-	var rows []repository.Collection
+	var rows []repository.ListCollectionsByUserRow
 
 	if total == 0 {
-		rows = make([]repository.Collection, 0)
+		rows = make([]repository.ListCollectionsByUserRow, 0)
 	} else {
 		rows, err = s.Repo.ListCollectionsByUser(ctx, repository.ListCollectionsByUserParams{
 			UserID: r.UserID,
@@ -44,13 +93,8 @@ func (s *Service) ListCollections(ctx context.Context, r repository.ListCollecti
 	}
 
 	cols := make([]Collection, len(rows))
-	for i, c := range rows {
-		cols[i] = Collection{
-			ID:          c.ID,
-			Name:        c.Name,
-			CreatedAt:   c.CreatedAt,
-			LastUpdated: c.LastUpdated,
-		}
+	for i, row := range rows {
+		cols[i] = collectionFromRecord(row.Collection, row.Role)
 	}
 
 	return &ListCollectionsResponse{
@@ -63,6 +107,9 @@ func (s *Service) ListCollections(ctx context.Context, r repository.ListCollecti
 
 // CreateCollection creates a new collection.
 func (s *Service) CreateCollection(ctx context.Context, r repository.CreateCollectionParams) (*Collection, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateCollection")
+	defer span.End()
+
 	col, err := s.Repo.CreateCollection(ctx, r)
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -77,60 +124,314 @@ func (s *Service) CreateCollection(ctx context.Context, r repository.CreateColle
 			http.StatusInternalServerError,
 		)
 	}
-	return &Collection{
-		ID:          col.ID,
-		Name:        col.Name,
-		CreatedAt:   col.CreatedAt,
-		LastUpdated: col.LastUpdated,
-	}, nil
+	c := collectionFromRecord(col, CollectionRoleOwner)
+	return &c, nil
 }
 
-// GetCollectionByID retrieves a single collection by ID.
-func (s *Service) GetCollectionByID(ctx context.Context, collectionID uuid.UUID) (*Collection, error) {
-	col, err := s.Repo.GetCollectionByID(ctx, collectionID)
+// getCollection fetches a collection owned by userID. Collections of other
+// users are reported as not found, so their IDs cannot be probed.
+func (s *Service) getCollection(ctx context.Context, userID, collectionID uuid.UUID) (*repository.Collection, error) {
+	col, err := s.Repo.GetCollectionByID(ctx, repository.GetCollectionByIDParams{
+		ID:     collectionID,
+		UserID: userID,
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, NewError(
 				fmt.Sprintf("collection %s not found", collectionID),
 				http.StatusNotFound,
 			)
-		} else {
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to fetch collection %s", collectionID),
+			http.StatusInternalServerError,
+		)
+	}
+	return &col, nil
+}
+
+// GetCollectionByID retrieves a single collection the user owns or is a
+// member of by ID.
+func (s *Service) GetCollectionByID(ctx context.Context, r repository.GetCollectionByIDParams) (*Collection, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetCollectionByID")
+	defer span.End()
+
+	col, role, err := s.accessCollection(ctx, r.UserID, r.ID, CollectionRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	c := collectionFromRecord(*col, role)
+	return &c, nil
+}
+
+// UpdateCollection updates the fields of a collection of the user that are
+// set in r, empty description, icon and color clear them. Publishing a
+// collection for the first time gives it a slug.
+func (s *Service) UpdateCollection(ctx context.Context, r repository.UpdateCollectionByIDParams) (*Collection, error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateCollection")
+	defer span.End()
+
+	if r.Name != nil {
+		name := strings.TrimSpace(*r.Name)
+		if name == "" {
+			return nil, NewError("collection name must not be empty", http.StatusBadRequest)
+		}
+		r.Name = &name
+	}
+	if r.Icon != nil && utf8.RuneCountInString(*r.Icon) > maxCollectionIconLength {
+		return nil, NewError(
+			fmt.Sprintf("icon must be at most %d characters", maxCollectionIconLength),
+			http.StatusBadRequest,
+		)
+	}
+	if r.Color != nil && *r.Color != "" && !collectionColor.MatchString(*r.Color) {
+		return nil, NewError(
+			fmt.Sprintf("invalid color %s, expected a hex color such as #1e90ff", *r.Color),
+			http.StatusBadRequest,
+		)
+	}
+	r.Slug = nil
+	if r.Public != nil && *r.Public {
+		slug, err := newCollectionSlug()
+		if err != nil {
+			return nil, NewError("failed to generate collection slug", http.StatusInternalServerError)
+		}
+		r.Slug = &slug
+	}
+
+	col, err := s.Repo.UpdateCollectionByID(ctx, r)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
 			return nil, NewError(
-				fmt.Sprintf("failed to fetch collection %s", collectionID),
-				http.StatusInternalServerError,
+				fmt.Sprintf("collection %s not found", r.ID),
+				http.StatusNotFound,
 			)
 		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return nil, NewError(
+				fmt.Sprintf("collection %s already exists", *r.Name),
+				http.StatusConflict,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to update collection %s", r.ID),
+			http.StatusInternalServerError,
+		)
 	}
-	return &Collection{
-		ID:          col.ID,
-		Name:        col.Name,
-		CreatedAt:   col.CreatedAt,
-		LastUpdated: col.LastUpdated,
-	}, nil
+	c := collectionFromRecord(col, CollectionRoleOwner)
+	return &c, nil
 }
 
-// DeleteCollection deletes a collection by ID.
-func (s *Service) DeleteCollection(ctx context.Context, collectionID uuid.UUID) error {
-	if err := s.Repo.DeleteCollectionByID(ctx, collectionID); err != nil {
+// RotateCollectionSlug replaces the slug of a collection of the user, so the
+// previous public URL stops working.
+func (s *Service) RotateCollectionSlug(ctx context.Context, r repository.GetCollectionByIDParams) (*Collection, error) {
+	ctx, span := tracer.Start(ctx, "Service.RotateCollectionSlug")
+	defer span.End()
+
+	slug, err := newCollectionSlug()
+	if err != nil {
+		return nil, NewError("failed to generate collection slug", http.StatusInternalServerError)
+	}
+	col, err := s.Repo.RotateCollectionSlug(ctx, repository.RotateCollectionSlugParams{
+		ID:     r.ID,
+		UserID: r.UserID,
+		Slug:   &slug,
+	})
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return NewError(
-				fmt.Sprintf("collection %s not found", collectionID),
+			return nil, NewError(
+				fmt.Sprintf("collection %s not found", r.ID),
 				http.StatusNotFound,
 			)
-		} else {
-			return NewError(
-				fmt.Sprintf("failed to delete collection %s", collectionID),
-				http.StatusInternalServerError,
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to rotate slug of collection %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	c := collectionFromRecord(col, CollectionRoleOwner)
+	return &c, nil
+}
+
+// GetPublicCollection retrieves a published collection and a page of its
+// items by slug. Private collections are reported as not found.
+func (s *Service) GetPublicCollection(ctx context.Context, r GetPublicCollectionRequest) (*PublicCollection, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetPublicCollection")
+	defer span.End()
+
+	col, err := s.Repo.GetPublicCollectionBySlug(ctx, &r.Slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("collection %s not found", r.Slug),
+				http.StatusNotFound,
 			)
 		}
+		return nil, NewError(
+			fmt.Sprintf("failed to fetch collection %s", r.Slug),
+			http.StatusInternalServerError,
+		)
+	}
+
+	total, rows, err := s.listCollectionRows(ctx, &col, r.Limit, r.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]PublicItem, len(rows))
+	for i, row := range rows {
+		auths := make(Authors, len(row.Authors))
+		for j, a := range row.Authors {
+			auths[j] = Person{Name: a.Name, Email: a.Email}
+		}
+		var image *string
+		if row.Image != nil && row.Image.URL != "" {
+			image = &row.Image.URL
+		}
+		items[i] = PublicItem{
+			ID:              row.ID,
+			Title:           row.Title,
+			Description:     row.Description,
+			Content:         row.Content,
+			Link:            row.Link,
+			PublishedParsed: row.PublishedParsed,
+			UpdatedParsed:   row.UpdatedParsed,
+			Authors:         auths,
+			ImageURL:        image,
+			Categories:      row.Categories,
+			AddedAt:         row.AddedAt,
+		}
+	}
+
+	return &PublicCollection{
+		Slug:        r.Slug,
+		Name:        col.Name,
+		Description: col.Description,
+		Icon:        col.Icon,
+		Color:       col.Color,
+		LastUpdated: col.LastUpdated,
+		Limit:       r.Limit,
+		Offset:      r.Offset,
+		TotalCount:  total,
+		Items:       items,
+	}, nil
+}
+
+// ReorderCollections sets the manual order of the collections of the user to
+// the order of r.Ids.
+func (s *Service) ReorderCollections(ctx context.Context, r repository.SetCollectionPositionsParams) error {
+	ctx, span := tracer.Start(ctx, "Service.ReorderCollections")
+	defer span.End()
+
+	if err := checkOrder(r.Ids); err != nil {
+		return err
+	}
+	updated, err := s.Repo.SetCollectionPositions(ctx, r)
+	if err != nil {
+		return NewError("failed to reorder collections", http.StatusInternalServerError)
+	}
+	if updated == 0 {
+		return NewError("one or more collections not found", http.StatusNotFound)
+	}
+	return nil
+}
+
+// ReorderCollectionItems sets the manual order of the items of a collection
+// the user can edit to the order of r.ItemIDs.
+func (s *Service) ReorderCollectionItems(ctx context.Context, r ReorderCollectionItemsRequest) error {
+	ctx, span := tracer.Start(ctx, "Service.ReorderCollectionItems")
+	defer span.End()
+
+	if err := checkOrder(r.ItemIDs); err != nil {
+		return err
+	}
+	col, _, err := s.accessCollection(ctx, r.UserID, r.CollectionID, CollectionRoleEditor)
+	if err != nil {
+		return err
+	}
+	if err := requireManual(col); err != nil {
+		return err
+	}
+	updated, err := s.Repo.SetCollectionItemPositions(ctx, repository.SetCollectionItemPositionsParams{
+		ItemIds:      r.ItemIDs,
+		CollectionID: r.CollectionID,
+	})
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to reorder items in collection %s", r.CollectionID),
+			http.StatusInternalServerError,
+		)
+	}
+	if updated == 0 {
+		return NewError(
+			fmt.Sprintf("one or more items are not in collection %s", r.CollectionID),
+			http.StatusBadRequest,
+		)
+	}
+	return nil
+}
+
+// checkOrder validates a list of IDs describing a manual order.
+func checkOrder(ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return NewError("order must not be empty", http.StatusBadRequest)
+	}
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			return NewError(fmt.Sprintf("%s is listed more than once", id), http.StatusBadRequest)
+		}
+		seen[id] = struct{}{}
+	}
+	return nil
+}
+
+// DeleteCollection deletes a collection of the user by ID.
+func (s *Service) DeleteCollection(ctx context.Context, r repository.DeleteCollectionByIDParams) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteCollection")
+	defer span.End()
+
+	deleted, err := s.Repo.DeleteCollectionByID(ctx, r)
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to delete collection %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	if deleted == 0 {
+		return NewError(
+			fmt.Sprintf("collection %s not found", r.ID),
+			http.StatusNotFound,
+		)
 	}
 	return nil
 }
 
-// AddItemToCollection adds an item to a collection.
+// AddItemToCollection adds an item to a collection the user r.UserID can
+// edit, attributing it to them.
 func (s *Service) AddItemToCollection(ctx context.Context, r repository.AddItemToCollectionParams) (*AddItemToCollectionResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.AddItemToCollection")
+	defer span.End()
+
+	col, _, err := s.accessCollection(ctx, r.UserID, r.CollectionID, CollectionRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManual(col); err != nil {
+		return nil, err
+	}
+	r.AddedBy = r.UserID
+	r.UserID = col.UserID
+
 	rec, err := s.Repo.AddItemToCollection(ctx, r)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("collection %s not found", r.CollectionID),
+				http.StatusNotFound,
+			)
+		}
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
 			switch pgErr.Code {
@@ -144,76 +445,77 @@ func (s *Service) AddItemToCollection(ctx context.Context, r repository.AddItemT
 				case "collection_items_collection_id_fkey":
 					return nil, NewError(
 						fmt.Sprintf("collection %s not found", r.CollectionID),
-						http.StatusBadRequest,
+						http.StatusNotFound,
 					)
 				case "collection_items_item_id_fkey":
 					return nil, NewError(
-						fmt.Sprintf("item %s not found", r.CollectionID),
+						fmt.Sprintf("item %s not found", r.ItemID),
 						http.StatusBadRequest,
 					)
 				default:
 					return nil, NewError("invalid reference", http.StatusBadRequest)
 				}
 			}
-		} else {
-			return nil, NewError(
-				fmt.Sprintf("failed to update collection %s", r.CollectionID),
-				http.StatusInternalServerError,
-			)
 		}
+		return nil, NewError(
+			fmt.Sprintf("failed to update collection %s", r.CollectionID),
+			http.StatusInternalServerError,
+		)
+	}
+	if err := workers.QueueCollectionItemWebhooks(ctx, &s.Repo, s.Client, r.CollectionID, r.ItemID); err != nil {
+		slog.ErrorContext(ctx, "failed to queue webhook deliveries",
+			slog.String("collection_id", r.CollectionID.String()),
+			slog.Any("error", err),
+		)
 	}
 	return &AddItemToCollectionResponse{
 		AddedAt: rec.AddedAt,
 	}, nil
 }
 
-// RemoveItemFromCollection removes an item from a collection.
+// RemoveItemFromCollection removes an item from a collection the user can
+// edit.
 func (s *Service) RemoveItemFromCollection(ctx context.Context, r repository.RemoveItemFromCollectionParams) error {
-	if err := s.Repo.RemoveItemFromCollection(ctx, r); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return NewError(
-				fmt.Sprintf("item %s is not in collection %s", r.ItemID, r.CollectionID),
-				http.StatusBadRequest,
-			)
-		} else {
-			return NewError(
-				fmt.Sprintf("failed to remove item %s from collection %s", r.ItemID, r.CollectionID),
-				http.StatusInternalServerError,
-			)
-		}
+	ctx, span := tracer.Start(ctx, "Service.RemoveItemFromCollection")
+	defer span.End()
+
+	col, _, err := s.accessCollection(ctx, r.UserID, r.CollectionID, CollectionRoleEditor)
+	if err != nil {
+		return err
+	}
+	r.UserID = col.UserID
+
+	removed, err := s.Repo.RemoveItemFromCollection(ctx, r)
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to remove item %s from collection %s", r.ItemID, r.CollectionID),
+			http.StatusInternalServerError,
+		)
+	}
+	if removed == 0 {
+		return NewError(
+			fmt.Sprintf("item %s is not in collection %s", r.ItemID, r.CollectionID),
+			http.StatusBadRequest,
+		)
 	}
 	return nil
 }
 
-// ListCollectionItems retrieves paginated items in a collection, including like status.
+// ListCollectionItems retrieves paginated items in a collection, including like
+// status and who added them. Items of smart collections are matched against
+// their rules.
 func (s *Service) ListCollectionItems(ctx context.Context, r ListCollectionItemsRequest) (*ListCollectionItemsResponse, error) {
-	total, err := s.Repo.CountItemsInCollection(ctx, r.CollectionID)
+	ctx, span := tracer.Start(ctx, "Service.ListCollectionItems")
+	defer span.End()
+
+	col, _, err := s.accessCollection(ctx, r.UserID, r.CollectionID, CollectionRoleViewer)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			total = 0
-		} else {
-			return nil, NewError(
-				fmt.Sprintf("failed counting items in collection %s", r.CollectionID),
-				http.StatusInternalServerError)
-		}
+		return nil, err
 	}
 
-	var rows []repository.ListItemsInCollectionRow
-
-	if total == 0 {
-		rows = make([]repository.ListItemsInCollectionRow, 0)
-	} else {
-		rows, err = s.Repo.ListItemsInCollection(ctx, repository.ListItemsInCollectionParams{
-			CollectionID: r.CollectionID,
-			Limit:        r.Limit,
-			Offset:       r.Offset,
-		})
-		if err != nil {
-			return nil, NewError(
-				fmt.Sprintf("failed to list items in colllection %s", r.CollectionID),
-				http.StatusInternalServerError,
-			)
-		}
+	total, rows, err := s.listCollectionRows(ctx, col, r.Limit, r.Offset)
+	if err != nil {
+		return nil, err
 	}
 
 	items := make([]Item, len(rows))
@@ -252,6 +554,12 @@ func (s *Service) ListCollectionItems(ctx context.Context, r ListCollectionItems
 			Liked:           liked,
 			LikedAt:         likedAt,
 		}
+		if col.Rules == nil {
+			items[i].AddedAt = &row.AddedAt
+		}
+		if row.AddedBy != nil && row.AddedByName != nil {
+			items[i].AddedBy = &UserSummary{ID: *row.AddedBy, Name: *row.AddedByName}
+		}
 	}
 
 	return &ListCollectionItemsResponse{
@@ -261,3 +569,84 @@ func (s *Service) ListCollectionItems(ctx context.Context, r ListCollectionItems
 		Items:      items,
 	}, nil
 }
+
+// GetCollectionByName retrieves a collection of the user by its name.
+func (s *Service) GetCollectionByName(ctx context.Context, r repository.GetCollectionByNameParams) (*Collection, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetCollectionByName")
+	defer span.End()
+
+	col, err := s.Repo.GetCollectionByName(ctx, r)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("collection %s not found", r.Name),
+				http.StatusNotFound,
+			)
+		} else {
+			return nil, NewError(
+				fmt.Sprintf("failed to fetch collection %s", r.Name),
+				http.StatusInternalServerError,
+			)
+		}
+	}
+	c := collectionFromRecord(col, CollectionRoleOwner)
+	return &c, nil
+}
+
+// exportPageSize is the number of items read at a time when exporting a
+// collection.
+const exportPageSize = 100
+
+// ExportCollection calls visit with every item of a collection the user can
+// read in the order they are listed. Items are read a page at a time, so visit
+// can stream them.
+func (s *Service) ExportCollection(ctx context.Context, r repository.GetCollectionByIDParams, visit func(ExportItem) error) error {
+	ctx, span := tracer.Start(ctx, "Service.ExportCollection")
+	defer span.End()
+
+	col, _, err := s.accessCollection(ctx, r.UserID, r.ID, CollectionRoleViewer)
+	if err != nil {
+		return err
+	}
+
+	feedTitles := make(map[uuid.UUID]*string)
+	for offset := int32(0); ; offset += exportPageSize {
+		_, rows, err := s.listCollectionRows(ctx, col, exportPageSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			feedTitle, ok := feedTitles[row.FeedID]
+			if !ok {
+				if feed, err := s.Repo.GetFeedByID(ctx, row.FeedID); err == nil {
+					feedTitle = feed.Title
+				}
+				feedTitles[row.FeedID] = feedTitle
+			}
+
+			auths := make(Authors, len(row.Authors))
+			for j, a := range row.Authors {
+				auths[j] = Person{Name: a.Name, Email: a.Email}
+			}
+			content := row.Content
+			if content == nil || *content == "" {
+				content = row.Description
+			}
+
+			err := visit(ExportItem{
+				Title:           row.Title,
+				Link:            row.Link,
+				FeedTitle:       feedTitle,
+				Authors:         auths,
+				PublishedParsed: row.PublishedParsed,
+				Content:         content,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if len(rows) < exportPageSize {
+			return nil
+		}
+	}
+}
@@ -5,25 +5,55 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
-	"github.com/hibiken/asynq"
+	"github.com/google/uuid"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/mmcdole/gofeed"
 	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/tracing"
 	"github.com/rhajizada/gazette/internal/typeext"
-	"github.com/rhajizada/gazette/internal/workers"
 )
 
 // ListFeeds retrieves a paginated list of feeds, optionally only subscribed.
 func (s *Service) ListFeeds(ctx context.Context, r ListFeedsRequest) (*ListFeedsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListFeeds")
+	defer span.End()
+
+	// a tag only applies to subscribed feeds
+	var tag *repository.Tag
+	if r.Tag != nil {
+		t, err := s.Repo.GetTagByName(ctx, repository.GetTagByNameParams{
+			UserID: r.UserID,
+			Name:   *r.Tag,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, NewError(
+					fmt.Sprintf("tag %s not found", *r.Tag),
+					http.StatusNotFound,
+				)
+			}
+			return nil, NewError(
+				fmt.Sprintf("failed to fetch tag %s", *r.Tag),
+				http.StatusInternalServerError,
+			)
+		}
+		tag = &t
+	}
+
 	// count
 	var total int64
 	var err error
-	if r.SubscbedOnly {
+	if tag != nil {
+		total, err = s.Repo.CountFeedsByTag(ctx, repository.CountFeedsByTagParams{
+			UserID: r.UserID,
+			TagID:  tag.ID,
+		})
+	} else if r.SubscbedOnly {
 		total, err = s.Repo.CountFeedsByUserID(ctx, r.UserID)
 	} else {
 		total, err = s.Repo.CountFeeds(ctx)
@@ -43,6 +73,23 @@ func (s *Service) ListFeeds(ctx context.Context, r ListFeedsRequest) (*ListFeeds
 
 	if total == 0 {
 		rows = make([]repository.ListFeedsByUserIDRow, 0)
+	} else if tag != nil {
+		tagged, err := s.Repo.ListFeedsByTag(ctx, repository.ListFeedsByTagParams{
+			UserID: r.UserID,
+			TagID:  tag.ID,
+			Limit:  r.Limit,
+			Offset: r.Offset,
+		})
+		if err != nil {
+			return nil, NewError(
+				"failed to list feeds",
+				http.StatusInternalServerError,
+			)
+		}
+		rows = make([]repository.ListFeedsByUserIDRow, len(tagged))
+		for i, row := range tagged {
+			rows[i] = repository.ListFeedsByUserIDRow(row)
+		}
 	} else {
 		rows, err = s.Repo.ListFeedsByUserID(ctx, repository.ListFeedsByUserIDParams{
 			UserID:  r.UserID,
@@ -58,6 +105,25 @@ func (s *Service) ListFeeds(ctx context.Context, r ListFeedsRequest) (*ListFeeds
 		}
 	}
 
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	tags, err := s.feedTagNames(ctx, r.UserID, ids)
+	if err != nil {
+		return nil, NewError(
+			"failed to list feed tags",
+			http.StatusInternalServerError,
+		)
+	}
+	subs, err := s.feedSubscriptions(ctx, r.UserID, ids)
+	if err != nil {
+		return nil, NewError(
+			"failed to list subscriptions",
+			http.StatusInternalServerError,
+		)
+	}
+
 	feeds := make([]Feed, len(rows))
 	for i, row := range rows {
 		auths := make(Authors, len(row.Authors))
@@ -86,6 +152,8 @@ func (s *Service) ListFeeds(ctx context.Context, r ListFeedsRequest) (*ListFeeds
 			LastUpdatedAt:   row.LastUpdatedAt,
 			Subscribed:      row.SubscribedAt != nil,
 			SubscribedAt:    row.SubscribedAt,
+			Tags:            tags[row.ID],
+			Subscription:    subs[row.ID],
 		}
 	}
 
@@ -99,6 +167,9 @@ func (s *Service) ListFeeds(ctx context.Context, r ListFeedsRequest) (*ListFeeds
 
 // ExportFeeds returns list of feed URLs, optionally only subscribed.
 func (s *Service) ExportFeeds(ctx context.Context, r ExportFeedsRequest) ([]string, error) {
+	ctx, span := tracer.Start(ctx, "Service.ExportFeeds")
+	defer span.End()
+
 	var feeds []string
 	var err error
 	feeds, err = s.Repo.ExportFeedsByUserID(ctx, repository.ExportFeedsByUserIDParams{
@@ -121,8 +192,12 @@ func (s *Service) ExportFeeds(ctx context.Context, r ExportFeedsRequest) ([]stri
 
 // CreateFeed creates a feed if needed, enqueues a sync task, and subscribes the user.
 func (s *Service) CreateFeed(ctx context.Context, r CreateFeedRequest) (*Feed, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateFeed")
+	defer span.End()
+
 	parser := gofeed.NewParser()
-	remote, err := parser.ParseURL(r.FeedURL)
+	parser.Client = tracing.HTTPClient
+	remote, err := parser.ParseURLWithContext(r.FeedURL, ctx)
 	if err != nil {
 		return nil, NewError(
 			fmt.Sprintf("invalid feed URL %s", r.FeedURL),
@@ -161,10 +236,11 @@ func (s *Service) CreateFeed(ctx context.Context, r CreateFeedRequest) (*Feed, e
 		)
 	}
 
-	task, _ := workers.NewSyncFeedTask(feed.ID)
-	_, err = s.Client.Enqueue(task, asynq.Queue("critical"))
-	if err != nil {
-		log.Printf("failed to queue sync task fo feed %s: %v", feed.ID, err)
+	if _, err := s.enqueueFeedSync(ctx, feed.ID); err != nil {
+		slog.ErrorContext(ctx, "failed to queue sync task",
+			slog.String("feed_id", feed.ID.String()),
+			slog.Any("error", err),
+		)
 	}
 
 	sub, err := s.Repo.CreateUserFeedSubscription(ctx, repository.CreateUserFeedSubscriptionParams{UserID: r.UserID, FeedID: feed.ID})
@@ -206,6 +282,9 @@ func (s *Service) CreateFeed(ctx context.Context, r CreateFeedRequest) (*Feed, e
 
 // GetFeed retrieves a feed and the user's subscription status.
 func (s *Service) GetFeed(ctx context.Context, r repository.GetUserFeedSubscriptionParams) (*Feed, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetFeed")
+	defer span.End()
+
 	feed, err := s.Repo.GetFeedByID(ctx, r.FeedID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -223,8 +302,19 @@ func (s *Service) GetFeed(ctx context.Context, r repository.GetUserFeedSubscript
 
 	// check subscription
 	subAt := (*time.Time)(nil)
+	var tags []string
+	var sub *Subscription
 	if uf, err := s.Repo.GetUserFeedSubscription(ctx, r); err == nil {
 		subAt = &uf.SubscribedAt
+		sub = subscriptionFromRecord(uf)
+		names, err := s.feedTagNames(ctx, r.UserID, []uuid.UUID{r.FeedID})
+		if err != nil {
+			return nil, NewError(
+				fmt.Sprintf("failed to list tags of feed %s", r.FeedID),
+				http.StatusInternalServerError,
+			)
+		}
+		tags = names[r.FeedID]
 	}
 
 	// map authors
@@ -252,11 +342,16 @@ func (s *Service) GetFeed(ctx context.Context, r repository.GetUserFeedSubscript
 		LastUpdatedAt:   feed.LastUpdatedAt,
 		Subscribed:      subAt != nil,
 		SubscribedAt:    subAt,
+		Tags:            tags,
+		Subscription:    sub,
 	}, nil
 }
 
 // DeleteFeed deletes a feed entirely.
 func (s *Service) DeleteFeed(ctx context.Context, r DeleteFeedRequest) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteFeed")
+	defer span.End()
+
 	err := s.Repo.DeleteFeedByID(ctx, r.FeedID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -276,6 +371,9 @@ func (s *Service) DeleteFeed(ctx context.Context, r DeleteFeedRequest) error {
 
 // SubscribeToFeed subscribes a user to a feed.
 func (s *Service) SubscribeToFeed(ctx context.Context, r repository.CreateUserFeedSubscriptionParams) (*SubscibeToFeedResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.SubscribeToFeed")
+	defer span.End()
+
 	sub, err := s.Repo.CreateUserFeedSubscription(ctx, r)
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -306,6 +404,9 @@ func (s *Service) SubscribeToFeed(ctx context.Context, r repository.CreateUserFe
 
 // UnsubscribeFromFeed removes a user's subscription.
 func (s *Service) UnsubscribeFromFeed(ctx context.Context, r repository.DeleteUserFeedSubscriptionParams) error {
+	ctx, span := tracer.Start(ctx, "Service.UnsubscribeFromFeed")
+	defer span.End()
+
 	err := s.Repo.DeleteUserFeedSubscription(ctx, r)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -324,8 +425,15 @@ func (s *Service) UnsubscribeFromFeed(ctx context.Context, r repository.DeleteUs
 }
 
 // ListItemsByFeedID returns paginated items from a feed, including per-user like status.
+// Items hidden from the user by their filter rules are left out.
 func (s *Service) ListItemsByFeedID(ctx context.Context, r repository.ListItemsByFeedIDForUserParams) (*ListItemsResponse, error) {
-	total, err := s.Repo.CountItemsByFeedID(ctx, r.FeedID)
+	ctx, span := tracer.Start(ctx, "Service.ListItemsByFeedID")
+	defer span.End()
+
+	total, err := s.Repo.CountItemsByFeedIDForUser(ctx, repository.CountItemsByFeedIDForUserParams{
+		FeedID: r.FeedID,
+		UserID: r.UserID,
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			total = 0
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"crypto/md5"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/repository"
+)
+
+// feverAPIKey returns the key Fever clients derive from the user's credentials.
+func feverAPIKey(email, password string) string {
+	sum := md5.Sum([]byte(email + ":" + password))
+	return hex.EncodeToString(sum[:])
+}
+
+// SetFeverPassword enables the Fever API for the user, replacing any previous password.
+func (s *Service) SetFeverPassword(ctx context.Context, r SetFeverPasswordRequest) (*FeverCredential, error) {
+	ctx, span := tracer.Start(ctx, "Service.SetFeverPassword")
+	defer span.End()
+
+	if r.Password == "" {
+		return nil, NewError("password is required", http.StatusBadRequest)
+	}
+
+	user, err := s.GetUserByID(ctx, r.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := s.Repo.UpsertFeverCredential(ctx, repository.UpsertFeverCredentialParams{
+		UserID: r.UserID,
+		ApiKey: hashSecret(feverAPIKey(user.Email, r.Password)),
+	})
+	if err != nil {
+		return nil, NewError("failed to set fever password", http.StatusInternalServerError)
+	}
+	return &FeverCredential{CreatedAt: rec.CreatedAt}, nil
+}
+
+// GetFeverCredential returns the Fever API access of the user.
+func (s *Service) GetFeverCredential(ctx context.Context, userID uuid.UUID) (*FeverCredential, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetFeverCredential")
+	defer span.End()
+
+	rec, err := s.Repo.GetFeverCredentialByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError("fever api is not enabled", http.StatusNotFound)
+		}
+		return nil, NewError("failed to fetch fever credentials", http.StatusInternalServerError)
+	}
+	return &FeverCredential{CreatedAt: rec.CreatedAt}, nil
+}
+
+// DeleteFeverPassword disables the Fever API for the user.
+func (s *Service) DeleteFeverPassword(ctx context.Context, userID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteFeverPassword")
+	defer span.End()
+
+	count, err := s.Repo.DeleteFeverCredential(ctx, userID)
+	if err != nil {
+		return NewError("failed to delete fever password", http.StatusInternalServerError)
+	}
+	if count == 0 {
+		return NewError("fever api is not enabled", http.StatusNotFound)
+	}
+	return nil
+}
+
+// AuthenticateFeverKey returns the ID of the user owning a Fever API key.
+func (s *Service) AuthenticateFeverKey(ctx context.Context, apiKey string) (uuid.UUID, error) {
+	ctx, span := tracer.Start(ctx, "Service.AuthenticateFeverKey")
+	defer span.End()
+
+	rec, err := s.Repo.GetFeverCredentialByAPIKey(ctx, hashSecret(strings.ToLower(apiKey)))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, NewError("invalid api key", http.StatusUnauthorized)
+		}
+		return uuid.Nil, NewError("failed to verify api key", http.StatusInternalServerError)
+	}
+
+	user, err := s.GetUserByID(ctx, rec.UserID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if err := checkUserActive(user); err != nil {
+		return uuid.Nil, err
+	}
+	return rec.UserID, nil
+}
+
+// ListSubscribedFeedRefs returns all feeds the user is subscribed to.
+func (s *Service) ListSubscribedFeedRefs(ctx context.Context, userID uuid.UUID) ([]FeedRef, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListSubscribedFeedRefs")
+	defer span.End()
+
+	rows, err := s.Repo.ListSubscribedFeedRefs(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to list feeds", http.StatusInternalServerError)
+	}
+
+	feeds := make([]FeedRef, len(rows))
+	for i, row := range rows {
+		feeds[i] = FeedRef{
+			ID:            row.ID,
+			Seq:           row.Seq,
+			Title:         row.Title,
+			Link:          row.Link,
+			FeedLink:      row.FeedLink,
+			LastUpdatedAt: row.LastUpdatedAt,
+		}
+	}
+	return feeds, nil
+}
+
+// ListItemsBySeqRange returns subscribed items after or before a sequence number.
+func (s *Service) ListItemsBySeqRange(ctx context.Context, r repository.ListItemsBySeqRangeParams) ([]StreamItem, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListItemsBySeqRange")
+	defer span.End()
+
+	rows, err := s.Repo.ListItemsBySeqRange(ctx, r)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to list items", http.StatusInternalServerError)
+	}
+
+	items := make([]StreamItem, len(rows))
+	for i, row := range rows {
+		items[i] = streamItemFromRow(repository.ListStreamItemsRow(row))
+	}
+	return items, nil
+}
+
+// CountSubscribedItems returns the number of items in feeds the user is subscribed to.
+func (s *Service) CountSubscribedItems(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ctx, span := tracer.Start(ctx, "Service.CountSubscribedItems")
+	defer span.End()
+
+	count, err := s.Repo.CountSubscribedItems(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, NewError("failed to count items", http.StatusInternalServerError)
+	}
+	return count, nil
+}
+
+// ListUnreadItemSeqs returns sequence numbers of unread subscribed items.
+func (s *Service) ListUnreadItemSeqs(ctx context.Context, userID uuid.UUID) ([]int64, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListUnreadItemSeqs")
+	defer span.End()
+
+	seqs, err := s.Repo.ListUnreadItemSeqs(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to list unread items", http.StatusInternalServerError)
+	}
+	return seqs, nil
+}
+
+// ListLikedItemSeqs returns sequence numbers of liked items.
+func (s *Service) ListLikedItemSeqs(ctx context.Context, userID uuid.UUID) ([]int64, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListLikedItemSeqs")
+	defer span.End()
+
+	seqs, err := s.Repo.ListLikedItemSeqs(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to list liked items", http.StatusInternalServerError)
+	}
+	return seqs, nil
+}
@@ -0,0 +1,418 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/filters"
+	"github.com/rhajizada/gazette/internal/repository"
+)
+
+const (
+	// maxFilterRules bounds the number of filter rules of a user.
+	maxFilterRules = 100
+
+	// maxFilterRuleNameLength bounds the length of a rule name in characters.
+	maxFilterRuleNameLength = 128
+
+	// filterRuleWindow is the number of recent items rules are previewed on,
+	// and hide rules applied to when saved.
+	filterRuleWindow = 500
+
+	// maxFilterRuleMatches bounds the matches listed by a preview.
+	maxFilterRuleMatches = 50
+)
+
+func filterRuleFromRecord(rec repository.FilterRule) FilterRule {
+	return FilterRule{
+		ID:           rec.ID,
+		Name:         rec.Name,
+		FeedID:       rec.FeedID,
+		Field:        rec.Field,
+		MatchType:    rec.MatchType,
+		Pattern:      rec.Pattern,
+		Action:       rec.Action,
+		CollectionID: rec.CollectionID,
+		Enabled:      rec.Enabled,
+		HitCount:     rec.HitCount,
+		LastHitAt:    rec.LastHitAt,
+		CreatedAt:    rec.CreatedAt,
+		UpdatedAt:    rec.UpdatedAt,
+	}
+}
+
+// compileFilterRule normalizes the definition of a rule and compiles its
+// pattern.
+func compileFilterRule(r *FilterRuleRequest) (*filters.Matcher, error) {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		r.Name = r.Pattern
+	}
+	if utf8.RuneCountInString(r.Name) > maxFilterRuleNameLength {
+		return nil, NewError(
+			fmt.Sprintf("name must be at most %d characters", maxFilterRuleNameLength),
+			http.StatusBadRequest,
+		)
+	}
+	if r.Field == "" {
+		r.Field = filters.FieldAny
+	}
+	m, err := filters.Compile(r.Field, r.MatchType, r.Pattern)
+	if err != nil {
+		return nil, NewError(err.Error(), http.StatusBadRequest)
+	}
+	if !filters.ValidAction(r.Action) {
+		return nil, NewError(
+			fmt.Sprintf("invalid action %s, expected %s, %s or %s", r.Action,
+				filters.ActionHide, filters.ActionMarkRead, filters.ActionAddToCollection),
+			http.StatusBadRequest,
+		)
+	}
+	if (r.Action == filters.ActionAddToCollection) != (r.CollectionID != nil) {
+		return nil, NewError(
+			fmt.Sprintf("collection_id must be set exactly when action is %s", filters.ActionAddToCollection),
+			http.StatusBadRequest,
+		)
+	}
+	if r.Enabled == nil {
+		enabled := true
+		r.Enabled = &enabled
+	}
+	return m, nil
+}
+
+// checkFilterRuleTargets checks that the user is subscribed to the feed of a
+// rule and can add items to its collection.
+func (s *Service) checkFilterRuleTargets(ctx context.Context, r FilterRuleRequest) error {
+	if r.FeedID != nil {
+		_, err := s.Repo.GetUserFeedSubscription(ctx, repository.GetUserFeedSubscriptionParams{
+			UserID: r.UserID,
+			FeedID: *r.FeedID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return NewError(
+					fmt.Sprintf("user not subscribed to feed %s", *r.FeedID),
+					http.StatusBadRequest,
+				)
+			}
+			return NewError(
+				fmt.Sprintf("failed to fetch subscription to feed %s", *r.FeedID),
+				http.StatusInternalServerError,
+			)
+		}
+	}
+	if r.CollectionID != nil {
+		col, _, err := s.accessCollection(ctx, r.UserID, *r.CollectionID, CollectionRoleEditor)
+		if err != nil {
+			return err
+		}
+		if err := requireManual(col); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getFilterRule fetches a filter rule of the user.
+func (s *Service) getFilterRule(ctx context.Context, r repository.GetFilterRuleParams) (*repository.FilterRule, error) {
+	rec, err := s.Repo.GetFilterRule(ctx, r)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("filter rule %s not found", r.ID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to fetch filter rule %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	return &rec, nil
+}
+
+// matchRecentItems matches a rule against the most recent items of the
+// subscriptions of the user, calling match with every item it matches, and
+// returns the number of items scanned.
+func (s *Service) matchRecentItems(ctx context.Context, repo *repository.Queries, userID uuid.UUID, feedID *uuid.UUID, m *filters.Matcher, match func(repository.ListRecentItemsForFiltersRow)) (int, error) {
+	rows, err := repo.ListRecentItemsForFilters(ctx, repository.ListRecentItemsForFiltersParams{
+		UserID: userID,
+		FeedID: feedID,
+		Limit:  filterRuleWindow,
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range rows {
+		item := filters.Item{
+			Link:       row.Link,
+			Authors:    filters.AuthorNames(row.Authors),
+			Categories: row.Categories,
+		}
+		if row.Title != nil {
+			item.Title = *row.Title
+		}
+		if row.Description != nil {
+			item.Description = *row.Description
+		}
+		if row.Content != nil {
+			item.Content = *row.Content
+		}
+		if m.Match(item) {
+			match(row)
+		}
+	}
+	return len(rows), nil
+}
+
+// applyHideRule hides the recent items a hide rule matches, so that a new
+// rule also cleans up what was already synced. Items synced later are
+// hidden by the sync itself.
+func (s *Service) applyHideRule(ctx context.Context, repo *repository.Queries, rec repository.FilterRule, m *filters.Matcher) error {
+	if rec.Action != filters.ActionHide || !rec.Enabled {
+		return nil
+	}
+	var ids []uuid.UUID
+	_, err := s.matchRecentItems(ctx, repo, rec.UserID, rec.FeedID, m, func(row repository.ListRecentItemsForFiltersRow) {
+		ids = append(ids, row.ID)
+	})
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+	hidden, err := repo.HideItems(ctx, repository.HideItemsParams{
+		UserID:  rec.UserID,
+		RuleID:  rec.ID,
+		ItemIds: ids,
+	})
+	if err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "applied hide rule",
+		slog.String("rule_id", rec.ID.String()),
+		slog.Int64("hidden_items", hidden),
+	)
+	return nil
+}
+
+// CreateFilterRule creates a filter rule of the user. A hide rule also hides
+// the recent items it matches, the rule is only created if they are hidden.
+func (s *Service) CreateFilterRule(ctx context.Context, r FilterRuleRequest) (*FilterRule, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateFilterRule")
+	defer span.End()
+
+	m, err := compileFilterRule(&r)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkFilterRuleTargets(ctx, r); err != nil {
+		return nil, err
+	}
+	existing, err := s.Repo.ListFilterRules(ctx, r.UserID)
+	if err != nil {
+		return nil, NewError("failed to list filter rules", http.StatusInternalServerError)
+	}
+	if len(existing) >= maxFilterRules {
+		return nil, NewError(
+			fmt.Sprintf("users can have at most %d filter rules", maxFilterRules),
+			http.StatusBadRequest,
+		)
+	}
+
+	var rec repository.FilterRule
+	err = s.withTx(ctx, func(repo *repository.Queries) error {
+		var err error
+		rec, err = repo.CreateFilterRule(ctx, repository.CreateFilterRuleParams{
+			UserID:       r.UserID,
+			Name:         r.Name,
+			FeedID:       r.FeedID,
+			Field:        r.Field,
+			MatchType:    r.MatchType,
+			Pattern:      r.Pattern,
+			Action:       r.Action,
+			CollectionID: r.CollectionID,
+			Enabled:      *r.Enabled,
+		})
+		if err != nil {
+			return NewError("failed to create filter rule", http.StatusInternalServerError)
+		}
+		if err := s.applyHideRule(ctx, repo, rec, m); err != nil {
+			return NewError("failed to apply filter rule", http.StatusInternalServerError)
+		}
+		return nil
+	})
+	if err != nil {
+		var serviceErr ServiceError
+		if errors.As(err, &serviceErr) {
+			return nil, err
+		}
+		return nil, NewError("failed to create filter rule", http.StatusInternalServerError)
+	}
+	rule := filterRuleFromRecord(rec)
+	return &rule, nil
+}
+
+// GetFilterRule retrieves a filter rule of the user.
+func (s *Service) GetFilterRule(ctx context.Context, r repository.GetFilterRuleParams) (*FilterRule, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetFilterRule")
+	defer span.End()
+
+	rec, err := s.getFilterRule(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	rule := filterRuleFromRecord(*rec)
+	return &rule, nil
+}
+
+// ListFilterRules lists the filter rules of the user, oldest first.
+func (s *Service) ListFilterRules(ctx context.Context, userID uuid.UUID) (*ListFilterRulesResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListFilterRules")
+	defer span.End()
+
+	recs, err := s.Repo.ListFilterRules(ctx, userID)
+	if err != nil {
+		return nil, NewError("failed to list filter rules", http.StatusInternalServerError)
+	}
+	rules := make([]FilterRule, len(recs))
+	for i, rec := range recs {
+		rules[i] = filterRuleFromRecord(rec)
+	}
+	return &ListFilterRulesResponse{Rules: rules}, nil
+}
+
+// UpdateFilterRule replaces the definition of a filter rule of the user. The
+// items the rule hid are shown again and, for an enabled hide rule, the
+// recent items it now matches hidden.
+func (s *Service) UpdateFilterRule(ctx context.Context, r UpdateFilterRuleRequest) (*FilterRule, error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateFilterRule")
+	defer span.End()
+
+	m, err := compileFilterRule(&r.FilterRuleRequest)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.getFilterRule(ctx, repository.GetFilterRuleParams{ID: r.RuleID, UserID: r.UserID}); err != nil {
+		return nil, err
+	}
+	if err := s.checkFilterRuleTargets(ctx, r.FilterRuleRequest); err != nil {
+		return nil, err
+	}
+
+	// the hides of the rule are replaced along with it
+	var rec repository.FilterRule
+	err = s.withTx(ctx, func(repo *repository.Queries) error {
+		var err error
+		rec, err = repo.UpdateFilterRule(ctx, repository.UpdateFilterRuleParams{
+			Name:         r.Name,
+			FeedID:       r.FeedID,
+			Field:        r.Field,
+			MatchType:    r.MatchType,
+			Pattern:      r.Pattern,
+			Action:       r.Action,
+			CollectionID: r.CollectionID,
+			Enabled:      *r.Enabled,
+			ID:           r.RuleID,
+			UserID:       r.UserID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return NewError(
+					fmt.Sprintf("filter rule %s not found", r.RuleID),
+					http.StatusNotFound,
+				)
+			}
+			return NewError(
+				fmt.Sprintf("failed to update filter rule %s", r.RuleID),
+				http.StatusInternalServerError,
+			)
+		}
+		if err := repo.DeleteFilterRuleHides(ctx, rec.ID); err != nil {
+			return NewError(
+				fmt.Sprintf("failed to apply filter rule %s", rec.ID),
+				http.StatusInternalServerError,
+			)
+		}
+		if err := s.applyHideRule(ctx, repo, rec, m); err != nil {
+			return NewError(
+				fmt.Sprintf("failed to apply filter rule %s", rec.ID),
+				http.StatusInternalServerError,
+			)
+		}
+		return nil
+	})
+	if err != nil {
+		var serviceErr ServiceError
+		if errors.As(err, &serviceErr) {
+			return nil, err
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to update filter rule %s", r.RuleID),
+			http.StatusInternalServerError,
+		)
+	}
+	rule := filterRuleFromRecord(rec)
+	return &rule, nil
+}
+
+// DeleteFilterRule deletes a filter rule of the user, showing the items it
+// hid again.
+func (s *Service) DeleteFilterRule(ctx context.Context, r repository.DeleteFilterRuleParams) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteFilterRule")
+	defer span.End()
+
+	deleted, err := s.Repo.DeleteFilterRule(ctx, r)
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to delete filter rule %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	if deleted == 0 {
+		return NewError(
+			fmt.Sprintf("filter rule %s not found", r.ID),
+			http.StatusNotFound,
+		)
+	}
+	return nil
+}
+
+// PreviewFilterRule reports which recent items of the subscriptions of the
+// user a rule would match, without saving it or running its action.
+func (s *Service) PreviewFilterRule(ctx context.Context, r FilterRuleRequest) (*PreviewFilterRuleResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.PreviewFilterRule")
+	defer span.End()
+
+	m, err := compileFilterRule(&r)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkFilterRuleTargets(ctx, r); err != nil {
+		return nil, err
+	}
+
+	resp := PreviewFilterRuleResponse{Items: make([]FilterRuleMatch, 0)}
+	resp.Scanned, err = s.matchRecentItems(ctx, &s.Repo, r.UserID, r.FeedID, m, func(row repository.ListRecentItemsForFiltersRow) {
+		resp.Matched++
+		if len(resp.Items) < maxFilterRuleMatches {
+			resp.Items = append(resp.Items, FilterRuleMatch{
+				ID:              row.ID,
+				FeedID:          row.FeedID,
+				Title:           row.Title,
+				Link:            row.Link,
+				PublishedParsed: row.PublishedParsed,
+			})
+		}
+	})
+	if err != nil {
+		return nil, NewError("failed to list recent items", http.StatusInternalServerError)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rhajizada/gazette/internal/repository"
+)
+
+func identityFromRecord(rec repository.UserIdentity) UserIdentity {
+	return UserIdentity{
+		ID:            rec.ID,
+		Provider:      rec.Provider,
+		Issuer:        rec.Issuer,
+		Email:         rec.Email,
+		EmailVerified: rec.EmailVerified,
+		CreatedAt:     rec.CreatedAt,
+		LastLoginAt:   rec.LastLoginAt,
+	}
+}
+
+func optionalString(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+// SignIn resolves the user for an identity asserted by a provider. Known
+// identities sign in to their user, users created before identities were
+// tracked are claimed by subject, anyone else gets a new account.
+func (s *Service) SignIn(ctx context.Context, r SignInRequest) (*User, error) {
+	ctx, span := tracer.Start(ctx, "Service.SignIn")
+	defer span.End()
+
+	identity, err := s.Repo.GetUserIdentityByIssuerSub(ctx, repository.GetUserIdentityByIssuerSubParams{
+		Issuer: r.Issuer,
+		Sub:    r.Sub,
+	})
+	if err == nil {
+		if err := s.Repo.TouchUserIdentity(ctx, repository.TouchUserIdentityParams{
+			ID:            identity.ID,
+			Provider:      r.Provider,
+			Email:         optionalString(r.Email),
+			EmailVerified: r.EmailVerified && r.Email != "",
+		}); err != nil {
+			return nil, NewError("failed to update identity", http.StatusInternalServerError)
+		}
+		user, err := s.GetUserByID(ctx, identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkUserActive(user); err != nil {
+			return nil, err
+		}
+		return s.syncUserRole(ctx, user, r.Role)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to fetch identity", http.StatusInternalServerError)
+	}
+
+	// subjects are only unique per issuer, so an account without identity is
+	// only claimed by its subject when it comes from the legacy issuer
+	var user repository.User
+	err = sql.ErrNoRows
+	if r.LegacyIssuer != "" && r.Issuer == r.LegacyIssuer {
+		user, err = s.Repo.GetUnlinkedUserBySub(ctx, r.Sub)
+	}
+	created := false
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError("failed to fetch user", http.StatusInternalServerError)
+		}
+		user, err = s.Repo.CreateUser(ctx, repository.CreateUserParams{
+			Sub:   r.Sub,
+			Name:  r.Name,
+			Email: r.Email,
+			Role:  r.Role,
+		})
+		if err != nil {
+			return nil, NewError("failed to create user", http.StatusInternalServerError)
+		}
+		created = true
+	}
+
+	_, err = s.Repo.CreateUserIdentity(ctx, repository.CreateUserIdentityParams{
+		UserID:        user.ID,
+		Provider:      r.Provider,
+		Issuer:        r.Issuer,
+		Sub:           r.Sub,
+		Email:         optionalString(r.Email),
+		EmailVerified: r.EmailVerified && r.Email != "",
+	})
+	if err != nil {
+		if created {
+			if err := s.Repo.DeleteUserByID(ctx, user.ID); err != nil {
+				slog.ErrorContext(ctx, "failed to delete user without identity",
+					slog.String("user_id", user.ID.String()),
+					slog.Any("error", err),
+				)
+			}
+		}
+		return nil, NewError("failed to create identity", http.StatusInternalServerError)
+	}
+
+	if created {
+		return userFromRecord(user), nil
+	}
+	if err := checkUserActive(userFromRecord(user)); err != nil {
+		return nil, err
+	}
+	return s.syncUserRole(ctx, userFromRecord(user), r.Role)
+}
+
+// syncUserRole updates the role of a user to the one granted by the
+// provider at login.
+func (s *Service) syncUserRole(ctx context.Context, user *User, role string) (*User, error) {
+	if user.Role == role {
+		return user, nil
+	}
+
+	if err := s.Repo.UpdateUserRole(ctx, repository.UpdateUserRoleParams{
+		ID:   user.ID,
+		Role: role,
+	}); err != nil {
+		return nil, NewError("failed to update user role", http.StatusInternalServerError)
+	}
+	user.Role = role
+	return user, nil
+}
+
+// LinkIdentity links a provider identity to an existing user.
+func (s *Service) LinkIdentity(ctx context.Context, r LinkIdentityRequest) (*UserIdentity, error) {
+	ctx, span := tracer.Start(ctx, "Service.LinkIdentity")
+	defer span.End()
+
+	identity, err := s.Repo.GetUserIdentityByIssuerSub(ctx, repository.GetUserIdentityByIssuerSubParams{
+		Issuer: r.Issuer,
+		Sub:    r.Sub,
+	})
+	if err == nil {
+		if identity.UserID != r.UserID {
+			return nil, NewError("identity is linked to another user", http.StatusConflict)
+		}
+		resp := identityFromRecord(identity)
+		return &resp, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to fetch identity", http.StatusInternalServerError)
+	}
+
+	identity, err = s.Repo.CreateUserIdentity(ctx, repository.CreateUserIdentityParams{
+		UserID:        r.UserID,
+		Provider:      r.Provider,
+		Issuer:        r.Issuer,
+		Sub:           r.Sub,
+		Email:         optionalString(r.Email),
+		EmailVerified: r.EmailVerified && r.Email != "",
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return nil, NewError("identity is linked to another user", http.StatusConflict)
+		}
+		return nil, NewError("failed to link identity", http.StatusInternalServerError)
+	}
+
+	resp := identityFromRecord(identity)
+	return &resp, nil
+}
+
+// ListUserIdentities lists identities linked to a user.
+func (s *Service) ListUserIdentities(ctx context.Context, userID uuid.UUID) (*ListUserIdentitiesResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListUserIdentities")
+	defer span.End()
+
+	recs, err := s.Repo.ListUserIdentitiesByUserID(ctx, userID)
+	if err != nil {
+		return nil, NewError("failed to list identities", http.StatusInternalServerError)
+	}
+
+	identities := make([]UserIdentity, len(recs))
+	for i, rec := range recs {
+		identities[i] = identityFromRecord(rec)
+	}
+	return &ListUserIdentitiesResponse{Identities: identities}, nil
+}
+
+// UnlinkIdentity removes an identity from a user, the last identity is kept
+// so the user can still sign in.
+func (s *Service) UnlinkIdentity(ctx context.Context, r repository.DeleteUserIdentityParams) error {
+	ctx, span := tracer.Start(ctx, "Service.UnlinkIdentity")
+	defer span.End()
+
+	count, err := s.Repo.CountUserIdentitiesByUserID(ctx, r.UserID)
+	if err != nil {
+		return NewError("failed to count identities", http.StatusInternalServerError)
+	}
+	if count <= 1 {
+		return NewError("cannot unlink the last identity", http.StatusConflict)
+	}
+
+	rows, err := s.Repo.DeleteUserIdentity(ctx, r)
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to unlink identity %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	if rows == 0 {
+		return NewError(
+			fmt.Sprintf("identity %s not found", r.ID),
+			http.StatusNotFound,
+		)
+	}
+	return nil
+}
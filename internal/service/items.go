@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/rhajizada/gazette/internal/repository"
@@ -15,6 +16,9 @@ import (
 
 // ListUserLikedItems returns paginated items the user has liked, with liked timestamps.
 func (s *Service) ListUserLikedItems(ctx context.Context, r repository.ListUserLikedItemsParams) (*ListItemsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListUserLikedItems")
+	defer span.End()
+
 	total, err := s.Repo.CountLikedItems(ctx, r.UserID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -79,6 +83,9 @@ func (s *Service) ListUserLikedItems(ctx context.Context, r repository.ListUserL
 
 // GetItem retrieves a single item and its like status for the user.
 func (s *Service) GetItem(ctx context.Context, r GetItemRequest) (*Item, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetItem")
+	defer span.End()
+
 	// fetch the item
 	row, err := s.Repo.GetItemByID(ctx, r.ItemID)
 	if err != nil {
@@ -103,6 +110,14 @@ func (s *Service) GetItem(ctx context.Context, r GetItemRequest) (*Item, error)
 		likedAt = &like.LikedAt
 	}
 
+	tags, err := s.itemTagNames(ctx, r.UserID, []uuid.UUID{r.ItemID})
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to list tags of item %s", r.ItemID),
+			http.StatusInternalServerError,
+		)
+	}
+
 	// map authors
 	auths := make(Authors, len(row.Authors))
 	for j, a := range row.Authors {
@@ -128,12 +143,16 @@ func (s *Service) GetItem(ctx context.Context, r GetItemRequest) (*Item, error)
 		UpdatedAt:       row.UpdatedAt,
 		Liked:           liked,
 		LikedAt:         likedAt,
+		Tags:            tags[r.ItemID],
 	}
 	return &item, nil
 }
 
 // LikeItem marks an item as liked by the user.
 func (s *Service) LikeItem(ctx context.Context, r repository.CreateUserLikeParams) (*LikeItemResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.LikeItem")
+	defer span.End()
+
 	like, err := s.Repo.CreateUserLike(ctx, r)
 	if err != nil {
 		var pgErr *pgconn.PgError
@@ -153,6 +172,9 @@ func (s *Service) LikeItem(ctx context.Context, r repository.CreateUserLikeParam
 
 // UnlikeItem removes a like from an item.
 func (s *Service) UnlikeItem(ctx context.Context, r repository.DeleteUserLikeParams) error {
+	ctx, span := tracer.Start(ctx, "Service.UnlikeItem")
+	defer span.End()
+
 	if err := s.Repo.DeleteUserLike(ctx, r); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return NewError(
@@ -170,6 +192,9 @@ func (s *Service) UnlikeItem(ctx context.Context, r repository.DeleteUserLikePar
 
 // ListItemCollections returns list of collections that item is in.
 func (s *Service) ListItemCollections(ctx context.Context, r repository.ListCollectionsByItemIDParams) (*ListCollectionsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListItemCollections")
+	defer span.End()
+
 	total, err := s.Repo.CountCollectionsByItemID(ctx, repository.CountCollectionsByItemIDParams{
 		ItemID: r.ItemID,
 		UserID: r.UserID,
@@ -185,9 +210,9 @@ func (s *Service) ListItemCollections(ctx context.Context, r repository.ListColl
 		}
 	}
 
-	var rows []repository.Collection
+	var rows []repository.ListCollectionsByItemIDRow
 	if total == 0 {
-		rows = []repository.Collection{}
+		rows = []repository.ListCollectionsByItemIDRow{}
 	} else {
 		rows, err = s.Repo.ListCollectionsByItemID(ctx, r)
 		if err != nil {
@@ -200,12 +225,7 @@ func (s *Service) ListItemCollections(ctx context.Context, r repository.ListColl
 
 	cols := make([]Collection, len(rows))
 	for i, row := range rows {
-		cols[i] = Collection{
-			ID:          row.ID,
-			Name:        row.Name,
-			CreatedAt:   row.CreatedAt,
-			LastUpdated: row.LastUpdated,
-		}
+		cols[i] = collectionFromRecord(row.Collection, row.Role)
 	}
 
 	return &ListCollectionsResponse{
@@ -215,3 +235,165 @@ func (s *Service) ListItemCollections(ctx context.Context, r repository.ListColl
 		Collections: cols,
 	}, nil
 }
+
+// MarkItemRead marks an item as read by the user.
+func (s *Service) MarkItemRead(ctx context.Context, r repository.CreateUserReadParams) error {
+	ctx, span := tracer.Start(ctx, "Service.MarkItemRead")
+	defer span.End()
+
+	if err := s.Repo.CreateUserRead(ctx, r); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return NewError(
+				fmt.Sprintf("item %s not found", r.ItemID),
+				http.StatusNotFound,
+			)
+		}
+		return NewError(
+			fmt.Sprintf("failed to mark item %s as read", r.ItemID),
+			http.StatusInternalServerError,
+		)
+	}
+	return nil
+}
+
+// MarkItemUnread removes the read mark of an item.
+func (s *Service) MarkItemUnread(ctx context.Context, r repository.DeleteUserReadParams) error {
+	ctx, span := tracer.Start(ctx, "Service.MarkItemUnread")
+	defer span.End()
+
+	if err := s.Repo.DeleteUserRead(ctx, r); err != nil {
+		return NewError(
+			fmt.Sprintf("failed to mark item %s as unread", r.ItemID),
+			http.StatusInternalServerError,
+		)
+	}
+	return nil
+}
+
+// MarkStreamRead marks every item of a stream published before a cutoff as read.
+func (s *Service) MarkStreamRead(ctx context.Context, r repository.MarkStreamItemsReadParams) (int64, error) {
+	ctx, span := tracer.Start(ctx, "Service.MarkStreamRead")
+	defer span.End()
+
+	count, err := s.Repo.MarkStreamItemsRead(ctx, r)
+	if err != nil {
+		return 0, NewError("failed to mark items as read", http.StatusInternalServerError)
+	}
+	return count, nil
+}
+
+// ListStreamItems returns a page of items matching the stream filter, with read and like state.
+func (s *Service) ListStreamItems(ctx context.Context, r repository.ListStreamItemsParams) ([]StreamItem, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListStreamItems")
+	defer span.End()
+
+	rows, err := s.Repo.ListStreamItems(ctx, r)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to list items", http.StatusInternalServerError)
+	}
+
+	items := make([]StreamItem, len(rows))
+	for i, row := range rows {
+		items[i] = streamItemFromRow(row)
+	}
+	return items, nil
+}
+
+// ListStreamItemsBySeqs returns items with the given sequence numbers, with read and like state.
+func (s *Service) ListStreamItemsBySeqs(ctx context.Context, r repository.ListStreamItemsBySeqsParams) ([]StreamItem, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListStreamItemsBySeqs")
+	defer span.End()
+
+	rows, err := s.Repo.ListStreamItemsBySeqs(ctx, r)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to list items", http.StatusInternalServerError)
+	}
+
+	items := make([]StreamItem, len(rows))
+	for i, row := range rows {
+		items[i] = streamItemFromRow(repository.ListStreamItemsRow(row))
+	}
+	return items, nil
+}
+
+// ListStreamItemRefs returns sequence numbers and crawl times of items matching the stream filter.
+func (s *Service) ListStreamItemRefs(ctx context.Context, r repository.ListStreamItemRefsParams) ([]repository.ListStreamItemRefsRow, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListStreamItemRefs")
+	defer span.End()
+
+	rows, err := s.Repo.ListStreamItemRefs(ctx, r)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to list items", http.StatusInternalServerError)
+	}
+	if rows == nil {
+		rows = make([]repository.ListStreamItemRefsRow, 0)
+	}
+	return rows, nil
+}
+
+// ResolveItemSeqs maps item sequence numbers to item IDs, unknown numbers are skipped.
+func (s *Service) ResolveItemSeqs(ctx context.Context, seqs []int64) ([]uuid.UUID, error) {
+	ctx, span := tracer.Start(ctx, "Service.ResolveItemSeqs")
+	defer span.End()
+
+	rows, err := s.Repo.ListItemIDsBySeqs(ctx, seqs)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to resolve items", http.StatusInternalServerError)
+	}
+
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	return ids, nil
+}
+
+// CountUnreadItems returns the number of unread items per subscribed feed.
+func (s *Service) CountUnreadItems(ctx context.Context, userID uuid.UUID) ([]UnreadCount, error) {
+	ctx, span := tracer.Start(ctx, "Service.CountUnreadItems")
+	defer span.End()
+
+	rows, err := s.Repo.CountUnreadItemsByFeed(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to count unread items", http.StatusInternalServerError)
+	}
+
+	counts := make([]UnreadCount, len(rows))
+	for i, row := range rows {
+		counts[i] = UnreadCount{
+			FeedID:       row.FeedID,
+			Count:        row.Count,
+			NewestItemAt: row.NewestItemAt,
+		}
+	}
+	return counts, nil
+}
+
+func streamItemFromRow(row repository.ListStreamItemsRow) StreamItem {
+	auths := make(Authors, len(row.Authors))
+	for j, a := range row.Authors {
+		auths[j] = Person{Name: a.Name, Email: a.Email}
+	}
+
+	return StreamItem{
+		Item: Item{
+			ID:              row.ID,
+			FeedID:          row.FeedID,
+			Title:           row.Title,
+			Description:     row.Description,
+			Content:         row.Content,
+			Link:            row.Link,
+			PublishedParsed: row.PublishedParsed,
+			Authors:         auths,
+			Categories:      row.Categories,
+			CreatedAt:       row.CreatedAt,
+			UpdatedAt:       row.UpdatedAt,
+			Liked:           row.Liked,
+		},
+		Seq:          row.Seq,
+		FeedTitle:    row.FeedTitle,
+		FeedSiteLink: row.FeedSiteLink,
+		Read:         row.Read,
+	}
+}
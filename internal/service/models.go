@@ -1,6 +1,7 @@
 package service
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,8 +12,10 @@ import (
 type ListFeedsRequest struct {
 	UserID       uuid.UUID
 	SubscbedOnly bool
-	Offset       int32
-	Limit        int32
+	// Tag limits the list to subscribed feeds with the tag of that name.
+	Tag    *string
+	Offset int32
+	Limit  int32
 }
 
 // ExportFeedsRequest wraps parameters for exporting feeds.
@@ -85,6 +88,41 @@ type Feed struct {
 	LastUpdatedAt   time.Time  `json:"last_updated_at"`
 	Subscribed      bool       `json:"subscribed"`
 	SubscribedAt    *time.Time `json:"subscribed_at,omitempty"`
+	Tags            []string   `json:"tags,omitempty"`
+	// Subscription holds the settings of the user for the feed, set when
+	// subscribed.
+	Subscription *Subscription `json:"subscription,omitempty"`
+}
+
+// Subscription holds the settings of a user for a feed they are subscribed
+// to.
+type Subscription struct {
+	// Folder files the feed in a folder, taking precedence over its tags.
+	Folder *string `json:"folder,omitempty"`
+	// Title and Icon override those of the feed for the user.
+	Title *string `json:"title,omitempty"`
+	Icon  *string `json:"icon,omitempty"`
+	// Notify is none or all, none leaves new items of the feed out of
+	// feed.item webhooks.
+	Notify string `json:"notify"`
+	// SortOrder is newest or oldest, the order items of the feed are listed.
+	SortOrder string `json:"sort_order"`
+	// Muted leaves items of the feed out of the reading list and tag
+	// timelines.
+	Muted bool `json:"muted"`
+}
+
+// UpdateSubscriptionRequest changes the settings of UserID for FeedID. Nil
+// fields are left unchanged, an empty Folder, Title or Icon clears it.
+type UpdateSubscriptionRequest struct {
+	UserID    uuid.UUID
+	FeedID    uuid.UUID
+	Folder    *string
+	Title     *string
+	Icon      *string
+	Notify    *string
+	SortOrder *string
+	Muted     *bool
 }
 
 // ListItemsByFeedIDRequest wraps parameters for listing items from a feed with user-specific like info.
@@ -119,6 +157,18 @@ type Item struct {
 	UpdatedAt       time.Time  `json:"updated_at"`
 	Liked           bool       `json:"liked"`
 	LikedAt         *time.Time `json:"liked_at,omitempty"`
+	// AddedAt and AddedBy are set when listing the items of a collection.
+	AddedAt *time.Time   `json:"added_at,omitempty"`
+	AddedBy *UserSummary `json:"added_by,omitempty"`
+	// Tags are the tags of the user on the item, set for single items.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// UserSummary identifies another user, such as the member of a collection
+// who added an item.
+type UserSummary struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
 }
 
 // ListItemsResponse wraps paginated items
@@ -136,15 +186,174 @@ type LikeItemResponse struct {
 
 // Collection represents a user's collection of items
 type Collection struct {
-	ID          uuid.UUID `json:"id"`
-	Name        string    `json:"name"`
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Description *string          `json:"description"`
+	Icon        *string          `json:"icon"`
+	Color       *string          `json:"color"`
+	Pinned      bool             `json:"pinned"`
+	Position    *int32           `json:"position"`
+	Public      bool             `json:"public"`
+	Slug        *string          `json:"slug,omitempty"`
+	Smart       bool             `json:"smart"`
+	Rules       *CollectionRules `json:"rules,omitempty"`
+	OwnerID     uuid.UUID        `json:"owner_id"`
+	// Role is the role of the user in the collection, owner, editor or
+	// viewer.
+	Role        string    `json:"role"`
 	CreatedAt   time.Time `json:"created_at"`
 	LastUpdated time.Time `json:"last_updated"`
 }
 
+// CollectionMember is a member of a collection.
+type CollectionMember struct {
+	UserID     uuid.UUID  `json:"user_id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	Role       string     `json:"role"`
+	InvitedAt  time.Time  `json:"invited_at"`
+	AcceptedAt *time.Time `json:"accepted_at"`
+}
+
+// CollectionMemberInvitation is an invitation to a collection that is not
+// accepted yet.
+type CollectionMemberInvitation struct {
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	InvitedAt time.Time `json:"invited_at"`
+}
+
+// ListCollectionMembersResponse lists the members of a collection and its
+// pending invitations.
+type ListCollectionMembersResponse struct {
+	Members     []CollectionMember           `json:"members"`
+	Invitations []CollectionMemberInvitation `json:"invitations"`
+}
+
+// InviteCollectionMemberRequest invites Email to a collection of UserID.
+type InviteCollectionMemberRequest struct {
+	UserID       uuid.UUID
+	CollectionID uuid.UUID
+	Email        string
+	Role         string
+}
+
+// UpdateCollectionMemberRequest changes the role of a member of a collection
+// of UserID.
+type UpdateCollectionMemberRequest struct {
+	UserID       uuid.UUID
+	CollectionID uuid.UUID
+	MemberID     uuid.UUID
+	Role         string
+}
+
+// WithdrawCollectionInvitationRequest withdraws the invitation of Email to a
+// collection of UserID.
+type WithdrawCollectionInvitationRequest struct {
+	UserID       uuid.UUID
+	CollectionID uuid.UUID
+	Email        string
+}
+
+// RemoveCollectionMemberRequest removes a member from a collection of UserID.
+type RemoveCollectionMemberRequest struct {
+	UserID       uuid.UUID
+	CollectionID uuid.UUID
+	MemberID     uuid.UUID
+}
+
+// CollectionInvitation is a pending invitation of the user to a collection.
+type CollectionInvitation struct {
+	CollectionID   uuid.UUID    `json:"collection_id"`
+	CollectionName string       `json:"collection_name"`
+	Role           string       `json:"role"`
+	InvitedBy      *UserSummary `json:"invited_by,omitempty"`
+	InvitedAt      time.Time    `json:"invited_at"`
+}
+
+// ListCollectionInvitationsResponse lists the pending invitations of the
+// user.
+type ListCollectionInvitationsResponse struct {
+	Invitations []CollectionInvitation `json:"invitations"`
+}
+
+// CollectionRules define the items of a smart collection. Items must match
+// every rule that is set and any of the values of a list. Only items of feeds
+// the owner is subscribed to are matched.
+type CollectionRules struct {
+	FeedIDs    []uuid.UUID `json:"feed_ids,omitempty"`
+	Categories []string    `json:"categories,omitempty"`
+	// Keywords are matched against the title, description and content.
+	Keywords []string `json:"keywords,omitempty"`
+	// Author is matched against the names of the authors.
+	Author          *string    `json:"author,omitempty"`
+	PublishedAfter  *time.Time `json:"published_after,omitempty"`
+	PublishedBefore *time.Time `json:"published_before,omitempty"`
+	// WithinDays limits items to those published in the last days.
+	WithinDays *int32 `json:"within_days,omitempty"`
+	Liked      *bool  `json:"liked,omitempty"`
+	Unread     *bool  `json:"unread,omitempty"`
+	// SimilarTo is a seed text items are semantically compared to. Items
+	// match once the text has been embedded.
+	SimilarTo *string `json:"similar_to,omitempty"`
+	// MinSimilarity is the cosine similarity to SimilarTo items need, between
+	// 0 and 1. Defaults to 0.5.
+	MinSimilarity *float64 `json:"min_similarity,omitempty"`
+}
+
+// SetCollectionRulesRequest turns a collection into a smart collection.
+type SetCollectionRulesRequest struct {
+	UserID       uuid.UUID
+	CollectionID uuid.UUID
+	Rules        CollectionRules
+}
+
+// PublicCollection is a published collection as shown to anonymous readers,
+// without owner details or like status.
+type PublicCollection struct {
+	Slug        string       `json:"slug"`
+	Name        string       `json:"name"`
+	Description *string      `json:"description,omitempty"`
+	Icon        *string      `json:"icon,omitempty"`
+	Color       *string      `json:"color,omitempty"`
+	LastUpdated time.Time    `json:"last_updated"`
+	Limit       int32        `json:"limit"`
+	Offset      int32        `json:"offset"`
+	TotalCount  int64        `json:"total_count"`
+	Items       []PublicItem `json:"items"`
+}
+
+// PublicItem is an item of a published collection.
+type PublicItem struct {
+	ID              uuid.UUID  `json:"id"`
+	Title           *string    `json:"title,omitempty"`
+	Description     *string    `json:"description,omitempty"`
+	Content         *string    `json:"content,omitempty"`
+	Link            string     `json:"link"`
+	PublishedParsed *time.Time `json:"published_parsed,omitempty"`
+	UpdatedParsed   *time.Time `json:"updated_parsed,omitempty"`
+	Authors         Authors    `json:"authors,omitempty"`
+	ImageURL        *string    `json:"image_url,omitempty"`
+	Categories      []string   `json:"categories,omitempty"`
+	AddedAt         time.Time  `json:"added_at"`
+}
+
+// GetPublicCollectionRequest wraps parameters to read a published collection.
+type GetPublicCollectionRequest struct {
+	Slug   string
+	Limit  int32
+	Offset int32
+}
+
+// ReorderCollectionItemsRequest sets the order of items in a collection.
+type ReorderCollectionItemsRequest struct {
+	UserID       uuid.UUID
+	CollectionID uuid.UUID
+	ItemIDs      []uuid.UUID
+}
+
 // ListCollectionItemsRequest wraps parameters to list collection items
 type ListCollectionItemsRequest struct {
-	UserID uuid.UUID
 	repository.ListItemsInCollectionParams
 }
 
@@ -164,6 +373,166 @@ type ListCollectionItemsResponse struct {
 	Items      []Item `json:"items"`
 }
 
+// ExportItem is an item of an exported collection.
+type ExportItem struct {
+	Title           *string
+	Link            string
+	FeedTitle       *string
+	Authors         Authors
+	PublishedParsed *time.Time
+	// Content is the content of the item, or its description when it has no
+	// content.
+	Content *string
+}
+
+// Annotation is a note of the user on an item, a highlight of its text or
+// both. Highlights are anchored to the quoted text and its offsets in
+// characters in the text of the sanitized content of the item.
+type Annotation struct {
+	ID          uuid.UUID `json:"id"`
+	ItemID      uuid.UUID `json:"item_id"`
+	Note        *string   `json:"note,omitempty"`
+	Quote       *string   `json:"quote,omitempty"`
+	StartOffset *int32    `json:"start_offset,omitempty"`
+	EndOffset   *int32    `json:"end_offset,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// Item is set when listing annotations across items.
+	Item *AnnotatedItem `json:"item,omitempty"`
+}
+
+// AnnotatedItem describes the item of an annotation.
+type AnnotatedItem struct {
+	FeedID uuid.UUID `json:"feed_id"`
+	Title  *string   `json:"title,omitempty"`
+	Link   string    `json:"link"`
+}
+
+// CreateAnnotationRequest annotates an item. A highlight is anchored by
+// Quote, by StartOffset and EndOffset or by both, which must then agree.
+type CreateAnnotationRequest struct {
+	UserID      uuid.UUID
+	ItemID      uuid.UUID
+	Note        *string
+	Quote       *string
+	StartOffset *int32
+	EndOffset   *int32
+}
+
+// UpdateAnnotationRequest replaces the note of an annotation, an empty note
+// removes it.
+type UpdateAnnotationRequest struct {
+	UserID       uuid.UUID
+	ItemID       uuid.UUID
+	AnnotationID uuid.UUID
+	Note         string
+}
+
+// ListAnnotationsResponse wraps a paginated list of annotations.
+type ListAnnotationsResponse struct {
+	Limit       int32        `json:"limit"`
+	Offset      int32        `json:"offset"`
+	TotalCount  int64        `json:"total_count"`
+	Annotations []Annotation `json:"annotations"`
+}
+
+// ListItemAnnotationsResponse lists the annotations of an item in reading
+// order.
+type ListItemAnnotationsResponse struct {
+	Annotations []Annotation `json:"annotations"`
+}
+
+// ExportAnnotatedItem is an item with the annotations of the user in reading
+// order.
+type ExportAnnotatedItem struct {
+	Title       *string
+	Link        string
+	FeedTitle   *string
+	Annotations []Annotation
+}
+
+// Tag is a label of the user on items and on the feeds they subscribe to.
+type Tag struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// ItemCount and FeedCount are set when listing tags.
+	ItemCount int64     `json:"item_count,omitempty"`
+	FeedCount int64     `json:"feed_count,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListTagsRequest lists the tags of UserID starting with Prefix.
+type ListTagsRequest struct {
+	UserID uuid.UUID
+	Prefix *string
+	Limit  int32
+}
+
+// ListTagsResponse lists tags, most used first.
+type ListTagsResponse struct {
+	Tags []Tag `json:"tags"`
+}
+
+// RenameTagRequest renames a tag of UserID, or merges it into the tag that
+// already has the name.
+type RenameTagRequest struct {
+	UserID uuid.UUID
+	TagID  uuid.UUID
+	Name   string
+}
+
+// SetTagsRequest replaces the tags of UserID on the item or feed ID.
+type SetTagsRequest struct {
+	UserID uuid.UUID
+	ID     uuid.UUID
+	Tags   []string
+}
+
+// TagsResponse lists the names of the tags of an item or feed.
+type TagsResponse struct {
+	Tags []string `json:"tags"`
+}
+
+// BulkTagRequest adds and removes tags of UserID on many items and feeds at
+// once. Tags that are added and do not exist yet are created.
+type BulkTagRequest struct {
+	UserID  uuid.UUID
+	Add     []string
+	Remove  []string
+	ItemIDs []uuid.UUID
+	FeedIDs []uuid.UUID
+}
+
+// BulkTagResponse counts the tags applied and removed.
+type BulkTagResponse struct {
+	Tagged   int64 `json:"tagged"`
+	Untagged int64 `json:"untagged"`
+}
+
+// FolderFeed is a feed listed in a folder.
+type FolderFeed struct {
+	ID       uuid.UUID `json:"id"`
+	Title    *string   `json:"title,omitempty"`
+	Link     *string   `json:"link,omitempty"`
+	FeedLink string    `json:"feed_link"`
+}
+
+// FeedFolder groups the subscribed feeds filed in a folder or with a tag.
+type FeedFolder struct {
+	// TagID is set when the folder is a tag.
+	TagID *uuid.UUID   `json:"tag_id,omitempty"`
+	Name  string       `json:"name"`
+	Feeds []FolderFeed `json:"feeds"`
+}
+
+// ListFeedFoldersResponse lists the subscribed feeds of the user by folder.
+// A feed filed in a folder is listed only there, a feed with several tags is
+// listed in each of their folders, other feeds are unfiled.
+type ListFeedFoldersResponse struct {
+	Folders []FeedFolder `json:"folders"`
+	Unfiled []FolderFeed `json:"unfiled"`
+}
+
 // AddItemToCollectionResponse
 type AddItemToCollectionResponse struct {
 	AddedAt time.Time `json:"added_at"`
@@ -181,3 +550,470 @@ type Person struct {
 // Authors is a list of Person.
 // swagger:model Authors
 type Authors []Person
+
+// StreamItem is an item with the per-user read state used by reader protocols.
+type StreamItem struct {
+	Item
+	Seq          int64
+	FeedTitle    *string
+	FeedSiteLink *string
+	Read         bool
+}
+
+// UnreadCount holds the number of unread items in a subscribed feed.
+type UnreadCount struct {
+	FeedID       uuid.UUID
+	Count        int64
+	NewestItemAt time.Time
+}
+
+// CreateAppPasswordRequest wraps parameters to create an app password.
+type CreateAppPasswordRequest struct {
+	UserID uuid.UUID
+	Name   string
+}
+
+// AuthenticateAppPasswordRequest wraps credentials sent by reader clients.
+type AuthenticateAppPasswordRequest struct {
+	Email    string
+	Password string
+}
+
+// AuthenticateAppPasswordResponse is the user a reader client authenticated
+// as and the app password it used.
+type AuthenticateAppPasswordResponse struct {
+	User          User
+	AppPasswordID uuid.UUID
+}
+
+// AppPassword represents a password used by third-party reader clients.
+type AppPassword struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// CreateAppPasswordResponse wraps a new app password, the plain text value
+// is only ever returned here.
+type CreateAppPasswordResponse struct {
+	AppPassword
+	Password string `json:"password"`
+}
+
+// ListAppPasswordsResponse wraps the user's app passwords.
+type ListAppPasswordsResponse struct {
+	AppPasswords []AppPassword `json:"app_passwords"`
+}
+
+// SetFeverPasswordRequest wraps parameters to enable the Fever API for a user.
+type SetFeverPasswordRequest struct {
+	UserID   uuid.UUID
+	Password string
+}
+
+// FeverCredential describes the Fever API access of a user.
+type FeverCredential struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FeedRef is a compact description of a subscribed feed used by reader protocols.
+type FeedRef struct {
+	ID            uuid.UUID
+	Seq           int64
+	Title         *string
+	Link          *string
+	FeedLink      string
+	LastUpdatedAt time.Time
+}
+
+// CreateAccessTokenRequest wraps parameters to create a personal access token.
+// GrantedScopes limits the scopes of the new token to those of the caller, it
+// is nil for browser sessions.
+type CreateAccessTokenRequest struct {
+	UserID        uuid.UUID
+	Name          string
+	Scopes        []string
+	ExpiresAt     *time.Time
+	GrantedScopes []string
+}
+
+// AccessToken represents a personal access token.
+type AccessToken struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// CreateAccessTokenResponse wraps a new personal access token, the plain text
+// value is only ever returned here.
+type CreateAccessTokenResponse struct {
+	AccessToken
+	Token string `json:"token"`
+}
+
+// ListAccessTokensResponse wraps the user's personal access tokens.
+type ListAccessTokensResponse struct {
+	AccessTokens []AccessToken `json:"access_tokens"`
+}
+
+// CreateSessionRequest wraps parameters to start a login session.
+type CreateSessionRequest struct {
+	UserID    uuid.UUID
+	Device    string
+	IP        string
+	UserAgent string
+	IDToken   *string
+	Provider  string
+	TTL       time.Duration
+}
+
+// RefreshSessionRequest wraps parameters to rotate a refresh token.
+type RefreshSessionRequest struct {
+	RefreshToken string
+	IP           string
+	UserAgent    string
+	TTL          time.Duration
+}
+
+// Session represents a login session of a user.
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Provider   string    `json:"provider,omitempty"`
+	Current    bool      `json:"current"`
+	IDToken    *string   `json:"-"`
+}
+
+// SessionTokens wraps a session together with its user and current refresh
+// token, the plain text token is only ever returned here.
+type SessionTokens struct {
+	Session      Session
+	User         User
+	RefreshToken string
+}
+
+// ListSessionsResponse wraps the user's active sessions.
+type ListSessionsResponse struct {
+	Sessions []Session `json:"sessions"`
+}
+
+// SignInRequest wraps the identity asserted by an OAuth provider at login.
+type SignInRequest struct {
+	Provider string
+	Issuer   string
+	Sub      string
+	Name     string
+	Email    string
+	// EmailVerified is set when the provider verified Email.
+	EmailVerified bool
+	Role          string
+	// LegacyIssuer is the issuer accounts created before identities were
+	// linked came from, they are only claimed by sign-ins from it.
+	LegacyIssuer string
+}
+
+// LinkIdentityRequest wraps parameters to link a provider identity to a user.
+type LinkIdentityRequest struct {
+	UserID   uuid.UUID
+	Provider string
+	Issuer   string
+	Sub      string
+	Email    string
+	// EmailVerified is set when the provider verified Email.
+	EmailVerified bool
+}
+
+// UserIdentity represents a provider account the user can sign in with.
+type UserIdentity struct {
+	ID       uuid.UUID `json:"id"`
+	Provider string    `json:"provider"`
+	Issuer   string    `json:"issuer"`
+	Email    *string   `json:"email,omitempty"`
+	// EmailVerified is set when the provider verified Email, collection
+	// invitations to it can be accepted.
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastLoginAt   time.Time `json:"last_login_at"`
+}
+
+// ListUserIdentitiesResponse wraps the user's linked identities.
+type ListUserIdentitiesResponse struct {
+	Identities []UserIdentity `json:"identities"`
+}
+
+// UserStats summarises what a user keeps on the instance.
+type UserStats struct {
+	Feeds       int64 `json:"feeds"`
+	Likes       int64 `json:"likes"`
+	Collections int64 `json:"collections"`
+	Sessions    int64 `json:"sessions"`
+}
+
+// AdminUser is a user as seen by instance administrators.
+type AdminUser struct {
+	ID            uuid.UUID  `json:"id"`
+	Sub           string     `json:"sub"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email"`
+	Role          string     `json:"role"`
+	CreatedAt     time.Time  `json:"created_at"`
+	LastUpdatedAt time.Time  `json:"last_updated_at"`
+	DisabledAt    *time.Time `json:"disabled_at,omitempty"`
+	Stats         UserStats  `json:"stats"`
+}
+
+// ListAdminUsersRequest wraps parameters to search users.
+type ListAdminUsersRequest struct {
+	Query  string
+	Limit  int32
+	Offset int32
+}
+
+// ListAdminUsersResponse wraps paginated users.
+type ListAdminUsersResponse struct {
+	Limit      int32       `json:"limit"`
+	Offset     int32       `json:"offset"`
+	TotalCount int64       `json:"total_count"`
+	Users      []AdminUser `json:"users"`
+}
+
+// FeedHealth reports how syncing a feed has gone recently.
+type FeedHealth struct {
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+	LastError     *string    `json:"last_error,omitempty"`
+	FailureCount  int32      `json:"failure_count"`
+}
+
+// AdminFeed is a feed as seen by instance administrators.
+type AdminFeed struct {
+	ID            uuid.UUID  `json:"id"`
+	Title         *string    `json:"title,omitempty"`
+	FeedLink      string     `json:"feed_link"`
+	CreatedAt     time.Time  `json:"created_at"`
+	LastUpdatedAt time.Time  `json:"last_updated_at"`
+	Subscribers   int64      `json:"subscribers"`
+	Items         int64      `json:"items"`
+	Health        FeedHealth `json:"health"`
+}
+
+// ListAdminFeedsRequest wraps parameters to list feeds with their health.
+type ListAdminFeedsRequest struct {
+	FailingOnly bool
+	Limit       int32
+	Offset      int32
+}
+
+// ListAdminFeedsResponse wraps paginated feeds with their health.
+type ListAdminFeedsResponse struct {
+	Limit      int32       `json:"limit"`
+	Offset     int32       `json:"offset"`
+	TotalCount int64       `json:"total_count"`
+	Feeds      []AdminFeed `json:"feeds"`
+}
+
+// MergeFeedsRequest wraps parameters to merge a duplicate feed into another.
+type MergeFeedsRequest struct {
+	SourceID uuid.UUID
+	TargetID uuid.UUID
+}
+
+// MergeFeedsResponse reports what was moved by a merge.
+type MergeFeedsResponse struct {
+	TargetID   uuid.UUID `json:"target_id"`
+	MovedItems int64     `json:"moved_items"`
+}
+
+// PurgeItemsRequest wraps parameters to delete old items.
+type PurgeItemsRequest struct {
+	repository.PurgeItemsParams
+}
+
+// PurgeItemsResponse reports how many items were purged.
+type PurgeItemsResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+// SyncFeedResponse describes the task syncing a feed. Deduplicated is set
+// when a sync was already queued and no new task was created.
+type SyncFeedResponse struct {
+	TaskID       string `json:"task_id"`
+	State        string `json:"state"`
+	Deduplicated bool   `json:"deduplicated"`
+}
+
+// SyncTask describes a queued or running sync task.
+type SyncTask struct {
+	ID            string     `json:"id"`
+	State         string     `json:"state"`
+	Retried       int        `json:"retried"`
+	MaxRetry      int        `json:"max_retry"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextProcessAt *time.Time `json:"next_process_at,omitempty"`
+}
+
+// SyncStatusResponse reports when a feed was and will be synced.
+type SyncStatusResponse struct {
+	FeedID        uuid.UUID  `json:"feed_id"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+	LastError     *string    `json:"last_error,omitempty"`
+	FailureCount  int32      `json:"failure_count"`
+	NextSyncAt    *time.Time `json:"next_sync_at,omitempty"`
+	Task          *SyncTask  `json:"task,omitempty"`
+}
+
+// FilterRule is a keyword or regex rule of a user acting on the items of
+// their subscriptions as feeds are synced.
+type FilterRule struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+	// FeedID limits the rule to a feed, it applies to all subscriptions
+	// otherwise.
+	FeedID *uuid.UUID `json:"feed_id,omitempty"`
+	// Field is any, title, content, author, category or link.
+	Field string `json:"field"`
+	// MatchType is keyword or regex.
+	MatchType string `json:"match_type"`
+	Pattern   string `json:"pattern"`
+	// Action is hide, mark_read or add_to_collection.
+	Action       string     `json:"action"`
+	CollectionID *uuid.UUID `json:"collection_id,omitempty"`
+	Enabled      bool       `json:"enabled"`
+	// HitCount counts the items the rule matched as feeds were synced.
+	HitCount  int64      `json:"hit_count"`
+	LastHitAt *time.Time `json:"last_hit_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// FilterRuleRequest defines a filter rule of UserID to create, replace or
+// preview. Enabled defaults to true.
+type FilterRuleRequest struct {
+	UserID       uuid.UUID
+	Name         string
+	FeedID       *uuid.UUID
+	Field        string
+	MatchType    string
+	Pattern      string
+	Action       string
+	CollectionID *uuid.UUID
+	Enabled      *bool
+}
+
+// UpdateFilterRuleRequest replaces the definition of the filter rule RuleID.
+type UpdateFilterRuleRequest struct {
+	RuleID uuid.UUID
+	FilterRuleRequest
+}
+
+// ListFilterRulesResponse lists the filter rules of a user.
+type ListFilterRulesResponse struct {
+	Rules []FilterRule `json:"rules"`
+}
+
+// FilterRuleMatch is a recent item a filter rule matches.
+type FilterRuleMatch struct {
+	ID              uuid.UUID  `json:"id"`
+	FeedID          uuid.UUID  `json:"feed_id"`
+	Title           *string    `json:"title,omitempty"`
+	Link            string     `json:"link"`
+	PublishedParsed *time.Time `json:"published_parsed,omitempty"`
+}
+
+// PreviewFilterRuleResponse reports which of the Scanned most recent items
+// of the subscriptions of the user a rule matches. Items lists at most the
+// first matches.
+type PreviewFilterRuleResponse struct {
+	Scanned int               `json:"scanned"`
+	Matched int               `json:"matched"`
+	Items   []FilterRuleMatch `json:"items"`
+}
+
+// Webhook is an outbound webhook of a user. Secret is only returned when it
+// is set, on creation or when replaced.
+type Webhook struct {
+	ID             uuid.UUID   `json:"id"`
+	Name           string      `json:"name"`
+	URL            string      `json:"url"`
+	Secret         string      `json:"secret,omitempty"`
+	Events         []string    `json:"events"`
+	FeedIDs        []uuid.UUID `json:"feed_ids"`
+	CollectionIDs  []uuid.UUID `json:"collection_ids"`
+	RuleIDs        []uuid.UUID `json:"rule_ids"`
+	Enabled        bool        `json:"enabled"`
+	FailureCount   int32       `json:"failure_count"`
+	DisabledReason *string     `json:"disabled_reason,omitempty"`
+	LastDeliveryAt *time.Time  `json:"last_delivery_at,omitempty"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+// WebhookRequest defines a webhook of UserID to create or replace. Empty
+// FeedIDs, CollectionIDs and RuleIDs select all feeds, collections and rules
+// of the user. A nil Secret is generated on creation and kept on update, an
+// empty one is generated. Enabled defaults to true.
+type WebhookRequest struct {
+	UserID        uuid.UUID
+	Name          string
+	URL           string
+	Secret        *string
+	Events        []string
+	FeedIDs       []uuid.UUID
+	CollectionIDs []uuid.UUID
+	RuleIDs       []uuid.UUID
+	Enabled       *bool
+}
+
+// UpdateWebhookRequest replaces the definition of the webhook WebhookID.
+type UpdateWebhookRequest struct {
+	WebhookID uuid.UUID
+	WebhookRequest
+}
+
+// ListWebhooksResponse lists the webhooks of a user.
+type ListWebhooksResponse struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// WebhookDelivery is an entry of the delivery log of a webhook. Status is
+// pending until the delivery succeeds or fails all its attempts.
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id"`
+	WebhookID      uuid.UUID       `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	ResponseStatus *int32          `json:"response_status,omitempty"`
+	Error          *string         `json:"error,omitempty"`
+	DurationMs     *int32          `json:"duration_ms,omitempty"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt      time.Time       `json:"created_at"`
+	AttemptedAt    *time.Time      `json:"attempted_at,omitempty"`
+}
+
+// ListWebhookDeliveriesRequest wraps parameters for listing the delivery log
+// of a webhook.
+type ListWebhookDeliveriesRequest struct {
+	UserID    uuid.UUID
+	WebhookID uuid.UUID
+	Limit     int32
+	Offset    int32
+}
+
+// ListWebhookDeliveriesResponse lists deliveries of a webhook, newest first.
+type ListWebhookDeliveriesResponse struct {
+	Limit      int32             `json:"limit"`
+	Offset     int32             `json:"offset"`
+	TotalCount int64             `json:"total_count"`
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
@@ -1,18 +1,46 @@
 package service
 
 import (
+	"context"
+
 	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rhajizada/gazette/internal/repository"
+	"go.opentelemetry.io/otel"
 )
 
+var tracer = otel.Tracer("github.com/rhajizada/gazette/internal/service")
+
 type Service struct {
-	Repo   repository.Queries
-	Client *asynq.Client
+	Pool      *pgxpool.Pool
+	Repo      repository.Queries
+	Client    *asynq.Client
+	Inspector *asynq.Inspector
+	// AllowPrivateWebhooks lets webhooks target private addresses and
+	// localhost, see config.WebhooksConfig.
+	AllowPrivateWebhooks bool
 }
 
-func New(repo *repository.Queries, client *asynq.Client) *Service {
+func New(pool *pgxpool.Pool, client *asynq.Client, inspector *asynq.Inspector) *Service {
 	return &Service{
-		Repo:   *repo,
-		Client: client,
+		Pool:      pool,
+		Repo:      *repository.New(pool),
+		Client:    client,
+		Inspector: inspector,
+	}
+}
+
+// withTx runs fn with queries bound to a transaction, which is committed when
+// fn succeeds and rolled back otherwise.
+func (s *Service) withTx(ctx context.Context, fn func(repo *repository.Queries) error) error {
+	tx, err := s.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(s.Repo.WithTx(tx)); err != nil {
+		return err
 	}
+	return tx.Commit(ctx)
 }
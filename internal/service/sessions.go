@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/repository"
+)
+
+func newRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func sessionFromRecord(rec repository.Session) Session {
+	var provider string
+	if rec.Provider != nil {
+		provider = *rec.Provider
+	}
+	return Session{
+		ID:         rec.ID,
+		Device:     rec.Device,
+		IP:         rec.Ip,
+		UserAgent:  rec.UserAgent,
+		CreatedAt:  rec.CreatedAt,
+		LastSeenAt: rec.LastSeenAt,
+		ExpiresAt:  rec.ExpiresAt,
+		Provider:   provider,
+		IDToken:    rec.IDToken,
+	}
+}
+
+// issueRefreshToken stores a new refresh token for a session.
+func (s *Service) issueRefreshToken(ctx context.Context, sessionID uuid.UUID) (string, error) {
+	token, err := newRefreshToken()
+	if err != nil {
+		return "", NewError("failed to generate refresh token", http.StatusInternalServerError)
+	}
+	_, err = s.Repo.CreateRefreshToken(ctx, repository.CreateRefreshTokenParams{
+		SessionID: sessionID,
+		TokenHash: hashSecret(token),
+	})
+	if err != nil {
+		return "", NewError("failed to store refresh token", http.StatusInternalServerError)
+	}
+	return token, nil
+}
+
+// CreateSession starts a login session and returns its first refresh token.
+func (s *Service) CreateSession(ctx context.Context, r CreateSessionRequest) (*SessionTokens, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateSession")
+	defer span.End()
+
+	user, err := s.GetUserByID(ctx, r.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	rec, err := s.Repo.CreateSession(ctx, repository.CreateSessionParams{
+		UserID:    r.UserID,
+		Device:    r.Device,
+		Ip:        r.IP,
+		UserAgent: r.UserAgent,
+		IDToken:   r.IDToken,
+		Provider:  &r.Provider,
+		ExpiresAt: time.Now().Add(r.TTL),
+	})
+	if err != nil {
+		return nil, NewError("failed to create session", http.StatusInternalServerError)
+	}
+
+	token, err := s.issueRefreshToken(ctx, rec.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionTokens{
+		Session:      sessionFromRecord(rec),
+		User:         *user,
+		RefreshToken: token,
+	}, nil
+}
+
+// RefreshSession exchanges a refresh token for a new one and extends the
+// session. Presenting a token that was already used revokes the session, as
+// it means the token was leaked.
+func (s *Service) RefreshSession(ctx context.Context, r RefreshSessionRequest) (*SessionTokens, error) {
+	ctx, span := tracer.Start(ctx, "Service.RefreshSession")
+	defer span.End()
+
+	invalid := NewError("invalid refresh token", http.StatusUnauthorized)
+
+	rec, err := s.Repo.GetRefreshTokenByHash(ctx, hashSecret(r.RefreshToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, invalid
+		}
+		return nil, NewError("failed to verify refresh token", http.StatusInternalServerError)
+	}
+
+	session, err := s.Repo.GetSessionByID(ctx, rec.SessionID)
+	if err != nil {
+		return nil, NewError("failed to fetch session", http.StatusInternalServerError)
+	}
+	if session.RevokedAt != nil || session.ExpiresAt.Before(time.Now()) {
+		return nil, invalid
+	}
+
+	reused := rec.UsedAt != nil
+	if !reused {
+		count, err := s.Repo.MarkRefreshTokenUsed(ctx, rec.ID)
+		if err != nil {
+			return nil, NewError("failed to rotate refresh token", http.StatusInternalServerError)
+		}
+		// a concurrent request used the token first
+		reused = count == 0
+	}
+	if reused {
+		slog.WarnContext(ctx, "refresh token reuse detected, revoking session",
+			slog.String("session_id", session.ID.String()),
+			slog.String("user_id", session.UserID.String()),
+		)
+		if _, err := s.Repo.RevokeSession(ctx, repository.RevokeSessionParams{
+			ID:     session.ID,
+			UserID: session.UserID,
+		}); err != nil {
+			slog.ErrorContext(ctx, "failed to revoke session",
+				slog.String("session_id", session.ID.String()),
+				slog.Any("error", err),
+			)
+		}
+		return nil, NewError("refresh token reuse detected, session revoked", http.StatusUnauthorized)
+	}
+
+	expiresAt := time.Now().Add(r.TTL)
+	if err := s.Repo.ExtendSession(ctx, repository.ExtendSessionParams{
+		ID:        session.ID,
+		Ip:        r.IP,
+		UserAgent: r.UserAgent,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return nil, NewError("failed to extend session", http.StatusInternalServerError)
+	}
+	session.Ip, session.UserAgent, session.ExpiresAt = r.IP, r.UserAgent, expiresAt
+
+	token, err := s.issueRefreshToken(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.GetUserByID(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SessionTokens{
+		Session:      sessionFromRecord(session),
+		User:         *user,
+		RefreshToken: token,
+	}, nil
+}
+
+// GetSessionByRefreshToken returns the session a refresh token belongs to.
+func (s *Service) GetSessionByRefreshToken(ctx context.Context, refreshToken string) (*Session, uuid.UUID, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetSessionByRefreshToken")
+	defer span.End()
+
+	rec, err := s.Repo.GetRefreshTokenByHash(ctx, hashSecret(refreshToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, uuid.Nil, NewError("invalid refresh token", http.StatusUnauthorized)
+		}
+		return nil, uuid.Nil, NewError("failed to verify refresh token", http.StatusInternalServerError)
+	}
+	return s.GetSession(ctx, rec.SessionID)
+}
+
+// GetSession returns a session and the ID of the user owning it.
+func (s *Service) GetSession(ctx context.Context, sessionID uuid.UUID) (*Session, uuid.UUID, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetSession")
+	defer span.End()
+
+	rec, err := s.Repo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, uuid.Nil, NewError(
+				fmt.Sprintf("session %s not found", sessionID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, uuid.Nil, NewError(
+			fmt.Sprintf("failed to fetch session %s", sessionID),
+			http.StatusInternalServerError,
+		)
+	}
+	session := sessionFromRecord(rec)
+	return &session, rec.UserID, nil
+}
+
+// ValidateSession checks that a session is still active and records its use.
+func (s *Service) ValidateSession(ctx context.Context, sessionID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "Service.ValidateSession")
+	defer span.End()
+
+	active, err := s.Repo.TouchSession(ctx, sessionID)
+	if err != nil {
+		return NewError("failed to verify session", http.StatusInternalServerError)
+	}
+	if !active {
+		return NewError("session revoked or expired", http.StatusUnauthorized)
+	}
+	return nil
+}
+
+// ListSessions returns the active sessions of the user, marking the current one.
+func (s *Service) ListSessions(ctx context.Context, userID, currentID uuid.UUID) (*ListSessionsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListSessions")
+	defer span.End()
+
+	rows, err := s.Repo.ListActiveSessionsByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to list sessions", http.StatusInternalServerError)
+	}
+
+	sessions := make([]Session, len(rows))
+	for i, row := range rows {
+		sessions[i] = sessionFromRecord(row)
+		sessions[i].Current = row.ID == currentID
+	}
+
+	return &ListSessionsResponse{Sessions: sessions}, nil
+}
+
+// RevokeSession logs a session of the user out.
+func (s *Service) RevokeSession(ctx context.Context, r repository.RevokeSessionParams) error {
+	ctx, span := tracer.Start(ctx, "Service.RevokeSession")
+	defer span.End()
+
+	count, err := s.Repo.RevokeSession(ctx, r)
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to revoke session %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	if count == 0 {
+		return NewError(
+			fmt.Sprintf("session %s not found", r.ID),
+			http.StatusNotFound,
+		)
+	}
+	return nil
+}
+
+// RevokeOtherSessions logs out every session of the user except the current one.
+func (s *Service) RevokeOtherSessions(ctx context.Context, r repository.RevokeOtherSessionsParams) (int64, error) {
+	ctx, span := tracer.Start(ctx, "Service.RevokeOtherSessions")
+	defer span.End()
+
+	count, err := s.Repo.RevokeOtherSessions(ctx, r)
+	if err != nil {
+		return 0, NewError("failed to revoke sessions", http.StatusInternalServerError)
+	}
+	return count, nil
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/repository"
+)
+
+// Notification preferences of a subscription.
+const (
+	SubscriptionNotifyNone = "none"
+	SubscriptionNotifyAll  = "all"
+)
+
+// Orders the items of a subscribed feed are listed in.
+const (
+	SubscriptionSortNewest = "newest"
+	SubscriptionSortOldest = "oldest"
+)
+
+const (
+	// maxSubscriptionTitleLength bounds the length of a title override in
+	// characters, folders share the limit of tag names.
+	maxSubscriptionTitleLength = 256
+
+	// maxSubscriptionIconLength bounds the length of an icon, an emoji or an
+	// image URL.
+	maxSubscriptionIconLength = 2048
+)
+
+func subscriptionFromRecord(rec repository.UserFeed) *Subscription {
+	return &Subscription{
+		Folder:    rec.Folder,
+		Title:     rec.Title,
+		Icon:      rec.Icon,
+		Notify:    rec.Notify,
+		SortOrder: rec.SortOrder,
+		Muted:     rec.Muted,
+	}
+}
+
+// trimSetting trims a text setting and checks its length. Empty settings are
+// kept, they clear the setting.
+func trimSetting(name string, value *string, max int) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	v := strings.TrimSpace(*value)
+	if utf8.RuneCountInString(v) > max {
+		return nil, NewError(
+			fmt.Sprintf("%s must be at most %d characters", name, max),
+			http.StatusBadRequest,
+		)
+	}
+	return &v, nil
+}
+
+// validateSubscriptionUpdate normalizes the settings of an update.
+func validateSubscriptionUpdate(r *UpdateSubscriptionRequest) error {
+	var err error
+	if r.Folder, err = trimSetting("folder", r.Folder, maxTagLength); err != nil {
+		return err
+	}
+	if r.Title, err = trimSetting("title", r.Title, maxSubscriptionTitleLength); err != nil {
+		return err
+	}
+	if r.Icon, err = trimSetting("icon", r.Icon, maxSubscriptionIconLength); err != nil {
+		return err
+	}
+	if r.Icon != nil && strings.Contains(*r.Icon, "://") {
+		u, err := url.Parse(*r.Icon)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return NewError(
+				fmt.Sprintf("invalid icon URL %s", *r.Icon),
+				http.StatusBadRequest,
+			)
+		}
+	}
+	if r.Notify != nil && *r.Notify != SubscriptionNotifyNone && *r.Notify != SubscriptionNotifyAll {
+		return NewError(
+			fmt.Sprintf("invalid notify %s, expected %s or %s", *r.Notify, SubscriptionNotifyNone, SubscriptionNotifyAll),
+			http.StatusBadRequest,
+		)
+	}
+	if r.SortOrder != nil && *r.SortOrder != SubscriptionSortNewest && *r.SortOrder != SubscriptionSortOldest {
+		return NewError(
+			fmt.Sprintf("invalid sort_order %s, expected %s or %s", *r.SortOrder, SubscriptionSortNewest, SubscriptionSortOldest),
+			http.StatusBadRequest,
+		)
+	}
+	return nil
+}
+
+// feedSubscriptions returns the subscriptions of the user to feeds.
+func (s *Service) feedSubscriptions(ctx context.Context, userID uuid.UUID, feedIDs []uuid.UUID) (map[uuid.UUID]*Subscription, error) {
+	rows, err := s.Repo.ListUserFeedSubscriptionsByFeedIDs(ctx, repository.ListUserFeedSubscriptionsByFeedIDsParams{
+		UserID:  userID,
+		FeedIds: feedIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	subs := make(map[uuid.UUID]*Subscription, len(rows))
+	for _, row := range rows {
+		subs[row.FeedID] = subscriptionFromRecord(row)
+	}
+	return subs, nil
+}
+
+// UpdateSubscription changes the settings of the user for a feed they are
+// subscribed to.
+func (s *Service) UpdateSubscription(ctx context.Context, r UpdateSubscriptionRequest) (*Subscription, error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateSubscription")
+	defer span.End()
+
+	if err := validateSubscriptionUpdate(&r); err != nil {
+		return nil, err
+	}
+
+	rec, err := s.Repo.UpdateUserFeedSubscription(ctx, repository.UpdateUserFeedSubscriptionParams{
+		Folder:    r.Folder,
+		Title:     r.Title,
+		Icon:      r.Icon,
+		Notify:    r.Notify,
+		SortOrder: r.SortOrder,
+		Muted:     r.Muted,
+		UserID:    r.UserID,
+		FeedID:    r.FeedID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("user not subscribed to feed %s", r.FeedID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to update subscription to feed %s", r.FeedID),
+			http.StatusInternalServerError,
+		)
+	}
+	return subscriptionFromRecord(rec), nil
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/rhajizada/gazette/internal/workers"
+)
+
+// syncQueue is the queue feed syncs run on.
+const syncQueue = "critical"
+
+// enqueueFeedSync queues a sync of a feed unless one is already queued or
+// running, in which case that task is reported instead.
+func (s *Service) enqueueFeedSync(ctx context.Context, feedID uuid.UUID) (*SyncFeedResponse, error) {
+	task, err := workers.NewSyncFeedTask(ctx, feedID)
+	if err != nil {
+		return nil, NewError("failed to create sync task", http.StatusInternalServerError)
+	}
+
+	info, duplicate, err := workers.Enqueue(ctx, s.Client, s.Inspector, task, workers.SyncFeedTaskID(feedID), syncQueue)
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to queue sync task for feed %s", feedID),
+			http.StatusInternalServerError,
+		)
+	}
+
+	resp := SyncFeedResponse{Deduplicated: duplicate}
+	if info != nil {
+		resp.TaskID = info.ID
+		resp.State = info.State.String()
+	} else {
+		resp.TaskID = workers.SyncFeedTaskID(feedID)
+		resp.State = asynq.TaskStatePending.String()
+	}
+	return &resp, nil
+}
+
+// RefreshFeed queues an immediate sync of a feed.
+func (s *Service) RefreshFeed(ctx context.Context, feedID uuid.UUID) (*SyncFeedResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.RefreshFeed")
+	defer span.End()
+
+	if _, err := s.getFeed(ctx, feedID); err != nil {
+		return nil, err
+	}
+	return s.enqueueFeedSync(ctx, feedID)
+}
+
+// GetSyncStatus reports the last sync of a feed, the sync task currently
+// queued or running and when the scheduler syncs feeds next.
+func (s *Service) GetSyncStatus(ctx context.Context, feedID uuid.UUID) (*SyncStatusResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetSyncStatus")
+	defer span.End()
+
+	if _, err := s.getFeed(ctx, feedID); err != nil {
+		return nil, err
+	}
+
+	resp := SyncStatusResponse{FeedID: feedID}
+
+	health, err := s.Repo.GetFeedHealth(ctx, feedID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError("failed to fetch sync status", http.StatusInternalServerError)
+	}
+	if err == nil {
+		resp.LastSuccessAt = health.LastSuccessAt
+		resp.LastFailureAt = health.LastFailureAt
+		resp.LastError = health.LastError
+		resp.FailureCount = health.FailureCount
+	}
+
+	info, err := s.Inspector.GetTaskInfo(syncQueue, workers.SyncFeedTaskID(feedID))
+	switch {
+	case err == nil:
+		task := SyncTask{
+			ID:        info.ID,
+			State:     info.State.String(),
+			Retried:   info.Retried,
+			MaxRetry:  info.MaxRetry,
+			LastError: info.LastErr,
+		}
+		if !info.NextProcessAt.IsZero() {
+			task.NextProcessAt = &info.NextProcessAt
+		}
+		resp.Task = &task
+	case errors.Is(err, asynq.ErrTaskNotFound), errors.Is(err, asynq.ErrQueueNotFound):
+	default:
+		return nil, NewError("failed to inspect sync task", http.StatusInternalServerError)
+	}
+
+	entries, err := s.Inspector.SchedulerEntries()
+	if err != nil {
+		return nil, NewError("failed to inspect scheduler", http.StatusInternalServerError)
+	}
+	for _, entry := range entries {
+		if entry.Task.Type() != workers.TypeSyncData {
+			continue
+		}
+		if resp.NextSyncAt == nil || entry.Next.Before(*resp.NextSyncAt) {
+			next := entry.Next
+			resp.NextSyncAt = &next
+		}
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,629 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rhajizada/gazette/internal/repository"
+)
+
+const (
+	// maxTagLength bounds the length of a tag name in characters.
+	maxTagLength = 64
+
+	// maxTagsPerRequest bounds the number of tags set or changed at once.
+	maxTagsPerRequest = 50
+
+	// maxBulkTagTargets bounds the number of items and feeds tagged at once.
+	maxBulkTagTargets = 500
+)
+
+func tagFromRecord(rec repository.Tag) Tag {
+	return Tag{
+		ID:        rec.ID,
+		Name:      rec.Name,
+		CreatedAt: rec.CreatedAt,
+	}
+}
+
+// normalizeTagName trims a tag name and validates it.
+func normalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", NewError("tag name must not be empty", http.StatusBadRequest)
+	}
+	if utf8.RuneCountInString(name) > maxTagLength {
+		return "", NewError(
+			fmt.Sprintf("tag %s must be at most %d characters", name, maxTagLength),
+			http.StatusBadRequest,
+		)
+	}
+	if strings.IndexFunc(name, unicode.IsControl) >= 0 {
+		return "", NewError(
+			fmt.Sprintf("tag %q must not contain control characters", name),
+			http.StatusBadRequest,
+		)
+	}
+	return name, nil
+}
+
+// normalizeTagNames trims and validates tag names and drops names repeated
+// ignoring case.
+func normalizeTagNames(names []string) ([]string, error) {
+	if len(names) > maxTagsPerRequest {
+		return nil, NewError(
+			fmt.Sprintf("at most %d tags can be set at once", maxTagsPerRequest),
+			http.StatusBadRequest,
+		)
+	}
+	out := make([]string, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		name, err := normalizeTagName(n)
+		if err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(name)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, name)
+	}
+	return out, nil
+}
+
+// upsertTags returns the IDs of the tags of the user named names, creating
+// the missing ones.
+func (s *Service) upsertTags(ctx context.Context, userID uuid.UUID, names []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(names))
+	if len(names) == 0 {
+		return ids, nil
+	}
+	tags, err := s.Repo.UpsertTags(ctx, repository.UpsertTagsParams{
+		UserID: userID,
+		Names:  names,
+	})
+	if err != nil {
+		return nil, NewError("failed to create tags", http.StatusInternalServerError)
+	}
+	for _, t := range tags {
+		ids = append(ids, t.ID)
+	}
+	return ids, nil
+}
+
+// getTag fetches a tag of the user.
+func (s *Service) getTag(ctx context.Context, userID, tagID uuid.UUID) (*repository.Tag, error) {
+	tag, err := s.Repo.GetTagByID(ctx, repository.GetTagByIDParams{
+		ID:     tagID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("tag %s not found", tagID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to fetch tag %s", tagID),
+			http.StatusInternalServerError,
+		)
+	}
+	return &tag, nil
+}
+
+// ListTags lists the tags of the user starting with a prefix, most used
+// first, to complete tag names.
+func (s *Service) ListTags(ctx context.Context, r ListTagsRequest) (*ListTagsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListTags")
+	defer span.End()
+
+	if r.Prefix != nil && strings.TrimSpace(*r.Prefix) == "" {
+		r.Prefix = nil
+	} else if r.Prefix != nil {
+		prefix := strings.TrimSpace(*r.Prefix)
+		r.Prefix = &prefix
+	}
+	rows, err := s.Repo.ListTags(ctx, repository.ListTagsParams{
+		UserID: r.UserID,
+		Prefix: r.Prefix,
+		Limit:  r.Limit,
+	})
+	if err != nil {
+		return nil, NewError("failed to list tags", http.StatusInternalServerError)
+	}
+
+	tags := make([]Tag, len(rows))
+	for i, row := range rows {
+		tags[i] = Tag{
+			ID:        row.ID,
+			Name:      row.Name,
+			ItemCount: row.ItemCount,
+			FeedCount: row.FeedCount,
+			CreatedAt: row.CreatedAt,
+		}
+	}
+	return &ListTagsResponse{Tags: tags}, nil
+}
+
+// RenameTag renames a tag of the user. When another tag already has the name
+// the tag is merged into it, and the other tag is returned.
+func (s *Service) RenameTag(ctx context.Context, r RenameTagRequest) (*Tag, error) {
+	ctx, span := tracer.Start(ctx, "Service.RenameTag")
+	defer span.End()
+
+	name, err := normalizeTagName(r.Name)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := s.getTag(ctx, r.UserID, r.TagID)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.Repo.GetTagByName(ctx, repository.GetTagByNameParams{
+		UserID: r.UserID,
+		Name:   name,
+	})
+	if err == nil && target.ID != tag.ID {
+		return s.mergeTag(ctx, tag, &target)
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, NewError(
+			fmt.Sprintf("failed to fetch tag %s", name),
+			http.StatusInternalServerError,
+		)
+	}
+
+	rec, err := s.Repo.RenameTag(ctx, repository.RenameTagParams{
+		ID:     r.TagID,
+		UserID: r.UserID,
+		Name:   name,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("tag %s not found", r.TagID),
+				http.StatusNotFound,
+			)
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return nil, NewError(
+				fmt.Sprintf("tag %s already exists", name),
+				http.StatusConflict,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to rename tag %s", r.TagID),
+			http.StatusInternalServerError,
+		)
+	}
+	t := tagFromRecord(rec)
+	return &t, nil
+}
+
+// mergeTag moves the items and feeds of source to target and deletes source
+// in a transaction.
+func (s *Service) mergeTag(ctx context.Context, source, target *repository.Tag) (*Tag, error) {
+	params := repository.MoveTagItemsParams{
+		TargetID: target.ID,
+		SourceID: source.ID,
+	}
+	err := s.withTx(ctx, func(repo *repository.Queries) error {
+		if err := repo.MoveTagItems(ctx, params); err != nil {
+			return err
+		}
+		if err := repo.MoveTagFeeds(ctx, repository.MoveTagFeedsParams(params)); err != nil {
+			return err
+		}
+		_, err := repo.DeleteTag(ctx, repository.DeleteTagParams{ID: source.ID, UserID: source.UserID})
+		return err
+	})
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to merge tag %s into %s", source.Name, target.Name),
+			http.StatusInternalServerError,
+		)
+	}
+	t := tagFromRecord(*target)
+	return &t, nil
+}
+
+// DeleteTag deletes a tag of the user and removes it from items and feeds.
+func (s *Service) DeleteTag(ctx context.Context, r repository.DeleteTagParams) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteTag")
+	defer span.End()
+
+	deleted, err := s.Repo.DeleteTag(ctx, r)
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to delete tag %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	if deleted == 0 {
+		return NewError(
+			fmt.Sprintf("tag %s not found", r.ID),
+			http.StatusNotFound,
+		)
+	}
+	return nil
+}
+
+// itemTagNames returns the names of the tags of the user on items.
+func (s *Service) itemTagNames(ctx context.Context, userID uuid.UUID, itemIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	rows, err := s.Repo.ListItemTagNames(ctx, repository.ListItemTagNamesParams{
+		UserID:  userID,
+		ItemIds: itemIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID][]string, len(itemIDs))
+	for _, row := range rows {
+		names[row.ItemID] = append(names[row.ItemID], row.Name)
+	}
+	return names, nil
+}
+
+// feedTagNames returns the names of the tags of the user on feeds.
+func (s *Service) feedTagNames(ctx context.Context, userID uuid.UUID, feedIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	rows, err := s.Repo.ListFeedTagNames(ctx, repository.ListFeedTagNamesParams{
+		UserID:  userID,
+		FeedIds: feedIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID][]string, len(feedIDs))
+	for _, row := range rows {
+		names[row.FeedID] = append(names[row.FeedID], row.Name)
+	}
+	return names, nil
+}
+
+// SetItemTags replaces the tags of the user on an item, creating tags that
+// do not exist yet.
+func (s *Service) SetItemTags(ctx context.Context, r SetTagsRequest) (*TagsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.SetItemTags")
+	defer span.End()
+
+	names, err := normalizeTagNames(r.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.Repo.GetItemByID(ctx, r.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("item %s not found", r.ID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to fetch item %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+
+	ids, err := s.upsertTags(ctx, r.UserID, names)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.Repo.UntagItemExcept(ctx, repository.UntagItemExceptParams{
+		UserID:  r.UserID,
+		ItemID:  r.ID,
+		KeepIds: ids,
+	})
+	if err == nil && len(ids) > 0 {
+		_, err = s.Repo.TagItems(ctx, repository.TagItemsParams{
+			TagIds:  ids,
+			ItemIds: []uuid.UUID{r.ID},
+		})
+	}
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to tag item %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	return s.ListItemTags(ctx, repository.ListItemTagNamesParams{UserID: r.UserID, ItemIds: []uuid.UUID{r.ID}})
+}
+
+// ListItemTags lists the names of the tags of the user on an item.
+func (s *Service) ListItemTags(ctx context.Context, r repository.ListItemTagNamesParams) (*TagsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListItemTags")
+	defer span.End()
+
+	rows, err := s.Repo.ListItemTagNames(ctx, r)
+	if err != nil {
+		return nil, NewError("failed to list item tags", http.StatusInternalServerError)
+	}
+	tags := make([]string, len(rows))
+	for i, row := range rows {
+		tags[i] = row.Name
+	}
+	return &TagsResponse{Tags: tags}, nil
+}
+
+// SetFeedTags replaces the tags of the user on a feed they are subscribed
+// to, creating tags that do not exist yet.
+func (s *Service) SetFeedTags(ctx context.Context, r SetTagsRequest) (*TagsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.SetFeedTags")
+	defer span.End()
+
+	names, err := normalizeTagNames(r.Tags)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.Repo.GetUserFeedSubscription(ctx, repository.GetUserFeedSubscriptionParams{
+		UserID: r.UserID,
+		FeedID: r.ID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("user not subscribed to feed %s", r.ID),
+				http.StatusBadRequest,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to fetch subscription to feed %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+
+	ids, err := s.upsertTags(ctx, r.UserID, names)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.Repo.UntagFeedExcept(ctx, repository.UntagFeedExceptParams{
+		UserID:  r.UserID,
+		FeedID:  r.ID,
+		KeepIds: ids,
+	})
+	if err == nil && len(ids) > 0 {
+		_, err = s.Repo.TagFeeds(ctx, repository.TagFeedsParams{
+			TagIds:  ids,
+			UserID:  r.UserID,
+			FeedIds: []uuid.UUID{r.ID},
+		})
+	}
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to tag feed %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+
+	return s.ListFeedTags(ctx, repository.ListFeedTagNamesParams{UserID: r.UserID, FeedIds: []uuid.UUID{r.ID}})
+}
+
+// ListFeedTags lists the names of the tags of the user on a subscribed feed.
+func (s *Service) ListFeedTags(ctx context.Context, r repository.ListFeedTagNamesParams) (*TagsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListFeedTags")
+	defer span.End()
+
+	rows, err := s.Repo.ListFeedTagNames(ctx, r)
+	if err != nil {
+		return nil, NewError("failed to list feed tags", http.StatusInternalServerError)
+	}
+	tags := make([]string, len(rows))
+	for i, row := range rows {
+		tags[i] = row.Name
+	}
+	return &TagsResponse{Tags: tags}, nil
+}
+
+// BulkTag adds and removes tags of the user on many items and feeds at once.
+// Items that do not exist and feeds the user is not subscribed to are
+// skipped.
+func (s *Service) BulkTag(ctx context.Context, r BulkTagRequest) (*BulkTagResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.BulkTag")
+	defer span.End()
+
+	add, err := normalizeTagNames(r.Add)
+	if err != nil {
+		return nil, err
+	}
+	remove, err := normalizeTagNames(r.Remove)
+	if err != nil {
+		return nil, err
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, NewError("no tags to add or remove", http.StatusBadRequest)
+	}
+	if len(r.ItemIDs) == 0 && len(r.FeedIDs) == 0 {
+		return nil, NewError("no items or feeds to tag", http.StatusBadRequest)
+	}
+	if len(r.ItemIDs)+len(r.FeedIDs) > maxBulkTagTargets {
+		return nil, NewError(
+			fmt.Sprintf("at most %d items and feeds can be tagged at once", maxBulkTagTargets),
+			http.StatusBadRequest,
+		)
+	}
+	lower := make([]string, len(remove))
+	for i, name := range remove {
+		lower[i] = strings.ToLower(name)
+	}
+	for _, name := range add {
+		for _, l := range lower {
+			if strings.ToLower(name) == l {
+				return nil, NewError(
+					fmt.Sprintf("tag %s is both added and removed", name),
+					http.StatusBadRequest,
+				)
+			}
+		}
+	}
+
+	var resp BulkTagResponse
+	if len(add) > 0 {
+		ids, err := s.upsertTags(ctx, r.UserID, add)
+		if err != nil {
+			return nil, err
+		}
+		if len(r.ItemIDs) > 0 {
+			n, err := s.Repo.TagItems(ctx, repository.TagItemsParams{TagIds: ids, ItemIds: r.ItemIDs})
+			if err != nil {
+				return nil, NewError("failed to tag items", http.StatusInternalServerError)
+			}
+			resp.Tagged += n
+		}
+		if len(r.FeedIDs) > 0 {
+			n, err := s.Repo.TagFeeds(ctx, repository.TagFeedsParams{TagIds: ids, UserID: r.UserID, FeedIds: r.FeedIDs})
+			if err != nil {
+				return nil, NewError("failed to tag feeds", http.StatusInternalServerError)
+			}
+			resp.Tagged += n
+		}
+	}
+
+	if len(remove) > 0 {
+		tags, err := s.Repo.ListTagsByNames(ctx, repository.ListTagsByNamesParams{UserID: r.UserID, Names: lower})
+		if err != nil {
+			return nil, NewError("failed to fetch tags", http.StatusInternalServerError)
+		}
+		ids := make([]uuid.UUID, len(tags))
+		for i, t := range tags {
+			ids[i] = t.ID
+		}
+		if len(ids) > 0 && len(r.ItemIDs) > 0 {
+			n, err := s.Repo.UntagItems(ctx, repository.UntagItemsParams{TagIds: ids, ItemIds: r.ItemIDs})
+			if err != nil {
+				return nil, NewError("failed to untag items", http.StatusInternalServerError)
+			}
+			resp.Untagged += n
+		}
+		if len(ids) > 0 && len(r.FeedIDs) > 0 {
+			n, err := s.Repo.UntagFeeds(ctx, repository.UntagFeedsParams{TagIds: ids, FeedIds: r.FeedIDs})
+			if err != nil {
+				return nil, NewError("failed to untag feeds", http.StatusInternalServerError)
+			}
+			resp.Untagged += n
+		}
+	}
+	return &resp, nil
+}
+
+// ListFeedFolders lists the subscribed feeds of the user grouped by the folder
+// they are filed in or else by their tags, which act as folders. A folder and
+// a tag with the same name ignoring case are a single folder.
+func (s *Service) ListFeedFolders(ctx context.Context, userID uuid.UUID) (*ListFeedFoldersResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListFeedFolders")
+	defer span.End()
+
+	rows, err := s.Repo.ListFeedFolders(ctx, userID)
+	if err != nil {
+		return nil, NewError("failed to list feed folders", http.StatusInternalServerError)
+	}
+
+	resp := ListFeedFoldersResponse{
+		Folders: make([]FeedFolder, 0),
+		Unfiled: make([]FolderFeed, 0),
+	}
+	for _, row := range rows {
+		feed := FolderFeed{
+			ID:       row.ID,
+			Title:    row.Title,
+			Link:     row.Link,
+			FeedLink: row.FeedLink,
+		}
+		if row.Folder == nil {
+			resp.Unfiled = append(resp.Unfiled, feed)
+			continue
+		}
+		// rows are ordered by folder, so the feeds of a folder are adjacent
+		n := len(resp.Folders)
+		if n == 0 || !strings.EqualFold(resp.Folders[n-1].Name, *row.Folder) {
+			resp.Folders = append(resp.Folders, FeedFolder{Name: *row.Folder})
+		}
+		folder := &resp.Folders[len(resp.Folders)-1]
+		if folder.TagID == nil && row.TagID != nil {
+			folder.TagID, folder.Name = row.TagID, *row.Folder
+		}
+		folder.Feeds = append(folder.Feeds, feed)
+	}
+	return &resp, nil
+}
+
+// ListItemsByTag returns a paginated timeline of the items with a tag of the
+// user and the items of the subscribed feeds with the tag, newest first.
+func (s *Service) ListItemsByTag(ctx context.Context, r repository.ListItemsByTagParams) (*ListItemsResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListItemsByTag")
+	defer span.End()
+
+	if _, err := s.getTag(ctx, r.UserID, r.TagID); err != nil {
+		return nil, err
+	}
+	total, err := s.Repo.CountItemsByTag(ctx, repository.CountItemsByTagParams{
+		TagID:  r.TagID,
+		UserID: r.UserID,
+	})
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to count items with tag %s", r.TagID),
+			http.StatusInternalServerError,
+		)
+	}
+
+	var rows []repository.ListItemsByTagRow
+	if total == 0 {
+		rows = make([]repository.ListItemsByTagRow, 0)
+	} else {
+		rows, err = s.Repo.ListItemsByTag(ctx, r)
+		if err != nil {
+			return nil, NewError(
+				fmt.Sprintf("failed to list items with tag %s", r.TagID),
+				http.StatusInternalServerError,
+			)
+		}
+	}
+
+	items := make([]Item, len(rows))
+	for i, row := range rows {
+		auths := make(Authors, len(row.Authors))
+		for j, a := range row.Authors {
+			auths[j] = Person{Name: a.Name, Email: a.Email}
+		}
+
+		items[i] = Item{
+			ID:              row.ID,
+			FeedID:          row.FeedID,
+			Title:           row.Title,
+			Description:     row.Description,
+			Content:         row.Content,
+			Link:            row.Link,
+			Links:           row.Links,
+			UpdatedParsed:   row.UpdatedParsed,
+			PublishedParsed: row.PublishedParsed,
+			Authors:         auths,
+			GUID:            row.Guid,
+			Image:           row.Image,
+			Categories:      row.Categories,
+			Enclosures:      row.Enclosures,
+			CreatedAt:       row.CreatedAt,
+			UpdatedAt:       row.UpdatedAt,
+			Liked:           row.LikedAt != nil,
+			LikedAt:         row.LikedAt,
+		}
+	}
+
+	return &ListItemsResponse{
+		Limit:      r.Limit,
+		Offset:     r.Offset,
+		TotalCount: total,
+		Items:      items,
+	}, nil
+}
@@ -9,20 +9,26 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/repository"
 )
 
 // User is common model representing application user.
 type User struct {
-	ID            uuid.UUID `json:"id"`
-	Sub           string    `json:"sub"`
-	Name          string    `json:"name"`
-	Email         string    `json:"email"`
-	CreatedAt     time.Time `json:"createdAt"`
-	LastUpdatedAt time.Time `json:"lastUpdatedAt"`
+	ID            uuid.UUID  `json:"id"`
+	Sub           string     `json:"sub"`
+	Name          string     `json:"name"`
+	Email         string     `json:"email"`
+	Role          string     `json:"role"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	LastUpdatedAt time.Time  `json:"lastUpdatedAt"`
+	DisabledAt    *time.Time `json:"disabledAt,omitempty"`
 }
 
 // GetUserByID gets user by user ID.
 func (s *Service) GetUserByID(ctx context.Context, userID uuid.UUID) (*User, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetUserByID")
+	defer span.End()
+
 	user, err := s.Repo.GetUserByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -38,12 +44,26 @@ func (s *Service) GetUserByID(ctx context.Context, userID uuid.UUID) (*User, err
 		}
 	}
 
+	return userFromRecord(user), nil
+}
+
+func userFromRecord(rec repository.User) *User {
 	return &User{
-		ID:            user.ID,
-		Sub:           user.Sub,
-		Name:          user.Name,
-		Email:         user.Email,
-		CreatedAt:     user.CreatedAt,
-		LastUpdatedAt: user.CreatedAt,
-	}, nil
+		ID:            rec.ID,
+		Sub:           rec.Sub,
+		Name:          rec.Name,
+		Email:         rec.Email,
+		Role:          rec.Role,
+		CreatedAt:     rec.CreatedAt,
+		LastUpdatedAt: rec.LastUpdatedAt,
+		DisabledAt:    rec.DisabledAt,
+	}
+}
+
+// checkUserActive refuses users disabled by an administrator.
+func checkUserActive(user *User) error {
+	if user.DisabledAt != nil {
+		return NewError("user is disabled", http.StatusForbidden)
+	}
+	return nil
 }
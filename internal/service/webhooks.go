@@ -0,0 +1,466 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/netguard"
+	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/webhooks"
+	"github.com/rhajizada/gazette/internal/workers"
+)
+
+const (
+	// maxWebhooks bounds the number of webhooks of a user.
+	maxWebhooks = 20
+
+	// maxWebhookNameLength bounds the length of a webhook name in characters.
+	maxWebhookNameLength = 128
+
+	// maxWebhookURLLength bounds the length of a webhook URL.
+	maxWebhookURLLength = 2048
+
+	// minWebhookSecretLength and maxWebhookSecretLength bound the length of
+	// secrets chosen by users.
+	minWebhookSecretLength = 16
+	maxWebhookSecretLength = 256
+
+	// maxWebhookTargets bounds the number of feeds, collections and rules a
+	// webhook selects.
+	maxWebhookTargets = 50
+
+	// webhookSecretPrefix marks generated webhook secrets.
+	webhookSecretPrefix = "whsec_"
+)
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func webhookFromRecord(rec repository.Webhook) Webhook {
+	return Webhook{
+		ID:             rec.ID,
+		Name:           rec.Name,
+		URL:            rec.Url,
+		Events:         rec.Events,
+		FeedIDs:        rec.FeedIds,
+		CollectionIDs:  rec.CollectionIds,
+		RuleIDs:        rec.RuleIds,
+		Enabled:        rec.Enabled,
+		FailureCount:   rec.FailureCount,
+		DisabledReason: rec.DisabledReason,
+		LastDeliveryAt: rec.LastDeliveryAt,
+		CreatedAt:      rec.CreatedAt,
+		UpdatedAt:      rec.UpdatedAt,
+	}
+}
+
+func webhookDeliveryFromRecord(rec repository.WebhookDelivery) WebhookDelivery {
+	return WebhookDelivery{
+		ID:             rec.ID,
+		WebhookID:      rec.WebhookID,
+		Event:          rec.Event,
+		Status:         rec.Status,
+		Attempts:       rec.Attempts,
+		ResponseStatus: rec.ResponseStatus,
+		Error:          rec.Error,
+		DurationMs:     rec.DurationMs,
+		Payload:        json.RawMessage(rec.Payload),
+		CreatedAt:      rec.CreatedAt,
+		AttemptedAt:    rec.AttemptedAt,
+	}
+}
+
+// uniqueIDs removes duplicate IDs, keeping their order, and never returns
+// nil so that empty selections are stored as empty arrays.
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !slices.Contains(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// validateWebhook normalizes the definition of a webhook. Unless
+// allowPrivate is set, URLs with a private address or localhost as host are
+// rejected, names resolving to private addresses are refused on delivery.
+func validateWebhook(r *WebhookRequest, allowPrivate bool) error {
+	r.URL = strings.TrimSpace(r.URL)
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return NewError(
+			fmt.Sprintf("invalid webhook URL %s, expected an http or https URL", r.URL),
+			http.StatusBadRequest,
+		)
+	}
+	if !allowPrivate && !netguard.PublicHost(u.Hostname()) {
+		return NewError(
+			fmt.Sprintf("invalid webhook URL %s, webhooks cannot target private addresses", r.URL),
+			http.StatusBadRequest,
+		)
+	}
+	if len(r.URL) > maxWebhookURLLength {
+		return NewError(
+			fmt.Sprintf("url must be at most %d bytes", maxWebhookURLLength),
+			http.StatusBadRequest,
+		)
+	}
+
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		r.Name = u.Host
+	}
+	if utf8.RuneCountInString(r.Name) > maxWebhookNameLength {
+		return NewError(
+			fmt.Sprintf("name must be at most %d characters", maxWebhookNameLength),
+			http.StatusBadRequest,
+		)
+	}
+
+	if r.Secret != nil && *r.Secret != "" {
+		n := len(*r.Secret)
+		if n < minWebhookSecretLength || n > maxWebhookSecretLength {
+			return NewError(
+				fmt.Sprintf("secret must be between %d and %d bytes", minWebhookSecretLength, maxWebhookSecretLength),
+				http.StatusBadRequest,
+			)
+		}
+	}
+
+	events := make([]string, 0, len(r.Events))
+	for _, event := range r.Events {
+		if !webhooks.ValidEvent(event) {
+			return NewError(
+				fmt.Sprintf("invalid event %s, expected %s, %s or %s", event,
+					webhooks.EventFeedItem, webhooks.EventCollectionItem, webhooks.EventRuleMatch),
+				http.StatusBadRequest,
+			)
+		}
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		return NewError("at least one event is required", http.StatusBadRequest)
+	}
+	r.Events = events
+
+	r.FeedIDs = uniqueIDs(r.FeedIDs)
+	r.CollectionIDs = uniqueIDs(r.CollectionIDs)
+	r.RuleIDs = uniqueIDs(r.RuleIDs)
+	if len(r.FeedIDs) > maxWebhookTargets || len(r.CollectionIDs) > maxWebhookTargets || len(r.RuleIDs) > maxWebhookTargets {
+		return NewError(
+			fmt.Sprintf("webhooks can select at most %d feeds, collections and rules each", maxWebhookTargets),
+			http.StatusBadRequest,
+		)
+	}
+
+	if r.Enabled == nil {
+		enabled := true
+		r.Enabled = &enabled
+	}
+	return nil
+}
+
+// checkWebhookTargets checks that the user is subscribed to the feeds a
+// webhook selects and can access its collections and rules.
+func (s *Service) checkWebhookTargets(ctx context.Context, r WebhookRequest) error {
+	if len(r.FeedIDs) > 0 {
+		subs, err := s.feedSubscriptions(ctx, r.UserID, r.FeedIDs)
+		if err != nil {
+			return NewError("failed to fetch subscriptions", http.StatusInternalServerError)
+		}
+		for _, id := range r.FeedIDs {
+			if _, ok := subs[id]; !ok {
+				return NewError(
+					fmt.Sprintf("user not subscribed to feed %s", id),
+					http.StatusBadRequest,
+				)
+			}
+		}
+	}
+	for _, id := range r.CollectionIDs {
+		if _, _, err := s.accessCollection(ctx, r.UserID, id, CollectionRoleViewer); err != nil {
+			return err
+		}
+	}
+	if len(r.RuleIDs) > 0 {
+		rules, err := s.Repo.ListFilterRules(ctx, r.UserID)
+		if err != nil {
+			return NewError("failed to list filter rules", http.StatusInternalServerError)
+		}
+		for _, id := range r.RuleIDs {
+			if !slices.ContainsFunc(rules, func(rule repository.FilterRule) bool { return rule.ID == id }) {
+				return NewError(
+					fmt.Sprintf("filter rule %s not found", id),
+					http.StatusBadRequest,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// getWebhook fetches a webhook of the user.
+func (s *Service) getWebhook(ctx context.Context, r repository.GetWebhookParams) (*repository.Webhook, error) {
+	rec, err := s.Repo.GetWebhook(ctx, r)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("webhook %s not found", r.ID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to fetch webhook %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	return &rec, nil
+}
+
+// CreateWebhook creates a webhook of the user. The response holds its secret,
+// which is not returned again.
+func (s *Service) CreateWebhook(ctx context.Context, r WebhookRequest) (*Webhook, error) {
+	ctx, span := tracer.Start(ctx, "Service.CreateWebhook")
+	defer span.End()
+
+	if err := validateWebhook(&r, s.AllowPrivateWebhooks); err != nil {
+		return nil, err
+	}
+	if err := s.checkWebhookTargets(ctx, r); err != nil {
+		return nil, err
+	}
+	count, err := s.Repo.CountWebhooks(ctx, r.UserID)
+	if err != nil {
+		return nil, NewError("failed to count webhooks", http.StatusInternalServerError)
+	}
+	if count >= maxWebhooks {
+		return nil, NewError(
+			fmt.Sprintf("users can have at most %d webhooks", maxWebhooks),
+			http.StatusBadRequest,
+		)
+	}
+
+	secret := ""
+	if r.Secret != nil {
+		secret = *r.Secret
+	}
+	if secret == "" {
+		if secret, err = newWebhookSecret(); err != nil {
+			return nil, NewError("failed to generate webhook secret", http.StatusInternalServerError)
+		}
+	}
+
+	rec, err := s.Repo.CreateWebhook(ctx, repository.CreateWebhookParams{
+		UserID:        r.UserID,
+		Name:          r.Name,
+		Url:           r.URL,
+		Secret:        secret,
+		Events:        r.Events,
+		FeedIds:       r.FeedIDs,
+		CollectionIds: r.CollectionIDs,
+		RuleIds:       r.RuleIDs,
+		Enabled:       *r.Enabled,
+	})
+	if err != nil {
+		return nil, NewError("failed to create webhook", http.StatusInternalServerError)
+	}
+	hook := webhookFromRecord(rec)
+	hook.Secret = rec.Secret
+	return &hook, nil
+}
+
+// GetWebhook retrieves a webhook of the user.
+func (s *Service) GetWebhook(ctx context.Context, r repository.GetWebhookParams) (*Webhook, error) {
+	ctx, span := tracer.Start(ctx, "Service.GetWebhook")
+	defer span.End()
+
+	rec, err := s.getWebhook(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	hook := webhookFromRecord(*rec)
+	return &hook, nil
+}
+
+// ListWebhooks lists the webhooks of the user, oldest first.
+func (s *Service) ListWebhooks(ctx context.Context, userID uuid.UUID) (*ListWebhooksResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListWebhooks")
+	defer span.End()
+
+	recs, err := s.Repo.ListWebhooks(ctx, userID)
+	if err != nil {
+		return nil, NewError("failed to list webhooks", http.StatusInternalServerError)
+	}
+	hooks := make([]Webhook, len(recs))
+	for i, rec := range recs {
+		hooks[i] = webhookFromRecord(rec)
+	}
+	return &ListWebhooksResponse{Webhooks: hooks}, nil
+}
+
+// UpdateWebhook replaces the definition of a webhook of the user. Enabling a
+// webhook that was disabled after failed deliveries resets its failures.
+func (s *Service) UpdateWebhook(ctx context.Context, r UpdateWebhookRequest) (*Webhook, error) {
+	ctx, span := tracer.Start(ctx, "Service.UpdateWebhook")
+	defer span.End()
+
+	if err := validateWebhook(&r.WebhookRequest, s.AllowPrivateWebhooks); err != nil {
+		return nil, err
+	}
+	if _, err := s.getWebhook(ctx, repository.GetWebhookParams{ID: r.WebhookID, UserID: r.UserID}); err != nil {
+		return nil, err
+	}
+	if err := s.checkWebhookTargets(ctx, r.WebhookRequest); err != nil {
+		return nil, err
+	}
+	if r.Secret != nil && *r.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return nil, NewError("failed to generate webhook secret", http.StatusInternalServerError)
+		}
+		r.Secret = &secret
+	}
+
+	rec, err := s.Repo.UpdateWebhook(ctx, repository.UpdateWebhookParams{
+		Name:          r.Name,
+		Url:           r.URL,
+		Secret:        r.Secret,
+		Events:        r.Events,
+		FeedIds:       r.FeedIDs,
+		CollectionIds: r.CollectionIDs,
+		RuleIds:       r.RuleIDs,
+		Enabled:       *r.Enabled,
+		ID:            r.WebhookID,
+		UserID:        r.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, NewError(
+				fmt.Sprintf("webhook %s not found", r.WebhookID),
+				http.StatusNotFound,
+			)
+		}
+		return nil, NewError(
+			fmt.Sprintf("failed to update webhook %s", r.WebhookID),
+			http.StatusInternalServerError,
+		)
+	}
+	hook := webhookFromRecord(rec)
+	if r.Secret != nil {
+		hook.Secret = rec.Secret
+	}
+	return &hook, nil
+}
+
+// DeleteWebhook deletes a webhook of the user with its delivery log.
+func (s *Service) DeleteWebhook(ctx context.Context, r repository.DeleteWebhookParams) error {
+	ctx, span := tracer.Start(ctx, "Service.DeleteWebhook")
+	defer span.End()
+
+	deleted, err := s.Repo.DeleteWebhook(ctx, r)
+	if err != nil {
+		return NewError(
+			fmt.Sprintf("failed to delete webhook %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	if deleted == 0 {
+		return NewError(
+			fmt.Sprintf("webhook %s not found", r.ID),
+			http.StatusNotFound,
+		)
+	}
+	return nil
+}
+
+// TestWebhook queues a ping event to a webhook of the user, delivered and
+// logged like any other event.
+func (s *Service) TestWebhook(ctx context.Context, r repository.GetWebhookParams) (*WebhookDelivery, error) {
+	ctx, span := tracer.Start(ctx, "Service.TestWebhook")
+	defer span.End()
+
+	rec, err := s.getWebhook(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if !rec.Enabled {
+		return nil, NewError(
+			fmt.Sprintf("webhook %s is disabled", r.ID),
+			http.StatusBadRequest,
+		)
+	}
+	deliveries, err := workers.QueueWebhookDeliveries(ctx, &s.Repo, s.Client, []repository.Webhook{*rec}, webhooks.EventPing, webhooks.Data{})
+	if err != nil || len(deliveries) == 0 {
+		return nil, NewError(
+			fmt.Sprintf("failed to queue test delivery to webhook %s", r.ID),
+			http.StatusInternalServerError,
+		)
+	}
+	delivery := webhookDeliveryFromRecord(deliveries[0])
+	return &delivery, nil
+}
+
+// ListWebhookDeliveries lists the delivery log of a webhook of the user,
+// newest first.
+func (s *Service) ListWebhookDeliveries(ctx context.Context, r ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	ctx, span := tracer.Start(ctx, "Service.ListWebhookDeliveries")
+	defer span.End()
+
+	if _, err := s.getWebhook(ctx, repository.GetWebhookParams{ID: r.WebhookID, UserID: r.UserID}); err != nil {
+		return nil, err
+	}
+	total, err := s.Repo.CountWebhookDeliveries(ctx, r.WebhookID)
+	if err != nil {
+		return nil, NewError(
+			fmt.Sprintf("failed to count deliveries of webhook %s", r.WebhookID),
+			http.StatusInternalServerError,
+		)
+	}
+
+	var recs []repository.WebhookDelivery
+	if total == 0 {
+		recs = make([]repository.WebhookDelivery, 0)
+	} else {
+		recs, err = s.Repo.ListWebhookDeliveries(ctx, repository.ListWebhookDeliveriesParams{
+			WebhookID: r.WebhookID,
+			Limit:     r.Limit,
+			Offset:    r.Offset,
+		})
+		if err != nil {
+			return nil, NewError(
+				fmt.Sprintf("failed to list deliveries of webhook %s", r.WebhookID),
+				http.StatusInternalServerError,
+			)
+		}
+	}
+
+	deliveries := make([]WebhookDelivery, len(recs))
+	for i, rec := range recs {
+		deliveries[i] = webhookDeliveryFromRecord(rec)
+	}
+	return &ListWebhookDeliveriesResponse{
+		Limit:      r.Limit,
+		Offset:     r.Offset,
+		TotalCount: total,
+		Deliveries: deliveries,
+	}, nil
+}
@@ -0,0 +1,230 @@
+// Package webhooks builds, signs and delivers the events posted to the
+// outbound webhooks of users.
+//
+// Every delivery is a POST of a JSON Event with the headers:
+//
+//	X-Gazette-Event      event type, e.g. feed.item
+//	X-Gazette-Delivery   ID of the delivery, the same across retries
+//	X-Gazette-Timestamp  Unix time the request was signed at
+//	X-Gazette-Signature  sha256=<hex HMAC-SHA256 of "<timestamp>.<body>">
+//
+// keyed by the secret of the webhook. Receivers check the signature with
+// Verify and should reject stale timestamps.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/netguard"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Events webhooks subscribe to.
+const (
+	// EventFeedItem is sent when a new item of a subscribed feed is synced.
+	EventFeedItem = "feed.item"
+	// EventCollectionItem is sent when an item is added to a collection.
+	EventCollectionItem = "collection.item"
+	// EventRuleMatch is sent when a filter rule matches a new item.
+	EventRuleMatch = "rule.match"
+	// EventPing is sent on demand to test a webhook.
+	EventPing = "ping"
+)
+
+// States of deliveries.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Headers of deliveries.
+const (
+	EventHeader     = "X-Gazette-Event"
+	DeliveryHeader  = "X-Gazette-Delivery"
+	TimestampHeader = "X-Gazette-Timestamp"
+	SignatureHeader = "X-Gazette-Signature"
+)
+
+const (
+	// MaxRetry is the number of times a failed delivery is retried.
+	MaxRetry = 8
+
+	// MaxFailures is the number of deliveries in a row that may fail after
+	// all their retries before their webhook is disabled.
+	MaxFailures = 5
+
+	// DeliveryRetention is how long deliveries are kept in the log.
+	DeliveryRetention = 30 * 24 * time.Hour
+
+	// Timeout bounds a delivery request, including reading the response.
+	Timeout = 10 * time.Second
+
+	// maxResponseBytes bounds how much of a response is read.
+	maxResponseBytes = 4 << 10
+)
+
+// ValidEvent reports whether webhooks can subscribe to event.
+func ValidEvent(event string) bool {
+	switch event {
+	case EventFeedItem, EventCollectionItem, EventRuleMatch:
+		return true
+	}
+	return false
+}
+
+// Event is the body of a delivery.
+type Event struct {
+	Event     string    `json:"event"`
+	WebhookID uuid.UUID `json:"webhook_id"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      Data      `json:"data"`
+}
+
+// Data describes what an event is about. Ping events carry no item.
+type Data struct {
+	Item       *Item       `json:"item,omitempty"`
+	Feed       *Feed       `json:"feed,omitempty"`
+	Collection *Collection `json:"collection,omitempty"`
+	Rule       *Rule       `json:"rule,omitempty"`
+}
+
+type Item struct {
+	ID              uuid.UUID  `json:"id"`
+	FeedID          uuid.UUID  `json:"feed_id"`
+	Title           *string    `json:"title,omitempty"`
+	Summary         string     `json:"summary,omitempty"`
+	Link            string     `json:"link"`
+	Authors         []string   `json:"authors,omitempty"`
+	Categories      []string   `json:"categories,omitempty"`
+	PublishedParsed *time.Time `json:"published_parsed,omitempty"`
+}
+
+type Feed struct {
+	ID    uuid.UUID `json:"id"`
+	Title *string   `json:"title,omitempty"`
+	Link  *string   `json:"link,omitempty"`
+}
+
+type Collection struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type Rule struct {
+	ID     uuid.UUID `json:"id"`
+	Name   string    `json:"name"`
+	Action string    `json:"action"`
+}
+
+// Sign returns the signature of a body signed at timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a delivery given its timestamp header.
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, ts, body)), []byte(signature))
+}
+
+// NewClient returns a client to send deliveries with. It only connects to
+// public addresses unless allowPrivate is set, so webhooks cannot reach the
+// network of the server. Redirects are not followed, a webhook must point at
+// its final URL.
+func NewClient(allowPrivate bool) *http.Client {
+	var transport http.RoundTripper = netguard.Transport()
+	if allowPrivate {
+		transport = http.DefaultTransport
+	}
+	return &http.Client{
+		Timeout:   Timeout,
+		Transport: otelhttp.NewTransport(transport),
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Result is the outcome of a delivery attempt.
+type Result struct {
+	// StatusCode is the response status, zero when no response was received.
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// Retryable reports whether a failed attempt may succeed when retried.
+// Redirects, client errors other than timeouts and rate limits, and refused
+// private addresses are not retried.
+func (r Result) Retryable() bool {
+	if errors.Is(r.Err, netguard.ErrPrivateAddress) {
+		return false
+	}
+	if r.StatusCode >= 300 && r.StatusCode < 500 {
+		return r.StatusCode == http.StatusRequestTimeout || r.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// Deliver posts a signed event body to url. Any 2xx response is a success.
+func Deliver(ctx context.Context, client *http.Client, url, secret, event string, deliveryID uuid.UUID, body []byte) Result {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Result{Err: err}
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Gazette-Webhooks/1.0")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(DeliveryHeader, deliveryID.String())
+	req.Header.Set(TimestampHeader, strconv.FormatInt(ts, 10))
+	req.Header.Set(SignatureHeader, Sign(secret, ts, body))
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return Result{Duration: time.Since(start), Err: err}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	res := Result{StatusCode: resp.StatusCode, Duration: time.Since(start)}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		res.Err = fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return res
+}
+
+// ErrDisabled is recorded on deliveries of webhooks disabled in the meantime.
+var ErrDisabled = errors.New("webhook is disabled")
+
+// Backoff returns how long to wait before retrying a delivery that failed n
+// times: 30 seconds doubling up to an hour, with up to 10% jitter.
+func Backoff(n int) time.Duration {
+	d := 30 * time.Second
+	for i := 0; i < n && d < time.Hour; i++ {
+		d *= 2
+	}
+	d = min(d, time.Hour)
+	return d + rand.N(d/10+1)
+}
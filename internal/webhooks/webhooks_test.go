@@ -0,0 +1,141 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/netguard"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"event":"ping"}`)
+	sig := Sign("secret", 1700000000, body)
+	if !Verify("secret", "1700000000", body, sig) {
+		t.Fatal("signature does not verify")
+	}
+	if Verify("other", "1700000000", body, sig) {
+		t.Error("signature verifies with another secret")
+	}
+	if Verify("secret", "1700000001", body, sig) {
+		t.Error("signature verifies with another timestamp")
+	}
+	if Verify("secret", "1700000000", []byte(`{"event":"pong"}`), sig) {
+		t.Error("signature verifies with another body")
+	}
+}
+
+func TestDeliverSigns(t *testing.T) {
+	body := []byte(`{"event":"ping"}`)
+	deliveryID := uuid.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method != http.MethodPost:
+			t.Errorf("got method %s", r.Method)
+		case r.Header.Get(EventHeader) != EventPing:
+			t.Errorf("got event %q", r.Header.Get(EventHeader))
+		case r.Header.Get(DeliveryHeader) != deliveryID.String():
+			t.Errorf("got delivery %q", r.Header.Get(DeliveryHeader))
+		case !Verify("secret", r.Header.Get(TimestampHeader), got, r.Header.Get(SignatureHeader)):
+			t.Error("signature does not verify")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	res := Deliver(context.Background(), NewClient(true), srv.URL, "secret", EventPing, deliveryID, body)
+	if res.Err != nil || res.StatusCode != http.StatusNoContent {
+		t.Fatalf("got %d: %v", res.StatusCode, res.Err)
+	}
+}
+
+func TestDeliverRetryable(t *testing.T) {
+	tests := []struct {
+		status    int
+		failed    bool
+		retryable bool
+	}{
+		{http.StatusOK, false, true},
+		{http.StatusAccepted, false, true},
+		{http.StatusMovedPermanently, true, false},
+		{http.StatusBadRequest, true, false},
+		{http.StatusNotFound, true, false},
+		{http.StatusRequestTimeout, true, true},
+		{http.StatusTooManyRequests, true, true},
+		{http.StatusInternalServerError, true, true},
+		{http.StatusServiceUnavailable, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status == http.StatusMovedPermanently {
+					w.Header().Set("Location", "/elsewhere")
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			res := Deliver(context.Background(), NewClient(true), srv.URL, "secret", EventPing, uuid.New(), nil)
+			if res.StatusCode != tt.status {
+				t.Fatalf("got status %d, want %d", res.StatusCode, tt.status)
+			}
+			if (res.Err != nil) != tt.failed {
+				t.Errorf("got error %v, want failed %v", res.Err, tt.failed)
+			}
+			if tt.failed && res.Retryable() != tt.retryable {
+				t.Errorf("got retryable %v, want %v", res.Retryable(), tt.retryable)
+			}
+		})
+	}
+}
+
+func TestDeliverRetriesUnreachableReceivers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := srv.URL
+	srv.Close()
+
+	res := Deliver(context.Background(), NewClient(true), url, "secret", EventPing, uuid.New(), nil)
+	if res.Err == nil || res.StatusCode != 0 {
+		t.Fatalf("got %d: %v", res.StatusCode, res.Err)
+	}
+	if !res.Retryable() {
+		t.Error("unreachable receivers are not retried")
+	}
+}
+
+func TestDeliverRefusesPrivateAddresses(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer srv.Close()
+
+	res := Deliver(context.Background(), NewClient(false), srv.URL, "secret", EventPing, uuid.New(), nil)
+	if !errors.Is(res.Err, netguard.ErrPrivateAddress) {
+		t.Fatalf("got %v, want %v", res.Err, netguard.ErrPrivateAddress)
+	}
+	if res.Retryable() {
+		t.Error("deliveries to private addresses are retried")
+	}
+	if n := hits.Load(); n != 0 {
+		t.Errorf("receiver got %d requests", n)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for n, want := range []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute} {
+		if got := Backoff(n); got < want || got > want+want/10 {
+			t.Errorf("Backoff(%d) = %s, want %s plus up to 10%%", n, got, want)
+		}
+	}
+	if got := Backoff(20); got < time.Hour || got > time.Hour+time.Hour/10 {
+		t.Errorf("Backoff(20) = %s, want an hour plus up to 10%%", got)
+	}
+}
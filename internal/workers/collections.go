@@ -0,0 +1,89 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/ollama/ollama/api"
+	"github.com/rhajizada/gazette/internal/logging"
+	"github.com/rhajizada/gazette/internal/metrics"
+	"github.com/rhajizada/gazette/internal/repository"
+)
+
+// collectionSeed is the part of the rules of a smart collection the worker
+// needs, the rules themselves are defined by the service.
+type collectionSeed struct {
+	SimilarTo *string `json:"similar_to"`
+}
+
+// HandleEmbedCollection embeds the seed text of a smart collection, which
+// items are compared against when the collection is listed. The embedding is
+// removed when the collection no longer has a seed text.
+func (h *Handler) HandleEmbedCollection(ctx context.Context, t *asynq.Task) error {
+	var p EmbedCollectionPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v", asynq.SkipRetry)
+	}
+	collectionID := p.CollectionID
+	ctx = logging.With(ctx, slog.String("collection_id", collectionID.String()))
+
+	rules, err := h.Repo.GetCollectionRulesByID(ctx, collectionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.InfoContext(ctx, "collection was deleted, skipping embedding")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch collection %s: %v", collectionID, err)
+	}
+
+	var seed collectionSeed
+	if len(rules) > 0 {
+		if err := json.Unmarshal(rules, &seed); err != nil {
+			return fmt.Errorf("invalid rules for collection %s: %v", collectionID, asynq.SkipRetry)
+		}
+	}
+	if seed.SimilarTo == nil || strings.TrimSpace(*seed.SimilarTo) == "" {
+		if err := h.Repo.DeleteCollectionEmbeddingByID(ctx, collectionID); err != nil {
+			return fmt.Errorf("failed to delete embedding for collection %s: %v", collectionID, err)
+		}
+		slog.InfoContext(ctx, "collection has no seed text, removed embedding")
+		return nil
+	}
+
+	client, err := GetOllamaClient(h.OllamaConfig)
+	if err != nil {
+		return fmt.Errorf("failed to initialize ollama client: %v", err)
+	}
+
+	req := api.EmbeddingRequest{
+		Model:  h.OllamaConfig.EmbeddingsModel,
+		Prompt: *seed.SimilarTo,
+	}
+
+	start := time.Now()
+	resp, err := client.Embeddings(ctx, &req)
+	metrics.EmbeddingDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.EmbeddingFailures.Inc()
+		return fmt.Errorf("failed to generate embedding for collection %s: %v", collectionID, err)
+	}
+
+	embeddingValue := vectorFromFloat64s(resp.Embedding)
+	err = h.Repo.UpsertCollectionEmbedding(ctx, repository.UpsertCollectionEmbeddingParams{
+		CollectionID: collectionID,
+		Embedding:    &embeddingValue,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store embedding for collection %s: %v", collectionID, err)
+	}
+	slog.InfoContext(ctx, "stored embedding")
+
+	return nil
+}
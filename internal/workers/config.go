@@ -7,14 +7,16 @@ import (
 
 // GetConfig generates asynq server configuration.
 func GetConfig(cfg *config.QueuesConfig) *asynq.Config {
-	total := cfg.Critical + cfg.Default + cfg.Low
+	total := cfg.Critical + cfg.Default + cfg.Low + cfg.Webhooks
 	return &asynq.Config{
 		Concurrency: total,
 		Queues: map[string]int{
-			"critical": cfg.Critical,
-			"default":  cfg.Default,
-			"low":      cfg.Low,
+			"critical":   cfg.Critical,
+			"default":    cfg.Default,
+			"low":        cfg.Low,
+			WebhookQueue: cfg.Webhooks,
 		},
 		StrictPriority: true,
+		RetryDelayFunc: retryDelay,
 	}
 }
@@ -5,17 +5,17 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/hibiken/asynq"
 	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/webhooks"
 )
 
 func (h *Handler) HandleDataSync(ctx context.Context, t *asynq.Task) error {
-	prefix := fmt.Sprintf(
-		"task %s -",
-		t.ResultWriter().TaskID(),
-	)
+	h.pruneWebhookDeliveries(ctx)
+
 	count, err := h.Repo.CountFeeds(ctx)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return fmt.Errorf("failed to count feeds: %v", err)
@@ -39,19 +39,38 @@ func (h *Handler) HandleDataSync(ctx context.Context, t *asynq.Task) error {
 		}
 
 		for _, feed := range feeds {
-			task, err := NewSyncFeedTask(feed.ID)
+			task, err := NewSyncFeedTask(ctx, feed.ID)
 			if err != nil {
 				return err
 			}
 
-			ti, err := h.Client.Enqueue(task, asynq.Queue("critical"))
+			_, duplicate, err := Enqueue(ctx, h.Client, h.Inspector, task, SyncFeedTaskID(feed.ID), "critical")
 			if err != nil {
 				return err
 			}
-			log.Printf("%s queued sync task %s for feed %s", prefix, ti.ID, feed.ID)
-
+			if duplicate {
+				slog.DebugContext(ctx, "sync task is already queued", slog.String("feed_id", feed.ID.String()))
+				continue
+			}
+			slog.InfoContext(ctx, "queued sync task",
+				slog.String("feed_id", feed.ID.String()),
+				slog.String("queued_task_id", SyncFeedTaskID(feed.ID)),
+			)
 		}
 
 	}
 	return nil
 }
+
+// pruneWebhookDeliveries removes deliveries older than the retention of the
+// delivery log.
+func (h *Handler) pruneWebhookDeliveries(ctx context.Context) {
+	deleted, err := h.Repo.DeleteWebhookDeliveriesBefore(ctx, time.Now().Add(-webhooks.DeliveryRetention))
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to prune webhook deliveries", slog.Any("error", err))
+		return
+	}
+	if deleted > 0 {
+		slog.InfoContext(ctx, "pruned webhook deliveries", slog.Int64("deleted", deleted))
+	}
+}
@@ -1,37 +1,92 @@
 package workers
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 	"github.com/mmcdole/gofeed"
+	"github.com/rhajizada/gazette/internal/logging"
+	"github.com/rhajizada/gazette/internal/metrics"
 	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/tracing"
 	"github.com/rhajizada/gazette/internal/typeext"
 )
 
-func (h *Handler) HandleFeedSync(ctx context.Context, t *asynq.Task) error {
-	prefix := fmt.Sprintf(
-		"task %s -",
-		t.ResultWriter().TaskID(),
-	)
+// fetchFeed downloads and parses the feed at url, recording fetch metrics.
+func fetchFeed(ctx context.Context, url string) (*gofeed.Feed, error) {
+	fp := gofeed.NewParser()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", fp.UserAgent)
+
+	start := time.Now()
+	resp, err := tracing.HTTPClient.Do(req)
+	if err != nil {
+		metrics.FeedFetchDuration.WithLabelValues("error").Observe(time.Since(start).Seconds())
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	metrics.FeedFetchBytes.Add(float64(len(body)))
+	metrics.FeedFetchDuration.WithLabelValues(strconv.Itoa(resp.StatusCode)).Observe(time.Since(start).Seconds())
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, gofeed.HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fp.Parse(bytes.NewReader(body))
+}
+
+// recordFeedHealth stores the outcome of a feed sync for the admin API.
+func (h *Handler) recordFeedHealth(ctx context.Context, feedID uuid.UUID, syncErr error) {
+	var err error
+	if syncErr == nil {
+		err = h.Repo.RecordFeedSyncSuccess(ctx, feedID)
+	} else {
+		msg := syncErr.Error()
+		err = h.Repo.RecordFeedSyncFailure(ctx, repository.RecordFeedSyncFailureParams{
+			FeedID:    feedID,
+			LastError: &msg,
+		})
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record sync health", slog.Any("error", err))
+	}
+}
+
+func (h *Handler) HandleFeedSync(ctx context.Context, t *asynq.Task) (err error) {
 	var p SyncFeedPayload
 	if err := json.Unmarshal(t.Payload(), &p); err != nil {
 		return fmt.Errorf("json.Unmarshal failed: %v", asynq.SkipRetry)
 	}
 	feedID := p.FeedID
+	ctx = logging.With(ctx, slog.String("feed_id", feedID.String()))
+	defer func() { h.recordFeedHealth(ctx, feedID, err) }()
 
 	data, err := h.Repo.GetFeedByID(ctx, feedID)
 	if err != nil {
 		return fmt.Errorf("failed to get feed %q: %v", feedID, err)
 	}
 
-	fp := gofeed.NewParser()
-	feed, err := fp.ParseURL(data.FeedLink)
+	feed, err := fetchFeed(ctx, data.FeedLink)
 	if err != nil {
 		return fmt.Errorf("failed to parse feed %q: %v", feedID, err)
 	}
@@ -53,7 +108,15 @@ func (h *Handler) HandleFeedSync(ctx context.Context, t *asynq.Task) error {
 		}
 	}
 
-	log.Printf("%s %d items to sync for feed %q", prefix, len(itemsToSync), feedID)
+	slog.InfoContext(ctx, "syncing feed items", slog.Int("items", len(itemsToSync)))
+
+	var rules []filterRule
+	if len(itemsToSync) > 0 {
+		rules, err = h.loadFilterRules(ctx, feedID)
+		if err != nil {
+			return fmt.Errorf("failed to load filter rules for feed %q: %v", feedID, err)
+		}
+	}
 
 	for _, itm := range itemsToSync {
 		content := &itm.Content
@@ -80,16 +143,24 @@ func (h *Handler) HandleFeedSync(ctx context.Context, t *asynq.Task) error {
 		if err != nil {
 			return fmt.Errorf("failed to create item %q for feed %q: %v", itm.GUID, feedID, err)
 		}
-		log.Printf("%s synced item %s from feed %s", prefix, itm.GUID, feedID)
-		task, _ := NewEmbedItemTask(r.ID)
-		tResp, err := h.Client.Enqueue(task, asynq.Queue("default"))
+		metrics.ItemsIngested.WithLabelValues(feedID.String()).Inc()
+		itemCtx := logging.With(ctx,
+			slog.String("item_id", r.ID.String()),
+			slog.String("guid", itm.GUID),
+		)
+		slog.DebugContext(itemCtx, "synced item")
+		h.applyFilterRules(itemCtx, rules, data, r)
+		h.queueFeedItemWebhooks(itemCtx, data, r)
+		task, _ := NewEmbedItemTask(ctx, r.ID)
+		_, duplicate, err := Enqueue(ctx, h.Client, h.Inspector, task, EmbedItemTaskID(r.ID), "default")
 		if err != nil {
 			return fmt.Errorf("failed to queue embedding task for item %s", r.ID)
 		}
-		log.Printf(
-			"%s queued embdding task %s for item %s ",
-			prefix, r.ID, tResp.ID,
-		)
+		if duplicate {
+			slog.DebugContext(itemCtx, "embedding task is already queued")
+			continue
+		}
+		slog.DebugContext(itemCtx, "queued embedding task", slog.String("queued_task_id", EmbedItemTaskID(r.ID)))
 	}
 
 	return nil
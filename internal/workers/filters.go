@@ -0,0 +1,119 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/rhajizada/gazette/internal/filters"
+	"github.com/rhajizada/gazette/internal/metrics"
+	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/webhooks"
+)
+
+// filterRule is a filter rule of a subscriber of a feed compiled for
+// matching.
+type filterRule struct {
+	repository.FilterRule
+	matcher *filters.Matcher
+}
+
+// loadFilterRules compiles the enabled filter rules of the subscribers of a
+// feed. Rules that no longer compile are skipped.
+func (h *Handler) loadFilterRules(ctx context.Context, feedID uuid.UUID) ([]filterRule, error) {
+	recs, err := h.Repo.ListFilterRulesForFeed(ctx, feedID)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]filterRule, 0, len(recs))
+	for _, rec := range recs {
+		m, err := filters.Compile(rec.Field, rec.MatchType, rec.Pattern)
+		if err != nil {
+			slog.WarnContext(ctx, "skipping invalid filter rule",
+				slog.String("rule_id", rec.ID.String()),
+				slog.Any("error", err),
+			)
+			continue
+		}
+		rules = append(rules, filterRule{FilterRule: rec, matcher: m})
+	}
+	return rules, nil
+}
+
+// applyFilterRules runs the actions of the rules matching a new item, counts
+// their hits and notifies webhooks of the matches. The item is already
+// stored, so failures are logged rather than failing the sync.
+func (h *Handler) applyFilterRules(ctx context.Context, rules []filterRule, feed repository.Feed, item repository.Item) {
+	if len(rules) == 0 {
+		return
+	}
+	fi := filters.Item{
+		Link:       item.Link,
+		Authors:    filters.AuthorNames(item.Authors),
+		Categories: item.Categories,
+	}
+	if item.Title != nil {
+		fi.Title = *item.Title
+	}
+	if item.Description != nil {
+		fi.Description = *item.Description
+	}
+	if item.Content != nil {
+		fi.Content = *item.Content
+	}
+
+	for _, rule := range rules {
+		if !rule.matcher.Match(fi) {
+			continue
+		}
+		var err error
+		added := false
+		switch rule.Action {
+		case filters.ActionHide:
+			_, err = h.Repo.HideItems(ctx, repository.HideItemsParams{
+				UserID:  rule.UserID,
+				RuleID:  rule.ID,
+				ItemIds: []uuid.UUID{item.ID},
+			})
+		case filters.ActionMarkRead:
+			err = h.Repo.CreateUserRead(ctx, repository.CreateUserReadParams{
+				UserID: rule.UserID,
+				ItemID: item.ID,
+			})
+		case filters.ActionAddToCollection:
+			if rule.CollectionID != nil {
+				var n int64
+				n, err = h.Repo.AddItemToCollectionByRule(ctx, repository.AddItemToCollectionByRuleParams{
+					ItemID:       item.ID,
+					UserID:       rule.UserID,
+					CollectionID: *rule.CollectionID,
+				})
+				added = n > 0
+			}
+		}
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to apply filter rule",
+				slog.String("rule_id", rule.ID.String()),
+				slog.String("action", rule.Action),
+				slog.Any("error", err),
+			)
+			continue
+		}
+		metrics.FilterRuleHits.WithLabelValues(rule.Action).Inc()
+		if err := h.Repo.RecordFilterRuleHit(ctx, rule.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to record filter rule hit",
+				slog.String("rule_id", rule.ID.String()),
+				slog.Any("error", err),
+			)
+		}
+		h.queueRuleMatchWebhooks(ctx, rule, feed, item)
+		if added {
+			if err := QueueCollectionItemWebhooks(ctx, &h.Repo, h.Client, *rule.CollectionID, item.ID); err != nil {
+				slog.ErrorContext(ctx, "failed to queue webhook deliveries",
+					slog.String("event", webhooks.EventCollectionItem),
+					slog.Any("error", err),
+				)
+			}
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package workers
 
 import (
+	"net/http"
+
 	"github.com/hibiken/asynq"
 	"github.com/rhajizada/gazette/internal/config"
 	"github.com/rhajizada/gazette/internal/repository"
@@ -11,13 +13,18 @@ const MaxLimit = 100
 type Handler struct {
 	Repo         repository.Queries
 	Client       *asynq.Client
+	Inspector    *asynq.Inspector
 	OllamaConfig *config.OllamaConfig
+	// WebhookClient sends webhook deliveries, see webhooks.NewClient.
+	WebhookClient *http.Client
 }
 
-func NewHandler(repo *repository.Queries, client *asynq.Client, ollamaCfg *config.OllamaConfig) *Handler {
+func NewHandler(repo *repository.Queries, client *asynq.Client, inspector *asynq.Inspector, ollamaCfg *config.OllamaConfig, webhookClient *http.Client) *Handler {
 	return &Handler{
-		Repo:         *repo,
-		Client:       client,
-		OllamaConfig: ollamaCfg,
+		Repo:          *repo,
+		Client:        client,
+		Inspector:     inspector,
+		OllamaConfig:  ollamaCfg,
+		WebhookClient: webhookClient,
 	}
 }
@@ -6,23 +6,23 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/hibiken/asynq"
 	"github.com/ollama/ollama/api"
+	"github.com/rhajizada/gazette/internal/logging"
+	"github.com/rhajizada/gazette/internal/metrics"
 	"github.com/rhajizada/gazette/internal/repository"
 )
 
 func (h *Handler) HandleEmbedItem(ctx context.Context, t *asynq.Task) error {
-	prefix := fmt.Sprintf(
-		"task %s -",
-		t.ResultWriter().TaskID(),
-	)
 	var p EmbedItemPayload
 	if err := json.Unmarshal(t.Payload(), &p); err != nil {
 		return fmt.Errorf("json.Unmarshal failed: %v", asynq.SkipRetry)
 	}
 	itemID := p.ItemID
+	ctx = logging.With(ctx, slog.String("item_id", itemID.String()))
 
 	client, err := GetOllamaClient(h.OllamaConfig)
 	if err != nil {
@@ -49,14 +49,14 @@ func (h *Handler) HandleEmbedItem(ctx context.Context, t *asynq.Task) error {
 		Prompt: extracted,
 	}
 
+	start := time.Now()
 	resp, err := client.Embeddings(ctx, &req)
+	metrics.EmbeddingDuration.Observe(time.Since(start).Seconds())
 	if err != nil {
+		metrics.EmbeddingFailures.Inc()
 		return fmt.Errorf("failed to generate embedding for item %s: %v", itemID, err)
 	}
-	log.Printf(
-		"%s generated embddings for item %s ",
-		prefix, itemID,
-	)
+	slog.DebugContext(ctx, "generated embedding")
 
 	embeddingValue := vectorFromFloat64s(resp.Embedding)
 
@@ -85,10 +85,7 @@ func (h *Handler) HandleEmbedItem(ctx context.Context, t *asynq.Task) error {
 			return fmt.Errorf("failed to sync embeddings for item %s: %v", itemID, err)
 		}
 	}
-	log.Printf(
-		"%s synced embddings for item %s ",
-		prefix, itemID,
-	)
+	slog.InfoContext(ctx, "stored embedding")
 
 	return nil
 }
@@ -21,9 +21,9 @@ func GetOllamaClient(cfg *config.OllamaConfig) (*api.Client, error) {
 	return client, nil
 }
 
-func InitModels(c *api.Client, cfg *config.OllamaConfig) error {
+// InitModels verifies Ollama is reachable and serves the embeddings model.
+func InitModels(ctx context.Context, c *api.Client, cfg *config.OllamaConfig) error {
 	model := cfg.EmbeddingsModel
-	ctx := context.Background()
 	listReponse, err := c.List(ctx)
 	if err != nil {
 		return err
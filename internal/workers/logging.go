@@ -0,0 +1,38 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/rhajizada/gazette/internal/logging"
+)
+
+// Logging adds the task ID, type and queue, and the ID of the request that
+// queued the task, to every record logged while processing it.
+func Logging(next asynq.Handler) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, t *asynq.Task) error {
+		queue, _ := asynq.GetQueueName(ctx)
+		retried, _ := asynq.GetRetryCount(ctx)
+		ctx = logging.With(ctx,
+			slog.String("task_id", t.ResultWriter().TaskID()),
+			slog.String("task_type", t.Type()),
+			slog.String("queue", queue),
+			slog.Int("retry", retried),
+		)
+		if id := taskMetadata(t).RequestID; id != "" {
+			ctx = logging.WithRequestID(ctx, id)
+		}
+
+		start := time.Now()
+		err := next.ProcessTask(ctx, t)
+		elapsed := slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000)
+		if err != nil {
+			slog.ErrorContext(ctx, "task failed", elapsed, slog.Any("error", err))
+			return err
+		}
+		slog.InfoContext(ctx, "task processed", elapsed)
+		return nil
+	})
+}
@@ -1,42 +1,186 @@
 package workers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
+	"github.com/rhajizada/gazette/internal/logging"
+	"github.com/rhajizada/gazette/internal/tracing"
+	"github.com/rhajizada/gazette/internal/webhooks"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 const (
-	TypeSyncData  = "sync:data"
-	TypeSyncFeed  = "sync:feed"
-	TypeEmbedItem = "embed:item"
+	TypeSyncData        = "sync:data"
+	TypeSyncFeed        = "sync:feed"
+	TypeEmbedItem       = "embed:item"
+	TypeEmbedCollection = "embed:collection"
+	TypeDeliverWebhook  = "webhook:deliver"
 )
 
+// SyncInterval is how often the scheduler syncs all feeds.
+const SyncInterval = 30 * time.Minute
+
+// Payloads carry the trace context and request ID of the request that queued
+// them so worker spans and logs can be correlated with it. Since these differ
+// between requests, tasks are deduplicated by their ID rather than by payload:
+// a task is not queued again while one with the same ID is pending or running.
+
+// TaskMetadata is embedded in task payloads.
+type TaskMetadata struct {
+	TraceContext propagation.MapCarrier `json:",omitempty"`
+	RequestID    string                 `json:",omitempty"`
+}
+
+func newTaskMetadata(ctx context.Context) TaskMetadata {
+	return TaskMetadata{
+		TraceContext: tracing.Inject(ctx),
+		RequestID:    logging.RequestID(ctx),
+	}
+}
+
 type SyncFeedPayload struct {
 	FeedID uuid.UUID
+	TaskMetadata
 }
 
 type EmbedItemPayload struct {
 	ItemID uuid.UUID
+	TaskMetadata
+}
+
+type EmbedCollectionPayload struct {
+	CollectionID uuid.UUID
+	TaskMetadata
+}
+
+type DeliverWebhookPayload struct {
+	DeliveryID uuid.UUID
+	TaskMetadata
+}
+
+// SyncFeedTaskID returns the ID of the task syncing a feed, a feed can only
+// have one sync task at a time.
+func SyncFeedTaskID(feedID uuid.UUID) string {
+	return TypeSyncFeed + ":" + feedID.String()
+}
+
+// EmbedItemTaskID returns the ID of the task embedding an item.
+func EmbedItemTaskID(itemID uuid.UUID) string {
+	return TypeEmbedItem + ":" + itemID.String()
+}
+
+// EmbedCollectionTaskID returns the ID of the task embedding the seed text of
+// a smart collection.
+func EmbedCollectionTaskID(collectionID uuid.UUID) string {
+	return TypeEmbedCollection + ":" + collectionID.String()
+}
+
+// DeliverWebhookTaskID returns the ID of the task sending a webhook delivery.
+func DeliverWebhookTaskID(deliveryID uuid.UUID) string {
+	return TypeDeliverWebhook + ":" + deliveryID.String()
 }
 
 func NewSyncDataTask() (*asynq.Task, error) {
 	return asynq.NewTask(TypeSyncData, nil), nil
 }
 
-func NewSyncFeedTask(feedID uuid.UUID) (*asynq.Task, error) {
-	payload, err := json.Marshal(SyncFeedPayload{FeedID: feedID})
+func NewSyncFeedTask(ctx context.Context, feedID uuid.UUID) (*asynq.Task, error) {
+	payload, err := json.Marshal(SyncFeedPayload{
+		FeedID:       feedID,
+		TaskMetadata: newTaskMetadata(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(
+		TypeSyncFeed, payload,
+		asynq.TaskID(SyncFeedTaskID(feedID)),
+	), nil
+}
+
+func NewEmbedItemTask(ctx context.Context, itemID uuid.UUID) (*asynq.Task, error) {
+	payload, err := json.Marshal(EmbedItemPayload{
+		ItemID:       itemID,
+		TaskMetadata: newTaskMetadata(ctx),
+	})
 	if err != nil {
 		return nil, err
 	}
-	return asynq.NewTask(TypeSyncFeed, payload), nil
+	return asynq.NewTask(
+		TypeEmbedItem, payload,
+		asynq.TaskID(EmbedItemTaskID(itemID)),
+	), nil
 }
 
-func NewEmbedItemTask(itemID uuid.UUID) (*asynq.Task, error) {
-	payload, err := json.Marshal(EmbedItemPayload{ItemID: itemID})
+func NewEmbedCollectionTask(ctx context.Context, collectionID uuid.UUID) (*asynq.Task, error) {
+	payload, err := json.Marshal(EmbedCollectionPayload{
+		CollectionID: collectionID,
+		TaskMetadata: newTaskMetadata(ctx),
+	})
 	if err != nil {
 		return nil, err
 	}
-	return asynq.NewTask(TypeEmbedItem, payload), nil
+	return asynq.NewTask(
+		TypeEmbedCollection, payload,
+		asynq.TaskID(EmbedCollectionTaskID(collectionID)),
+	), nil
+}
+
+func NewDeliverWebhookTask(ctx context.Context, deliveryID uuid.UUID) (*asynq.Task, error) {
+	payload, err := json.Marshal(DeliverWebhookPayload{
+		DeliveryID:   deliveryID,
+		TaskMetadata: newTaskMetadata(ctx),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(
+		TypeDeliverWebhook, payload,
+		asynq.TaskID(DeliverWebhookTaskID(deliveryID)),
+		asynq.MaxRetry(webhooks.MaxRetry),
+	), nil
+}
+
+// IsDuplicate reports whether an enqueue failed because a task with the same
+// ID already exists.
+func IsDuplicate(err error) bool {
+	return errors.Is(err, asynq.ErrDuplicateTask) || errors.Is(err, asynq.ErrTaskIDConflict)
+}
+
+// Enqueue queues a task created with a deterministic ID. When a task with the
+// same ID is still pending, scheduled, retrying or running nothing is queued
+// and that task is returned with duplicate set. Archived tasks keep their ID
+// until deleted, so they are replaced, otherwise the task could never run
+// again.
+func Enqueue(ctx context.Context, client *asynq.Client, inspector *asynq.Inspector, task *asynq.Task, id, queue string) (info *asynq.TaskInfo, duplicate bool, err error) {
+	info, err = client.EnqueueContext(ctx, task, asynq.Queue(queue))
+	if !IsDuplicate(err) {
+		return info, false, err
+	}
+
+	existing, err := inspector.GetTaskInfo(queue, id)
+	if errors.Is(err, asynq.ErrTaskNotFound) || errors.Is(err, asynq.ErrQueueNotFound) {
+		// the task was removed in the meantime
+		return nil, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if existing.State != asynq.TaskStateArchived && existing.State != asynq.TaskStateCompleted {
+		return existing, true, nil
+	}
+
+	if err := inspector.DeleteTask(queue, id); err != nil && !errors.Is(err, asynq.ErrTaskNotFound) {
+		return nil, false, err
+	}
+	info, err = client.EnqueueContext(ctx, task, asynq.Queue(queue))
+	if IsDuplicate(err) {
+		return nil, true, nil
+	}
+	return info, false, err
 }
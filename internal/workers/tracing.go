@@ -0,0 +1,50 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hibiken/asynq"
+	"github.com/rhajizada/gazette/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/rhajizada/gazette/internal/workers")
+
+// taskMetadata decodes the metadata embedded in the payload of t.
+func taskMetadata(t *asynq.Task) TaskMetadata {
+	var m TaskMetadata
+	if len(t.Payload()) > 0 {
+		_ = json.Unmarshal(t.Payload(), &m)
+	}
+	return m
+}
+
+// Tracing starts a span for every task, continuing the trace of the request
+// that queued it when the payload carries one.
+func Tracing(next asynq.Handler) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, t *asynq.Task) error {
+		ctx = tracing.Extract(ctx, taskMetadata(t).TraceContext)
+
+		queue, _ := asynq.GetQueueName(ctx)
+		ctx, span := tracer.Start(ctx, t.Type(),
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(
+				attribute.String("messaging.system", "asynq"),
+				attribute.String("messaging.destination.name", queue),
+				attribute.String("messaging.message.id", t.ResultWriter().TaskID()),
+			),
+		)
+		defer span.End()
+
+		err := next.ProcessTask(ctx, t)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return err
+	})
+}
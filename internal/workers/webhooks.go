@@ -0,0 +1,293 @@
+package workers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/rhajizada/gazette/internal/export"
+	"github.com/rhajizada/gazette/internal/filters"
+	"github.com/rhajizada/gazette/internal/logging"
+	"github.com/rhajizada/gazette/internal/metrics"
+	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/webhooks"
+)
+
+// WebhookQueue is the queue webhook deliveries go through.
+const WebhookQueue = "webhooks"
+
+// maxWebhookSummaryLength bounds the length of the summaries of items in
+// webhook events in characters.
+const maxWebhookSummaryLength = 500
+
+// retryDelay spaces out the retries of webhook deliveries with
+// webhooks.Backoff, other tasks are retried as asynq does by default.
+func retryDelay(n int, err error, t *asynq.Task) time.Duration {
+	if t.Type() == TypeDeliverWebhook {
+		return webhooks.Backoff(n)
+	}
+	return asynq.DefaultRetryDelayFunc(n, err, t)
+}
+
+// webhookItem describes an item in webhook events, with the start of its
+// text as summary.
+func webhookItem(rec repository.Item) *webhooks.Item {
+	var text string
+	if rec.Description != nil {
+		text = export.Text(*rec.Description)
+	}
+	if strings.TrimSpace(text) == "" && rec.Content != nil {
+		text = export.Text(*rec.Content)
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) > maxWebhookSummaryLength {
+		text = string([]rune(text)[:maxWebhookSummaryLength]) + "…"
+	}
+	return &webhooks.Item{
+		ID:              rec.ID,
+		FeedID:          rec.FeedID,
+		Title:           rec.Title,
+		Summary:         text,
+		Link:            rec.Link,
+		Authors:         filters.AuthorNames(rec.Authors),
+		Categories:      rec.Categories,
+		PublishedParsed: rec.PublishedParsed,
+	}
+}
+
+func webhookFeed(rec repository.Feed) *webhooks.Feed {
+	return &webhooks.Feed{
+		ID:    rec.ID,
+		Title: rec.Title,
+		Link:  rec.Link,
+	}
+}
+
+// QueueWebhookDeliveries logs a delivery of an event to each webhook and
+// queues it. Deliveries that cannot be queued are logged as failed.
+func QueueWebhookDeliveries(ctx context.Context, repo *repository.Queries, client *asynq.Client, hooks []repository.Webhook, event string, data webhooks.Data) ([]repository.WebhookDelivery, error) {
+	deliveries := make([]repository.WebhookDelivery, 0, len(hooks))
+	var errs []error
+	for _, hook := range hooks {
+		body, err := json.Marshal(webhooks.Event{
+			Event:     event,
+			WebhookID: hook.ID,
+			CreatedAt: time.Now().UTC(),
+			Data:      data,
+		})
+		if err != nil {
+			return deliveries, err
+		}
+		d, err := repo.CreateWebhookDelivery(ctx, repository.CreateWebhookDeliveryParams{
+			WebhookID: hook.ID,
+			Event:     event,
+			Payload:   body,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to log delivery to webhook %s: %v", hook.ID, err))
+			continue
+		}
+		task, err := NewDeliverWebhookTask(ctx, d.ID)
+		if err == nil {
+			_, err = client.EnqueueContext(ctx, task, asynq.Queue(WebhookQueue))
+		}
+		if err != nil {
+			msg := fmt.Sprintf("failed to queue delivery: %v", err)
+			d.Status = webhooks.DeliveryFailed
+			d.Error = &msg
+			if err := repo.RecordWebhookAttempt(ctx, repository.RecordWebhookAttemptParams{
+				Status: d.Status,
+				Error:  d.Error,
+				ID:     d.ID,
+			}); err != nil {
+				slog.ErrorContext(ctx, "failed to record webhook delivery", slog.Any("error", err))
+			}
+			errs = append(errs, fmt.Errorf("failed to queue delivery %s: %v", d.ID, err))
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, errors.Join(errs...)
+}
+
+// QueueCollectionItemWebhooks notifies the webhooks of the owner and members
+// of a collection that an item was added to it.
+func QueueCollectionItemWebhooks(ctx context.Context, repo *repository.Queries, client *asynq.Client, collectionID, itemID uuid.UUID) error {
+	hooks, err := repo.ListWebhooksForCollectionItem(ctx, collectionID)
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+	name, err := repo.GetCollectionNameByID(ctx, collectionID)
+	if err != nil {
+		return err
+	}
+	item, err := repo.GetItemByID(ctx, itemID)
+	if err != nil {
+		return err
+	}
+	feed, err := repo.GetFeedByID(ctx, item.FeedID)
+	if err != nil {
+		return err
+	}
+	_, err = QueueWebhookDeliveries(ctx, repo, client, hooks, webhooks.EventCollectionItem, webhooks.Data{
+		Item:       webhookItem(item),
+		Feed:       webhookFeed(feed),
+		Collection: &webhooks.Collection{ID: collectionID, Name: name},
+	})
+	return err
+}
+
+// queueFeedItemWebhooks notifies the webhooks of the subscribers of a feed of
+// a new item. Failures are logged rather than failing the sync.
+func (h *Handler) queueFeedItemWebhooks(ctx context.Context, feed repository.Feed, item repository.Item) {
+	hooks, err := h.Repo.ListWebhooksForFeedItem(ctx, repository.ListWebhooksForFeedItemParams{
+		FeedID: feed.ID,
+		ItemID: item.ID,
+	})
+	if err == nil && len(hooks) > 0 {
+		_, err = QueueWebhookDeliveries(ctx, &h.Repo, h.Client, hooks, webhooks.EventFeedItem, webhooks.Data{
+			Item: webhookItem(item),
+			Feed: webhookFeed(feed),
+		})
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to queue webhook deliveries",
+			slog.String("event", webhooks.EventFeedItem),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// queueRuleMatchWebhooks notifies the webhooks of the owner of a filter rule
+// that it matched a new item.
+func (h *Handler) queueRuleMatchWebhooks(ctx context.Context, rule filterRule, feed repository.Feed, item repository.Item) {
+	hooks, err := h.Repo.ListWebhooksForRuleMatch(ctx, repository.ListWebhooksForRuleMatchParams{
+		UserID: rule.UserID,
+		RuleID: rule.ID,
+	})
+	if err == nil && len(hooks) > 0 {
+		_, err = QueueWebhookDeliveries(ctx, &h.Repo, h.Client, hooks, webhooks.EventRuleMatch, webhooks.Data{
+			Item: webhookItem(item),
+			Feed: webhookFeed(feed),
+			Rule: &webhooks.Rule{ID: rule.ID, Name: rule.Name, Action: rule.Action},
+		})
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to queue webhook deliveries",
+			slog.String("event", webhooks.EventRuleMatch),
+			slog.String("rule_id", rule.ID.String()),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// HandleWebhookDelivery posts a webhook delivery. Failed attempts are retried
+// with backoff, a delivery failing all of them counts against its webhook,
+// which is disabled after webhooks.MaxFailures such deliveries in a row.
+func (h *Handler) HandleWebhookDelivery(ctx context.Context, t *asynq.Task) error {
+	var p DeliverWebhookPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v", asynq.SkipRetry)
+	}
+	ctx = logging.With(ctx, slog.String("delivery_id", p.DeliveryID.String()))
+
+	d, err := h.Repo.GetWebhookDelivery(ctx, p.DeliveryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.InfoContext(ctx, "webhook was deleted, skipping delivery")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch webhook delivery %s: %v", p.DeliveryID, err)
+	}
+	if d.Status != webhooks.DeliveryPending {
+		return nil
+	}
+	hook, err := h.Repo.GetWebhookByID(ctx, d.WebhookID)
+	if errors.Is(err, sql.ErrNoRows) {
+		slog.InfoContext(ctx, "webhook was deleted, skipping delivery")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch webhook %s: %v", d.WebhookID, err)
+	}
+	ctx = logging.With(ctx, slog.String("webhook_id", hook.ID.String()))
+
+	if !hook.Enabled {
+		msg := webhooks.ErrDisabled.Error()
+		if err := h.Repo.RecordWebhookAttempt(ctx, repository.RecordWebhookAttemptParams{
+			Status: webhooks.DeliveryFailed,
+			Error:  &msg,
+			ID:     d.ID,
+		}); err != nil {
+			return fmt.Errorf("failed to record webhook delivery %s: %v", d.ID, err)
+		}
+		slog.InfoContext(ctx, "webhook is disabled, skipping delivery")
+		return nil
+	}
+
+	res := webhooks.Deliver(ctx, h.WebhookClient, hook.Url, hook.Secret, d.Event, d.ID, d.Payload)
+	metrics.WebhookDeliveryDuration.Observe(res.Duration.Seconds())
+
+	retried, _ := asynq.GetRetryCount(ctx)
+	maxRetry, _ := asynq.GetMaxRetry(ctx)
+	status := webhooks.DeliverySucceeded
+	if res.Err != nil {
+		status = webhooks.DeliveryFailed
+		if res.Retryable() && retried < maxRetry {
+			status = webhooks.DeliveryPending
+		}
+	}
+
+	attempt := repository.RecordWebhookAttemptParams{
+		Status:     status,
+		DurationMs: int32(res.Duration.Milliseconds()),
+		ID:         d.ID,
+	}
+	if res.StatusCode != 0 {
+		code := int32(res.StatusCode)
+		attempt.ResponseStatus = &code
+	}
+	if res.Err != nil {
+		msg := res.Err.Error()
+		attempt.Error = &msg
+	}
+	// the request was sent, so a failure to log it must not resend it
+	if err := h.Repo.RecordWebhookAttempt(ctx, attempt); err != nil {
+		slog.ErrorContext(ctx, "failed to record webhook delivery", slog.Any("error", err))
+	}
+
+	switch status {
+	case webhooks.DeliverySucceeded:
+		metrics.WebhookDeliveries.WithLabelValues("succeeded").Inc()
+		if err := h.Repo.RecordWebhookSuccess(ctx, hook.ID); err != nil {
+			slog.ErrorContext(ctx, "failed to record webhook success", slog.Any("error", err))
+		}
+		slog.InfoContext(ctx, "delivered webhook", slog.Int("status", res.StatusCode))
+		return nil
+	case webhooks.DeliveryPending:
+		metrics.WebhookDeliveries.WithLabelValues("retrying").Inc()
+		return fmt.Errorf("failed to deliver webhook: %v", res.Err)
+	}
+
+	metrics.WebhookDeliveries.WithLabelValues("failed").Inc()
+	row, err := h.Repo.RecordWebhookFailure(ctx, repository.RecordWebhookFailureParams{
+		MaxFailures: webhooks.MaxFailures,
+		Reason:      fmt.Sprintf("disabled after %d failed deliveries in a row", webhooks.MaxFailures),
+		ID:          hook.ID,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record webhook failure", slog.Any("error", err))
+	} else if !row.Enabled {
+		slog.WarnContext(ctx, "disabled webhook after repeated failures",
+			slog.Int("failures", int(row.FailureCount)),
+		)
+	}
+	return fmt.Errorf("failed to deliver webhook: %v: %w", res.Err, asynq.SkipRetry)
+}
@@ -0,0 +1,175 @@
+package workers_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/rhajizada/gazette/internal/oauth"
+	"github.com/rhajizada/gazette/internal/repository"
+	"github.com/rhajizada/gazette/internal/webhooks"
+	"github.com/rhajizada/gazette/internal/workers"
+)
+
+// testDatabaseEnv names the Postgres database, with the pgvector extension
+// available, the worker tests run against. They are skipped when it is unset.
+const testDatabaseEnv = "GAZETTE_TEST_POSTGRES_URL"
+
+func newTestRepo(t *testing.T) *repository.Queries {
+	t.Helper()
+	dsn := os.Getenv(testDatabaseEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDatabaseEnv)
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	if err := goose.SetDialect("postgres"); err != nil {
+		t.Fatal(err)
+	}
+	db := stdlib.OpenDBFromPool(pool)
+	t.Cleanup(func() { db.Close() })
+	if err := goose.Up(db, "../../data/sql/migrations"); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+	return repository.New(pool)
+}
+
+// receiver stands in for the endpoint of a webhook. It answers with status
+// and counts the requests whose signature verifies.
+type receiver struct {
+	*httptest.Server
+	status   atomic.Int32
+	verified atomic.Int32
+	hits     atomic.Int32
+}
+
+func newReceiver(t *testing.T, secret string) *receiver {
+	rcv := &receiver{}
+	rcv.status.Store(http.StatusOK)
+	rcv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rcv.hits.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		if webhooks.Verify(secret, r.Header.Get(webhooks.TimestampHeader), body, r.Header.Get(webhooks.SignatureHeader)) {
+			rcv.verified.Add(1)
+		}
+		w.WriteHeader(int(rcv.status.Load()))
+	}))
+	t.Cleanup(rcv.Close)
+	return rcv
+}
+
+// TestHandleWebhookDelivery delivers signed events to a receiver until it
+// fails webhooks.MaxFailures deliveries in a row, which disables the webhook.
+func TestHandleWebhookDelivery(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	id := uuid.NewString()
+	user, err := repo.CreateUser(ctx, repository.CreateUserParams{
+		Sub:   id,
+		Name:  "user-" + id,
+		Email: id + "@example.com",
+		Role:  oauth.RoleMember,
+	})
+	if err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	secret := "0123456789abcdef0123456789abcdef"
+	rcv := newReceiver(t, secret)
+	hook, err := repo.CreateWebhook(ctx, repository.CreateWebhookParams{
+		UserID:        user.ID,
+		Name:          "receiver",
+		Url:           rcv.URL,
+		Secret:        secret,
+		Events:        []string{webhooks.EventFeedItem},
+		FeedIds:       []uuid.UUID{},
+		CollectionIds: []uuid.UUID{},
+		RuleIds:       []uuid.UUID{},
+		Enabled:       true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create webhook: %v", err)
+	}
+
+	// the receiver listens on a loopback address
+	h := workers.NewHandler(repo, nil, nil, nil, webhooks.NewClient(true))
+
+	// deliver queues a ping to the webhook, handles it as its last attempt
+	// and returns the logged delivery
+	deliver := func() (repository.WebhookDelivery, error) {
+		t.Helper()
+		d, err := repo.CreateWebhookDelivery(ctx, repository.CreateWebhookDeliveryParams{
+			WebhookID: hook.ID,
+			Event:     webhooks.EventPing,
+			Payload:   []byte(`{"event":"ping"}`),
+		})
+		if err != nil {
+			t.Fatalf("failed to log delivery: %v", err)
+		}
+		task, err := workers.NewDeliverWebhookTask(ctx, d.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handleErr := h.HandleWebhookDelivery(ctx, task)
+		d, err = repo.GetWebhookDelivery(ctx, d.ID)
+		if err != nil {
+			t.Fatalf("failed to fetch delivery: %v", err)
+		}
+		return d, handleErr
+	}
+	getHook := func() repository.Webhook {
+		t.Helper()
+		hook, err := repo.GetWebhookByID(ctx, hook.ID)
+		if err != nil {
+			t.Fatalf("failed to fetch webhook: %v", err)
+		}
+		return hook
+	}
+
+	d, err := deliver()
+	if err != nil || d.Status != webhooks.DeliverySucceeded {
+		t.Fatalf("got %s: %v", d.Status, err)
+	}
+	if rcv.verified.Load() != 1 {
+		t.Fatal("signature of delivery does not verify")
+	}
+
+	rcv.status.Store(http.StatusInternalServerError)
+	for i := 1; i <= webhooks.MaxFailures; i++ {
+		d, err := deliver()
+		if err == nil || d.Status != webhooks.DeliveryFailed {
+			t.Fatalf("delivery %d: got %s: %v", i, d.Status, err)
+		}
+		if d.ResponseStatus == nil || *d.ResponseStatus != http.StatusInternalServerError {
+			t.Errorf("delivery %d: response status was not logged", i)
+		}
+		if hook := getHook(); hook.FailureCount != int32(i) || hook.Enabled != (i < webhooks.MaxFailures) {
+			t.Fatalf("after %d failures: got %d failures, enabled %v", i, hook.FailureCount, hook.Enabled)
+		}
+	}
+
+	// deliveries to the disabled webhook are logged as failed without
+	// reaching it
+	hits := rcv.hits.Load()
+	d, err = deliver()
+	if err != nil || d.Status != webhooks.DeliveryFailed || d.Error == nil || *d.Error != webhooks.ErrDisabled.Error() {
+		t.Fatalf("got %s: %v", d.Status, err)
+	}
+	if rcv.hits.Load() != hits {
+		t.Error("disabled webhook was delivered to")
+	}
+}
@@ -18,6 +18,11 @@ interface AuthContextType {
 
 const AuthContext = createContext<AuthContextType | undefined>(undefined);
 
+const baseUrl = import.meta.env.VITE_API_BASE_URL!;
+
+// how long before expiry the JWT is refreshed
+const REFRESH_MARGIN_MS = 60_000;
+
 export function AuthProvider({ children }: { children: ReactNode }) {
   // initialize token from localStorage (if present)
   const [token, setToken] = useState<string | null>(
@@ -27,7 +32,7 @@ export function AuthProvider({ children }: { children: ReactNode }) {
   // one shared Api client
   const api = useMemo(() => {
     return new Api<unknown>({
-      baseUrl: import.meta.env.VITE_API_BASE_URL!,
+      baseUrl,
       baseApiParams: { secure: true, format: "json" },
       securityWorker: () => {
         const t = window.localStorage.getItem("token");
@@ -46,28 +51,54 @@ export function AuthProvider({ children }: { children: ReactNode }) {
     }
   }, [token, api]);
 
-  // auto‐logout when JWT expires
+  // refresh the JWT shortly before it expires, the refresh token lives in
+  // an HttpOnly cookie scoped to /oauth
   useEffect(() => {
     if (!token) return;
     // assume JWT has exp claim
     const { exp } = jwtDecode<{ exp: number }>(token);
-    const msUntilExpiry = exp * 1000 - Date.now();
-    if (msUntilExpiry <= 0) {
-      logout();
-    } else {
-      const timer = setTimeout(logout, msUntilExpiry);
-      return () => clearTimeout(timer);
-    }
+    const msUntilRefresh = exp * 1000 - Date.now() - REFRESH_MARGIN_MS;
+    const timer = setTimeout(refresh, Math.max(msUntilRefresh, 0));
+    return () => clearTimeout(timer);
   }, [token]);
 
+  async function refresh() {
+    try {
+      const res = await fetch(`${baseUrl}/oauth/refresh`, {
+        method: "POST",
+        credentials: "include",
+      });
+      if (!res.ok) throw new Error(await res.text());
+      const { token: newToken } = (await res.json()) as { token: string };
+      setToken(newToken);
+    } catch {
+      setToken(null);
+      window.location.href = "/login";
+    }
+  }
+
   function login(newToken: string) {
     setToken(newToken);
   }
 
-  function logout() {
-    setToken(null);
-    // force nav to login
-    window.location.href = "/login";
+  async function logout() {
+    let redirectUrl = "/login";
+    try {
+      const t = window.localStorage.getItem("token");
+      const res = await fetch(`${baseUrl}/oauth/logout`, {
+        method: "POST",
+        credentials: "include",
+        headers: t ? { Authorization: `Bearer ${t}` } : {},
+      });
+      if (res.ok) {
+        const body = (await res.json()) as { redirect_url?: string };
+        redirectUrl = body.redirect_url || redirectUrl;
+      }
+    } finally {
+      setToken(null);
+      // force nav to login or the identity provider's logout page
+      window.location.href = redirectUrl;
+    }
   }
 
   return (
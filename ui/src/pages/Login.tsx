@@ -1,9 +1,26 @@
 import { Footer } from "@/components/Footer";
 import { Button } from "@/components/ui/button";
 import { Card } from "@/components/ui/card";
+import { useEffect, useState } from "react";
 import { Link } from "react-router-dom";
 
+const baseUrl = import.meta.env.VITE_API_BASE_URL!;
+
+interface Provider {
+  name: string;
+  display_name: string;
+}
+
 export default function Login() {
+  const [providers, setProviders] = useState<Provider[]>([]);
+
+  useEffect(() => {
+    fetch(`${baseUrl}/oauth/providers`)
+      .then((res) => (res.ok ? res.json() : { providers: [] }))
+      .then((data) => setProviders(data.providers ?? []))
+      .catch(() => setProviders([]));
+  }, []);
+
   return (
     <div className="h-screen flex flex-col justify-between">
       <div className="flex flex-1 items-center justify-center p-4">
@@ -19,11 +36,32 @@ export default function Login() {
               Smart RSS aggregator with personalized feeds.
             </p>
 
-            <Button asChild size="lg" className="w-full">
-              <Link to="/oauth/login" reloadDocument>
-                Login
-              </Link>
-            </Button>
+            {providers.length > 1 ? (
+              <div className="w-full space-y-3">
+                {providers.map((provider) => (
+                  <Button
+                    key={provider.name}
+                    asChild
+                    size="lg"
+                    variant="outline"
+                    className="w-full"
+                  >
+                    <Link
+                      to={`/oauth/login?provider=${encodeURIComponent(provider.name)}`}
+                      reloadDocument
+                    >
+                      Continue with {provider.display_name}
+                    </Link>
+                  </Button>
+                ))}
+              </div>
+            ) : (
+              <Button asChild size="lg" className="w-full">
+                <Link to="/oauth/login" reloadDocument>
+                  Login
+                </Link>
+              </Button>
+            )}
           </div>
         </Card>
       </div>